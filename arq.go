@@ -0,0 +1,297 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommframe"
+)
+
+var ErrNotAcknowledged = errors.New("frame not acknowledged")
+
+const (
+	ACK byte = 0x06 // Positive acknowledgement
+	NAK byte = 0x15 // Negative acknowledgement
+
+	arqData   byte = 0x01 // Kind of the frames carrying data
+	arqHeader      = 2    // Kind and sequence bit
+	arqCheck       = 4    // CRC-32 of the header and the payload
+)
+
+type ARQOptions struct {
+	Codec      FrameCodec    // Framing on the link, defaults to COBS
+	AckTimeout time.Duration // Wait for the acknowledgement, defaults to 500ms
+	MaxRetries int           // Retransmissions after the first attempt, defaults to 3
+}
+
+type ARQ struct {
+	Unicomm
+	Options ARQOptions
+
+	framed          *Framed
+	sendSeq         byte    // Sequence bit of the next frame sent
+	recvSeq         byte    // Sequence bit of the next frame expected
+	inbox           []Frame // Frames received while waiting for acknowledgements
+	pending         []byte  // Delivered payload bytes not consumed yet
+	retransmissions atomic.Uint64
+	mutex           sync.Mutex // Keep one frame in flight
+}
+
+/*
+Creates a stop-and-wait reliability layer for lossy links, such
+as serial radios. Each message is sent in a frame with a sequence
+bit and a CRC-32, and retransmitted until the peer acknowledges
+it. Both ends must use the layer with the same options
+*/
+func NewARQ(comm Unicomm, options ARQOptions) *ARQ {
+	if options.Codec == nil {
+		options.Codec = unicommframe.COBS{}
+	}
+	if options.AckTimeout == 0 {
+		options.AckTimeout = 500 * time.Millisecond
+	}
+	if options.MaxRetries == 0 {
+		options.MaxRetries = 3
+	}
+	return &ARQ{
+		Unicomm: comm,
+		Options: options,
+		framed:  NewFramed(comm, options.Codec),
+	}
+}
+
+/*
+Returns how many frames were sent again since the start
+*/
+func (a *ARQ) Retransmissions() uint64 {
+	return a.retransmissions.Load()
+}
+
+/*
+Builds a frame of the given kind and sequence bit
+*/
+func arqFrame(kind byte, seq byte, payload []byte) []byte {
+	frame := make([]byte, 0, arqHeader+len(payload)+arqCheck)
+	frame = append(frame, kind, seq)
+	frame = append(frame, payload...)
+	return binary.BigEndian.AppendUint32(frame, crc32.ChecksumIEEE(frame))
+}
+
+/*
+Splits a frame into its fields, ok is false when the frame is
+truncated or corrupted
+*/
+func parseARQFrame(frame []byte) (kind byte, seq byte, payload []byte, ok bool) {
+	if len(frame) < arqHeader+arqCheck {
+		return 0, 0, nil, false
+	}
+	body, check := frame[:len(frame)-arqCheck], frame[len(frame)-arqCheck:]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(check) {
+		return 0, 0, nil, false
+	}
+	return body[0], body[1], body[arqHeader:], true
+}
+
+/*
+Reads the next frame and handles it. Data frames are
+acknowledged and new ones queued in the inbox, corrupted frames
+are answered with a NAK. Acknowledgements are returned to the
+caller. Must be called with the mutex locked
+*/
+func (a *ARQ) receive() (kind byte, seq byte, err error) {
+	frame, err := a.framed.ReadFrame()
+	if err != nil {
+		if isTimeout(err) || !a.Unicomm.IsConnected() {
+			return 0, 0, err
+		}
+		// Frames the codec cannot decode were corrupted on the link
+		frame.Payload = nil
+	}
+
+	kind, seq, payload, ok := parseARQFrame(frame.Payload)
+	if !ok {
+		return 0, 0, a.framed.Write(arqFrame(NAK, a.recvSeq, nil))
+	}
+	if kind == arqData {
+		if err := a.framed.Write(arqFrame(ACK, seq, nil)); err != nil {
+			return 0, 0, err
+		}
+		// Duplicates happen when an acknowledgement is lost
+		if seq == a.recvSeq {
+			frame.Payload = bytes.Clone(payload)
+			a.inbox = append(a.inbox, frame)
+			a.recvSeq ^= 1
+		}
+	}
+	return kind, seq, nil
+}
+
+/*
+Waits for the acknowledgement of the frame with the sequence bit.
+Returns false when the frame must be sent again
+*/
+func (a *ARQ) waitAck(seq byte) (bool, error) {
+	deadline := time.Now().Add(a.Options.AckTimeout)
+	for time.Now().Before(deadline) {
+		kind, received, err := a.receive()
+		if err != nil {
+			if isTimeout(err) {
+				continue
+			}
+			return false, err
+		}
+		if kind == ACK && received == seq {
+			return true, nil
+		}
+		if kind == NAK && received == seq {
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+/*
+Sends the message and waits for its acknowledgement, sending it
+again on NAK or timeout up to the maximum retries
+*/
+func (a *ARQ) Write(message []byte) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	frame := arqFrame(arqData, a.sendSeq, message)
+	for attempt := 0; attempt <= a.Options.MaxRetries; attempt++ {
+		if attempt > 0 {
+			a.retransmissions.Add(1)
+		}
+		if err := a.framed.Write(frame); err != nil {
+			return err
+		}
+		acknowledged, err := a.waitAck(a.sendSeq)
+		if err != nil {
+			return err
+		}
+		if acknowledged {
+			a.sendSeq ^= 1
+			return nil
+		}
+	}
+	return fmt.Errorf("%w after %d attempts", ErrNotAcknowledged, a.Options.MaxRetries+1)
+}
+
+/*
+Returns the next frame received, waiting for it up to the
+read timeout. Must be called with the mutex locked
+*/
+func (a *ARQ) next() (Frame, error) {
+	for len(a.inbox) == 0 {
+		if _, _, err := a.receive(); err != nil {
+			return Frame{}, err
+		}
+	}
+	frame := a.inbox[0]
+	a.inbox = a.inbox[1:]
+	return frame, nil
+}
+
+/*
+Reads the next message sent by the peer. The raw bytes are the
+frame on the link, with the sequence bit and the checksum
+*/
+func (a *ARQ) ReadFrame() (Frame, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if len(a.pending) > 0 {
+		payload := a.pending
+		a.pending = nil
+		return Frame{Payload: payload, Received: time.Now()}, nil
+	}
+	return a.next()
+}
+
+/*
+Reads up to n payload bytes
+*/
+func (a *ARQ) Read(n uint) ([]byte, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if len(a.pending) == 0 {
+		frame, err := a.next()
+		if err != nil {
+			return nil, err
+		}
+		a.pending = frame.Payload
+	}
+	size := min(int(n), len(a.pending))
+	data := bytes.Clone(a.pending[:size])
+	a.pending = a.pending[size:]
+	return data, nil
+}
+
+/*
+Reads payload data until a target delimiter is found
+*/
+func (a *ARQ) ReadUntil(delimiter string) ([]byte, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for {
+		if index := bytes.Index(a.pending, []byte(delimiter)); index >= 0 {
+			data := bytes.Clone(a.pending[:index+len(delimiter)])
+			a.pending = a.pending[index+len(delimiter):]
+			return data, nil
+		}
+		frame, err := a.next()
+		if err != nil {
+			return nil, err
+		}
+		a.pending = append(a.pending, frame.Payload...)
+	}
+}
+
+/*
+Closes the connection and restarts the sequence bits, so both
+ends must reconnect together
+*/
+func (a *ARQ) Disconnect() error {
+	a.mutex.Lock()
+	a.sendSeq, a.recvSeq = 0, 0
+	a.inbox, a.pending = nil, nil
+	a.mutex.Unlock()
+
+	return a.framed.Disconnect()
+}
+
+/*
+Writes an array of bytes, the same as Write since the frames
+never carry the end delimiter of the backend
+*/
+func (a *ARQ) WriteRaw(message []byte) error {
+	return a.Write(message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (a *ARQ) Unwrap() Unicomm {
+	return a.framed
+}
+
+/*
+Stops the lookup of data capabilities, which would bypass the
+retransmissions
+*/
+func (a *ARQ) transformsData() {}
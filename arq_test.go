@@ -0,0 +1,70 @@
+package unicomm_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+)
+
+// Drops the first written message, as a noisy radio link would
+type dropFirst struct {
+	unicomm.Unicomm
+	dropped bool
+}
+
+func (d *dropFirst) Write(message []byte) error {
+	return d.WriteRaw(message)
+}
+
+func (d *dropFirst) WriteRaw(message []byte) error {
+	if !d.dropped {
+		d.dropped = true
+		return nil
+	}
+	return d.Unicomm.(unicomm.RawWriter).WriteRaw(message)
+}
+
+func TestARQRetransmission(t *testing.T) {
+	local, remote := net.Pipe()
+	// The end delimiter of the link must not be appended to the frames
+	options := unicommserial.SerialOptions{PortName: "radio", ReadTimeout: 20 * time.Millisecond, EndDelimiter: "\r\n"}
+	arqOptions := unicomm.ARQOptions{AckTimeout: 100 * time.Millisecond}
+
+	sender := unicomm.NewARQ(&dropFirst{Unicomm: unicommserial.NewSerialFromStream(local, options)}, arqOptions)
+	receiver := unicomm.NewARQ(unicommserial.NewSerialFromStream(remote, options), arqOptions)
+	defer sender.Disconnect()
+	defer receiver.Disconnect()
+
+	received := make(chan []byte, 2)
+	go func() {
+		for range 2 {
+			for {
+				frame, err := receiver.ReadFrame()
+				if err == nil {
+					received <- frame.Payload
+					break
+				}
+			}
+		}
+	}()
+
+	for _, message := range []string{"TEMP 21.5", "TEMP 21.6"} {
+		if err := sender.Write([]byte(message)); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case payload := <-received:
+			if string(payload) != message {
+				t.Fatalf("got %q, want %q", payload, message)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for %q", message)
+		}
+	}
+	if sender.Retransmissions() != 1 {
+		t.Fatalf("expected 1 retransmission, got %d", sender.Retransmissions())
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+type AuditEntry struct {
+	Sequence  uint64 // Monotonic, starts at 1
+	Time      time.Time
+	Direction TapDirection
+	Data      []byte
+	Err       error
+}
+
+type Audited struct {
+	Unicomm
+
+	ring     []AuditEntry // Fixed capacity, overwritten when full
+	next     int          // Position of the next entry in the ring
+	sequence uint64       // Sequence of the last entry
+	mutex    sync.Mutex   // Serialize operations so sequences follow the wire
+}
+
+/*
+Creates a middleware that stamps every write and read with a
+monotonically increasing sequence number and keeps the last
+entries in a ring buffer. Operations are serialized, so the
+sequence is the order in which they reached the device
+*/
+func NewAudited(comm Unicomm, capacity int) *Audited {
+	if capacity <= 0 {
+		capacity = 1024
+	}
+	return &Audited{
+		Unicomm: comm,
+		ring:    make([]AuditEntry, 0, capacity),
+	}
+}
+
+/*
+Appends an entry to the ring, must be called with the mutex
+locked
+*/
+func (a *Audited) append(direction TapDirection, data []byte, err error) {
+	a.sequence++
+	entry := AuditEntry{
+		Sequence:  a.sequence,
+		Time:      time.Now(),
+		Direction: direction,
+		Data:      bytes.Clone(data),
+		Err:       err,
+	}
+	if len(a.ring) < cap(a.ring) {
+		a.ring = append(a.ring, entry)
+	} else {
+		a.ring[a.next] = entry
+	}
+	a.next = (a.next + 1) % cap(a.ring)
+}
+
+/*
+Returns the entries in the ring, oldest first
+*/
+func (a *Audited) Entries() []AuditEntry {
+	return a.Since(0)
+}
+
+/*
+Returns the entries in the ring with a sequence greater than
+the given one, oldest first
+*/
+func (a *Audited) Since(sequence uint64) []AuditEntry {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	entries := make([]AuditEntry, 0, len(a.ring))
+	start := 0
+	if len(a.ring) == cap(a.ring) {
+		start = a.next
+	}
+	for i := range len(a.ring) {
+		entry := a.ring[(start+i)%len(a.ring)]
+		if entry.Sequence > sequence {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+/*
+Returns the sequence number of the last entry
+*/
+func (a *Audited) Sequence() uint64 {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.sequence
+}
+
+/*
+Reads a number of bytes
+*/
+func (a *Audited) Read(n uint) ([]byte, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	data, err := a.Unicomm.Read(n)
+	a.append(Received, data, err)
+	return data, err
+}
+
+/*
+Reads data until a target delimiter is found
+*/
+func (a *Audited) ReadUntil(delimiter string) ([]byte, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	data, err := ReadUntil(a.Unicomm, delimiter)
+	a.append(Received, data, err)
+	return data, err
+}
+
+/*
+Reads everything currently buffered by the instance
+*/
+func (a *Audited) ReadAvailable() ([]byte, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	data, err := ReadAvailable(a.Unicomm)
+	a.append(Received, data, err)
+	return data, err
+}
+
+/*
+Writes an array of bytes
+*/
+func (a *Audited) Write(message []byte) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	data := bytes.Clone(message)
+	err := a.Unicomm.Write(message)
+	a.append(Transmitted, data, err)
+	return err
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (a *Audited) WriteRaw(message []byte) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	data := bytes.Clone(message)
+	err := writeRaw(a.Unicomm, message)
+	a.append(Transmitted, data, err)
+	return err
+}
+
+/*
+Returns the wrapped instance
+*/
+func (a *Audited) Unwrap() Unicomm {
+	return a.Unicomm
+}
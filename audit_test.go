@@ -0,0 +1,42 @@
+package unicomm_test
+
+import (
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+func TestAuditedRingBuffer(t *testing.T) {
+	port := serveOnce(t, nil)
+	audited := unicomm.NewAudited(unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+	}), 3)
+	if err := audited.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer audited.Disconnect()
+
+	for _, command := range []string{"A\n", "B\n", "C\n", "D\n", "E\n"} {
+		if err := audited.Write([]byte(command)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries := audited.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Sequence != uint64(i+3) {
+			t.Fatalf("entry %d has sequence %d, want %d", i, entry.Sequence, i+3)
+		}
+	}
+	if string(entries[0].Data) != "C\n" {
+		t.Fatalf("oldest entry is %q, want %q", entries[0].Data, "C\n")
+	}
+	if since := audited.Since(4); len(since) != 1 || since[0].Sequence != 5 {
+		t.Fatalf("unexpected entries since 4: %+v", since)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"fmt"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+/*
+Implemented by instances able to drain their receive buffer
+without waiting for the read timeout
+*/
+type AvailableReader interface {
+	ReadAvailable() ([]byte, error)
+}
+
+/*
+Returns everything currently buffered by the instance without
+waiting for the read timeout, which is useful for callers
+doing their own framing. Not supported through wrappers that
+transform the data without implementing ReadAvailable
+*/
+func ReadAvailable(comm Unicomm) ([]byte, error) {
+	if reader, ok := asData[AvailableReader](comm); ok {
+		return reader.ReadAvailable()
+	}
+	return nil, fmt.Errorf("read available is not supported")
+}
+
+/*
+Implemented by instances able to read into a buffer provided
+by the caller
+*/
+type IntoReader interface {
+	ReadInto(buffer []byte) (int, error)
+}
+
+/*
+Reads into the buffer and returns the number of bytes read.
+Instances without ReadInto, including wrappers that transform
+the data, are read with Read and copied
+*/
+func ReadInto(comm Unicomm, buffer []byte) (int, error) {
+	if reader, ok := comm.(IntoReader); ok {
+		return reader.ReadInto(buffer)
+	}
+	data, err := comm.Read(uint(len(buffer)))
+	return copy(buffer, data), err
+}
+
+/*
+Implemented by instances able to read until a delimiter with
+size constraints
+*/
+type FullReader interface {
+	ReadUntilFull(delimiter string, minimum uint, maximum uint) ([]byte, error)
+}
+
+/*
+Reads until the delimiter is found with at least minimum bytes
+read, or until maximum bytes are read, returning them along with
+ErrMaximumReached. Instances without ReadUntilFull, such as the
+backends, are read one byte at a time so no byte of the next
+frame is consumed
+*/
+func ReadUntilFull(comm Unicomm, delimiter string, minimum uint, maximum uint) ([]byte, error) {
+	if reader, ok := comm.(FullReader); ok {
+		return reader.ReadUntilFull(delimiter, minimum, maximum)
+	}
+
+	var buffer []byte
+	for {
+		if end, err := fullFrameEnd(buffer, delimiter, minimum, maximum); end > 0 {
+			return buffer, err
+		}
+		data, err := comm.Read(1)
+		buffer = append(buffer, data...)
+		if err != nil {
+			return buffer, err
+		}
+		if len(data) == 0 {
+			return buffer, unicommio.TimeoutError("read until")
+		}
+	}
+}
+
+/*
+Implemented by instances able to read a message of a known size
+in as few system calls as possible
+*/
+type AtLeastReader interface {
+	ReadAtLeast(minimum uint, maximum uint) ([]byte, error)
+}
+
+/*
+Reads at least minimum and at most maximum bytes, e.g. a fixed
+header and whatever part of the payload already arrived. Bytes
+received before a timeout are returned along with it. Instances
+without ReadAtLeast are read with Read until minimum bytes arrive
+*/
+func ReadAtLeast(comm Unicomm, minimum uint, maximum uint) ([]byte, error) {
+	if maximum < minimum {
+		return nil, fmt.Errorf("invalid sizes: maximum %d is lower than minimum %d", maximum, minimum)
+	}
+	if reader, ok := comm.(AtLeastReader); ok {
+		return reader.ReadAtLeast(minimum, maximum)
+	}
+
+	var buffer []byte
+	for uint(len(buffer)) < minimum {
+		data, err := comm.Read(maximum - uint(len(buffer)))
+		buffer = append(buffer, data...)
+		if err != nil {
+			return buffer, err
+		}
+		if len(data) == 0 {
+			return buffer, unicommio.TimeoutError("read at least")
+		}
+	}
+	return buffer, nil
+}
+
+/*
+Implemented by instances able to fill several buffers with a
+single read
+*/
+type VectoredReader interface {
+	ReadVectored(buffers [][]byte) (int, error)
+}
+
+/*
+Fills the buffers in order, e.g. a header and its payload, and
+returns the number of bytes read, which is less than the size of
+the buffers only along with an error. Instances without
+ReadVectored are read with ReadAtLeast and the data is copied
+*/
+func ReadVectored(comm Unicomm, buffers [][]byte) (int, error) {
+	if reader, ok := comm.(VectoredReader); ok {
+		return reader.ReadVectored(buffers)
+	}
+	size := uint(unicommio.VectorSize(buffers))
+	data, err := ReadAtLeast(comm, size, size)
+	return unicommio.Scatter(buffers, data), err
+}
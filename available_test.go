@@ -0,0 +1,74 @@
+package unicomm_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+func TestReadAvailable(t *testing.T) {
+	port := serveOnce(t, []byte("partial frame"))
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP: unicommtcp.TCPOptions{
+			Host:        "127.0.0.1",
+			Port:        port,
+			ReadTimeout: 5 * time.Second,
+		},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	data, err := unicomm.ReadAvailable(comm)
+	if err != nil || string(data) != "partial frame" {
+		t.Fatalf("read available returned %q, %v", data, err)
+	}
+
+	data, err = unicomm.ReadAvailable(comm)
+	if err != nil || len(data) != 0 {
+		t.Fatalf("read available on empty buffer returned %q, %v", data, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("read available waited %v", elapsed)
+	}
+}
+
+func TestTCPReadInto(t *testing.T) {
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	comm := unicommtcp.NewTCPFromConn(local, unicommtcp.TCPOptions{ReadTimeout: 50 * time.Millisecond})
+	defer comm.Disconnect()
+
+	// The bytes after the delimiter are kept pending by ReadUntil
+	go remote.Write([]byte("READY\nTEMP 21.5"))
+	if frame, err := comm.ReadUntil("\n"); err != nil || string(frame) != "READY\n" {
+		t.Fatalf("ReadUntil = %q, %v", frame, err)
+	}
+
+	// Pending bytes are read first, into the same buffer every time
+	buffer := make([]byte, 4)
+	for _, expected := range []string{"TEMP", " 21.", "5"} {
+		n, err := comm.ReadInto(buffer)
+		if err != nil || string(buffer[:n]) != expected {
+			t.Fatalf("ReadInto = %q, %v, want %q", buffer[:n], err, expected)
+		}
+	}
+
+	// Then the connection is read, nothing before the timeout is not an error
+	n, err := comm.ReadInto(buffer)
+	if n != 0 || err != nil {
+		t.Fatalf("ReadInto = %d, %v, want 0 and no error on timeout", n, err)
+	}
+	go remote.Write([]byte("OK"))
+	if n, err := comm.ReadInto(buffer); err != nil || string(buffer[:n]) != "OK" {
+		t.Fatalf("ReadInto = %q, %v", buffer[:n], err)
+	}
+}
@@ -0,0 +1,55 @@
+package unicomm_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+/*
+Measures the cost of reading delimited frames from a device
+streaming telemetry as fast as the link allows
+*/
+func BenchmarkReadUntil(b *testing.B) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		frames := bytes.Repeat([]byte("T=21.53;H=40.12;P=1013.25\r\n"), 256)
+		for {
+			if _, err := conn.Write(frames); err != nil {
+				return
+			}
+		}
+	}()
+
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP: unicommtcp.TCPOptions{
+			Host: "127.0.0.1",
+			Port: uint(listener.Addr().(*net.TCPAddr).Port),
+		},
+	})
+	if err := comm.Connect(); err != nil {
+		b.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	b.ReportAllocs()
+	for range b.N {
+		if _, err := unicomm.ReadUntil(comm, "\r\n"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
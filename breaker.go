@@ -0,0 +1,295 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type BreakerState uint8
+
+const (
+	BreakerClosed   BreakerState = 0 // Operations reach the device
+	BreakerOpen     BreakerState = 1 // Operations fail fast until the cool-down ends
+	BreakerHalfOpen BreakerState = 2 // One probe decides whether to close or open again
+)
+
+var ErrBreakerOpen = errors.New("circuit breaker is open")
+
+type BreakerOptions struct {
+	Failures  int                 // Consecutive failures opening the breaker, defaults to 5
+	CoolDown  time.Duration       // Time failing fast before probing, defaults to 30s
+	IsFailure func(error) bool    // Errors counted as failures, defaults to all of them
+	Probe     func(Unicomm) error // Checks the device after the cool-down, nil lets the next operation probe it
+	OnState   func(BreakerState)  // Called on every state change
+}
+
+type Breaker struct {
+	Unicomm
+	Options BreakerOptions
+
+	state    BreakerState
+	failures int       // Consecutive failures while closed
+	until    time.Time // End of the cool-down while open
+	probing  bool      // A probe is running while half-open
+	timer    *time.Timer
+	mutex    sync.Mutex // Protect the state
+}
+
+/*
+Returns the name of the state
+*/
+func (bs BreakerState) String() string {
+	switch bs {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", bs)
+	}
+}
+
+/*
+Creates a wrapper that stops reaching a device after consecutive
+failures, failing fast with ErrBreakerOpen during a cool-down
+instead of waiting for a timeout on every operation. A probe then
+closes the breaker when the device answers again
+*/
+func NewBreaker(comm Unicomm, options BreakerOptions) *Breaker {
+	if options.Failures == 0 {
+		options.Failures = 5
+	}
+	if options.CoolDown == 0 {
+		options.CoolDown = 30 * time.Second
+	}
+	return &Breaker{
+		Unicomm: comm,
+		Options: options,
+	}
+}
+
+/*
+Returns the current state
+*/
+func (b *Breaker) State() BreakerState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.state
+}
+
+/*
+Closes the breaker, e.g. after the device is replaced
+*/
+func (b *Breaker) Reset() {
+	b.mutex.Lock()
+	changed := b.setState(BreakerClosed)
+	b.mutex.Unlock()
+
+	b.notify(changed, BreakerClosed)
+}
+
+/*
+Changes the state, must be called with the mutex locked.
+Returns true if the state changed
+*/
+func (b *Breaker) setState(state BreakerState) bool {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.failures = 0
+	b.probing = false
+	if state == BreakerOpen {
+		b.until = time.Now().Add(b.Options.CoolDown)
+		b.timer = time.AfterFunc(b.Options.CoolDown, b.halfOpen)
+	}
+	if b.state == state {
+		return false
+	}
+	b.state = state
+	return true
+}
+
+/*
+Calls OnState outside of the mutex, so it may use the breaker
+*/
+func (b *Breaker) notify(changed bool, state BreakerState) {
+	if changed && b.Options.OnState != nil {
+		guard(func() (any, error) {
+			b.Options.OnState(state)
+			return nil, nil
+		})
+	}
+}
+
+/*
+Ends the cool-down, running the probe when configured
+*/
+func (b *Breaker) halfOpen() {
+	b.mutex.Lock()
+	if b.state != BreakerOpen || time.Now().Before(b.until) {
+		b.mutex.Unlock()
+		return
+	}
+	b.timer = nil
+	b.state = BreakerHalfOpen
+	b.probing = b.Options.Probe != nil
+	b.mutex.Unlock()
+
+	b.notify(true, BreakerHalfOpen)
+	if b.Options.Probe != nil {
+		// Runs in the timer goroutine, where a panic would crash the process
+		_, err := guard(func() (any, error) {
+			return nil, b.Options.Probe(b.Unicomm)
+		})
+		b.record(err, true)
+	}
+}
+
+/*
+Returns ErrBreakerOpen when the operation must fail fast. In
+the half-open state, the first operation is let through as the
+probe
+*/
+func (b *Breaker) allow() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		return fmt.Errorf("%w, retrying in %v", ErrBreakerOpen, time.Until(b.until).Round(time.Millisecond))
+	case BreakerHalfOpen:
+		if b.probing {
+			return fmt.Errorf("%w, probing the device", ErrBreakerOpen)
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+/*
+Counts the result of an operation or of a probe
+*/
+func (b *Breaker) record(err error, probe bool) {
+	failed := err != nil && !errors.Is(err, ErrBreakerOpen)
+	if failed && b.Options.IsFailure != nil {
+		failed = b.Options.IsFailure(err)
+	}
+
+	b.mutex.Lock()
+	changed, state := false, b.state
+	switch {
+	case b.state == BreakerHalfOpen && (probe || b.probing):
+		state = BreakerClosed
+		if failed {
+			state = BreakerOpen
+		}
+		changed = b.setState(state)
+	case b.state != BreakerClosed:
+		// Operations started before the breaker opened
+	case !failed:
+		b.failures = 0
+	default:
+		b.failures++
+		if b.failures >= b.Options.Failures {
+			state = BreakerOpen
+			changed = b.setState(state)
+		}
+	}
+	b.mutex.Unlock()
+
+	b.notify(changed, state)
+}
+
+/*
+Runs the operation unless the breaker is open
+*/
+func (b *Breaker) do(operation func() error) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+	err := operation()
+	b.record(err, false)
+	return err
+}
+
+/*
+Establishes the connection unless the breaker is open
+*/
+func (b *Breaker) Connect() error {
+	return b.do(b.Unicomm.Connect)
+}
+
+/*
+Reads a number of bytes unless the breaker is open
+*/
+func (b *Breaker) Read(n uint) ([]byte, error) {
+	var data []byte
+	err := b.do(func() (err error) {
+		data, err = b.Unicomm.Read(n)
+		return err
+	})
+	return data, err
+}
+
+/*
+Reads data until a target delimiter is found unless the
+breaker is open
+*/
+func (b *Breaker) ReadUntil(delimiter string) ([]byte, error) {
+	var data []byte
+	err := b.do(func() (err error) {
+		data, err = ReadUntil(b.Unicomm, delimiter)
+		return err
+	})
+	return data, err
+}
+
+/*
+Writes an array of bytes unless the breaker is open
+*/
+func (b *Breaker) Write(message []byte) error {
+	return b.do(func() error {
+		return b.Unicomm.Write(message)
+	})
+}
+
+/*
+Reads everything currently buffered unless the breaker is open
+*/
+func (b *Breaker) ReadAvailable() ([]byte, error) {
+	var data []byte
+	err := b.do(func() (err error) {
+		data, err = ReadAvailable(b.Unicomm)
+		return err
+	})
+	return data, err
+}
+
+/*
+Writes an array of bytes without the end delimiter unless the
+breaker is open
+*/
+func (b *Breaker) WriteRaw(message []byte) error {
+	return b.do(func() error {
+		return writeRaw(b.Unicomm, message)
+	})
+}
+
+/*
+Returns the wrapped instance
+*/
+func (b *Breaker) Unwrap() Unicomm {
+	return b.Unicomm
+}
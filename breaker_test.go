@@ -0,0 +1,113 @@
+package unicomm_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestBreakerOpensAndProbes(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	tcp := server.TCPOptions()
+	tcp.ReadTimeout = 50 * time.Millisecond
+
+	var mutex sync.Mutex
+	var states []unicomm.BreakerState
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      tcp,
+		Breaker: &unicomm.BreakerOptions{
+			Failures: 2,
+			CoolDown: 100 * time.Millisecond,
+			OnState: func(state unicomm.BreakerState) {
+				mutex.Lock()
+				states = append(states, state)
+				mutex.Unlock()
+			},
+		},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+	breaker, _ := unicomm.As[*unicomm.Breaker](comm)
+
+	// The echo server stays silent until something is written
+	for range 2 {
+		if _, err := unicomm.ReadUntil(comm, "\n"); !unicomm.IsTimeout(err) {
+			t.Fatalf("expected a timeout, got %v", err)
+		}
+	}
+	start := time.Now()
+	if _, err := unicomm.ReadUntil(comm, "\n"); !errors.Is(err, unicomm.ErrBreakerOpen) {
+		t.Fatalf("expected the breaker to be open, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Fatalf("open breaker took %v to fail", elapsed)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if state := breaker.State(); state != unicomm.BreakerHalfOpen {
+		t.Fatalf("got state %v", state)
+	}
+	// The probe succeeds and closes the breaker
+	if err := comm.Write([]byte("ping\n")); err != nil {
+		t.Fatal(err)
+	}
+	unicommtest.ExpectFrame(t, comm, "\n", "ping\n")
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	expected := []unicomm.BreakerState{unicomm.BreakerOpen, unicomm.BreakerHalfOpen, unicomm.BreakerClosed}
+	if len(states) != len(expected) {
+		t.Fatalf("got states %v", states)
+	}
+	for index := range expected {
+		if states[index] != expected[index] {
+			t.Fatalf("got states %v", states)
+		}
+	}
+}
+
+func TestBreakerProbeReopens(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	probes := make(chan struct{}, 4)
+	breaker := unicomm.NewBreaker(unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      server.TCPOptions(),
+	}), unicomm.BreakerOptions{
+		Failures: 1,
+		CoolDown: 30 * time.Millisecond,
+		Probe: func(comm unicomm.Unicomm) error {
+			probes <- struct{}{}
+			return errors.New("still dead")
+		},
+	})
+	if err := breaker.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer breaker.Disconnect()
+
+	breaker.Unwrap().Disconnect()
+	if err := breaker.Write([]byte("x")); err == nil {
+		t.Fatal("expected the write to fail")
+	}
+	for range 2 {
+		select {
+		case <-probes:
+		case <-time.After(time.Second):
+			t.Fatal("probe not run")
+		}
+	}
+	if state := breaker.State(); state != unicomm.BreakerOpen && state != unicomm.BreakerHalfOpen {
+		t.Fatalf("got state %v", state)
+	}
+	breaker.Reset()
+	if state := breaker.State(); state != unicomm.BreakerClosed {
+		t.Fatalf("got state %v", state)
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*
+Transforms data flowing through a bridge, returning nil drops
+the data
+*/
+type Transform func(data []byte) []byte
+
+type BridgeOptions struct {
+	ChunkSize  uint          // Bytes requested on each read
+	AToB       Transform     // Applied to data flowing from A to B
+	BToA       Transform     // Applied to data flowing from B to A
+	RetryDelay time.Duration // Wait after a failed operation
+	OnError    func(error)   // Called for errors other than timeouts
+}
+
+type BridgeStats struct {
+	BytesAToB uint64
+	BytesBToA uint64
+	Errors    uint64
+}
+
+type Bridge struct {
+	Options BridgeOptions
+
+	a, b      Unicomm
+	bytesAToB atomic.Uint64
+	bytesBToA atomic.Uint64
+	errors    atomic.Uint64
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	mutex     sync.Mutex // Protect running state
+}
+
+/*
+Creates a bridge that pumps data in both directions between
+two connected instances, e.g. a serial-to-TCP gateway. Data
+is written as received, the end delimiters of the instances
+are never appended
+*/
+func NewBridge(a, b Unicomm, options BridgeOptions) *Bridge {
+	if options.ChunkSize == 0 {
+		options.ChunkSize = 1024
+	}
+	if options.RetryDelay == 0 {
+		options.RetryDelay = 100 * time.Millisecond
+	}
+	return &Bridge{
+		Options: options,
+		a:       a,
+		b:       b,
+	}
+}
+
+/*
+Starts pumping data in both directions
+*/
+func (br *Bridge) Start() error {
+	br.mutex.Lock()
+	defer br.mutex.Unlock()
+
+	if br.stop != nil {
+		return fmt.Errorf("bridge is already running")
+	}
+	br.stop = make(chan struct{})
+	br.wg.Add(2)
+	go br.pump(br.a, br.b, br.Options.AToB, &br.bytesAToB, br.stop)
+	go br.pump(br.b, br.a, br.Options.BToA, &br.bytesBToA, br.stop)
+	return nil
+}
+
+/*
+Stops pumping data and waits for pending reads to finish.
+The instances are left connected
+*/
+func (br *Bridge) Stop() error {
+	br.mutex.Lock()
+	defer br.mutex.Unlock()
+
+	if br.stop == nil {
+		return fmt.Errorf("bridge is not running")
+	}
+	close(br.stop)
+	br.wg.Wait()
+	br.stop = nil
+	return nil
+}
+
+/*
+Returns the bytes transferred in each direction and the
+number of errors
+*/
+func (br *Bridge) Stats() BridgeStats {
+	return BridgeStats{
+		BytesAToB: br.bytesAToB.Load(),
+		BytesBToA: br.bytesBToA.Load(),
+		Errors:    br.errors.Load(),
+	}
+}
+
+/*
+Reports an error and waits before the next attempt
+*/
+func (br *Bridge) fail(err error) {
+	br.errors.Add(1)
+	report(br.Options.OnError, err)
+	time.Sleep(br.Options.RetryDelay)
+}
+
+/*
+Copies data from source to target until stopped. Data read
+along with an error is forwarded before the error is handled
+*/
+func (br *Bridge) pump(source, target Unicomm, transform Transform, counter *atomic.Uint64, stop chan struct{}) {
+	defer br.wg.Done()
+
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		data, err := guard(func() ([]byte, error) { return source.Read(br.Options.ChunkSize) })
+		if len(data) > 0 {
+			br.forward(target, data, transform, counter)
+		}
+		if err != nil && !isTimeout(err) {
+			br.fail(err)
+		}
+	}
+}
+
+/*
+Transforms and writes data to target as received, without
+the end delimiter of the target
+*/
+func (br *Bridge) forward(target Unicomm, data []byte, transform Transform, counter *atomic.Uint64) {
+	data, err := guard(func() ([]byte, error) {
+		if transform != nil {
+			return transform(data), nil
+		}
+		return data, nil
+	})
+	if err != nil {
+		br.fail(err)
+		return
+	}
+	if len(data) == 0 {
+		return
+	}
+	if _, err := guard(func() ([]byte, error) { return nil, writeRaw(target, data) }); err != nil {
+		br.fail(err)
+		return
+	}
+	counter.Add(uint64(len(data)))
+}
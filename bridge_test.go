@@ -0,0 +1,167 @@
+package unicomm_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+)
+
+/*
+Endpoint returning its scripted reads once, then timing out,
+and recording the writes with and without the end delimiter
+*/
+type scriptedEnd struct {
+	mutex     sync.Mutex
+	reads     [][]byte
+	readErrs  []error
+	delimited []string
+	raw       []string
+}
+
+func (se *scriptedEnd) Connect() error    { return nil }
+func (se *scriptedEnd) Disconnect() error { return nil }
+func (se *scriptedEnd) IsConnected() bool { return true }
+
+func (se *scriptedEnd) Read(size uint) ([]byte, error) {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+
+	if len(se.reads) == 0 {
+		time.Sleep(time.Millisecond)
+		return []byte{}, nil
+	}
+	data, err := se.reads[0], se.readErrs[0]
+	se.reads, se.readErrs = se.reads[1:], se.readErrs[1:]
+	return data, err
+}
+
+func (se *scriptedEnd) Write(message []byte) error {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+
+	se.delimited = append(se.delimited, string(message))
+	return nil
+}
+
+func (se *scriptedEnd) WriteRaw(message []byte) error {
+	se.mutex.Lock()
+	defer se.mutex.Unlock()
+
+	se.raw = append(se.raw, string(message))
+	return nil
+}
+
+/*
+Reads the bytes written to the far end of a pipe and fails the
+test if they differ from the expected ones or more bytes follow
+*/
+func expectPipe(t *testing.T, conn net.Conn, expected string) {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	data := make([]byte, len(expected))
+	if _, err := io.ReadFull(conn, data); err != nil {
+		t.Fatalf("reading %q: %v", expected, err)
+	}
+	if string(data) != expected {
+		t.Fatalf("got %q, want %q", data, expected)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	if n, _ := conn.Read(data); n > 0 {
+		t.Fatalf("got %q after %q", data[:n], expected)
+	}
+}
+
+func TestBridgeDirections(t *testing.T) {
+	aLocal, aRemote := net.Pipe()
+	bLocal, bRemote := net.Pipe()
+	// Bytes are forwarded as received, never followed by the delimiter
+	options := unicommserial.SerialOptions{PortName: "pipe", ReadTimeout: 20 * time.Millisecond, EndDelimiter: "\r\n"}
+	a := unicommserial.NewSerialFromStream(aLocal, options)
+	b := unicommserial.NewSerialFromStream(bLocal, options)
+	defer a.Disconnect()
+	defer b.Disconnect()
+
+	bridge := unicomm.NewBridge(a, b, unicomm.BridgeOptions{
+		AToB: bytes.ToUpper,
+		BToA: func(data []byte) []byte {
+			if string(data) == "NOISE" {
+				return nil
+			}
+			return data
+		},
+	})
+	if err := bridge.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer bridge.Stop()
+	// Unblocks the writes nobody reads when the test fails
+	defer aRemote.Close()
+	defer bRemote.Close()
+
+	if _, err := aRemote.Write([]byte("temp?")); err != nil {
+		t.Fatal(err)
+	}
+	expectPipe(t, bRemote, "TEMP?")
+
+	// Data dropped by the transform is neither written nor counted
+	for _, chunk := range []string{"NOISE", "21.5"} {
+		if _, err := bRemote.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectPipe(t, aRemote, "21.5")
+
+	if err := bridge.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	stats := bridge.Stats()
+	if stats.BytesAToB != 5 || stats.BytesBToA != 4 || stats.Errors != 0 {
+		t.Fatalf("stats = %+v, want 5 bytes A to B and 4 bytes B to A", stats)
+	}
+}
+
+func TestBridgeForwardsBeforeError(t *testing.T) {
+	lost := errors.New("link lost")
+	source := &scriptedEnd{reads: [][]byte{[]byte("TAIL")}, readErrs: []error{lost}}
+	target := &scriptedEnd{}
+
+	reported := make(chan error, 1)
+	bridge := unicomm.NewBridge(source, target, unicomm.BridgeOptions{
+		RetryDelay: time.Millisecond,
+		OnError:    func(err error) { reported <- err },
+	})
+	if err := bridge.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The bytes read along with the error still reach the target
+	select {
+	case err := <-reported:
+		if !errors.Is(err, lost) {
+			t.Fatalf("reported %v, want %v", err, lost)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("the error was not reported")
+	}
+	if err := bridge.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	target.mutex.Lock()
+	defer target.mutex.Unlock()
+	if len(target.raw) != 1 || target.raw[0] != "TAIL" || len(target.delimited) != 0 {
+		t.Fatalf("target got raw %q and delimited %q, want only the raw data", target.raw, target.delimited)
+	}
+	if stats := bridge.Stats(); stats.BytesAToB != 4 || stats.Errors != 1 {
+		t.Fatalf("stats = %+v, want 4 bytes forwarded and 1 error", stats)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type Broadcast struct {
+	Targets []Unicomm
+}
+
+type BroadcastError struct {
+	Errors []error // Error of each target, nil when it succeeded
+}
+
+/*
+Creates a fan-out writer that sends the same payload to
+all the target instances
+*/
+func NewBroadcast(targets ...Unicomm) *Broadcast {
+	return &Broadcast{
+		Targets: targets,
+	}
+}
+
+/*
+Describes the targets that failed
+*/
+func (be *BroadcastError) Error() string {
+	failures := make([]string, 0, len(be.Errors))
+	for index, err := range be.Errors {
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("target %d: %v", index, err))
+		}
+	}
+	return fmt.Sprintf(
+		"broadcast failed for %d of %d targets (%s)",
+		len(failures), len(be.Errors), strings.Join(failures, "; "),
+	)
+}
+
+/*
+Returns the errors of the failed targets
+*/
+func (be *BroadcastError) Unwrap() []error {
+	failures := make([]error, 0, len(be.Errors))
+	for _, err := range be.Errors {
+		if err != nil {
+			failures = append(failures, err)
+		}
+	}
+	return failures
+}
+
+/*
+Runs the operation on every target concurrently, returning a
+BroadcastError when at least one of them fails
+*/
+func (b *Broadcast) forEach(operation func(target Unicomm) error) error {
+	var wg sync.WaitGroup
+	results := make([]error, len(b.Targets))
+
+	for index, target := range b.Targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[index] = operation(target)
+		}()
+	}
+	wg.Wait()
+
+	if errors.Join(results...) == nil {
+		return nil
+	}
+	return &BroadcastError{Errors: results}
+}
+
+/*
+Connects all the targets
+*/
+func (b *Broadcast) Connect() error {
+	return b.forEach(func(target Unicomm) error {
+		return target.Connect()
+	})
+}
+
+/*
+Disconnects all the targets
+*/
+func (b *Broadcast) Disconnect() error {
+	return b.forEach(func(target Unicomm) error {
+		return target.Disconnect()
+	})
+}
+
+/*
+Writes the same message to all the targets concurrently
+*/
+func (b *Broadcast) Write(message []byte) error {
+	return b.forEach(func(target Unicomm) error {
+		// Each target gets its own copy, since backends may append
+		// the end delimiter to the message
+		return target.Write(bytes.Clone(message))
+	})
+}
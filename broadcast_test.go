@@ -0,0 +1,64 @@
+package unicomm_test
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+)
+
+/*
+Path that terminates the message it writes in place, as the
+backends appending the end delimiter may do
+*/
+type terminatingPath struct {
+	failingPath
+}
+
+func (tp *terminatingPath) Write(message []byte) error {
+	if len(message) > 0 {
+		message[len(message)-1] = '\n'
+	}
+	return tp.failingPath.Write(message)
+}
+
+func TestBroadcastWrite(t *testing.T) {
+	first, second, third := &failingPath{}, &terminatingPath{}, &failingPath{}
+	broadcast := unicomm.NewBroadcast(first, second, third)
+	if err := broadcast.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every target gets the message untouched by the others
+	if err := broadcast.Write([]byte("START;")); err != nil {
+		t.Fatal(err)
+	}
+	for index, written := range [][]string{first.written, second.written, third.written} {
+		want := "START;"
+		if index == 1 {
+			want = "START\n"
+		}
+		if len(written) != 1 || written[0] != want {
+			t.Fatalf("target %d wrote %q, want %q", index, written, want)
+		}
+	}
+
+	// Failures are reported per target, the others still write
+	third.writeErr = syscall.EPIPE
+	err := broadcast.Write([]byte("STOP;"))
+	var broadcastErr *unicomm.BroadcastError
+	if !errors.As(err, &broadcastErr) {
+		t.Fatalf("Write = %v, want a broadcast error", err)
+	}
+	if broadcastErr.Errors[0] != nil || broadcastErr.Errors[1] != nil || broadcastErr.Errors[2] != syscall.EPIPE {
+		t.Fatalf("errors = %v", broadcastErr.Errors)
+	}
+	if !errors.Is(err, syscall.EPIPE) || !strings.Contains(err.Error(), "1 of 3 targets (target 2:") {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(first.written) != 2 || len(second.written) != 2 {
+		t.Fatal("a failed target kept the others from writing")
+	}
+}
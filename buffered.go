@@ -0,0 +1,255 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+var ErrMaximumReached = errors.New("maximum size reached before the delimiter")
+
+type Buffered struct {
+	Unicomm
+
+	pending []byte
+	mutex   sync.Mutex // Protect pending bytes
+}
+
+/*
+Creates a buffered receive path on top of a Unicomm instance,
+allowing incoming bytes to be examined without consuming them
+*/
+func NewBuffered(comm Unicomm) *Buffered {
+	return &Buffered{
+		Unicomm: comm,
+	}
+}
+
+/*
+Removes and returns the first n pending bytes
+*/
+func (b *Buffered) take(n int) []byte {
+	data := make([]byte, n)
+	copy(data, b.pending[:n])
+	b.pending = b.pending[n:]
+	return data
+}
+
+/*
+Returns the next n bytes without consuming them. When fewer
+bytes arrive before the read timeout, the available bytes are
+returned along with an error
+*/
+func (b *Buffered) Peek(n uint) ([]byte, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for uint(len(b.pending)) < n {
+		data, err := b.Unicomm.Read(n - uint(len(b.pending)))
+		b.pending = append(b.pending, data...)
+		if err != nil {
+			return bytes.Clone(b.pending), err
+		}
+		if len(data) == 0 {
+			return bytes.Clone(b.pending), unicommio.TimeoutError("peek")
+		}
+	}
+	return bytes.Clone(b.pending[:n]), nil
+}
+
+/*
+Pushes data back to the receive path, so it is returned by
+the next read before any byte coming from the device
+*/
+func (b *Buffered) Unread(data []byte) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.pending = append(bytes.Clone(data), b.pending...)
+}
+
+/*
+Appends data received elsewhere to the receive path, so it is
+returned after the bytes already pending
+*/
+func (b *Buffered) push(data []byte) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.pending = append(b.pending, data...)
+}
+
+/*
+Returns the number of bytes waiting in the receive path
+*/
+func (b *Buffered) Buffered() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return len(b.pending)
+}
+
+/*
+Reads up to n bytes, serving pending bytes first
+*/
+func (b *Buffered) Read(n uint) ([]byte, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if len(b.pending) > 0 {
+		return b.take(min(int(n), len(b.pending))), nil
+	}
+	return b.Unicomm.Read(n)
+}
+
+/*
+Reads data until a target delimiter is found, serving
+pending bytes first
+*/
+func (b *Buffered) ReadUntil(delimiter string) ([]byte, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for len(b.pending) > 0 {
+		if index := bytes.Index(b.pending, []byte(delimiter)); index >= 0 {
+			return b.take(index + len(delimiter)), nil
+		}
+		if !endsWithPartial(b.pending, delimiter) {
+			break
+		}
+
+		// The delimiter may be split between pending and incoming bytes
+		data, err := b.Unicomm.Read(1)
+		b.pending = append(b.pending, data...)
+		if err != nil {
+			return b.take(len(b.pending)), err
+		}
+		if len(data) == 0 {
+			return b.take(len(b.pending)), unicommio.TimeoutError("read until")
+		}
+	}
+
+	data, err := ReadUntil(b.Unicomm, delimiter)
+	return append(b.take(len(b.pending)), data...), err
+}
+
+/*
+Reads until the delimiter is found with at least minimum bytes
+read, so delimiters inside a fixed-size header are skipped. When
+maximum bytes are read first, they are returned along with
+ErrMaximumReached. Zero maximum reads without limit
+*/
+func (b *Buffered) ReadUntilFull(delimiter string, minimum uint, maximum uint) ([]byte, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for {
+		if end, err := fullFrameEnd(b.pending, delimiter, minimum, maximum); end > 0 {
+			return b.take(end), err
+		}
+
+		size := uint(unicommio.ReadChunkSize)
+		if maximum > 0 {
+			size = min(size, maximum-uint(len(b.pending)))
+		}
+		data, err := b.Unicomm.Read(size)
+		b.pending = append(b.pending, data...)
+		if err != nil {
+			return b.take(len(b.pending)), err
+		}
+		if len(data) == 0 {
+			return b.take(len(b.pending)), unicommio.TimeoutError("read until")
+		}
+	}
+}
+
+/*
+Returns where the frame read by ReadUntilFull ends in the
+buffer, or zero when more bytes are needed
+*/
+func fullFrameEnd(buffer []byte, delimiter string, minimum uint, maximum uint) (int, error) {
+	// Only delimiters ending at or after the minimum count
+	start := max(int(minimum)-len(delimiter), 0)
+	if start <= len(buffer) {
+		if index := bytes.Index(buffer[start:], []byte(delimiter)); index >= 0 {
+			end := start + index + len(delimiter)
+			if maximum == 0 || end <= int(maximum) {
+				return end, nil
+			}
+		}
+	}
+	if maximum > 0 && len(buffer) >= int(maximum) {
+		return int(maximum), ErrMaximumReached
+	}
+	return 0, nil
+}
+
+/*
+Returns true if the buffer ends with a proper prefix of
+the delimiter
+*/
+func endsWithPartial(buffer []byte, delimiter string) bool {
+	for size := min(len(delimiter)-1, len(buffer)); size > 0; size-- {
+		if strings.HasPrefix(delimiter, string(buffer[len(buffer)-size:])) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Reads at least minimum and at most maximum bytes, serving
+pending bytes first
+*/
+func (b *Buffered) ReadAtLeast(minimum uint, maximum uint) ([]byte, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	buffer := b.take(min(int(maximum), len(b.pending)))
+	if uint(len(buffer)) >= minimum {
+		return buffer, nil
+	}
+	data, err := ReadAtLeast(b.Unicomm, minimum-uint(len(buffer)), maximum-uint(len(buffer)))
+	return append(buffer, data...), err
+}
+
+/*
+Returns the pending bytes along with everything buffered by
+the underlying instance, without waiting for a timeout
+*/
+func (b *Buffered) ReadAvailable() ([]byte, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	data, err := ReadAvailable(b.Unicomm)
+	return append(b.take(len(b.pending)), data...), err
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (b *Buffered) WriteRaw(message []byte) error {
+	return writeRaw(b.Unicomm, message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (b *Buffered) Unwrap() Unicomm {
+	return b.Unicomm
+}
+
+/*
+Stops the lookup of data capabilities, which would bypass the
+buffered data
+*/
+func (b *Buffered) transformsData() {}
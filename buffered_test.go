@@ -0,0 +1,118 @@
+package unicomm_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+/*
+Starts a local TCP server that sends the payload to the
+first client and keeps the connection open
+*/
+func serveOnce(t *testing.T, payload []byte) uint {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		conn.Write(payload)
+		time.Sleep(time.Second)
+		conn.Close()
+	}()
+	return uint(listener.Addr().(*net.TCPAddr).Port)
+}
+
+/*
+Serves a single connection that sends the chunks with the delay
+between them, as a slow link splitting a frame
+*/
+func serveChunks(t *testing.T, delay time.Duration, chunks ...[]byte) uint {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for index, chunk := range chunks {
+			if index > 0 {
+				time.Sleep(delay)
+			}
+			conn.Write(chunk)
+		}
+		time.Sleep(time.Second)
+	}()
+	return uint(listener.Addr().(*net.TCPAddr).Port)
+}
+
+func TestBufferedPeekUnread(t *testing.T) {
+	port := serveOnce(t, []byte("MB:hello\r\nworld\r\n"))
+	comm := unicomm.NewBuffered(unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+	}))
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	header, err := comm.Peek(3)
+	if err != nil || string(header) != "MB:" {
+		t.Fatalf("peek returned %q, %v", header, err)
+	}
+
+	line, err := comm.ReadUntil("\r\n")
+	if err != nil || string(line) != "MB:hello\r\n" {
+		t.Fatalf("read until returned %q, %v", line, err)
+	}
+
+	comm.Unread([]byte("hello "))
+	line, err = comm.ReadUntil("\r\n")
+	if err != nil || string(line) != "hello world\r\n" {
+		t.Fatalf("read until after unread returned %q, %v", line, err)
+	}
+}
+
+func TestReadUntilFull(t *testing.T) {
+	// The 4-byte headers contain the delimiter before the body
+	port := serveOnce(t, []byte("\x01\n\x00\x02body\n\x02\n\x00\x00toolongbody\n"))
+	for _, buffered := range []bool{true, false} {
+		var comm unicomm.Unicomm = unicomm.New(unicomm.Options{
+			Protocol: unicomm.TCP,
+			TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+		})
+		if buffered {
+			comm = unicomm.NewBuffered(comm)
+		}
+		if err := comm.Connect(); err != nil {
+			t.Fatal(err)
+		}
+
+		frame, err := unicomm.ReadUntilFull(comm, "\n", 5, 12)
+		if err != nil || string(frame) != "\x01\n\x00\x02body\n" {
+			t.Fatalf("read until full returned %q, %v", frame, err)
+		}
+		frame, err = unicomm.ReadUntilFull(comm, "\n", 5, 12)
+		if !errors.Is(err, unicomm.ErrMaximumReached) || string(frame) != "\x02\n\x00\x00toolongb" {
+			t.Fatalf("read until full returned %q, %v", frame, err)
+		}
+		comm.Disconnect()
+		port = serveOnce(t, []byte("\x01\n\x00\x02body\n\x02\n\x00\x00toolongbody\n"))
+	}
+}
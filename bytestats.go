@@ -0,0 +1,231 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+type ByteStatsOptions struct {
+	Window  time.Duration // Period of each histogram, defaults to 1 minute
+	History int           // Histograms kept besides the current one, defaults to 60
+}
+
+/*
+Received byte values and framing errors over a period. A frame
+counts as a framing error when its delimiter did not arrive
+before the read timeout, or when reported by FramingError
+*/
+type ByteHistogram struct {
+	Start         time.Time
+	Counts        [256]uint64
+	Bytes         uint64
+	Frames        uint64 // Frames read until their delimiter
+	FramingErrors uint64
+}
+
+/*
+Wrapper collecting a histogram of the received byte values and
+the rate of framing errors over time, to tell a baud mismatch,
+which spreads garbage across all the values, from noise on the
+wiring, which arrives as bursts of 0x00 and 0xFF
+*/
+type ByteStats struct {
+	Unicomm
+	Options ByteStatsOptions
+
+	total   ByteHistogram
+	history []ByteHistogram // Closed histograms, oldest first
+	current ByteHistogram
+	mutex   sync.Mutex // Protect the histograms
+}
+
+/*
+Returns the fraction of the frames that were framing errors
+*/
+func (bh ByteHistogram) FramingErrorRate() float64 {
+	if bh.Frames+bh.FramingErrors == 0 {
+		return 0
+	}
+	return float64(bh.FramingErrors) / float64(bh.Frames+bh.FramingErrors)
+}
+
+/*
+Returns how many distinct byte values were received
+*/
+func (bh ByteHistogram) Distinct() int {
+	distinct := 0
+	for _, count := range bh.Counts {
+		if count > 0 {
+			distinct++
+		}
+	}
+	return distinct
+}
+
+/*
+Returns the fraction of the received bytes holding one of the
+values, e.g. Share(0x00, 0xFF) for line noise
+*/
+func (bh ByteHistogram) Share(values ...byte) float64 {
+	if bh.Bytes == 0 {
+		return 0
+	}
+	var count uint64
+	for _, value := range values {
+		count += bh.Counts[value]
+	}
+	return float64(count) / float64(bh.Bytes)
+}
+
+/*
+Creates a wrapper collecting the statistics of the bytes received
+by the instance
+*/
+func NewByteStats(comm Unicomm, options ByteStatsOptions) *ByteStats {
+	if options.Window == 0 {
+		options.Window = time.Minute
+	}
+	if options.History == 0 {
+		options.History = 60
+	}
+	now := time.Now()
+	return &ByteStats{
+		Unicomm: comm,
+		Options: options,
+		total:   ByteHistogram{Start: now},
+		current: ByteHistogram{Start: now},
+	}
+}
+
+/*
+Returns the histogram since the creation or the last reset
+*/
+func (bs *ByteStats) Total() ByteHistogram {
+	bs.mutex.Lock()
+	defer bs.mutex.Unlock()
+
+	return bs.total
+}
+
+/*
+Returns the histogram of each window, oldest first, the last one
+being the current window
+*/
+func (bs *ByteStats) Windows() []ByteHistogram {
+	bs.mutex.Lock()
+	defer bs.mutex.Unlock()
+
+	bs.rotate(time.Now())
+	return append(slices.Clone(bs.history), bs.current)
+}
+
+/*
+Clears the histograms
+*/
+func (bs *ByteStats) Reset() {
+	bs.mutex.Lock()
+	defer bs.mutex.Unlock()
+
+	now := time.Now()
+	bs.total = ByteHistogram{Start: now}
+	bs.current = ByteHistogram{Start: now}
+	bs.history = nil
+}
+
+/*
+Records a framing error detected above the wrapper, such as a
+checksum mismatch or a frame the codec could not decode
+*/
+func (bs *ByteStats) FramingError() {
+	bs.record(nil, 0, 1)
+}
+
+/*
+Closes the current window once its period is over, must be
+called with the mutex locked
+*/
+func (bs *ByteStats) rotate(now time.Time) {
+	if now.Sub(bs.current.Start) < bs.Options.Window {
+		return
+	}
+	bs.history = append(bs.history, bs.current)
+	if len(bs.history) > bs.Options.History {
+		bs.history = slices.Delete(bs.history, 0, len(bs.history)-bs.Options.History)
+	}
+	bs.current = ByteHistogram{Start: now.Truncate(bs.Options.Window)}
+}
+
+/*
+Adds the received bytes and frames to the total and the current
+window
+*/
+func (bs *ByteStats) record(data []byte, frames uint64, framingErrors uint64) {
+	bs.mutex.Lock()
+	defer bs.mutex.Unlock()
+
+	bs.rotate(time.Now())
+	for _, histogram := range []*ByteHistogram{&bs.total, &bs.current} {
+		for _, value := range data {
+			histogram.Counts[value]++
+		}
+		histogram.Bytes += uint64(len(data))
+		histogram.Frames += frames
+		histogram.FramingErrors += framingErrors
+	}
+}
+
+/*
+Reads a number of bytes
+*/
+func (bs *ByteStats) Read(n uint) ([]byte, error) {
+	data, err := bs.Unicomm.Read(n)
+	bs.record(data, 0, 0)
+	return data, err
+}
+
+/*
+Reads data until a target delimiter is found, counting the frame
+as a framing error when the delimiter did not arrive in time
+*/
+func (bs *ByteStats) ReadUntil(delimiter string) ([]byte, error) {
+	data, err := ReadUntil(bs.Unicomm, delimiter)
+	switch {
+	case err == nil:
+		bs.record(data, 1, 0)
+	case isTimeout(err) && len(data) > 0:
+		bs.record(data, 0, 1)
+	default:
+		bs.record(data, 0, 0)
+	}
+	return data, err
+}
+
+/*
+Reads everything currently buffered by the instance
+*/
+func (bs *ByteStats) ReadAvailable() ([]byte, error) {
+	data, err := ReadAvailable(bs.Unicomm)
+	bs.record(data, 0, 0)
+	return data, err
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (bs *ByteStats) WriteRaw(message []byte) error {
+	return writeRaw(bs.Unicomm, message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (bs *ByteStats) Unwrap() Unicomm {
+	return bs.Unicomm
+}
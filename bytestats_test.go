@@ -0,0 +1,52 @@
+package unicomm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestByteStatsHistogram(t *testing.T) {
+	port := serveOnce(t, []byte("OK\nOK\n\x00\xff\x00\x00"))
+	comm := unicomm.New(unicomm.Options{
+		Protocol:  unicomm.TCP,
+		TCP:       unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port, ReadTimeout: 50 * time.Millisecond},
+		ByteStats: &unicomm.ByteStatsOptions{Window: time.Hour},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	for range 2 {
+		unicommtest.ExpectFrame(t, comm, "\n", "OK\n")
+	}
+	if _, err := unicomm.ReadUntil(comm, "\n"); !unicomm.IsTimeout(err) {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+
+	stats, ok := unicomm.As[*unicomm.ByteStats](comm)
+	if !ok {
+		t.Fatal("byte statistics are not enabled")
+	}
+	stats.FramingError()
+	total := stats.Total()
+	if total.Bytes != 10 || total.Frames != 2 || total.FramingErrors != 2 {
+		t.Fatalf("unexpected histogram %+v", total)
+	}
+	if total.Counts['O'] != 2 || total.Counts[0x00] != 3 || total.Distinct() != 5 {
+		t.Fatalf("unexpected counts %v", total.Counts)
+	}
+	if share := total.Share(0x00, 0xFF); share != 0.4 {
+		t.Fatalf("got share %v", share)
+	}
+	if rate := total.FramingErrorRate(); rate != 0.5 {
+		t.Fatalf("got framing error rate %v", rate)
+	}
+	if windows := stats.Windows(); len(windows) != 1 || windows[0].Bytes != 10 {
+		t.Fatalf("unexpected windows %+v", windows)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	response []byte
+	expires  time.Time
+}
+
+type ResponseCache struct {
+	ttls    map[string]time.Duration // Cacheable commands and their TTL
+	entries map[string]cacheEntry
+	mutex   sync.Mutex // Protect entries
+}
+
+/*
+Creates a cache for responses of idempotent queries, such as
+identity and firmware version. Only registered commands are
+cached
+*/
+func NewResponseCache() *ResponseCache {
+	return &ResponseCache{
+		ttls:    make(map[string]time.Duration),
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+/*
+Marks a command as cacheable for the given time
+*/
+func (rc *ResponseCache) Register(command string, ttl time.Duration) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	rc.ttls[command] = ttl
+}
+
+/*
+Removes the cached response of a command
+*/
+func (rc *ResponseCache) Invalidate(command string) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	delete(rc.entries, command)
+}
+
+/*
+Removes all cached responses
+*/
+func (rc *ResponseCache) InvalidateAll() {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	clear(rc.entries)
+}
+
+/*
+Returns a copy of the cached response, if it did not expire
+*/
+func (rc *ResponseCache) lookup(command string) ([]byte, bool) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	entry, ok := rc.entries[command]
+	if !ok || time.Now().After(entry.expires) {
+		delete(rc.entries, command)
+		return nil, false
+	}
+	return bytes.Clone(entry.response), true
+}
+
+/*
+Stores the response if the command is cacheable
+*/
+func (rc *ResponseCache) store(command string, response []byte) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	if ttl, ok := rc.ttls[command]; ok {
+		rc.entries[command] = cacheEntry{
+			response: bytes.Clone(response),
+			expires:  time.Now().Add(ttl),
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package unicomm_test
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestResponseCache(t *testing.T) {
+	// Every response carries the number of requests received
+	var requests atomic.Int64
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte {
+			count := requests.Add(1)
+			return []byte(strings.TrimSpace(string(request)) + " " + strconv.FormatInt(count, 10) + "\n")
+		},
+	})
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions()})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	client := unicomm.NewClient(comm, "\n")
+	client.Cache = unicomm.NewResponseCache()
+	client.Cache.Register("*IDN?\n", 200*time.Millisecond)
+	query := func(command string) string {
+		t.Helper()
+		response, err := client.Query([]byte(command))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(response)
+	}
+
+	if first := query("*IDN?\n"); first != "*IDN? 1\n" || query("*IDN?\n") != first {
+		t.Fatalf("registered query was not cached, got %q", first)
+	}
+	if query("MEAS?\n") != "MEAS? 2\n" || query("MEAS?\n") != "MEAS? 3\n" {
+		t.Fatal("unregistered query was cached")
+	}
+
+	// Callers get copies, so changing a response keeps the cache
+	response, _ := client.Query([]byte("*IDN?\n"))
+	response[0] = 'X'
+	if query("*IDN?\n") != "*IDN? 1\n" {
+		t.Fatal("cached response changed through a returned slice")
+	}
+
+	// A fresh query refreshes the cache, invalidation drops it
+	if fresh, _ := client.QueryFresh([]byte("*IDN?\n")); string(fresh) != "*IDN? 4\n" || query("*IDN?\n") != "*IDN? 4\n" {
+		t.Fatalf("fresh query did not refresh the cache, got %q", fresh)
+	}
+	client.Cache.Invalidate("*IDN?\n")
+	if query("*IDN?\n") != "*IDN? 5\n" {
+		t.Fatal("invalidated response was returned")
+	}
+
+	// Responses expire after their TTL
+	time.Sleep(250 * time.Millisecond)
+	if query("*IDN?\n") != "*IDN? 6\n" {
+		t.Fatal("expired response was returned")
+	}
+}
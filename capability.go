@@ -0,0 +1,205 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommbroker"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+/*
+Returned when a write transmits only part of the message
+*/
+type PartialWriteError = unicommio.PartialWriteError
+
+/*
+Tells transient errors, retried by the backends within the
+operation deadline, from those requiring a reconnect
+*/
+type ErrorClass = unicommio.ErrorClass
+
+const (
+	UnknownError   = unicommio.UnknownError
+	TemporaryError = unicommio.TemporaryError
+	FatalError     = unicommio.FatalError
+)
+
+/*
+Classifies an error returned by a Unicomm instance
+*/
+func Classify(err error) ErrorClass {
+	return unicommio.Classify(err)
+}
+
+/*
+Matched by the errors of operations that did not complete
+before their timeout, which still return the bytes received
+*/
+var ErrTimeout = unicommio.ErrTimeout
+
+/*
+Returns true if the error is a timeout
+*/
+func IsTimeout(err error) bool {
+	return unicommio.IsTimeout(err)
+}
+
+var (
+	_ Framer          = (*unicommserial.UnicommSerial)(nil)
+	_ Framer          = (*unicommtcp.UnicommTCP)(nil)
+	_ Framer          = (*unicommbroker.UnicommBroker)(nil)
+	_ ControlLines    = (*unicommserial.UnicommSerial)(nil)
+	_ BreakSender     = (*unicommserial.UnicommSerial)(nil)
+	_ AvailableReader = (*unicommserial.UnicommSerial)(nil)
+	_ AvailableReader = (*unicommtcp.UnicommTCP)(nil)
+	_ AvailableReader = (*unicommbroker.UnicommBroker)(nil)
+	_ IntoReader      = (*unicommserial.UnicommSerial)(nil)
+	_ IntoReader      = (*unicommtcp.UnicommTCP)(nil)
+	_ AtLeastReader   = (*unicommserial.UnicommSerial)(nil)
+	_ AtLeastReader   = (*unicommtcp.UnicommTCP)(nil)
+	_ VectoredReader  = (*unicommtcp.UnicommTCP)(nil)
+	_ LinkProber      = (*unicommserial.UnicommSerial)(nil)
+	_ LinkProber      = (*unicommtcp.UnicommTCP)(nil)
+	_ DelimitedWriter = (*unicommserial.UnicommSerial)(nil)
+	_ DelimitedWriter = (*unicommtcp.UnicommTCP)(nil)
+)
+
+/*
+Manages the connection with the device
+*/
+type Connector interface {
+	Connect() error
+	Disconnect() error
+	IsConnected() bool
+}
+
+/*
+Reads raw bytes from the device
+*/
+type Reader interface {
+	Read(size uint) ([]byte, error)
+}
+
+/*
+Writes messages to the device
+*/
+type Writer interface {
+	Write(message []byte) error
+}
+
+/*
+Reads delimited frames from the device. Optional, since backends
+of datagrams or fixed transfers have no delimiter to look for
+*/
+type Framer interface {
+	ReadUntil(delimiter string) ([]byte, error)
+}
+
+/*
+Reads until the delimiter is found. Instances without ReadUntil
+report that it is not supported
+*/
+func ReadUntil(comm Unicomm, delimiter string) ([]byte, error) {
+	if framer, ok := asData[Framer](comm); ok {
+		return framer.ReadUntil(delimiter)
+	}
+	return nil, fmt.Errorf("read until is not supported")
+}
+
+/*
+Drives the modem control lines of serial ports
+*/
+type ControlLines interface {
+	SetDTR(level bool) error
+	SetRTS(level bool) error
+}
+
+/*
+Sends break conditions on serial lines
+*/
+type BreakSender interface {
+	SendBreak(duration time.Duration) error
+}
+
+/*
+Implemented by wrappers to expose the instance they wrap
+*/
+type Wrapper interface {
+	Unwrap() Unicomm
+}
+
+/*
+Finds the first instance in the wrapping chain that provides
+the capability T, e.g. As[ControlLines](comm)
+*/
+func As[T any](comm Unicomm) (T, bool) {
+	for comm != nil {
+		if capability, ok := comm.(T); ok {
+			return capability, true
+		}
+		wrapper, ok := comm.(Wrapper)
+		if !ok {
+			break
+		}
+		comm = wrapper.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
+
+/*
+Implemented by wrappers that buffer or transform the received
+data, e.g. framing or decryption. Lookups of data capabilities
+stop at them, since the wrapped instance would return the data
+before the transformation
+*/
+type dataWrapper interface {
+	Wrapper
+	transformsData()
+}
+
+/*
+Finds the first instance in the wrapping chain that provides
+the data capability T, stopping at wrappers that buffer or
+transform the data
+*/
+func asData[T any](comm Unicomm) (T, bool) {
+	for comm != nil {
+		if capability, ok := comm.(T); ok {
+			return capability, true
+		}
+		if _, ok := comm.(dataWrapper); ok {
+			break
+		}
+		wrapper, ok := comm.(Wrapper)
+		if !ok {
+			break
+		}
+		comm = wrapper.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
+
+/*
+Returns the instance at the end of the wrapping chain, usually
+a backend
+*/
+func innermost(comm Unicomm) Unicomm {
+	for {
+		wrapper, ok := comm.(Wrapper)
+		if !ok || wrapper.Unwrap() == nil {
+			return comm
+		}
+		comm = wrapper.Unwrap()
+	}
+}
@@ -0,0 +1,64 @@
+package unicomm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommframe"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+/*
+Backend of fixed-size transfers, without ReadUntil
+*/
+type transferDevice struct{}
+
+func (transferDevice) Connect() error                 { return nil }
+func (transferDevice) Disconnect() error              { return nil }
+func (transferDevice) IsConnected() bool              { return true }
+func (transferDevice) Read(size uint) ([]byte, error) { return make([]byte, size), nil }
+func (transferDevice) Write(message []byte) error     { return nil }
+
+func TestReadUntilOptional(t *testing.T) {
+	for name, comm := range map[string]unicomm.Unicomm{
+		"Backend": transferDevice{},
+		"Wrapped": unicomm.NewClient(transferDevice{}, "\n"),
+	} {
+		if _, err := unicomm.ReadUntil(comm, "\n"); err == nil {
+			t.Fatalf("%s: expected read until to be not supported", name)
+		}
+	}
+
+	// Wrappers without ReadUntil reach the backend
+	port := serveOnce(t, []byte("line\n"))
+	tcp := unicommtcp.NewTCP(unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port})
+	if err := tcp.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer tcp.Disconnect()
+	line, err := unicomm.ReadUntil(unicomm.NewClient(tcp, "\n"), "\n")
+	if err != nil || string(line) != "line\n" {
+		t.Fatalf("read until returned %q, %v", line, err)
+	}
+}
+
+func TestReadAvailableStopsAtFraming(t *testing.T) {
+	port := serveOnce(t, []byte("\x00\x02hi"))
+	tcp := unicommtcp.NewTCP(unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port})
+	if err := tcp.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer tcp.Disconnect()
+	time.Sleep(50 * time.Millisecond)
+
+	// The encoded bytes of the backend must not bypass the codec
+	framed := unicomm.NewFramed(tcp, unicommframe.LengthPrefix{Size: 2})
+	if data, err := unicomm.ReadAvailable(framed); err == nil {
+		t.Fatalf("read available bypassed the codec, got %q", data)
+	}
+	data, err := unicomm.ReadAvailable(unicomm.NewClient(tcp, "\n"))
+	if err != nil || string(data) != "\x00\x02hi" {
+		t.Fatalf("read available returned %q, %v", data, err)
+	}
+}
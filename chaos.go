@@ -0,0 +1,172 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+type ChaosOptions struct {
+	TimeoutRate      float64 // Probability of a simulated timeout
+	PartialWriteRate float64 // Probability of sending only part of a message
+	CorruptRate      float64 // Probability of flipping a bit of the data
+	DisconnectRate   float64 // Probability of dropping the connection
+	Seed             uint64  // Seed of the random generator
+}
+
+type Chaos struct {
+	Unicomm
+	Options ChaosOptions
+
+	random *rand.Rand
+	mutex  sync.Mutex // Protect random generator
+}
+
+/*
+Creates a testing wrapper that randomly injects failures into
+the operations of a Unicomm instance, to validate the
+reconnect and retry logic of drivers
+*/
+func NewChaos(comm Unicomm, options ChaosOptions) *Chaos {
+	return &Chaos{
+		Unicomm: comm,
+		Options: options,
+		random:  rand.New(rand.NewPCG(options.Seed, options.Seed)),
+	}
+}
+
+/*
+Returns true with the given probability
+*/
+func (c *Chaos) chance(rate float64) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return rate > 0 && c.random.Float64() < rate
+}
+
+/*
+Returns a copy of the data with one random bit flipped
+*/
+func (c *Chaos) corrupt(data []byte) []byte {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	data = bytes.Clone(data)
+	if len(data) > 0 {
+		data[c.random.IntN(len(data))] ^= 1 << c.random.IntN(8)
+	}
+	return data
+}
+
+/*
+Returns how many bytes of a message of the given size are
+sent on a partial write
+*/
+func (c *Chaos) partialSize(size int) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.random.IntN(size)
+}
+
+/*
+Injects failures that are common to all operations, with the
+errors returned by the backends
+*/
+func (c *Chaos) inject(operation string) error {
+	if c.chance(c.Options.DisconnectRate) {
+		c.Unicomm.Disconnect()
+		return fmt.Errorf("injected disconnect")
+	}
+	if c.chance(c.Options.TimeoutRate) {
+		return unicommio.TimeoutError(operation)
+	}
+	return nil
+}
+
+/*
+Reads a number of bytes with failure injection
+*/
+func (c *Chaos) Read(n uint) ([]byte, error) {
+	if err := c.inject("read"); err != nil {
+		return nil, err
+	}
+	data, err := c.Unicomm.Read(n)
+	if err == nil && c.chance(c.Options.CorruptRate) {
+		data = c.corrupt(data)
+	}
+	return data, err
+}
+
+/*
+Reads data until a target delimiter is found with failure
+injection
+*/
+func (c *Chaos) ReadUntil(delimiter string) ([]byte, error) {
+	if err := c.inject("read until"); err != nil {
+		return nil, err
+	}
+	data, err := ReadUntil(c.Unicomm, delimiter)
+	if err == nil && c.chance(c.Options.CorruptRate) {
+		data = c.corrupt(data)
+	}
+	return data, err
+}
+
+/*
+Writes an array of bytes with failure injection
+*/
+func (c *Chaos) Write(message []byte) error {
+	if err := c.inject("write"); err != nil {
+		return err
+	}
+	if len(message) > 0 && c.chance(c.Options.PartialWriteRate) {
+		size := c.partialSize(len(message))
+		if err := c.Unicomm.Write(message[:size]); err != nil {
+			return err
+		}
+		return &unicommio.PartialWriteError{Written: size, Expected: len(message)}
+	}
+	if c.chance(c.Options.CorruptRate) {
+		message = c.corrupt(message)
+	}
+	return c.Unicomm.Write(message)
+}
+
+/*
+Writes an array of bytes without the end delimiter, with the
+same faults as Write
+*/
+func (c *Chaos) WriteRaw(message []byte) error {
+	if err := c.inject("write"); err != nil {
+		return err
+	}
+	if len(message) > 0 && c.chance(c.Options.PartialWriteRate) {
+		size := c.partialSize(len(message))
+		if err := writeRaw(c.Unicomm, message[:size]); err != nil {
+			return err
+		}
+		return &unicommio.PartialWriteError{Written: size, Expected: len(message)}
+	}
+	if c.chance(c.Options.CorruptRate) {
+		message = c.corrupt(message)
+	}
+	return writeRaw(c.Unicomm, message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (c *Chaos) Unwrap() Unicomm {
+	return c.Unicomm
+}
@@ -0,0 +1,101 @@
+package unicomm_test
+
+import (
+	"errors"
+	"math/bits"
+	"strings"
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+func TestChaosFaults(t *testing.T) {
+	message := []byte("MEAS:VOLT?")
+
+	t.Run("none", func(t *testing.T) {
+		path := &failingPath{}
+		chaos := unicomm.NewChaos(path, unicomm.ChaosOptions{})
+		chaos.Connect()
+		if err := chaos.Write(message); err != nil || len(path.written) != 1 || path.written[0] != string(message) {
+			t.Fatalf("Write = %v, wrote %q", err, path.written)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		path := &failingPath{}
+		chaos := unicomm.NewChaos(path, unicomm.ChaosOptions{TimeoutRate: 1})
+		chaos.Connect()
+		if _, err := chaos.Read(4); !errors.Is(err, unicommio.ErrTimeout) || err.Error() != "read timeout" {
+			t.Fatalf("Read = %v, want a timeout", err)
+		}
+		if _, err := chaos.ReadUntil("\n"); !unicommio.IsTimeout(err) {
+			t.Fatalf("ReadUntil = %v, want a timeout", err)
+		}
+		if err := chaos.Write(message); !errors.Is(err, unicommio.ErrTimeout) || len(path.written) != 0 {
+			t.Fatalf("Write = %v, wrote %q despite the timeout", err, path.written)
+		}
+	})
+
+	t.Run("disconnect", func(t *testing.T) {
+		path := &failingPath{}
+		chaos := unicomm.NewChaos(path, unicomm.ChaosOptions{DisconnectRate: 1})
+		chaos.Connect()
+		if err := chaos.Write(message); err == nil || path.IsConnected() {
+			t.Fatalf("Write = %v, connected %t", err, path.IsConnected())
+		}
+	})
+
+	t.Run("partial write", func(t *testing.T) {
+		path := &failingPath{}
+		chaos := unicomm.NewChaos(path, unicomm.ChaosOptions{PartialWriteRate: 1})
+		chaos.Connect()
+		err := chaos.Write(message)
+		if len(path.written) != 1 {
+			t.Fatalf("Write = %v, wrote %q", err, path.written)
+		}
+		written := path.written[0]
+		var partial *unicommio.PartialWriteError
+		if !errors.As(err, &partial) || partial.Written != len(written) || partial.Expected != len(message) {
+			t.Fatalf("Write = %v, want a partial write of %d bytes", err, len(written))
+		}
+		if len(written) >= len(message) || !strings.HasPrefix(string(message), written) {
+			t.Fatalf("wrote %q, want a strict prefix of %q", written, message)
+		}
+	})
+
+	t.Run("corruption", func(t *testing.T) {
+		path := &failingPath{}
+		chaos := unicomm.NewChaos(path, unicomm.ChaosOptions{CorruptRate: 1})
+		chaos.Connect()
+		if err := chaos.Write(message); err != nil {
+			t.Fatal(err)
+		}
+		flipped := 0
+		for index := range message {
+			flipped += bits.OnesCount8(message[index] ^ path.written[0][index])
+		}
+		if flipped != 1 || string(message) != "MEAS:VOLT?" {
+			t.Fatalf("wrote %q from %q, want a single flipped bit on a copy", path.written[0], message)
+		}
+	})
+}
+
+func TestChaosSeedIsReproducible(t *testing.T) {
+	run := func() []string {
+		path := &failingPath{}
+		chaos := unicomm.NewChaos(path, unicomm.ChaosOptions{CorruptRate: 0.5, Seed: 42})
+		chaos.Connect()
+		for range 16 {
+			chaos.Write([]byte("DATA"))
+		}
+		return path.written
+	}
+	first, second := run(), run()
+	if strings.Join(first, ",") != strings.Join(second, ",") {
+		t.Fatalf("same seed injected different faults: %q and %q", first, second)
+	}
+	if !strings.Contains(strings.Join(first, ","), "DATA") || strings.Count(strings.Join(first, ","), "DATA") == 16 {
+		t.Fatalf("expected a mix of clean and corrupted writes, got %q", first)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+type CoalesceOptions struct {
+	Window   time.Duration // Time to wait for more writes
+	MaxBytes int           // Flushes as soon as the batch reaches this size
+
+	// Appended to each message to keep frame boundaries. Empty uses
+	// the end delimiter of the backend, which only ends the batch
+	// otherwise, so it must be set for backends not reporting theirs
+	Delimiter string
+}
+
+type Coalescer struct {
+	Unicomm
+	Options CoalesceOptions
+
+	pending []byte
+	timer   *time.Timer
+	lastErr error      // Error of the last background flush
+	mutex   sync.Mutex // Protect pending batch
+}
+
+/*
+Creates a wrapper that coalesces writes issued within a window
+into a single transmission
+*/
+func NewCoalescer(comm Unicomm, options CoalesceOptions) *Coalescer {
+	if options.Window == 0 {
+		options.Window = 5 * time.Millisecond
+	}
+	if options.MaxBytes == 0 {
+		options.MaxBytes = 1024
+	}
+	return &Coalescer{
+		Unicomm: comm,
+		Options: options,
+	}
+}
+
+/*
+Sends the pending batch, must be called with the mutex locked
+*/
+func (c *Coalescer) flush() error {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if len(c.pending) == 0 {
+		return nil
+	}
+	batch := c.pending
+	c.pending = nil
+	return c.Unicomm.Write(batch)
+}
+
+/*
+Sends the pending batch when the window expires
+*/
+func (c *Coalescer) flushWindow() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.timer = nil
+	if err := c.flush(); err != nil {
+		c.lastErr = err
+	}
+}
+
+/*
+Returns and clears the error of the last background flush
+*/
+func (c *Coalescer) takeError() error {
+	err := c.lastErr
+	c.lastErr = nil
+	return err
+}
+
+/*
+Adds the message to the current batch. Errors of batches sent
+in background are returned by the next Write or Flush
+*/
+func (c *Coalescer) Write(message []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.takeError(); err != nil {
+		return err
+	}
+
+	delimiter := c.Options.Delimiter
+	if delimiter == "" {
+		if writer, ok := As[DelimitedWriter](c.Unicomm); ok {
+			delimiter = writer.EndDelimiter()
+		}
+	}
+	c.pending = append(c.pending, message...)
+	if delimiter != "" && !strings.HasSuffix(string(message), delimiter) {
+		c.pending = append(c.pending, delimiter...)
+	}
+
+	if len(c.pending) >= c.Options.MaxBytes {
+		return c.flush()
+	}
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.Options.Window, c.flushWindow)
+	}
+	return nil
+}
+
+/*
+Sends the pending batch immediately
+*/
+func (c *Coalescer) Flush() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.takeError(); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+/*
+Sends the pending batch before reading, so responses to the
+batched commands can arrive
+*/
+func (c *Coalescer) Read(n uint) ([]byte, error) {
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+	return c.Unicomm.Read(n)
+}
+
+/*
+Sends the pending batch before reading until a target
+delimiter is found
+*/
+func (c *Coalescer) ReadUntil(delimiter string) ([]byte, error) {
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+	return ReadUntil(c.Unicomm, delimiter)
+}
+
+/*
+Sends the pending batch and closes the connection
+*/
+func (c *Coalescer) Disconnect() error {
+	c.Flush()
+	return c.Unicomm.Disconnect()
+}
+
+/*
+Sends the pending batch and then the message without the end
+delimiter, since it cannot be merged with the next writes
+*/
+func (c *Coalescer) WriteRaw(message []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.takeError(); err != nil {
+		return err
+	}
+	if err := c.flush(); err != nil {
+		return err
+	}
+	return writeRaw(c.Unicomm, message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (c *Coalescer) Unwrap() Unicomm {
+	return c.Unicomm
+}
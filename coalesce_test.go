@@ -0,0 +1,115 @@
+package unicomm_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+/*
+Waits until the instance received the expected number of writes
+*/
+func waitWrites(t *testing.T, conn *countingConn, count int) []string {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(conn.written()) < count {
+		if time.Now().After(deadline) {
+			t.Fatalf("got writes %q, want %d", conn.written(), count)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return conn.written()
+}
+
+func TestCoalescerBatchesWindow(t *testing.T) {
+	inner := &countingConn{}
+	comm := unicomm.NewCoalescer(inner, unicomm.CoalesceOptions{Window: 50 * time.Millisecond, Delimiter: ";"})
+
+	// Delimiters already present are not repeated
+	for _, message := range []string{"A", "B;", "C"} {
+		if err := comm.Write([]byte(message)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if writes := inner.written(); len(writes) != 0 {
+		t.Fatalf("sent %q before the window expired", writes)
+	}
+	if writes := waitWrites(t, inner, 1); len(writes) != 1 || writes[0] != "A;B;C;" {
+		t.Fatalf("sent %q, want a single batch", writes)
+	}
+}
+
+func TestCoalescerFlushes(t *testing.T) {
+	inner := &countingConn{}
+	comm := unicomm.NewCoalescer(inner, unicomm.CoalesceOptions{Window: time.Hour, MaxBytes: 8})
+
+	// A full batch is sent right away
+	comm.Write([]byte("1234"))
+	comm.Write([]byte("5678"))
+	if writes := inner.written(); len(writes) != 1 || writes[0] != "12345678" {
+		t.Fatalf("sent %q, want the full batch", writes)
+	}
+
+	// Reads send the commands waiting for a response
+	comm.Write([]byte("MEAS?"))
+	if _, err := comm.Read(1); err != nil {
+		t.Fatal(err)
+	}
+	if writes := inner.written(); len(writes) != 2 || writes[1] != "MEAS?" {
+		t.Fatalf("sent %q, want the batch before the read", writes)
+	}
+}
+
+func TestCoalescerBackgroundError(t *testing.T) {
+	inner := &countingConn{}
+	comm := unicomm.NewCoalescer(inner, unicomm.CoalesceOptions{Window: 10 * time.Millisecond})
+
+	inner.failWrites(syscall.EPIPE)
+	if err := comm.Write([]byte("LOST")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// The failure of the batch sent by the window surfaces once
+	inner.failWrites(nil)
+	if err := comm.Write([]byte("NEXT")); !errors.Is(err, syscall.EPIPE) {
+		t.Fatalf("Write = %v, want the error of the background flush", err)
+	}
+	if err := comm.Flush(); err != nil {
+		t.Fatalf("Flush = %v, want the error reported once", err)
+	}
+}
+
+func TestCoalescerBackendDelimiter(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{Handler: func([]byte) []byte { return nil }})
+	options := server.TCPOptions()
+	options.EndDelimiter = "\r\n"
+	comm := unicomm.NewCoalescer(
+		unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: options}),
+		unicomm.CoalesceOptions{Window: 20 * time.Millisecond},
+	)
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	// Without a delimiter, each message ends with the one of the backend
+	for _, message := range []string{"VOLT 5", "CURR 1\r\n", "OUTP ON"} {
+		if err := comm.Write([]byte(message)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := "VOLT 5\r\nCURR 1\r\nOUTP ON\r\n"
+	deadline := time.Now().Add(2 * time.Second)
+	for string(server.Received()) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("server received %q, want %q", server.Received(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommframe"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+/*
+Converts payloads to frames on the link and back, see the
+unicommframe package for the built-in codecs
+*/
+type FrameCodec = unicommframe.Codec
+
+type Framed struct {
+	Unicomm
+	Codec FrameCodec
+
+	stream  []byte     // Received bytes not decoded yet
+	started time.Time  // When the first byte of the stream was received
+	pending []byte     // Decoded payload bytes not consumed yet
+	mutex   sync.Mutex // Protect stream and pending bytes
+}
+
+/*
+Creates a middleware that encodes each written message as a
+frame and decodes the received frames with the codec. Frames
+are written without the end delimiter of the wrapped instance
+*/
+func NewFramed(comm Unicomm, codec FrameCodec) *Framed {
+	return &Framed{
+		Unicomm: comm,
+		Codec:   codec,
+	}
+}
+
+/*
+Reads until the stream holds a complete frame and decodes it,
+must be called with the mutex locked. Frames failing validation
+are returned along with the error
+*/
+func (f *Framed) readFrame() (Frame, error) {
+	for {
+		payload, rest, err := f.Codec.Decode(f.stream)
+		if !errors.Is(err, unicommframe.ErrIncomplete) {
+			frame := Frame{
+				Payload:  payload,
+				Raw:      bytes.Clone(f.stream[:len(f.stream)-len(rest)]),
+				Started:  f.started,
+				Received: time.Now(),
+				Err:      err,
+			}
+			f.stream = rest
+			f.started = time.Time{}
+			if len(rest) > 0 {
+				f.started = frame.Received
+			}
+			return frame, err
+		}
+
+		data, err := f.Unicomm.Read(unicommio.ReadChunkSize)
+		if err != nil {
+			return Frame{}, err
+		}
+		if len(data) == 0 {
+			return Frame{}, unicommio.TimeoutError("read frame")
+		}
+		if len(f.stream) == 0 {
+			f.started = time.Now()
+		}
+		f.stream = append(f.stream, data...)
+	}
+}
+
+/*
+Reads the next frame. The remainder of a frame partially
+consumed by Read is returned first, without raw bytes
+*/
+func (f *Framed) ReadFrame() (Frame, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.pending) > 0 {
+		payload := f.pending
+		f.pending = nil
+		return Frame{Payload: payload, Received: time.Now()}, nil
+	}
+	return f.readFrame()
+}
+
+/*
+Removes and returns the first n decoded bytes
+*/
+func (f *Framed) take(n int) []byte {
+	data := bytes.Clone(f.pending[:n])
+	f.pending = f.pending[n:]
+	return data
+}
+
+/*
+Reads up to n payload bytes
+*/
+func (f *Framed) Read(n uint) ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.pending) == 0 {
+		frame, err := f.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		f.pending = frame.Payload
+	}
+	return f.take(min(int(n), len(f.pending))), nil
+}
+
+/*
+Reads payload data until a target delimiter is found
+*/
+func (f *Framed) ReadUntil(delimiter string) ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for {
+		if index := bytes.Index(f.pending, []byte(delimiter)); index >= 0 {
+			return f.take(index + len(delimiter)), nil
+		}
+		frame, err := f.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		f.pending = append(f.pending, frame.Payload...)
+	}
+}
+
+/*
+Writes an array of bytes as a single frame, without the end
+delimiter of the backend, which would corrupt the next frame
+*/
+func (f *Framed) Write(message []byte) error {
+	frame, err := encodeFrame(f.Codec, message)
+	if err != nil {
+		return err
+	}
+	return writeRaw(f.Unicomm, frame)
+}
+
+/*
+Encodes the payload, checking first the codecs that can be
+misconfigured, since Encode cannot report an error
+*/
+func encodeFrame(codec FrameCodec, payload []byte) ([]byte, error) {
+	if validator, ok := codec.(interface{ Validate() error }); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return codec.Encode(payload), nil
+}
+
+/*
+Discards the partially received frames
+*/
+func (f *Framed) Disconnect() error {
+	f.mutex.Lock()
+	f.stream = nil
+	f.pending = nil
+	f.mutex.Unlock()
+
+	return f.Unicomm.Disconnect()
+}
+
+/*
+Writes an array of bytes as a single frame, the same as Write
+*/
+func (f *Framed) WriteRaw(message []byte) error {
+	return f.Write(message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (f *Framed) Unwrap() Unicomm {
+	return f.Unicomm
+}
+
+/*
+Stops the lookup of data capabilities, which would bypass the
+decoding of the frames
+*/
+func (f *Framed) transformsData() {}
@@ -0,0 +1,65 @@
+package unicomm_test
+
+import (
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommframe"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestFramedIgnoresEndDelimiter(t *testing.T) {
+	// The gateway echoes the frames, which must not carry the delimiter
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	options := server.TCPOptions()
+	options.EndDelimiter = "\n"
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      options,
+		Codec:    unicommframe.LengthPrefix{Size: 2},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	framed, ok := unicomm.As[*unicomm.Framed](comm)
+	if !ok {
+		t.Fatal("expected a framed instance")
+	}
+	for _, message := range []string{"first", "second"} {
+		if err := comm.Write([]byte(message)); err != nil {
+			t.Fatal(err)
+		}
+		frame, err := framed.ReadFrame()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(frame.Payload) != message {
+			t.Fatalf("got %q, want %q", frame.Payload, message)
+		}
+	}
+	if received := string(server.Received()); received != "\x00\x05first\x00\x06second" {
+		t.Fatalf("server received %q", received)
+	}
+}
+
+func TestFramedRejectsInvalidCodec(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      server.TCPOptions(),
+		Codec:    unicommframe.Delimiter{Delimiter: "\r\n", Escape: "\x1B\x1B"},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	if err := comm.Write([]byte("payload")); err == nil {
+		t.Fatal("expected the invalid escape to be reported")
+	}
+	if received := server.Received(); len(received) != 0 {
+		t.Fatalf("server received %q", received)
+	}
+}
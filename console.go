@@ -0,0 +1,266 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+type ConsoleOptions struct {
+	LineEnding string              // Appended to each typed line, empty relies on the end delimiter of the instance
+	Delimiter  string              // Splits the responses into frames, empty prints them whole
+	Timeout    time.Duration       // Wait for the first byte of a response, defaults to 1s
+	Hex        bool                // Prints the hex view of the frames
+	Prompt     string              // Defaults to "> "
+	Macros     map[string][]string // Lines sent in order by ":run name"
+}
+
+/*
+Interactive helper to talk to a device by hand, the base of a
+terminal feature in a CLI. Typed lines are sent with escapes such
+as \r and \x02 interpreted, and the frames received until the
+device goes silent are printed. Lines starting with ':' are
+console commands, listed by ":help"
+*/
+type Console struct {
+	Options ConsoleOptions
+
+	comm  Unicomm
+	mutex sync.Mutex // Keep commands apart
+}
+
+/*
+Returned by Execute after the ":quit" command
+*/
+var ErrConsoleQuit = errors.New("console quit")
+
+const consoleHelp = `:help               shows this help
+:hex on|off         toggles the hex view
+:raw <hex bytes>    sends bytes as is, e.g. ":raw 02 31 03"
+:run <macro>        sends the lines of a macro
+:define <macro> <line>; <line>...
+                    defines a macro
+:macros             lists the macros
+:quit               leaves the console
+`
+
+/*
+Creates a console over a Unicomm instance, connected by Run
+when needed
+*/
+func NewConsole(comm Unicomm, options ConsoleOptions) *Console {
+	if options.Timeout == 0 {
+		options.Timeout = time.Second
+	}
+	if options.Prompt == "" {
+		options.Prompt = "> "
+	}
+	if options.Macros == nil {
+		options.Macros = make(map[string][]string)
+	}
+	return &Console{
+		Options: options,
+		comm:    comm,
+	}
+}
+
+/*
+Sends a typed line and returns the frames of the response
+*/
+func (c *Console) Send(line string) ([][]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.send(line)
+}
+
+/*
+Sends a line, must be called with the mutex locked
+*/
+func (c *Console) send(line string) ([][]byte, error) {
+	if err := c.comm.Write([]byte(unescapeLine(line) + c.Options.LineEnding)); err != nil {
+		return nil, err
+	}
+	return c.receive()
+}
+
+/*
+Reads until the device goes silent after answering, or until
+the timeout when nothing arrives, and splits the response into
+frames
+*/
+func (c *Console) receive() ([][]byte, error) {
+	deadline := time.Now().Add(c.Options.Timeout)
+	var buffer []byte
+	for time.Now().Before(deadline) {
+		data, err := c.comm.Read(unicommio.ReadChunkSize)
+		buffer = append(buffer, data...)
+		if err != nil {
+			return c.frames(buffer), err
+		}
+		if len(data) == 0 && len(buffer) > 0 {
+			break
+		}
+	}
+	return c.frames(buffer), nil
+}
+
+/*
+Splits the data into frames ending with the delimiter, keeping
+an incomplete last frame
+*/
+func (c *Console) frames(data []byte) [][]byte {
+	var frames [][]byte
+	for len(data) > 0 {
+		frame, rest, ok := unicommio.SplitFrame(data, c.Options.Delimiter)
+		if !ok {
+			return append(frames, data)
+		}
+		frames = append(frames, frame)
+		data = rest
+	}
+	return frames
+}
+
+/*
+Runs a typed line, either a console command or a line sent to
+the device, and prints the result
+*/
+func (c *Console) Execute(line string, out io.Writer) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !strings.HasPrefix(line, ":") {
+		frames, err := c.send(line)
+		c.print(out, frames)
+		return err
+	}
+
+	command, argument, _ := strings.Cut(strings.TrimPrefix(line, ":"), " ")
+	argument = strings.TrimSpace(argument)
+	switch command {
+	case "help":
+		fmt.Fprint(out, consoleHelp)
+	case "hex":
+		c.Options.Hex = argument != "off"
+	case "raw":
+		data, err := hex.DecodeString(strings.Join(strings.Fields(argument), ""))
+		if err != nil {
+			return fmt.Errorf("invalid hex bytes: %w", err)
+		}
+		if err := writeRaw(c.comm, data); err != nil {
+			return err
+		}
+		frames, err := c.receive()
+		c.print(out, frames)
+		return err
+	case "run":
+		lines, ok := c.Options.Macros[argument]
+		if !ok {
+			return fmt.Errorf("unknown macro %q", argument)
+		}
+		for _, line := range lines {
+			fmt.Fprintf(out, "%s%s\n", c.Options.Prompt, line)
+			frames, err := c.send(line)
+			c.print(out, frames)
+			if err != nil {
+				return err
+			}
+		}
+	case "define":
+		name, body, _ := strings.Cut(argument, " ")
+		if name == "" {
+			return fmt.Errorf("missing macro name")
+		}
+		var lines []string
+		for _, line := range strings.Split(body, ";") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		c.Options.Macros[name] = lines
+	case "macros":
+		for _, name := range slices.Sorted(maps.Keys(c.Options.Macros)) {
+			fmt.Fprintf(out, "%s: %s\n", name, strings.Join(c.Options.Macros[name], "; "))
+		}
+	case "quit":
+		return ErrConsoleQuit
+	default:
+		return fmt.Errorf("unknown command %q, see :help", command)
+	}
+	return nil
+}
+
+/*
+Prints the frames quoted, followed by their hex view when enabled
+*/
+func (c *Console) print(out io.Writer, frames [][]byte) {
+	for _, frame := range frames {
+		fmt.Fprintf(out, "< %s\n", strconv.Quote(string(frame)))
+		if c.Options.Hex {
+			fmt.Fprint(out, hex.Dump(frame))
+		}
+	}
+}
+
+/*
+Reads lines from the input until it ends or ":quit" is typed,
+executing each one. Errors are printed and the console goes on
+*/
+func (c *Console) Run(in io.Reader, out io.Writer) error {
+	if !c.comm.IsConnected() {
+		if err := c.comm.Connect(); err != nil {
+			return err
+		}
+	}
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, c.Options.Prompt)
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		err := c.Execute(line, out)
+		if errors.Is(err, ErrConsoleQuit) {
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+		}
+	}
+}
+
+/*
+Interprets the escapes of a typed line, such as \r, \t and \x02.
+Lines with invalid escapes are sent as typed
+*/
+func unescapeLine(line string) string {
+	if !strings.Contains(line, `\`) {
+		return line
+	}
+	unquoted, err := strconv.Unquote(`"` + strings.ReplaceAll(line, `"`, `\"`) + `"`)
+	if err != nil {
+		return line
+	}
+	return unquoted
+}
@@ -0,0 +1,54 @@
+package unicomm_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestConsole(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	tcp := server.TCPOptions()
+	tcp.ReadTimeout = 50 * time.Millisecond
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: tcp})
+	defer comm.Disconnect()
+
+	console := unicomm.NewConsole(comm, unicomm.ConsoleOptions{LineEnding: "\n", Delimiter: "\n"})
+	input := strings.Join([]string{
+		`hello`,
+		`:hex on`,
+		`:define greet a; b`,
+		`:run greet`,
+		`:hex off`,
+		`:raw 41 42`,
+		`tab\there`,
+		`:bogus`,
+		`:quit`,
+		`never sent`,
+	}, "\n")
+	var out bytes.Buffer
+	if err := console.Run(strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`< "hello\n"`,
+		"00000000  61 0a",
+		"> a\n< \"a\\n\"",
+		"> b\n< \"b\\n\"",
+		`< "AB"`,
+		`< "tab\there\n"`,
+		`error: unknown command "bogus"`,
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Fatalf("output lacks %q:\n%s", expected, out.String())
+		}
+	}
+	if strings.Contains(string(server.Received()), "never") {
+		t.Fatal("lines after :quit were sent")
+	}
+}
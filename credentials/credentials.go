@@ -0,0 +1,132 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+/*
+Secrets used by transports that need authentication. Values
+are redacted when formatted or logged
+*/
+type Credentials struct {
+	Username string
+	Password string
+	Key      []byte // Key material, e.g. a PEM encoded private key
+	Token    string
+}
+
+/*
+Resolves the credentials at connect time, allowing secrets
+to be fetched from vaults instead of stored in options
+*/
+type Provider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+/*
+Adapts a function to the Provider interface
+*/
+type ProviderFunc func(ctx context.Context) (Credentials, error)
+
+type staticProvider struct {
+	credentials Credentials
+}
+
+/*
+Calls the function to resolve the credentials
+*/
+func (pf ProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return pf(ctx)
+}
+
+/*
+Creates a provider that always returns the same credentials
+*/
+func Static(credentials Credentials) Provider {
+	return &staticProvider{credentials: credentials}
+}
+
+/*
+Returns a copy of the static credentials
+*/
+func (sp *staticProvider) Credentials(ctx context.Context) (Credentials, error) {
+	credentials := sp.credentials
+	credentials.Key = append([]byte(nil), sp.credentials.Key...)
+	return credentials, nil
+}
+
+/*
+Resolves the credentials of a provider, returning an error
+when no provider is configured
+*/
+func Resolve(ctx context.Context, provider Provider) (Credentials, error) {
+	if provider == nil {
+		return Credentials{}, fmt.Errorf("there is no credentials provider configured")
+	}
+	credentials, err := provider.Credentials(ctx)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("was not possible to resolve credentials: %w", err)
+	}
+	return credentials, nil
+}
+
+/*
+Overwrites the key material and clears the secrets, to be
+called once the transport is authenticated
+*/
+func (c *Credentials) Wipe() {
+	for index := range c.Key {
+		c.Key[index] = 0
+	}
+	c.Key = nil
+	c.Password = ""
+	c.Token = ""
+}
+
+/*
+Formats the credentials without revealing the secrets
+*/
+func (c Credentials) String() string {
+	return fmt.Sprintf(
+		"{Username:%s Password:%s Key:%s Token:%s}",
+		c.Username, redact(c.Password != ""), redact(len(c.Key) > 0), redact(c.Token != ""),
+	)
+}
+
+/*
+Formats the credentials without revealing the secrets, also
+when the %#v verb is used
+*/
+func (c Credentials) GoString() string {
+	return c.String()
+}
+
+/*
+Logs the credentials without revealing the secrets
+*/
+func (c Credentials) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("username", c.Username),
+		slog.String("password", redact(c.Password != "")),
+		slog.String("key", redact(len(c.Key) > 0)),
+		slog.String("token", redact(c.Token != "")),
+	)
+}
+
+/*
+Returns the placeholder of a secret
+*/
+func redact(present bool) string {
+	if present {
+		return "[REDACTED]"
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package credentials_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/devicehub-go/unicomm/credentials"
+)
+
+func TestRedacted(t *testing.T) {
+	secret := credentials.Credentials{
+		Username: "admin",
+		Password: "hunter2",
+		Key:      []byte("-----BEGIN KEY-----"),
+		Token:    "t0ken",
+	}
+
+	var logged bytes.Buffer
+	slog.New(slog.NewTextHandler(&logged, nil)).Info("connect", "credentials", secret)
+	outputs := []string{
+		fmt.Sprint(secret),
+		fmt.Sprintf("%+v", secret),
+		fmt.Sprintf("%#v", secret),
+		fmt.Sprintf("%v", &secret),
+		logged.String(),
+	}
+	for _, output := range outputs {
+		for _, leaked := range []string{"hunter2", "BEGIN KEY", "t0ken"} {
+			if strings.Contains(output, leaked) {
+				t.Errorf("%q reveals %q", output, leaked)
+			}
+		}
+		if !strings.Contains(output, "admin") || !strings.Contains(output, "[REDACTED]") {
+			t.Errorf("%q lacks the username or the placeholders", output)
+		}
+	}
+}
+
+func TestStaticReturnsCopies(t *testing.T) {
+	provider := credentials.Static(credentials.Credentials{Key: []byte{1, 2, 3}})
+
+	first, err := provider.Credentials(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Wipe()
+
+	second, err := provider.Credentials(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(second.Key, []byte{1, 2, 3}) {
+		t.Fatalf("key = %v after wiping a previous copy", second.Key)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	if _, err := credentials.Resolve(context.Background(), nil); err == nil {
+		t.Fatal("Resolve without a provider succeeded")
+	}
+
+	sealed := errors.New("vault sealed")
+	failing := credentials.ProviderFunc(func(ctx context.Context) (credentials.Credentials, error) {
+		return credentials.Credentials{Token: "partial"}, sealed
+	})
+	resolved, err := credentials.Resolve(context.Background(), failing)
+	if !errors.Is(err, sealed) {
+		t.Fatalf("Resolve = %v, want the provider error", err)
+	}
+	if resolved.Token != "" {
+		t.Fatal("Resolve returned credentials along with an error")
+	}
+}
+
+func TestWipe(t *testing.T) {
+	key := []byte{1, 2, 3}
+	secret := credentials.Credentials{Username: "admin", Password: "p", Key: key, Token: "t"}
+	secret.Wipe()
+
+	if !bytes.Equal(key, []byte{0, 0, 0}) {
+		t.Fatalf("key material = %v, want zeroed", key)
+	}
+	if secret.Key != nil || secret.Password != "" || secret.Token != "" {
+		t.Fatalf("secrets left after Wipe: %#v", secret)
+	}
+	if secret.Username != "admin" {
+		t.Fatal("Wipe cleared the username")
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import "bytes"
+
+/*
+Makes the payload returned by ReadUntil explicit. Partial data
+that is not returned on timeout is kept and served first by the
+next read, so no byte is lost
+*/
+type ReadUntilOptions struct {
+	StripDelimiter          bool // Removes the delimiter from the payload
+	IncludePartialOnTimeout bool // Returns the bytes read so far with the timeout error
+}
+
+type Delimited struct {
+	*Buffered
+	Options ReadUntilOptions
+}
+
+/*
+Creates a wrapper applying the payload semantics of the options
+to every ReadUntil
+*/
+func NewDelimited(comm Unicomm, options ReadUntilOptions) *Delimited {
+	return &Delimited{
+		Buffered: NewBuffered(comm),
+		Options:  options,
+	}
+}
+
+/*
+Reads data until a target delimiter is found
+*/
+func (d *Delimited) ReadUntil(delimiter string) ([]byte, error) {
+	data, err := d.Buffered.ReadUntil(delimiter)
+	if err != nil {
+		if d.Options.IncludePartialOnTimeout || !isTimeout(err) {
+			return data, err
+		}
+		d.Buffered.Unread(data)
+		return nil, err
+	}
+	if d.Options.StripDelimiter {
+		data = bytes.TrimSuffix(data, []byte(delimiter))
+	}
+	return data, nil
+}
+
+/*
+Returns the wrapped instance
+*/
+func (d *Delimited) Unwrap() Unicomm {
+	return d.Buffered
+}
+
+/*
+Stops the lookup of data capabilities, which would bypass the
+buffered frames
+*/
+func (d *Delimited) transformsData() {}
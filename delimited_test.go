@@ -0,0 +1,48 @@
+package unicomm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestDelimitedReadUntil(t *testing.T) {
+	// The reply to the first command arrives split across two writes
+	replies := map[string]string{"A": "12", "B": "3\n"}
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte { return []byte(replies[string(request)]) },
+	})
+	options := server.TCPOptions()
+	options.ReadTimeout = 50 * time.Millisecond
+
+	comm := unicomm.New(unicomm.Options{
+		Protocol:  unicomm.TCP,
+		TCP:       options,
+		ReadUntil: &unicomm.ReadUntilOptions{StripDelimiter: true},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	if err := comm.Write([]byte("A")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := unicomm.ReadUntil(comm, "\n")
+	if err == nil || data != nil {
+		t.Fatalf("expected a timeout without data, got %q, %v", data, err)
+	}
+
+	if err := comm.Write([]byte("B")); err != nil {
+		t.Fatal(err)
+	}
+	data, err = unicomm.ReadUntil(comm, "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "123" {
+		t.Fatalf("got %q", data)
+	}
+}
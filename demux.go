@@ -0,0 +1,286 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+/*
+Returns the channel of a frame and the payload that must be
+delivered to it. Frames that do not belong to any channel
+must return ok as false
+*/
+type ChannelExtractor func(frame []byte) (channel string, payload []byte, ok bool)
+
+type DemuxOptions struct {
+	Reader      ReaderOptions    // Options of the frames reader
+	Extract     ChannelExtractor // Routes each frame to a channel
+	ReadTimeout time.Duration    // Timeout of reads on virtual channels
+}
+
+type Demux struct {
+	Options DemuxOptions
+
+	comm     Unicomm
+	reader   *BackgroundReader
+	channels map[string]*VirtualChannel
+	unrouted atomic.Uint64
+	stop     chan struct{}
+	done     chan struct{}
+	mutex    sync.Mutex // Protect channels and running state
+}
+
+type VirtualChannel struct {
+	Name string
+
+	comm    Unicomm                    // Shared connection
+	timeout time.Duration              // Timeout of reads
+	write   func(message []byte) error // Sends data of the channel
+	buffer  []byte
+	notify  chan struct{} // Closed when new data arrives
+	mutex   sync.Mutex    // Protect buffer
+}
+
+/*
+Creates a demultiplexer that routes the frames read from a
+Unicomm instance into separate virtual channels
+*/
+func NewDemux(comm Unicomm, options DemuxOptions) *Demux {
+	if options.ReadTimeout == 0 {
+		options.ReadTimeout = 100 * time.Millisecond
+	}
+	return &Demux{
+		Options:  options,
+		comm:     comm,
+		reader:   NewBackgroundReader(comm, options.Reader),
+		channels: make(map[string]*VirtualChannel),
+	}
+}
+
+/*
+Returns the virtual channel with the given name, creating it
+if needed
+*/
+func (d *Demux) Channel(name string) *VirtualChannel {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if channel, ok := d.channels[name]; ok {
+		return channel
+	}
+	channel := &VirtualChannel{
+		Name:    name,
+		comm:    d.comm,
+		timeout: d.Options.ReadTimeout,
+		write:   d.comm.Write,
+		notify:  make(chan struct{}),
+	}
+	d.channels[name] = channel
+	return channel
+}
+
+/*
+Returns how many frames did not match any channel
+*/
+func (d *Demux) Unrouted() uint64 {
+	return d.unrouted.Load()
+}
+
+/*
+Starts reading and routing frames
+*/
+func (d *Demux) Start() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.stop != nil {
+		return fmt.Errorf("demultiplexer is already running")
+	}
+	if err := d.reader.Start(); err != nil {
+		return err
+	}
+	d.stop = make(chan struct{})
+	d.done = make(chan struct{})
+	go d.route(d.stop, d.done)
+	return nil
+}
+
+/*
+Stops reading and routing frames
+*/
+func (d *Demux) Stop() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.stop == nil {
+		return fmt.Errorf("demultiplexer is not running")
+	}
+	close(d.stop)
+	<-d.done
+	d.stop = nil
+	return d.reader.Stop()
+}
+
+/*
+Delivers each frame to its channel until stopped
+*/
+func (d *Demux) route(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	for {
+		select {
+		case <-stop:
+			return
+		case frame := <-d.reader.Frames():
+			var name string
+			var payload []byte
+			ok, err := guard(func() (ok bool, err error) {
+				name, payload, ok = d.Options.Extract(frame.Payload)
+				return ok, nil
+			})
+			if err != nil {
+				report(d.Options.Reader.OnError, err)
+			}
+			if !ok {
+				d.unrouted.Add(1)
+				continue
+			}
+
+			d.mutex.Lock()
+			channel, ok := d.channels[name]
+			d.mutex.Unlock()
+			if !ok {
+				d.unrouted.Add(1)
+				continue
+			}
+			channel.deliver(payload)
+		}
+	}
+}
+
+/*
+Appends data to the channel buffer and wakes up readers
+*/
+func (vc *VirtualChannel) deliver(data []byte) {
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+
+	vc.buffer = append(vc.buffer, data...)
+	close(vc.notify)
+	vc.notify = make(chan struct{})
+}
+
+/*
+Waits until the condition holds for the buffered data, then
+removes and returns size bytes. Returns false when the read
+timeout expires first
+*/
+func (vc *VirtualChannel) wait(size func(buffer []byte) int) ([]byte, bool) {
+	deadline := time.After(vc.timeout)
+
+	for {
+		vc.mutex.Lock()
+		if n := size(vc.buffer); n > 0 {
+			data := bytes.Clone(vc.buffer[:n])
+			vc.buffer = vc.buffer[n:]
+			vc.mutex.Unlock()
+			return data, true
+		}
+		notify := vc.notify
+		vc.mutex.Unlock()
+
+		select {
+		case <-notify:
+		case <-deadline:
+			return nil, false
+		}
+	}
+}
+
+/*
+Virtual channels share one connection, so connecting only
+checks that it is established
+*/
+func (vc *VirtualChannel) Connect() error {
+	if !vc.comm.IsConnected() {
+		return fmt.Errorf("there is no connection established")
+	}
+	return nil
+}
+
+/*
+Virtual channels share one connection, which must be closed
+through the underlying instance
+*/
+func (vc *VirtualChannel) Disconnect() error {
+	return nil
+}
+
+/*
+Returns true if the shared connection is established
+*/
+func (vc *VirtualChannel) IsConnected() bool {
+	return vc.comm.IsConnected()
+}
+
+/*
+Reads up to n bytes delivered to the channel. As the backends,
+it returns no data and no error when the read timeout expires
+*/
+func (vc *VirtualChannel) Read(n uint) ([]byte, error) {
+	data, ok := vc.wait(func(buffer []byte) int {
+		return min(int(n), len(buffer))
+	})
+	if !ok {
+		return []byte{}, nil
+	}
+	return data, nil
+}
+
+/*
+Reads the data delivered to the channel until a target
+delimiter is found. On timeout the data delivered so far is
+consumed and returned with the timeout error
+*/
+func (vc *VirtualChannel) ReadUntil(delimiter string) ([]byte, error) {
+	until := func(buffer []byte) int {
+		if index := bytes.Index(buffer, []byte(delimiter)); index >= 0 {
+			return index + len(delimiter)
+		}
+		return 0
+	}
+	if data, ok := vc.wait(until); ok {
+		return data, nil
+	}
+
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+
+	// Data delivered right at the deadline may complete the frame
+	if n := until(vc.buffer); n > 0 {
+		data := bytes.Clone(vc.buffer[:n])
+		vc.buffer = vc.buffer[n:]
+		return data, nil
+	}
+	data := vc.buffer
+	vc.buffer = nil
+	return data, unicommio.TimeoutError("read until")
+}
+
+/*
+Writes an array of bytes through the shared connection
+*/
+func (vc *VirtualChannel) Write(message []byte) error {
+	return vc.write(message)
+}
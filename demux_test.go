@@ -0,0 +1,111 @@
+package unicomm_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+/*
+Starts a demultiplexer over an echo server, routing the frames
+"<channel>:<payload>\n" by their prefix
+*/
+func startDemux(t *testing.T) (unicomm.Unicomm, *unicomm.Demux) {
+	t.Helper()
+
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions()})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { comm.Disconnect() })
+
+	demux := unicomm.NewDemux(comm, unicomm.DemuxOptions{
+		Reader:      unicomm.ReaderOptions{Delimiter: "\n"},
+		ReadTimeout: 50 * time.Millisecond,
+		Extract: func(frame []byte) (string, []byte, bool) {
+			name, payload, ok := bytes.Cut(frame, []byte(":"))
+			return string(name), payload, ok
+		},
+	})
+	if err := demux.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return comm, demux
+}
+
+func TestDemuxRouting(t *testing.T) {
+	comm, demux := startDemux(t)
+	defer demux.Stop()
+
+	sensors, relays := demux.Channel("sensors"), demux.Channel("relays")
+	if demux.Channel("sensors") != sensors {
+		t.Fatal("expected the same channel for the same name")
+	}
+	for _, frame := range []string{"relays:ON 3\n", "garbage\n", "motors:STOP\n", "sensors:21.5\n"} {
+		if err := comm.Write([]byte(frame)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	unicommtest.ExpectFrame(t, sensors, "\n", "21.5\n")
+	unicommtest.ExpectFrame(t, relays, "\n", "ON 3\n")
+
+	// Frames without a channel, or for a channel never created, are counted
+	if unrouted := demux.Unrouted(); unrouted != 2 {
+		t.Fatalf("unrouted = %d, want 2", unrouted)
+	}
+	if data, err := relays.Read(16); err != nil || len(data) != 0 {
+		t.Fatalf("Read = %q, %v, want nothing else on the channel", data, err)
+	}
+}
+
+func TestDemuxChannelTimeout(t *testing.T) {
+	comm, demux := startDemux(t)
+	defer demux.Stop()
+
+	// A channel without data times out on its own, others still receive
+	sensors, relays := demux.Channel("sensors"), demux.Channel("relays")
+	if err := comm.Write([]byte("relays:ON 3\n")); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	data, err := sensors.ReadUntil("\n")
+	if !unicomm.IsTimeout(err) || len(data) != 0 {
+		t.Fatalf("ReadUntil = %q, %v, want a timeout without data", data, err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Fatalf("timed out after %v, want the read timeout", elapsed)
+	}
+	unicommtest.ExpectFrame(t, relays, "\n", "ON 3\n")
+}
+
+func TestDemuxStop(t *testing.T) {
+	comm, demux := startDemux(t)
+	relays := demux.Channel("relays")
+
+	if err := demux.Start(); err == nil {
+		t.Fatal("expected a second start to fail")
+	}
+	if err := demux.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if err := demux.Stop(); err == nil {
+		t.Fatal("expected a second stop to fail")
+	}
+
+	// Stopped, frames wait on the connection until routing restarts
+	if err := comm.Write([]byte("relays:LATE\n")); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := relays.ReadUntil("\n"); !unicomm.IsTimeout(err) || len(data) != 0 {
+		t.Fatalf("ReadUntil = %q, %v, want no frame while stopped", data, err)
+	}
+	if err := demux.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer demux.Stop()
+	unicommtest.ExpectFrame(t, relays, "\n", "LATE\n")
+}
@@ -0,0 +1,146 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrProtocolNotDetected = errors.New("no protocol probe matched")
+
+/*
+A candidate protocol tried after the connection is established.
+Detect talks to the device and returns true when it answers
+as expected by the protocol
+*/
+type ProtocolProbe struct {
+	Name   string
+	Detect func(comm Unicomm) bool
+}
+
+type Detector struct {
+	Unicomm
+	Probes []ProtocolProbe
+
+	detected string
+	mutex    sync.Mutex // Protect detected
+}
+
+/*
+Creates a probe that writes a command and matches when the
+response read until the delimiter contains the expected bytes
+*/
+func QueryProbe(name string, command []byte, delimiter string, expect []byte) ProtocolProbe {
+	return ProtocolProbe{
+		Name: name,
+		Detect: func(comm Unicomm) bool {
+			if err := comm.Write(command); err != nil {
+				return false
+			}
+			response, err := ReadUntil(comm, delimiter)
+			return err == nil && bytes.Contains(response, expect)
+		},
+	}
+}
+
+/*
+Creates a wrapper that tries the probes in order after every
+successful connect, recording the name of the first protocol
+the device answers to
+*/
+func NewDetector(comm Unicomm, probes []ProtocolProbe) *Detector {
+	return &Detector{
+		Unicomm: comm,
+		Probes:  probes,
+	}
+}
+
+/*
+Returns the protocol detected by a Unicomm instance, if it
+has a detector
+*/
+func DetectedProtocol(comm Unicomm) (string, bool) {
+	if detector, ok := As[*Detector](comm); ok {
+		protocol := detector.Protocol()
+		return protocol, protocol != ""
+	}
+	return "", false
+}
+
+/*
+Returns the name of the detected protocol, empty while not
+connected
+*/
+func (d *Detector) Protocol() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.detected
+}
+
+/*
+Tries the probes in order. Leftovers of a failed probe are
+discarded so they do not confuse the next one
+*/
+func (d *Detector) detect() (string, error) {
+	for _, probe := range d.Probes {
+		if probe.Detect(d.Unicomm) {
+			return probe.Name, nil
+		}
+		ReadAvailable(d.Unicomm)
+	}
+	return "", ErrProtocolNotDetected
+}
+
+/*
+Establishes the connection and detects the protocol. The
+connection is closed if no probe matches
+*/
+func (d *Detector) Connect() error {
+	if err := d.Unicomm.Connect(); err != nil {
+		return err
+	}
+	protocol, err := d.detect()
+	if err != nil {
+		d.Unicomm.Disconnect()
+		return fmt.Errorf("protocol detection failed: %w", err)
+	}
+
+	d.mutex.Lock()
+	d.detected = protocol
+	d.mutex.Unlock()
+	return nil
+}
+
+/*
+Closes the connection and forgets the detected protocol, which
+may change with the firmware of the next device
+*/
+func (d *Detector) Disconnect() error {
+	d.mutex.Lock()
+	d.detected = ""
+	d.mutex.Unlock()
+
+	return d.Unicomm.Disconnect()
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (d *Detector) WriteRaw(message []byte) error {
+	return writeRaw(d.Unicomm, message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (d *Detector) Unwrap() Unicomm {
+	return d.Unicomm
+}
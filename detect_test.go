@@ -0,0 +1,64 @@
+package unicomm_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestDetectProtocol(t *testing.T) {
+	// The firmware only answers the vendor identification command
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte {
+			if bytes.Equal(request, []byte("*IDN?\n")) {
+				return []byte("ACME,PSU-100\n")
+			}
+			return nil
+		},
+	})
+	options := server.TCPOptions()
+	options.ReadTimeout = 50 * time.Millisecond
+
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      options,
+		Probes: []unicomm.ProtocolProbe{
+			unicomm.QueryProbe("ascii", []byte("VER\r\n"), "\r\n", []byte("VER")),
+			unicomm.QueryProbe("scpi", []byte("*IDN?\n"), "\n", []byte("ACME")),
+		},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	protocol, ok := unicomm.DetectedProtocol(comm)
+	if !ok || protocol != "scpi" {
+		t.Fatalf("detected %q", protocol)
+	}
+}
+
+func TestDetectProtocolNoMatch(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte { return nil },
+	})
+	options := server.TCPOptions()
+	options.ReadTimeout = 50 * time.Millisecond
+
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      options,
+		Probes: []unicomm.ProtocolProbe{
+			unicomm.QueryProbe("scpi", []byte("*IDN?\n"), "\n", []byte("ACME")),
+		},
+	})
+	if err := comm.Connect(); err == nil {
+		t.Fatal("expected the detection to fail")
+	}
+	if comm.IsConnected() {
+		t.Fatal("connection must be closed when no probe matches")
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"sync"
+)
+
+type EncryptOptions struct {
+	Key            []byte // Pre-shared key of 16, 24 or 32 bytes
+	Delimiter      string // Ends each encrypted frame on the link
+	AdditionalData []byte // Authenticated but not encrypted, e.g. a device ID
+}
+
+type Encrypted struct {
+	Unicomm
+	Options EncryptOptions
+
+	aead    cipher.AEAD
+	pending []byte     // Decrypted bytes not consumed yet
+	raw     []byte     // Received bytes of a frame not complete yet
+	mutex   sync.Mutex // Protect pending bytes
+}
+
+/*
+Creates a middleware that encrypts each written message with
+AES-GCM using a pre-shared key. Frames are sent as the base64
+encoding of nonce, ciphertext and tag, followed by the delimiter
+*/
+func NewEncrypted(comm Unicomm, options EncryptOptions) (*Encrypted, error) {
+	if options.Delimiter == "" {
+		options.Delimiter = "\n"
+	}
+	block, err := aes.NewCipher(options.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Encrypted{
+		Unicomm: comm,
+		Options: options,
+		aead:    aead,
+	}, nil
+}
+
+/*
+Encrypts the message into a frame
+*/
+func (e *Encrypted) seal(message []byte) ([]byte, error) {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := e.aead.Seal(nonce, nonce, message, e.Options.AdditionalData)
+	frame := base64.StdEncoding.AppendEncode(nil, sealed)
+	return append(frame, e.Options.Delimiter...), nil
+}
+
+/*
+Decrypts a frame, rejecting frames that fail authentication
+*/
+func (e *Encrypted) open(frame []byte) ([]byte, error) {
+	frame = bytes.TrimSuffix(frame, []byte(e.Options.Delimiter))
+	sealed, err := base64.StdEncoding.AppendDecode(nil, frame)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encrypted frame: %w", err)
+	}
+	nonceSize := e.aead.NonceSize()
+	if len(sealed) < nonceSize+e.aead.Overhead() {
+		return nil, fmt.Errorf("invalid encrypted frame: too short")
+	}
+	message, err := e.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], e.Options.AdditionalData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encrypted frame: %w", err)
+	}
+	return message, nil
+}
+
+/*
+Reads and decrypts the next frame into the pending bytes. Bytes
+received before a timeout are kept until the frame completes,
+must be called with the mutex locked
+*/
+func (e *Encrypted) fill() error {
+	delimiter := []byte(e.Options.Delimiter)
+	if !bytes.Contains(e.raw, delimiter) {
+		data, err := ReadUntil(e.Unicomm, e.Options.Delimiter)
+		e.raw = append(e.raw, data...)
+		if err != nil {
+			return err
+		}
+	}
+	// A delimiter split between two reads ends the frame earlier
+	index := bytes.Index(e.raw, delimiter)
+	frame := e.raw[:index]
+	e.raw = bytes.Clone(e.raw[index+len(delimiter):])
+
+	message, err := e.open(frame)
+	if err != nil {
+		return err
+	}
+	e.pending = append(e.pending, message...)
+	return nil
+}
+
+/*
+Removes and returns the first n decrypted bytes
+*/
+func (e *Encrypted) take(n int) []byte {
+	data := bytes.Clone(e.pending[:n])
+	e.pending = e.pending[n:]
+	return data
+}
+
+/*
+Reads up to n decrypted bytes
+*/
+func (e *Encrypted) Read(n uint) ([]byte, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if len(e.pending) == 0 {
+		if err := e.fill(); err != nil {
+			return nil, err
+		}
+	}
+	return e.take(min(int(n), len(e.pending))), nil
+}
+
+/*
+Reads decrypted data until a target delimiter is found
+*/
+func (e *Encrypted) ReadUntil(delimiter string) ([]byte, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for {
+		if index := bytes.Index(e.pending, []byte(delimiter)); index >= 0 {
+			return e.take(index + len(delimiter)), nil
+		}
+		if err := e.fill(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+/*
+Encrypts and writes an array of bytes as a single frame
+*/
+func (e *Encrypted) Write(message []byte) error {
+	frame, err := e.seal(message)
+	if err != nil {
+		return err
+	}
+	return e.Unicomm.Write(frame)
+}
+
+/*
+Writes an array of bytes as a single frame, which ends with the
+delimiter of the options instead of the one of the backend
+*/
+func (e *Encrypted) WriteRaw(message []byte) error {
+	frame, err := e.seal(message)
+	if err != nil {
+		return err
+	}
+	return writeRaw(e.Unicomm, frame)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (e *Encrypted) Unwrap() Unicomm {
+	return e.Unicomm
+}
+
+/*
+Stops the lookup of data capabilities, which would bypass the
+decryption
+*/
+func (e *Encrypted) transformsData() {}
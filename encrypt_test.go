@@ -0,0 +1,103 @@
+package unicomm_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// The server echoes the encrypted frames back without decrypting them
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buffer := make([]byte, 1024)
+		for {
+			n, err := conn.Read(buffer)
+			if err != nil {
+				return
+			}
+			conn.Write(buffer[:n])
+		}
+	}()
+
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP: unicommtcp.TCPOptions{
+			Host: "127.0.0.1",
+			Port: uint(listener.Addr().(*net.TCPAddr).Port),
+		},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	key := bytes.Repeat([]byte{0x42}, 32)
+	secure, err := unicomm.NewEncrypted(comm, unicomm.EncryptOptions{Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := []byte("SET VALVE 3 OPEN\n\x00\x01")
+	if err := secure.Write(payload); err != nil {
+		t.Fatal(err)
+	}
+	line, err := secure.ReadUntil("\n")
+	if err != nil || string(line) != "SET VALVE 3 OPEN\n" {
+		t.Fatalf("read until returned %q, %v", line, err)
+	}
+	rest, err := secure.Read(16)
+	if err != nil || !bytes.Equal(rest, []byte{0x00, 0x01}) {
+		t.Fatalf("read returned %q, %v", rest, err)
+	}
+}
+
+func TestEncryptedSplitFrame(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+
+	// Frames are sealed through a recording path, to split them
+	path := &failingPath{}
+	sealer, err := unicomm.NewEncrypted(path, unicomm.EncryptOptions{Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealer.Connect()
+	sealer.Write([]byte("first\n"))
+	sealer.Write([]byte("second\n"))
+	link := path.written[0] + path.written[1]
+	split := len(path.written[0]) / 2
+
+	port := serveChunks(t, 150*time.Millisecond, []byte(link[:split]), []byte(link[split:]))
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port, ReadTimeout: 50 * time.Millisecond},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+	secure, err := unicomm.NewEncrypted(comm, unicomm.EncryptOptions{Key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"first\n", "second\n"} {
+		if frame := readSplitFrame(t, secure, "\n"); string(frame) != want {
+			t.Fatalf("got %q, want %q", frame, want)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type EventKind uint8
+
+const (
+	EventConnected    EventKind = 0 // Connect succeeded
+	EventDisconnected EventKind = 1 // Disconnect was called
+	EventState        EventKind = 2 // State of a session or a breaker changed
+	EventError        EventKind = 3 // Operation failed with an error other than a timeout
+)
+
+/*
+Connection event delivered to the subscribers of a bus
+*/
+type Event struct {
+	Kind   EventKind
+	Time   time.Time
+	Source string // Set by the publisher, e.g. the device metadata
+	State  string // Name of the new state, for EventState
+	Err    error  // Failure, for EventError and failed sessions
+}
+
+/*
+Bus delivering connection events to any number of subscribers,
+so the metrics layer and the application can observe the same
+connection without sharing a callback. Publishing never blocks:
+each subscriber has its own queue and goroutine, events beyond
+its capacity are dropped and counted, and panics of a handler
+are recovered without affecting the others
+*/
+type EventBus struct {
+	subscribers map[*Subscription]struct{}
+	closed      bool
+	mutex       sync.RWMutex // Protect the subscribers
+}
+
+type Subscription struct {
+	bus     *EventBus
+	queue   chan Event
+	handler func(Event)
+	dropped atomic.Uint64
+	panics  atomic.Uint64
+	done    chan struct{}
+}
+
+/*
+Returns the name of the event kind
+*/
+func (ek EventKind) String() string {
+	switch ek {
+	case EventConnected:
+		return "connected"
+	case EventDisconnected:
+		return "disconnected"
+	case EventState:
+		return "state"
+	case EventError:
+		return "error"
+	default:
+		return fmt.Sprintf("EventKind(%d)", ek)
+	}
+}
+
+/*
+Creates a bus without subscribers
+*/
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[*Subscription]struct{})}
+}
+
+/*
+Calls the handler with every event published from now on, in
+order, from a goroutine of the subscription. Capacity is the
+number of events queued before they are dropped, defaults to 64
+*/
+func (eb *EventBus) Subscribe(handler func(Event), capacity int) (*Subscription, error) {
+	if capacity <= 0 {
+		capacity = 64
+	}
+	subscription := &Subscription{
+		bus:     eb,
+		queue:   make(chan Event, capacity),
+		handler: handler,
+		done:    make(chan struct{}),
+	}
+
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+
+	if eb.closed {
+		return nil, fmt.Errorf("event bus is closed")
+	}
+	eb.subscribers[subscription] = struct{}{}
+	go subscription.run()
+	return subscription, nil
+}
+
+/*
+Queues the event for every subscriber without waiting for them.
+The time is set when missing
+*/
+func (eb *EventBus) Publish(event Event) {
+	if eb == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	eb.mutex.RLock()
+	defer eb.mutex.RUnlock()
+
+	for subscription := range eb.subscribers {
+		select {
+		case subscription.queue <- event:
+		default:
+			subscription.dropped.Add(1)
+		}
+	}
+}
+
+/*
+Returns the number of subscribers
+*/
+func (eb *EventBus) Len() int {
+	eb.mutex.RLock()
+	defer eb.mutex.RUnlock()
+
+	return len(eb.subscribers)
+}
+
+/*
+Removes every subscriber, after they handle the events already
+queued. Events published afterwards are ignored
+*/
+func (eb *EventBus) Close() {
+	eb.mutex.Lock()
+	subscriptions := make([]*Subscription, 0, len(eb.subscribers))
+	for subscription := range eb.subscribers {
+		subscriptions = append(subscriptions, subscription)
+	}
+	eb.closed = true
+	eb.mutex.Unlock()
+
+	for _, subscription := range subscriptions {
+		subscription.Unsubscribe()
+		<-subscription.done
+	}
+}
+
+/*
+Delivers the queued events until unsubscribed
+*/
+func (s *Subscription) run() {
+	defer close(s.done)
+
+	for event := range s.queue {
+		_, err := guard(func() (any, error) {
+			s.handler(event)
+			return nil, nil
+		})
+		if err != nil {
+			s.panics.Add(1)
+		}
+	}
+}
+
+/*
+Stops the deliveries once the events already queued are handled.
+May be called from the handler
+*/
+func (s *Subscription) Unsubscribe() {
+	s.bus.mutex.Lock()
+	defer s.bus.mutex.Unlock()
+
+	if _, ok := s.bus.subscribers[s]; !ok {
+		return
+	}
+	delete(s.bus.subscribers, s)
+	close(s.queue)
+}
+
+/*
+Returns the number of events dropped because the queue was full
+*/
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+/*
+Returns the number of panics recovered from the handler
+*/
+func (s *Subscription) Panics() uint64 {
+	return s.panics.Load()
+}
+
+/*
+Wrapper publishing the connection events of an instance, along
+with the errors of its operations other than timeouts
+*/
+type Notifier struct {
+	Unicomm
+	Bus    *EventBus
+	Source string // Set in every event published
+}
+
+/*
+Creates a wrapper publishing the events of the instance on the
+bus
+*/
+func NewNotifier(comm Unicomm, bus *EventBus, source string) *Notifier {
+	return &Notifier{
+		Unicomm: comm,
+		Bus:     bus,
+		Source:  source,
+	}
+}
+
+/*
+Publishes an event of the instance
+*/
+func (n *Notifier) publish(kind EventKind, err error) {
+	n.Bus.Publish(Event{Kind: kind, Source: n.Source, Err: err})
+}
+
+/*
+Publishes the error of an operation, ignoring timeouts since
+they are expected while polling
+*/
+func (n *Notifier) check(err error) error {
+	if err != nil && !isTimeout(err) {
+		n.publish(EventError, err)
+	}
+	return err
+}
+
+/*
+Establishes the connection, publishing the result
+*/
+func (n *Notifier) Connect() error {
+	if err := n.Unicomm.Connect(); err != nil {
+		return n.check(err)
+	}
+	n.publish(EventConnected, nil)
+	return nil
+}
+
+/*
+Closes the connection, publishing it
+*/
+func (n *Notifier) Disconnect() error {
+	if err := n.Unicomm.Disconnect(); err != nil {
+		return n.check(err)
+	}
+	n.publish(EventDisconnected, nil)
+	return nil
+}
+
+/*
+Reads a number of bytes
+*/
+func (n *Notifier) Read(size uint) ([]byte, error) {
+	data, err := n.Unicomm.Read(size)
+	return data, n.check(err)
+}
+
+/*
+Reads data until a target delimiter is found
+*/
+func (n *Notifier) ReadUntil(delimiter string) ([]byte, error) {
+	data, err := ReadUntil(n.Unicomm, delimiter)
+	return data, n.check(err)
+}
+
+/*
+Writes an array of bytes
+*/
+func (n *Notifier) Write(message []byte) error {
+	return n.check(n.Unicomm.Write(message))
+}
+
+/*
+Reads everything currently buffered by the instance
+*/
+func (n *Notifier) ReadAvailable() ([]byte, error) {
+	data, err := ReadAvailable(n.Unicomm)
+	return data, n.check(err)
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (n *Notifier) WriteRaw(message []byte) error {
+	return n.check(writeRaw(n.Unicomm, message))
+}
+
+/*
+Returns the wrapped instance
+*/
+func (n *Notifier) Unwrap() Unicomm {
+	return n.Unicomm
+}
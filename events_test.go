@@ -0,0 +1,100 @@
+package unicomm_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestEventBusDeliversToEverySubscriber(t *testing.T) {
+	bus := unicomm.NewEventBus()
+	defer bus.Close()
+
+	var mutex sync.Mutex
+	var kinds []unicomm.EventKind
+	metrics, err := bus.Subscribe(func(event unicomm.Event) {
+		mutex.Lock()
+		kinds = append(kinds, event.Kind)
+		mutex.Unlock()
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	faulty, _ := bus.Subscribe(func(unicomm.Event) { panic("broken handler") }, 0)
+
+	// A stuck subscriber must not block the publishers
+	release := make(chan struct{})
+	stuck, _ := bus.Subscribe(func(unicomm.Event) { <-release }, 1)
+	defer close(release)
+
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      server.TCPOptions(),
+		Events:   bus,
+		Metadata: unicomm.Metadata{DeviceID: "psu-1"},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if err := comm.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	if err := comm.Write([]byte("PING\n")); err == nil {
+		t.Fatal("write while disconnected did not fail")
+	}
+
+	expected := []unicomm.EventKind{unicomm.EventConnected, unicomm.EventDisconnected, unicomm.EventError}
+	deadline := time.Now().Add(time.Second)
+	for {
+		mutex.Lock()
+		received := len(kinds)
+		mutex.Unlock()
+		if (received == len(expected) && faulty.Panics() == 3) || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	for index, kind := range expected {
+		if index >= len(kinds) || kinds[index] != kind {
+			t.Fatalf("got events %v, want %v", kinds, expected)
+		}
+	}
+	if metrics.Dropped() != 0 || metrics.Panics() != 0 {
+		t.Fatalf("metrics subscriber dropped %d and panicked %d times", metrics.Dropped(), metrics.Panics())
+	}
+	if faulty.Panics() != 3 {
+		t.Fatalf("recovered %d panics, want 3", faulty.Panics())
+	}
+	if stuck.Dropped() == 0 {
+		t.Fatal("stuck subscriber did not drop events")
+	}
+}
+
+func TestEventBusUnsubscribe(t *testing.T) {
+	bus := unicomm.NewEventBus()
+	defer bus.Close()
+
+	received := make(chan unicomm.Event, 4)
+	subscription, _ := bus.Subscribe(func(event unicomm.Event) { received <- event }, 0)
+	bus.Publish(unicomm.Event{Kind: unicomm.EventState, State: "ready"})
+	subscription.Unsubscribe()
+	bus.Publish(unicomm.Event{Kind: unicomm.EventState, State: "stopped"})
+
+	if event := <-received; event.State != "ready" || event.Time.IsZero() {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	select {
+	case event := <-received:
+		t.Fatalf("received %+v after unsubscribing", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if bus.Len() != 0 {
+		t.Fatalf("bus has %d subscribers, want 0", bus.Len())
+	}
+}
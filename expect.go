@@ -0,0 +1,111 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+type ExpectOptions struct {
+	LineEnding    string // Appended to each line sent
+	Pager         string // Pagination marker, e.g. "--More--"
+	PagerResponse string // Sent to continue the pagination
+	ChunkSize     uint   // Bytes requested on each read
+}
+
+type Expect struct {
+	Options ExpectOptions
+
+	comm   Unicomm
+	buffer []byte
+	mutex  sync.Mutex // Protect received buffer
+}
+
+/*
+Creates a prompt-aware helper to interact with CLI-based
+devices over any Unicomm instance
+*/
+func NewExpect(comm Unicomm, options ExpectOptions) *Expect {
+	if options.LineEnding == "" {
+		options.LineEnding = "\r\n"
+	}
+	if options.PagerResponse == "" {
+		options.PagerResponse = " "
+	}
+	if options.ChunkSize == 0 {
+		options.ChunkSize = 256
+	}
+	return &Expect{
+		Options: options,
+		comm:    comm,
+	}
+}
+
+/*
+Sends a line followed by the line ending
+*/
+func (e *Expect) Send(line string) error {
+	return e.comm.Write([]byte(line + e.Options.LineEnding))
+}
+
+/*
+Waits until the output matches the regular expression and
+returns everything received before the match. Pagination
+markers are answered and removed from the output. On timeout
+the output received so far is returned with an error
+*/
+func (e *Expect) Expect(pattern string, timeout time.Duration) (string, error) {
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		if e.Options.Pager != "" {
+			if index := bytes.Index(e.buffer, []byte(e.Options.Pager)); index >= 0 {
+				e.buffer = append(e.buffer[:index], e.buffer[index+len(e.Options.Pager):]...)
+				if err := e.comm.Write([]byte(e.Options.PagerResponse)); err != nil {
+					return "", err
+				}
+			}
+		}
+		if match := expression.FindIndex(e.buffer); match != nil {
+			output := string(e.buffer[:match[0]])
+			e.buffer = e.buffer[match[1]:]
+			return output, nil
+		}
+		if time.Now().After(deadline) {
+			output := string(e.buffer)
+			e.buffer = nil
+			return output, fmt.Errorf("expect %q: %w", pattern, ErrTimeout)
+		}
+
+		data, err := e.comm.Read(e.Options.ChunkSize)
+		if err != nil && !isTimeout(err) {
+			return string(e.buffer), err
+		}
+		e.buffer = append(e.buffer, data...)
+	}
+}
+
+/*
+Sends a command and captures its output until the prompt
+*/
+func (e *Expect) Command(line string, prompt string, timeout time.Duration) (string, error) {
+	if err := e.Send(line); err != nil {
+		return "", err
+	}
+	return e.Expect(prompt, timeout)
+}
@@ -0,0 +1,54 @@
+package unicomm_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+func TestExpectPagination(t *testing.T) {
+	port := serveOnce(t, []byte("show version\r\nline 1\r\n--More--line 2\r\nswitch# "))
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	console := unicomm.NewExpect(comm, unicomm.ExpectOptions{Pager: "--More--"})
+	output, err := console.Command("show version", `\w+# $`, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(output, "--More--") || !strings.HasSuffix(output, "line 1\r\nline 2\r\n") {
+		t.Fatalf("unexpected output %q", output)
+	}
+}
+
+func TestExpectTimeout(t *testing.T) {
+	port := serveOnce(t, []byte("login: "))
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	// The output received so far is returned with a timeout
+	console := unicomm.NewExpect(comm, unicomm.ExpectOptions{})
+	output, err := console.Expect(`\w+# $`, 200*time.Millisecond)
+	if !errors.Is(err, unicomm.ErrTimeout) || !unicomm.IsTimeout(err) {
+		t.Fatalf("Expect = %v, want a timeout", err)
+	}
+	if output != "login: " {
+		t.Fatalf("output = %q, want the partial output", output)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+type FailoverOptions struct {
+	Failback         bool               // Return to the primary when it recovers
+	FailbackInterval time.Duration      // Time between attempts to return
+	OnSwitch         func(primary bool) // Called when the active path changes
+}
+
+type Failover struct {
+	Options FailoverOptions
+
+	paths     [2]Unicomm // Primary and secondary paths
+	active    int
+	lastProbe time.Time
+	mutex     sync.Mutex // Protect active path
+}
+
+/*
+Creates a logical device reachable through two transports,
+e.g. a console cable and an Ethernet bridge, switching to the
+other path when the active one goes down
+*/
+func NewFailover(primary, secondary Unicomm, options FailoverOptions) *Failover {
+	if options.FailbackInterval == 0 {
+		options.FailbackInterval = 30 * time.Second
+	}
+	return &Failover{
+		Options: options,
+		paths:   [2]Unicomm{primary, secondary},
+	}
+}
+
+/*
+Returns true if the primary path is active
+*/
+func (f *Failover) IsPrimary() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.active == 0
+}
+
+/*
+Activates the path, must be called with the mutex locked
+*/
+func (f *Failover) activate(index int) {
+	if f.active == index {
+		return
+	}
+	f.active = index
+	if f.Options.OnSwitch != nil {
+		f.Options.OnSwitch(index == 0)
+	}
+}
+
+/*
+Connects the other path and activates it, must be called with
+the mutex locked
+*/
+func (f *Failover) switchPath() error {
+	other := 1 - f.active
+	if !f.paths[other].IsConnected() {
+		if err := f.paths[other].Connect(); err != nil {
+			return err
+		}
+	}
+	f.activate(other)
+	f.lastProbe = time.Now()
+	return nil
+}
+
+/*
+Returns to the primary path if it recovered, must be called
+with the mutex locked
+*/
+func (f *Failover) failback() {
+	if !f.Options.Failback || f.active == 0 {
+		return
+	}
+	if time.Since(f.lastProbe) < f.Options.FailbackInterval {
+		return
+	}
+	f.lastProbe = time.Now()
+	if f.paths[0].IsConnected() || f.paths[0].Connect() == nil {
+		if f.paths[1].IsConnected() {
+			f.paths[1].Disconnect()
+		}
+		f.activate(0)
+	}
+}
+
+/*
+Runs the operation on the active path, switching paths and
+retrying once when the active path is down. Writes interrupted
+after reaching the device are not repeated on the other path,
+which would deliver the message twice
+*/
+func (f *Failover) do(operation func(path Unicomm) error) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.failback()
+	err := operation(f.paths[f.active])
+	if err == nil || f.paths[f.active].IsConnected() {
+		return err
+	}
+	var partial *unicommio.PartialWriteError
+	if errors.As(err, &partial) && partial.Written > 0 {
+		return err
+	}
+	if switchErr := f.switchPath(); switchErr != nil {
+		return errors.Join(err, switchErr)
+	}
+	return operation(f.paths[f.active])
+}
+
+/*
+Connects the primary path, or the secondary if the primary
+is not reachable
+*/
+func (f *Failover) Connect() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	primaryErr := f.paths[0].Connect()
+	if primaryErr == nil {
+		f.activate(0)
+		return nil
+	}
+	if err := f.paths[1].Connect(); err != nil {
+		return fmt.Errorf("both paths failed: %w", errors.Join(primaryErr, err))
+	}
+	f.activate(1)
+	f.lastProbe = time.Now()
+	return nil
+}
+
+/*
+Closes the connected paths
+*/
+func (f *Failover) Disconnect() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var errs []error
+	for _, path := range f.paths {
+		if path.IsConnected() {
+			errs = append(errs, path.Disconnect())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+/*
+Returns true if the active path is connected
+*/
+func (f *Failover) IsConnected() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.paths[f.active].IsConnected()
+}
+
+/*
+Reads a number of bytes from the active path
+*/
+func (f *Failover) Read(n uint) ([]byte, error) {
+	var data []byte
+	err := f.do(func(path Unicomm) (err error) {
+		data, err = path.Read(n)
+		return err
+	})
+	return data, err
+}
+
+/*
+Reads data until a target delimiter is found on the active
+path
+*/
+func (f *Failover) ReadUntil(delimiter string) ([]byte, error) {
+	var data []byte
+	err := f.do(func(path Unicomm) (err error) {
+		data, err = ReadUntil(path, delimiter)
+		return err
+	})
+	return data, err
+}
+
+/*
+Writes an array of bytes to the active path
+*/
+func (f *Failover) Write(message []byte) error {
+	return f.do(func(path Unicomm) error {
+		return path.Write(message)
+	})
+}
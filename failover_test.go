@@ -0,0 +1,88 @@
+package unicomm_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+/*
+Path recording its writes, which fail with the configured error
+and drop the connection
+*/
+type failingPath struct {
+	connected bool
+	writeErr  error
+	written   []string
+}
+
+func (fp *failingPath) Connect() error                 { fp.connected = true; return nil }
+func (fp *failingPath) Disconnect() error              { fp.connected = false; return nil }
+func (fp *failingPath) IsConnected() bool              { return fp.connected }
+func (fp *failingPath) Read(size uint) ([]byte, error) { return nil, nil }
+
+func (fp *failingPath) Write(message []byte) error {
+	if !fp.connected {
+		return errors.New("there is no connection established")
+	}
+	if fp.writeErr != nil {
+		fp.connected = false
+		return fp.writeErr
+	}
+	fp.written = append(fp.written, string(message))
+	return nil
+}
+
+func TestFailoverSwitchesPath(t *testing.T) {
+	primary, secondary := &failingPath{}, &failingPath{}
+	var switches []bool
+	failover := unicomm.NewFailover(primary, secondary, unicomm.FailoverOptions{
+		OnSwitch: func(isPrimary bool) { switches = append(switches, isPrimary) },
+	})
+	if err := failover.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing reached the device, so the write is retried
+	primary.writeErr = &unicommio.PartialWriteError{Written: 0, Expected: 5, Err: syscall.EPIPE}
+	if err := failover.Write([]byte("OUT1\n")); err != nil {
+		t.Fatal(err)
+	}
+	if failover.IsPrimary() || len(secondary.written) != 1 {
+		t.Fatalf("write was not retried on the secondary path, wrote %q", secondary.written)
+	}
+	if len(switches) != 1 || switches[0] {
+		t.Fatalf("switches = %v, want one to the secondary path", switches)
+	}
+}
+
+func TestFailoverKeepsPartialWrite(t *testing.T) {
+	primary, secondary := &failingPath{}, &failingPath{}
+	failover := unicomm.NewFailover(primary, secondary, unicomm.FailoverOptions{})
+	if err := failover.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Part of the message reached the device through the primary path
+	primary.writeErr = &unicommio.PartialWriteError{Written: 3, Expected: 5, Err: syscall.EPIPE}
+	err := failover.Write([]byte("OUT1\n"))
+	var partial *unicommio.PartialWriteError
+	if !errors.As(err, &partial) || partial.Written != 3 {
+		t.Fatalf("Write = %v, want the partial write of the primary path", err)
+	}
+	if len(secondary.written) != 0 {
+		t.Fatalf("partial write repeated on the secondary path: %q", secondary.written)
+	}
+
+	// The next operation finds the primary path down and switches
+	primary.writeErr = nil
+	if err := failover.Write([]byte("OUT2\n")); err != nil {
+		t.Fatal(err)
+	}
+	if failover.IsPrimary() || len(secondary.written) != 1 || secondary.written[0] != "OUT2\n" {
+		t.Fatalf("next write was not sent on the secondary path, wrote %q", secondary.written)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"regexp"
+)
+
+/*
+Returns true if the frame matches a filter expression
+*/
+type FrameMatcher func(frame []byte) bool
+
+/*
+Matches frames starting with the prefix, e.g. "$GPRMC"
+*/
+func MatchPrefix(prefix string) FrameMatcher {
+	return func(frame []byte) bool {
+		return bytes.HasPrefix(frame, []byte(prefix))
+	}
+}
+
+/*
+Matches frames containing a match of the regular expression
+*/
+func MatchRegexp(expression *regexp.Regexp) FrameMatcher {
+	return expression.Match
+}
+
+/*
+Matches frames whose bytes at the offset, masked bitwise with the
+mask, equal the value. Useful for binary frames identified by
+flags or type fields
+*/
+func MatchMask(offset int, mask []byte, value []byte) FrameMatcher {
+	return func(frame []byte) bool {
+		if offset < 0 || len(mask) != len(value) || len(frame) < offset+len(mask) {
+			return false
+		}
+		for index := range mask {
+			if frame[offset+index]&mask[index] != value[index] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+/*
+Returns true if the frame passes the include and exclude filters.
+A frame is kept when it matches any include filter, or there are
+none, and does not match any exclude filter
+*/
+func passFilters(frame []byte, include []FrameMatcher, exclude []FrameMatcher) bool {
+	for _, match := range exclude {
+		if match(frame) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, match := range include {
+		if match(frame) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+type GapOptions struct {
+	Silence    time.Duration // Gap ending a frame, e.g. SerialOptions.ModbusSilence()
+	Resolution time.Duration // Poll period, defaults to a quarter of the silence
+	Timeout    time.Duration // Wait for the first byte of a frame, defaults to 1s
+}
+
+/*
+Bytes received together, along with when they were seen and
+the gap since the previous chunk
+*/
+type TimedChunk struct {
+	Data    []byte
+	Arrived time.Time
+	Gap     time.Duration // Zero for the first chunk
+}
+
+type GapFramed struct {
+	Unicomm
+	Options GapOptions
+
+	last  time.Time  // Arrival of the previous chunk
+	mutex sync.Mutex // Keep one poll loop at a time
+}
+
+/*
+Creates a wrapper that timestamps received bytes and splits
+frames on silence periods, for protocols without delimiters
+such as Modbus RTU. The instance is polled with ReadAvailable,
+so timestamps are as precise as the resolution and the platform
+timers allow. Frames must be read continuously, since bytes
+accumulated between reads cannot be told apart
+*/
+func NewGapFramed(comm Unicomm, options GapOptions) *GapFramed {
+	if options.Resolution == 0 {
+		options.Resolution = max(options.Silence/4, 100*time.Microsecond)
+	}
+	if options.Timeout == 0 {
+		options.Timeout = time.Second
+	}
+	return &GapFramed{
+		Unicomm: comm,
+		Options: options,
+	}
+}
+
+/*
+Polls the instance once, returning the received bytes with
+their timestamps. Must be called with the mutex locked
+*/
+func (g *GapFramed) poll() (TimedChunk, error) {
+	data, err := ReadAvailable(g.Unicomm)
+	if err != nil || len(data) == 0 {
+		return TimedChunk{}, err
+	}
+	chunk := TimedChunk{Data: data, Arrived: time.Now()}
+	if !g.last.IsZero() {
+		chunk.Gap = chunk.Arrived.Sub(g.last)
+	}
+	g.last = chunk.Arrived
+	return chunk, nil
+}
+
+/*
+Returns the next bytes received with their arrival time and the
+gap since the previous ones, waiting for them up to the timeout
+*/
+func (g *GapFramed) ReadTimed() (TimedChunk, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	deadline := time.Now().Add(g.Options.Timeout)
+	for {
+		chunk, err := g.poll()
+		if err != nil || len(chunk.Data) > 0 {
+			return chunk, err
+		}
+		if !time.Now().Before(deadline) {
+			return chunk, unicommio.TimeoutError("read timed")
+		}
+		time.Sleep(g.Options.Resolution)
+	}
+}
+
+/*
+Reads the next frame, which ends when the line stays silent for
+the silence period after its last byte
+*/
+func (g *GapFramed) ReadFrame() (Frame, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	var frame Frame
+	deadline := time.Now().Add(g.Options.Timeout)
+	for {
+		chunk, err := g.poll()
+		if err != nil {
+			return frame, err
+		}
+		if len(chunk.Data) > 0 {
+			if frame.Started.IsZero() {
+				frame.Started = chunk.Arrived
+			}
+			frame.Raw = append(frame.Raw, chunk.Data...)
+			frame.Received = chunk.Arrived
+			continue
+		}
+
+		if frame.Started.IsZero() {
+			if !time.Now().Before(deadline) {
+				return frame, unicommio.TimeoutError("read frame")
+			}
+		} else if time.Since(frame.Received) >= g.Options.Silence {
+			frame.Payload = frame.Raw
+			return frame, nil
+		}
+		time.Sleep(g.Options.Resolution)
+	}
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (g *GapFramed) WriteRaw(message []byte) error {
+	return writeRaw(g.Unicomm, message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (g *GapFramed) Unwrap() Unicomm {
+	return g.Unicomm
+}
+
+/*
+Stops the lookup of data capabilities, which would bypass the
+buffered frames
+*/
+func (g *GapFramed) transformsData() {}
@@ -0,0 +1,65 @@
+package unicomm_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+func TestGapFramedReadFrame(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// Two frames without delimiters, the first one sent in two bursts
+		for _, burst := range []struct {
+			data  string
+			pause time.Duration
+		}{{"\x01\x03", 5 * time.Millisecond}, {"\x00\x10", 80 * time.Millisecond}, {"\x01\x06", time.Second}} {
+			conn.Write([]byte(burst.data))
+			time.Sleep(burst.pause)
+		}
+	}()
+
+	comm := unicomm.NewGapFramed(unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: uint(listener.Addr().(*net.TCPAddr).Port)},
+	}), unicomm.GapOptions{Silence: 30 * time.Millisecond})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	for _, expected := range []string{"\x01\x03\x00\x10", "\x01\x06"} {
+		frame, err := comm.ReadFrame()
+		if err != nil || string(frame.Payload) != expected {
+			t.Fatalf("read frame returned %q, %v", frame.Payload, err)
+		}
+		if frame.Started.IsZero() || frame.Received.Before(frame.Started) {
+			t.Fatalf("unexpected timestamps %v and %v", frame.Started, frame.Received)
+		}
+	}
+}
+
+func TestModbusSilence(t *testing.T) {
+	// 9600 8E1 takes 11 bits per character
+	options := unicommserial.SerialOptions{BaudRate: 9600, Parity: unicommserial.EvenParity}
+	if silence := options.ModbusSilence(); silence < 4*time.Millisecond || silence > 4100*time.Microsecond {
+		t.Fatalf("silence at 9600 baud is %v", silence)
+	}
+	options.BaudRate = 115200
+	if silence := options.ModbusSilence(); silence != 1750*time.Microsecond {
+		t.Fatalf("silence at 115200 baud is %v", silence)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+)
+
+type Metadata struct {
+	DeviceID string
+	Site     string
+	Labels   map[string]string
+}
+
+type Identified struct {
+	Unicomm
+	metadata Metadata
+}
+
+/*
+Returns true if no metadata was configured
+*/
+func (m Metadata) IsZero() bool {
+	return m.DeviceID == "" && m.Site == "" && len(m.Labels) == 0
+}
+
+/*
+Formats the metadata as key=value pairs, with labels sorted
+by key
+*/
+func (m Metadata) String() string {
+	fields := make([]string, 0, len(m.Labels)+2)
+	if m.DeviceID != "" {
+		fields = append(fields, "device="+m.DeviceID)
+	}
+	if m.Site != "" {
+		fields = append(fields, "site="+m.Site)
+	}
+	for _, key := range slices.Sorted(maps.Keys(m.Labels)) {
+		fields = append(fields, key+"="+m.Labels[key])
+	}
+	return strings.Join(fields, " ")
+}
+
+/*
+Allows the metadata to be logged as a group with slog
+*/
+func (m Metadata) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(m.Labels)+2)
+	if m.DeviceID != "" {
+		attrs = append(attrs, slog.String("device", m.DeviceID))
+	}
+	if m.Site != "" {
+		attrs = append(attrs, slog.String("site", m.Site))
+	}
+	for _, key := range slices.Sorted(maps.Keys(m.Labels)) {
+		attrs = append(attrs, slog.String(key, m.Labels[key]))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+/*
+Attaches metadata to a Unicomm instance. Errors returned by
+the operations are prefixed with the device identity
+*/
+func NewIdentified(comm Unicomm, metadata Metadata) *Identified {
+	return &Identified{
+		Unicomm:  comm,
+		metadata: metadata,
+	}
+}
+
+/*
+Returns the metadata of a Unicomm instance, if it or one of
+the instances it wraps has any
+*/
+func MetadataOf(comm Unicomm) (Metadata, bool) {
+	if identified, ok := As[interface{ Metadata() Metadata }](comm); ok {
+		return identified.Metadata(), true
+	}
+	return Metadata{}, false
+}
+
+/*
+Returns the metadata attached to the instance
+*/
+func (i *Identified) Metadata() Metadata {
+	return i.metadata
+}
+
+/*
+Prefixes the error with the device identity
+*/
+func (i *Identified) wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("[%s] %w", i.metadata, err)
+}
+
+/*
+Establishes the connection
+*/
+func (i *Identified) Connect() error {
+	return i.wrap(i.Unicomm.Connect())
+}
+
+/*
+Closes the connection
+*/
+func (i *Identified) Disconnect() error {
+	return i.wrap(i.Unicomm.Disconnect())
+}
+
+/*
+Reads a number of bytes
+*/
+func (i *Identified) Read(n uint) ([]byte, error) {
+	data, err := i.Unicomm.Read(n)
+	return data, i.wrap(err)
+}
+
+/*
+Reads data until a target delimiter is found
+*/
+func (i *Identified) ReadUntil(delimiter string) ([]byte, error) {
+	data, err := ReadUntil(i.Unicomm, delimiter)
+	return data, i.wrap(err)
+}
+
+/*
+Writes an array of bytes
+*/
+func (i *Identified) Write(message []byte) error {
+	return i.wrap(i.Unicomm.Write(message))
+}
+
+/*
+Reads everything currently buffered by the instance
+*/
+func (i *Identified) ReadAvailable() ([]byte, error) {
+	data, err := ReadAvailable(i.Unicomm)
+	return data, i.wrap(err)
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (i *Identified) WriteRaw(message []byte) error {
+	return i.wrap(writeRaw(i.Unicomm, message))
+}
+
+/*
+Returns the wrapped instance
+*/
+func (i *Identified) Unwrap() Unicomm {
+	return i.Unicomm
+}
@@ -0,0 +1,46 @@
+package unicomm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestIdentifiedWrapped(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	metadata := unicomm.Metadata{DeviceID: "pump-07", Site: "plant-north", Labels: map[string]string{"line": "2"}}
+	comm := unicomm.NewBuffered(unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      server.TCPOptions(),
+		Metadata: metadata,
+	}))
+
+	// The identity is found under the wrappers, as the backend under it
+	identified, ok := unicomm.As[*unicomm.Identified](comm)
+	if !ok || identified.Metadata().String() != metadata.String() {
+		t.Fatal("identified instance not found through the wrapper")
+	}
+	if _, ok := unicomm.As[*unicommtcp.UnicommTCP](comm); !ok {
+		t.Fatal("backend not found through the identified instance")
+	}
+	got, ok := unicomm.MetadataOf(comm)
+	if !ok || got.String() != "device=pump-07 site=plant-north line=2" {
+		t.Fatalf("MetadataOf = %q, %t", got, ok)
+	}
+	if _, ok := unicomm.MetadataOf(unicomm.NewBuffered(unicommtcp.NewTCP(server.TCPOptions()))); ok {
+		t.Fatal("metadata found on an instance without identity")
+	}
+
+	// Errors returned through the outer wrapper carry the identity
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+	_, err := comm.ReadUntil("\n")
+	if !unicomm.IsTimeout(err) || !strings.HasPrefix(err.Error(), "[device=pump-07 site=plant-north line=2] ") {
+		t.Fatalf("ReadUntil = %v, want a timeout prefixed with the identity", err)
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type IdleCloser struct {
+	Unicomm
+	Timeout time.Duration
+
+	active     bool   // Connection was requested by the user
+	generation uint64 // Identifies the current inactivity timer
+	timer      *time.Timer
+	mutex      sync.Mutex // Protect connection state
+}
+
+/*
+Creates a wrapper that closes the connection after a period
+without operations and reconnects on the next operation
+*/
+func NewIdleCloser(comm Unicomm, timeout time.Duration) *IdleCloser {
+	return &IdleCloser{
+		Unicomm: comm,
+		Timeout: timeout,
+	}
+}
+
+/*
+Closes the underlying connection if it is still idle
+*/
+func (ic *IdleCloser) closeIdle(generation uint64) {
+	ic.mutex.Lock()
+	defer ic.mutex.Unlock()
+
+	if ic.timer == nil || generation != ic.generation {
+		return
+	}
+	ic.timer = nil
+	if ic.Unicomm.IsConnected() {
+		ic.Unicomm.Disconnect()
+	}
+}
+
+/*
+Restarts the inactivity timer, must be called with the
+mutex locked
+*/
+func (ic *IdleCloser) touch() {
+	if ic.timer != nil {
+		ic.timer.Stop()
+	}
+	ic.generation++
+	generation := ic.generation
+	ic.timer = time.AfterFunc(ic.Timeout, func() {
+		ic.closeIdle(generation)
+	})
+}
+
+/*
+Reconnects if the connection was closed due to inactivity
+and runs the operation
+*/
+func (ic *IdleCloser) do(operation func() error) error {
+	ic.mutex.Lock()
+	defer ic.mutex.Unlock()
+
+	if !ic.active {
+		return fmt.Errorf("there is no connection established")
+	}
+	if !ic.Unicomm.IsConnected() {
+		if err := ic.Unicomm.Connect(); err != nil {
+			return err
+		}
+	}
+	defer ic.touch()
+	return operation()
+}
+
+/*
+Establishes the connection and starts monitoring inactivity
+*/
+func (ic *IdleCloser) Connect() error {
+	ic.mutex.Lock()
+	defer ic.mutex.Unlock()
+
+	if err := ic.Unicomm.Connect(); err != nil {
+		return err
+	}
+	ic.active = true
+	ic.touch()
+	return nil
+}
+
+/*
+Closes the connection, if it was not closed due to inactivity
+*/
+func (ic *IdleCloser) Disconnect() error {
+	ic.mutex.Lock()
+	defer ic.mutex.Unlock()
+
+	if !ic.active {
+		return fmt.Errorf("there is no connection established")
+	}
+	ic.active = false
+	if ic.timer != nil {
+		ic.timer.Stop()
+		ic.timer = nil
+	}
+	if ic.Unicomm.IsConnected() {
+		return ic.Unicomm.Disconnect()
+	}
+	return nil
+}
+
+/*
+Returns true if the connection was established by the user,
+even when it is temporarily closed due to inactivity
+*/
+func (ic *IdleCloser) IsConnected() bool {
+	ic.mutex.Lock()
+	defer ic.mutex.Unlock()
+
+	return ic.active
+}
+
+/*
+Reads a number of bytes, reconnecting if needed
+*/
+func (ic *IdleCloser) Read(n uint) ([]byte, error) {
+	var data []byte
+	err := ic.do(func() (err error) {
+		data, err = ic.Unicomm.Read(n)
+		return err
+	})
+	return data, err
+}
+
+/*
+Reads data until a target delimiter is found, reconnecting
+if needed
+*/
+func (ic *IdleCloser) ReadUntil(delimiter string) ([]byte, error) {
+	var data []byte
+	err := ic.do(func() (err error) {
+		data, err = ReadUntil(ic.Unicomm, delimiter)
+		return err
+	})
+	return data, err
+}
+
+/*
+Writes an array of bytes, reconnecting if needed
+*/
+func (ic *IdleCloser) Write(message []byte) error {
+	return ic.do(func() error {
+		return ic.Unicomm.Write(message)
+	})
+}
+
+/*
+Reads everything currently buffered, reconnecting if needed
+*/
+func (ic *IdleCloser) ReadAvailable() ([]byte, error) {
+	var data []byte
+	err := ic.do(func() (err error) {
+		data, err = ReadAvailable(ic.Unicomm)
+		return err
+	})
+	return data, err
+}
+
+/*
+Writes an array of bytes without the end delimiter, reconnecting if needed
+*/
+func (ic *IdleCloser) WriteRaw(message []byte) error {
+	return ic.do(func() error {
+		return writeRaw(ic.Unicomm, message)
+	})
+}
+
+/*
+Returns the wrapped instance
+*/
+func (ic *IdleCloser) Unwrap() Unicomm {
+	return ic.Unicomm
+}
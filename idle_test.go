@@ -0,0 +1,128 @@
+package unicomm_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+)
+
+/*
+Instance counting its connections and recording its writes,
+safe to share with the timers of the wrappers
+*/
+type countingConn struct {
+	mutex     sync.Mutex
+	connected bool
+	connects  int
+	writes    []string
+	writeErr  error
+}
+
+func (cc *countingConn) Connect() error {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	cc.connected = true
+	cc.connects++
+	return nil
+}
+
+func (cc *countingConn) Disconnect() error {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	cc.connected = false
+	return nil
+}
+
+func (cc *countingConn) IsConnected() bool {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	return cc.connected
+}
+
+func (cc *countingConn) Read(size uint) ([]byte, error) { return nil, nil }
+
+func (cc *countingConn) Write(message []byte) error {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	if cc.writeErr != nil {
+		return cc.writeErr
+	}
+	cc.writes = append(cc.writes, string(message))
+	return nil
+}
+
+func (cc *countingConn) written() []string {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	return slices.Clone(cc.writes)
+}
+
+func (cc *countingConn) failWrites(err error) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	cc.writeErr = err
+}
+
+func (cc *countingConn) state() (bool, int) {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	return cc.connected, cc.connects
+}
+
+func TestIdleCloser(t *testing.T) {
+	inner := &countingConn{}
+	comm := unicomm.NewIdleCloser(inner, 100*time.Millisecond)
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Operations keep the connection open past the timeout
+	for range 4 {
+		time.Sleep(30 * time.Millisecond)
+		if err := comm.Write([]byte("PING\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if connected, connects := inner.state(); !connected || connects != 1 {
+		t.Fatalf("connected %t after %d connects, want the first one kept", connected, connects)
+	}
+
+	// Idle, it is closed while still reported as connected
+	time.Sleep(250 * time.Millisecond)
+	if connected, _ := inner.state(); connected {
+		t.Fatal("idle connection was not closed")
+	}
+	if !comm.IsConnected() {
+		t.Fatal("expected the idle closer to stay connected for the user")
+	}
+
+	// The next operation reconnects
+	if err := comm.Write([]byte("PING\n")); err != nil {
+		t.Fatal(err)
+	}
+	if connected, connects := inner.state(); !connected || connects != 2 {
+		t.Fatalf("connected %t after %d connects, want a reconnection", connected, connects)
+	}
+
+	// Disconnect stops the timer, which never connects again
+	if err := comm.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if connected, connects := inner.state(); connected || connects != 2 {
+		t.Fatalf("connected %t after %d connects once disconnected", connected, connects)
+	}
+	if err := comm.Write([]byte("PING\n")); err == nil {
+		t.Fatal("expected writes to fail once disconnected")
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+type JournalStatus uint8
+
+const (
+	JournalPending   JournalStatus = 0 // Not written yet, replayed on reconnect
+	JournalConfirmed JournalStatus = 1 // Written and confirmed by the device
+	JournalFailed    JournalStatus = 2 // Last attempt failed, replayed on reconnect
+)
+
+/*
+A configuration command of the journal. Commands must be
+idempotent, since they may be written again on every reconnect
+until confirmed
+*/
+type JournalEntry struct {
+	Key         string // Setting changed by the command, a newer entry replaces the one of the same key
+	Command     string
+	VerifyQuery string // Query confirming the command, empty confirms it once written
+	Expected    string // Response of the query, compared as in VerifiedWrite
+	Status      JournalStatus
+	Attempts    int       // Times the command was applied
+	Err         error     // Last failure, nil once confirmed
+	Updated     time.Time // Last attempt
+}
+
+type JournalOptions struct {
+	Delimiter       string             // Ends the responses to the verify queries, defaults to "\n"
+	Verify          VerifyOptions      // Attempts of each write before it fails
+	ReplayConfirmed bool               // Replays the confirmed entries too, for devices losing their configuration on power cycles
+	OnReplay        func(JournalEntry) // Called with every entry replayed on reconnect
+}
+
+/*
+Wrapper keeping a journal of the configuration commands and
+whether the device confirmed them. After every successful connect,
+the entries not confirmed are written again in order, so the
+device returns to the intended state after a reconnect or a
+power cycle
+*/
+type Journal struct {
+	Unicomm
+	Options JournalOptions
+
+	client  *Client
+	entries []*JournalEntry // In the order their keys were first applied
+	mutex   sync.Mutex      // Protect the entries and keep replays in order
+}
+
+/*
+Returns the name of the status
+*/
+func (js JournalStatus) String() string {
+	switch js {
+	case JournalPending:
+		return "pending"
+	case JournalConfirmed:
+		return "confirmed"
+	case JournalFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("JournalStatus(%d)", js)
+	}
+}
+
+/*
+Creates a journal over a Unicomm instance
+*/
+func NewJournal(comm Unicomm, options JournalOptions) *Journal {
+	if options.Delimiter == "" {
+		options.Delimiter = "\n"
+	}
+	return &Journal{
+		Unicomm: comm,
+		Options: options,
+		client:  NewClient(comm, options.Delimiter),
+	}
+}
+
+/*
+Records the entry and applies it to the device. The entry stays
+in the journal when the write fails, and is replayed on the next
+connect
+*/
+func (j *Journal) Apply(entry JournalEntry) error {
+	if entry.Key == "" || entry.Command == "" {
+		return fmt.Errorf("invalid journal entry: key and command are required")
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	entry.Status = JournalPending
+	entry.Attempts = 0
+	entry.Err = nil
+	index := slices.IndexFunc(j.entries, func(recorded *JournalEntry) bool {
+		return recorded.Key == entry.Key
+	})
+	if index >= 0 {
+		*j.entries[index] = entry
+	} else {
+		j.entries = append(j.entries, &entry)
+		index = len(j.entries) - 1
+	}
+
+	if !j.Unicomm.IsConnected() {
+		return fmt.Errorf("there is no connection established, %q is replayed on connect", entry.Key)
+	}
+	return j.apply(j.entries[index])
+}
+
+/*
+Writes an entry and confirms it, updating its status. Must be
+called with the mutex locked
+*/
+func (j *Journal) apply(entry *JournalEntry) error {
+	entry.Attempts++
+	entry.Updated = time.Now()
+
+	var err error
+	if entry.VerifyQuery == "" {
+		err = j.client.Unicomm.Write([]byte(entry.Command))
+	} else {
+		err = j.client.VerifiedWrite(entry.Command, entry.VerifyQuery, entry.Expected, j.Options.Verify)
+	}
+	entry.Err = err
+	if err != nil {
+		entry.Status = JournalFailed
+		return fmt.Errorf("journal entry %q failed: %w", entry.Key, err)
+	}
+	entry.Status = JournalConfirmed
+	return nil
+}
+
+/*
+Writes the entries not confirmed again, in order, or every entry
+when ReplayConfirmed is set. All the entries are attempted and
+their failures returned together
+*/
+func (j *Journal) Replay() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return j.replay()
+}
+
+/*
+Replays the entries, must be called with the mutex locked
+*/
+func (j *Journal) replay() error {
+	var errs []error
+	for _, entry := range j.entries {
+		if entry.Status == JournalConfirmed && !j.Options.ReplayConfirmed {
+			continue
+		}
+		errs = append(errs, j.apply(entry))
+		if j.Options.OnReplay != nil {
+			j.Options.OnReplay(*entry)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+/*
+Marks every entry as pending, so the next replay writes them
+all, e.g. after the device reports it was power cycled
+*/
+func (j *Journal) Invalidate() {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	for _, entry := range j.entries {
+		entry.Status = JournalPending
+	}
+}
+
+/*
+Removes the entry of a key from the journal
+*/
+func (j *Journal) Remove(key string) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	index := slices.IndexFunc(j.entries, func(entry *JournalEntry) bool {
+		return entry.Key == key
+	})
+	if index < 0 {
+		return fmt.Errorf("journal has no entry %q", key)
+	}
+	j.entries = slices.Delete(j.entries, index, index+1)
+	return nil
+}
+
+/*
+Returns a copy of the entries in order
+*/
+func (j *Journal) Entries() []JournalEntry {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	entries := make([]JournalEntry, len(j.entries))
+	for index, entry := range j.entries {
+		entries[index] = *entry
+	}
+	return entries
+}
+
+/*
+Establishes the connection and replays the journal. Failures to
+replay are kept in the entries and not returned
+*/
+func (j *Journal) Connect() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if err := j.Unicomm.Connect(); err != nil {
+		return err
+	}
+	j.replay()
+	return nil
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (j *Journal) WriteRaw(message []byte) error {
+	return writeRaw(j.Unicomm, message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (j *Journal) Unwrap() Unicomm {
+	return j.Unicomm
+}
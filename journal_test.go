@@ -0,0 +1,85 @@
+package unicomm_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestJournalReplaysUnconfirmedEntries(t *testing.T) {
+	var mutex sync.Mutex
+	settings := make(map[string]string)
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			var response []byte
+			for _, line := range strings.Fields(strings.ReplaceAll(string(request), " ", "=")) {
+				name, value, _ := strings.Cut(line, "=")
+				if query, ok := strings.CutSuffix(name, "?"); ok {
+					response = append(response, settings[query]+"\n"...)
+				} else {
+					settings[name] = value
+				}
+			}
+			return response
+		},
+	})
+
+	var replayed []string
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      server.TCPOptions(),
+		Journal: &unicomm.JournalOptions{
+			OnReplay: func(entry unicomm.JournalEntry) { replayed = append(replayed, entry.Key) },
+		},
+	})
+	journal, ok := unicomm.As[*unicomm.Journal](comm)
+	if !ok {
+		t.Fatal("journal is not enabled")
+	}
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	volt := unicomm.JournalEntry{Key: "volt", Command: "VOLT 5\n", VerifyQuery: "VOLT?\n", Expected: "5"}
+	if err := journal.Apply(volt); err != nil {
+		t.Fatal(err)
+	}
+
+	// Written while disconnected, so it is replayed on connect
+	comm.Disconnect()
+	curr := unicomm.JournalEntry{Key: "curr", Command: "CURR 2\n", VerifyQuery: "CURR?\n", Expected: "2"}
+	if err := journal.Apply(curr); err == nil {
+		t.Fatal("expected the entry to wait for the connection")
+	}
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	entries := journal.Entries()
+	if len(entries) != 2 || entries[0].Attempts != 1 || entries[1].Status != unicomm.JournalConfirmed {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if len(replayed) != 1 || replayed[0] != "curr" {
+		t.Fatalf("replayed %v", replayed)
+	}
+
+	// The device lost its configuration on a power cycle
+	mutex.Lock()
+	clear(settings)
+	mutex.Unlock()
+	journal.Invalidate()
+	if err := journal.Replay(); err != nil {
+		t.Fatal(err)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if settings["VOLT"] != "5" || settings["CURR"] != "2" {
+		t.Fatalf("device settings %v", settings)
+	}
+}
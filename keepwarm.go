@@ -0,0 +1,198 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"sync"
+	"time"
+)
+
+type KeepWarmOptions struct {
+	Interval  time.Duration // Idle time before the command is sent
+	Command   []byte        // No-op command understood by the device
+	Delimiter string        // Ends the reply, which is discarded. Empty expects no reply
+	OnError   func(error)   // Called when the command or its reply fails
+}
+
+type KeepWarm struct {
+	Unicomm
+	Options KeepWarmOptions
+
+	generation uint64 // Identifies the current idle timer
+	timer      *time.Timer
+	mutex      sync.Mutex // Keep operations and keep-warm commands apart
+}
+
+/*
+Creates a wrapper that sends a no-op command whenever the
+connection is idle for the interval, so terminal servers and
+instruments that drop silent sessions keep them open
+*/
+func NewKeepWarm(comm Unicomm, options KeepWarmOptions) *KeepWarm {
+	return &KeepWarm{
+		Unicomm: comm,
+		Options: options,
+	}
+}
+
+/*
+Replaces the options, restarting the idle timer with the new
+interval when the connection is established
+*/
+func (kw *KeepWarm) SetOptions(options KeepWarmOptions) {
+	kw.mutex.Lock()
+	defer kw.mutex.Unlock()
+
+	kw.Options = options
+	if kw.timer != nil {
+		kw.touch()
+	}
+}
+
+/*
+Sends the keep-warm command if the connection is still idle
+*/
+func (kw *KeepWarm) fire(generation uint64) {
+	kw.mutex.Lock()
+	defer kw.mutex.Unlock()
+
+	if kw.timer == nil || generation != kw.generation {
+		return
+	}
+	// Runs in the timer goroutine, where a panic would crash the process
+	_, err := guard(func() ([]byte, error) {
+		if !kw.Unicomm.IsConnected() {
+			return nil, nil
+		}
+		err := kw.Unicomm.Write(kw.Options.Command)
+		if err == nil && kw.Options.Delimiter != "" {
+			_, err = ReadUntil(kw.Unicomm, kw.Options.Delimiter)
+		}
+		return nil, err
+	})
+	if err != nil {
+		report(kw.Options.OnError, err)
+	}
+	kw.touch()
+}
+
+/*
+Restarts the idle timer, must be called with the mutex locked
+*/
+func (kw *KeepWarm) touch() {
+	if kw.timer != nil {
+		kw.timer.Stop()
+	}
+	kw.generation++
+	generation := kw.generation
+	kw.timer = time.AfterFunc(kw.Options.Interval, func() {
+		kw.fire(generation)
+	})
+}
+
+/*
+Runs the operation and restarts the idle timer
+*/
+func (kw *KeepWarm) do(operation func() error) error {
+	kw.mutex.Lock()
+	defer kw.mutex.Unlock()
+
+	err := operation()
+	if kw.timer != nil {
+		kw.touch()
+	}
+	return err
+}
+
+/*
+Establishes the connection and starts the idle timer
+*/
+func (kw *KeepWarm) Connect() error {
+	kw.mutex.Lock()
+	defer kw.mutex.Unlock()
+
+	if err := kw.Unicomm.Connect(); err != nil {
+		return err
+	}
+	kw.touch()
+	return nil
+}
+
+/*
+Stops the idle timer and closes the connection
+*/
+func (kw *KeepWarm) Disconnect() error {
+	kw.mutex.Lock()
+	defer kw.mutex.Unlock()
+
+	if kw.timer != nil {
+		kw.timer.Stop()
+		kw.timer = nil
+	}
+	return kw.Unicomm.Disconnect()
+}
+
+/*
+Reads a number of bytes
+*/
+func (kw *KeepWarm) Read(n uint) ([]byte, error) {
+	var data []byte
+	err := kw.do(func() (err error) {
+		data, err = kw.Unicomm.Read(n)
+		return err
+	})
+	return data, err
+}
+
+/*
+Reads data until a target delimiter is found
+*/
+func (kw *KeepWarm) ReadUntil(delimiter string) ([]byte, error) {
+	var data []byte
+	err := kw.do(func() (err error) {
+		data, err = ReadUntil(kw.Unicomm, delimiter)
+		return err
+	})
+	return data, err
+}
+
+/*
+Writes an array of bytes
+*/
+func (kw *KeepWarm) Write(message []byte) error {
+	return kw.do(func() error {
+		return kw.Unicomm.Write(message)
+	})
+}
+
+/*
+Reads everything currently buffered by the instance
+*/
+func (kw *KeepWarm) ReadAvailable() ([]byte, error) {
+	var data []byte
+	err := kw.do(func() (err error) {
+		data, err = ReadAvailable(kw.Unicomm)
+		return err
+	})
+	return data, err
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (kw *KeepWarm) WriteRaw(message []byte) error {
+	return kw.do(func() error {
+		return writeRaw(kw.Unicomm, message)
+	})
+}
+
+/*
+Returns the wrapped instance
+*/
+func (kw *KeepWarm) Unwrap() Unicomm {
+	return kw.Unicomm
+}
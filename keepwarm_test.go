@@ -0,0 +1,118 @@
+package unicomm_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+/*
+Connects a keep-warm wrapper sending "NOOP\n" to a server that
+answers it with "OK\n" when reply is true, and "MEAS?\n" with
+"21.5\n"
+*/
+func connectKeepWarm(t *testing.T, reply bool, options unicomm.KeepWarmOptions) (*unicommtest.Server, *unicomm.KeepWarm) {
+	t.Helper()
+
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte {
+			var response []byte
+			if reply && bytes.Contains(request, []byte("NOOP\n")) {
+				response = append(response, "OK\n"...)
+			}
+			if bytes.Contains(request, []byte("MEAS?\n")) {
+				response = append(response, "21.5\n"...)
+			}
+			return response
+		},
+	})
+	tcpOptions := server.TCPOptions()
+	tcpOptions.ReadTimeout = 50 * time.Millisecond
+	options.Command = []byte("NOOP\n")
+
+	warm := unicomm.NewKeepWarm(unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: tcpOptions}), options)
+	if err := warm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { warm.Disconnect() })
+	return server, warm
+}
+
+/*
+Waits until the server received the keep-warm command
+*/
+func waitKeepWarm(t *testing.T, server *unicommtest.Server) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(string(server.Received()), "NOOP") {
+		if time.Now().After(deadline) {
+			t.Fatal("the keep-warm command was never sent")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestKeepWarmIdle(t *testing.T) {
+	server, warm := connectKeepWarm(t, true, unicomm.KeepWarmOptions{Interval: 100 * time.Millisecond, Delimiter: "\n"})
+
+	// Traffic restarts the idle timer, so nothing is sent meanwhile
+	for range 15 {
+		if err := warm.Write([]byte("PING\n")); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if received := string(server.Received()); strings.Contains(received, "NOOP") {
+		t.Fatalf("keep-warm sent during traffic: %q", received)
+	}
+
+	start := time.Now()
+	waitKeepWarm(t, server)
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Fatalf("keep-warm sent after %v of idle time, before the interval", elapsed)
+	}
+}
+
+func TestKeepWarmConsumesReply(t *testing.T) {
+	server, warm := connectKeepWarm(t, true, unicomm.KeepWarmOptions{Interval: 30 * time.Millisecond, Delimiter: "\n"})
+	waitKeepWarm(t, server)
+
+	// The reply of the keep-warm command never reaches the caller
+	client := unicomm.NewClient(warm, "\n")
+	response, err := client.Query([]byte("MEAS?\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(response) != "21.5\n" {
+		t.Fatalf("got %q, want the reply of the query", response)
+	}
+}
+
+func TestKeepWarmError(t *testing.T) {
+	failures := make(chan error, 1)
+	connectKeepWarm(t, false, unicomm.KeepWarmOptions{
+		Interval:  30 * time.Millisecond,
+		Delimiter: "\n",
+		OnError: func(err error) {
+			select {
+			case failures <- err:
+			default:
+			}
+		},
+	})
+
+	// The device never answers, so the reply times out
+	select {
+	case err := <-failures:
+		if !unicomm.IsTimeout(err) {
+			t.Fatalf("reported %v, want the reply timeout", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnError was not called")
+	}
+}
@@ -0,0 +1,279 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+type Operation string
+
+const (
+	OpConnect   Operation = "connect"
+	OpRead      Operation = "read"
+	OpReadUntil Operation = "read_until"
+	OpWrite     Operation = "write"
+	OpQuery     Operation = "query" // Write and response of a Client
+)
+
+/*
+Default upper bounds of the histogram buckets
+*/
+var DefaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	20 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+type SlowOperation struct {
+	Operation Operation
+	Duration  time.Duration
+	Threshold time.Duration
+	Err       error // Error returned by the operation, if any
+	Time      time.Time
+}
+
+type LatencyOptions struct {
+	Buckets    []time.Duration             // Ascending upper bounds, defaults to DefaultLatencyBuckets
+	Thresholds map[Operation]time.Duration // Operations slower than these are reported
+	OnSlow     func(SlowOperation)         // Called for every slow operation
+}
+
+/*
+Latency distribution of an operation. Counts has one more entry
+than Bounds, counting the samples above the last bound
+*/
+type LatencyHistogram struct {
+	Bounds []time.Duration
+	Counts []uint64
+	Count  uint64
+	Sum    time.Duration
+	Max    time.Duration
+}
+
+type LatencyMonitor struct {
+	Unicomm
+	Options LatencyOptions
+
+	histograms map[Operation]*LatencyHistogram
+	mutex      sync.Mutex // Protect the histograms
+}
+
+/*
+Returns the mean latency
+*/
+func (lh LatencyHistogram) Mean() time.Duration {
+	if lh.Count == 0 {
+		return 0
+	}
+	return lh.Sum / time.Duration(lh.Count)
+}
+
+/*
+Returns an upper estimate of the quantile q, between 0 and 1,
+as the bound of the bucket holding it. Samples above the last
+bound are estimated by the maximum
+*/
+func (lh LatencyHistogram) Quantile(q float64) time.Duration {
+	if lh.Count == 0 {
+		return 0
+	}
+	rank := uint64(q * float64(lh.Count))
+	var seen uint64
+	for index, count := range lh.Counts {
+		seen += count
+		if seen > rank && index < len(lh.Bounds) {
+			return lh.Bounds[index]
+		}
+	}
+	return lh.Max
+}
+
+/*
+Creates a wrapper that collects a latency histogram for each
+operation and reports the ones exceeding their threshold, so
+degrading adapters can be flagged before they fail. Clients over
+the wrapper also record their queries
+*/
+func NewLatencyMonitor(comm Unicomm, options LatencyOptions) *LatencyMonitor {
+	if len(options.Buckets) == 0 {
+		options.Buckets = DefaultLatencyBuckets
+	}
+	return &LatencyMonitor{
+		Unicomm:    comm,
+		Options:    options,
+		histograms: make(map[Operation]*LatencyHistogram),
+	}
+}
+
+/*
+Records the latency of an operation and reports it when slow
+*/
+func (lm *LatencyMonitor) Observe(operation Operation, duration time.Duration, err error) {
+	lm.mutex.Lock()
+	histogram, ok := lm.histograms[operation]
+	if !ok {
+		histogram = &LatencyHistogram{
+			Bounds: lm.Options.Buckets,
+			Counts: make([]uint64, len(lm.Options.Buckets)+1),
+		}
+		lm.histograms[operation] = histogram
+	}
+	index, _ := slices.BinarySearch(histogram.Bounds, duration)
+	histogram.Counts[index]++
+	histogram.Count++
+	histogram.Sum += duration
+	histogram.Max = max(histogram.Max, duration)
+	lm.mutex.Unlock()
+
+	threshold, ok := lm.Options.Thresholds[operation]
+	if ok && duration > threshold && lm.Options.OnSlow != nil {
+		lm.Options.OnSlow(SlowOperation{
+			Operation: operation,
+			Duration:  duration,
+			Threshold: threshold,
+			Err:       err,
+			Time:      time.Now(),
+		})
+	}
+}
+
+/*
+Returns a copy of the histogram of an operation
+*/
+func (lm *LatencyMonitor) Histogram(operation Operation) LatencyHistogram {
+	lm.mutex.Lock()
+	defer lm.mutex.Unlock()
+
+	histogram, ok := lm.histograms[operation]
+	if !ok {
+		return LatencyHistogram{
+			Bounds: lm.Options.Buckets,
+			Counts: make([]uint64, len(lm.Options.Buckets)+1),
+		}
+	}
+	copied := *histogram
+	copied.Counts = slices.Clone(histogram.Counts)
+	return copied
+}
+
+/*
+Returns a copy of every histogram recorded so far
+*/
+func (lm *LatencyMonitor) Histograms() map[Operation]LatencyHistogram {
+	lm.mutex.Lock()
+	operations := make([]Operation, 0, len(lm.histograms))
+	for operation := range lm.histograms {
+		operations = append(operations, operation)
+	}
+	lm.mutex.Unlock()
+
+	histograms := make(map[Operation]LatencyHistogram, len(operations))
+	for _, operation := range operations {
+		histograms[operation] = lm.Histogram(operation)
+	}
+	return histograms
+}
+
+/*
+Clears the histograms
+*/
+func (lm *LatencyMonitor) Reset() {
+	lm.mutex.Lock()
+	defer lm.mutex.Unlock()
+
+	clear(lm.histograms)
+}
+
+/*
+Records a read unless it timed out, since timeouts measure the
+idle link rather than the device
+*/
+func (lm *LatencyMonitor) observeRead(operation Operation, start time.Time, err error) {
+	if err == nil || !isTimeout(err) {
+		lm.Observe(operation, time.Since(start), err)
+	}
+}
+
+/*
+Establishes the connection
+*/
+func (lm *LatencyMonitor) Connect() error {
+	start := time.Now()
+	err := lm.Unicomm.Connect()
+	lm.Observe(OpConnect, time.Since(start), err)
+	return err
+}
+
+/*
+Reads a number of bytes
+*/
+func (lm *LatencyMonitor) Read(n uint) ([]byte, error) {
+	start := time.Now()
+	data, err := lm.Unicomm.Read(n)
+	lm.observeRead(OpRead, start, err)
+	return data, err
+}
+
+/*
+Reads data until a target delimiter is found
+*/
+func (lm *LatencyMonitor) ReadUntil(delimiter string) ([]byte, error) {
+	start := time.Now()
+	data, err := ReadUntil(lm.Unicomm, delimiter)
+	lm.observeRead(OpReadUntil, start, err)
+	return data, err
+}
+
+/*
+Reads everything currently buffered by the instance
+*/
+func (lm *LatencyMonitor) ReadAvailable() ([]byte, error) {
+	start := time.Now()
+	data, err := ReadAvailable(lm.Unicomm)
+	lm.observeRead(OpRead, start, err)
+	return data, err
+}
+
+/*
+Writes an array of bytes
+*/
+func (lm *LatencyMonitor) Write(message []byte) error {
+	start := time.Now()
+	err := lm.Unicomm.Write(message)
+	lm.Observe(OpWrite, time.Since(start), err)
+	return err
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (lm *LatencyMonitor) WriteRaw(message []byte) error {
+	start := time.Now()
+	err := writeRaw(lm.Unicomm, message)
+	lm.Observe(OpWrite, time.Since(start), err)
+	return err
+}
+
+/*
+Returns the wrapped instance
+*/
+func (lm *LatencyMonitor) Unwrap() Unicomm {
+	return lm.Unicomm
+}
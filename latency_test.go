@@ -0,0 +1,58 @@
+package unicomm_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestLatencyMonitor(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte {
+			if strings.TrimSpace(string(request)) == "SLOW?" {
+				time.Sleep(100 * time.Millisecond)
+			}
+			return []byte("OK\n")
+		},
+	})
+	options := server.TCPOptions()
+	options.ReadTimeout = time.Second
+	var slow []unicomm.SlowOperation
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      options,
+		Latency: &unicomm.LatencyOptions{
+			Thresholds: map[unicomm.Operation]time.Duration{unicomm.OpQuery: 50 * time.Millisecond},
+			OnSlow:     func(operation unicomm.SlowOperation) { slow = append(slow, operation) },
+		},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	client := unicomm.NewClient(comm, "\n")
+	for _, command := range []string{"FAST?\n", "FAST?\n", "SLOW?\n"} {
+		if _, err := client.Query([]byte(command)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(slow) != 1 || slow[0].Duration < 100*time.Millisecond {
+		t.Fatalf("unexpected slow operations %+v", slow)
+	}
+	monitor, _ := unicomm.As[*unicomm.LatencyMonitor](comm)
+	histogram := monitor.Histogram(unicomm.OpQuery)
+	if histogram.Count != 3 || histogram.Max < 100*time.Millisecond {
+		t.Fatalf("unexpected histogram %+v", histogram)
+	}
+	if median := histogram.Quantile(0.5); median > 50*time.Millisecond {
+		t.Fatalf("median of %v is too high", median)
+	}
+	if writes := monitor.Histogram(unicomm.OpWrite); writes.Count != 3 {
+		t.Fatalf("recorded %d writes", writes.Count)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"fmt"
+	"sync"
+)
+
+type LazyConnector struct {
+	Unicomm
+
+	active      bool       // Connection was requested by the user
+	established bool       // An operation connected the wrapped instance
+	mutex       sync.Mutex // Protect connection state
+}
+
+/*
+Creates a wrapper that defers the connection until the first
+operation, so services creating many instances at startup only
+pay the connect latency of the devices they use. Connection
+errors are returned by that operation, and retried by the next,
+which also reconnects after the connection is lost
+*/
+func NewLazyConnector(comm Unicomm) *LazyConnector {
+	return &LazyConnector{Unicomm: comm}
+}
+
+/*
+Connects on the first operation and runs it
+*/
+func (lc *LazyConnector) do(operation func() error) error {
+	lc.mutex.Lock()
+	if !lc.active {
+		lc.mutex.Unlock()
+		return fmt.Errorf("there is no connection established")
+	}
+	if !lc.Unicomm.IsConnected() {
+		// A connection lost to the peer is released before dialing
+		// again, as backends refuse to connect over it
+		if lc.established {
+			lc.Unicomm.Disconnect()
+			lc.established = false
+		}
+		if err := lc.Unicomm.Connect(); err != nil {
+			lc.mutex.Unlock()
+			return fmt.Errorf("lazy connect failed: %w", err)
+		}
+	}
+	lc.established = true
+	lc.mutex.Unlock()
+	return operation()
+}
+
+/*
+Marks the connection as requested, without connecting yet
+*/
+func (lc *LazyConnector) Connect() error {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	if lc.active {
+		return fmt.Errorf("there is a connection already established")
+	}
+	lc.active = true
+	return nil
+}
+
+/*
+Closes the connection, if an operation established it, even
+when the peer already closed it
+*/
+func (lc *LazyConnector) Disconnect() error {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	if !lc.active {
+		return fmt.Errorf("there is no connection established")
+	}
+	lc.active = false
+	if !lc.established {
+		return nil
+	}
+	lc.established = false
+	return lc.Unicomm.Disconnect()
+}
+
+/*
+Returns true if the connection was requested by the user, even
+before the first operation establishes it
+*/
+func (lc *LazyConnector) IsConnected() bool {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	return lc.active
+}
+
+/*
+Returns true once the first operation established the connection
+*/
+func (lc *LazyConnector) Established() bool {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	return lc.active && lc.Unicomm.IsConnected()
+}
+
+/*
+Reads a number of bytes, connecting first if needed
+*/
+func (lc *LazyConnector) Read(n uint) ([]byte, error) {
+	var data []byte
+	err := lc.do(func() (err error) {
+		data, err = lc.Unicomm.Read(n)
+		return err
+	})
+	return data, err
+}
+
+/*
+Reads data until a target delimiter is found, connecting first
+if needed
+*/
+func (lc *LazyConnector) ReadUntil(delimiter string) ([]byte, error) {
+	var data []byte
+	err := lc.do(func() (err error) {
+		data, err = ReadUntil(lc.Unicomm, delimiter)
+		return err
+	})
+	return data, err
+}
+
+/*
+Writes an array of bytes, connecting first if needed
+*/
+func (lc *LazyConnector) Write(message []byte) error {
+	return lc.do(func() error {
+		return lc.Unicomm.Write(message)
+	})
+}
+
+/*
+Reads everything currently buffered, connecting first if needed
+*/
+func (lc *LazyConnector) ReadAvailable() ([]byte, error) {
+	var data []byte
+	err := lc.do(func() (err error) {
+		data, err = ReadAvailable(lc.Unicomm)
+		return err
+	})
+	return data, err
+}
+
+/*
+Writes an array of bytes without the end delimiter, connecting
+first if needed
+*/
+func (lc *LazyConnector) WriteRaw(message []byte) error {
+	return lc.do(func() error {
+		return writeRaw(lc.Unicomm, message)
+	})
+}
+
+/*
+Returns the wrapped instance
+*/
+func (lc *LazyConnector) Unwrap() Unicomm {
+	return lc.Unicomm
+}
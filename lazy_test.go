@@ -0,0 +1,117 @@
+package unicomm_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestLazyConnect(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	comm := unicomm.New(unicomm.Options{
+		Protocol:    unicomm.TCP,
+		TCP:         server.TCPOptions(),
+		LazyConnect: true,
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	lazy, _ := unicomm.As[*unicomm.LazyConnector](comm)
+	if !comm.IsConnected() || lazy.Established() {
+		t.Fatal("expected the connection to wait for the first operation")
+	}
+	if err := comm.Write([]byte("PING\n")); err != nil {
+		t.Fatal(err)
+	}
+	unicommtest.ExpectFrame(t, comm, "\n", "PING\n")
+	if !lazy.Established() {
+		t.Fatal("first operation did not connect")
+	}
+}
+
+func TestLazyConnectReturnsErrorsOnFirstOperation(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := uint(listener.Addr().(*net.TCPAddr).Port)
+	listener.Close()
+
+	comm := unicomm.New(unicomm.Options{
+		Protocol:    unicomm.TCP,
+		TCP:         unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+		LazyConnect: true,
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatalf("connect should be deferred, got %v", err)
+	}
+	defer comm.Disconnect()
+	if err := comm.Write([]byte("PING\n")); err == nil || !strings.Contains(err.Error(), "lazy connect failed") {
+		t.Fatalf("expected the connection error, got %v", err)
+	}
+}
+
+func TestLazyConnectAfterPeerClose(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// Every connection answers one line and is closed by the peer
+	accepted := make(chan struct{}, 4)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- struct{}{}
+			buffer := make([]byte, 64)
+			n, _ := conn.Read(buffer)
+			conn.Write(buffer[:n])
+			conn.Close()
+		}
+	}()
+
+	options := unicommtcp.TCPOptions{
+		Host:        "127.0.0.1",
+		Port:        uint(listener.Addr().(*net.TCPAddr).Port),
+		ReadTimeout: time.Second,
+	}
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: options, LazyConnect: true})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	lazy, _ := unicomm.As[*unicomm.LazyConnector](comm)
+	for _, message := range []string{"ONE\n", "TWO\n"} {
+		if err := comm.Write([]byte(message)); err != nil {
+			t.Fatal(err)
+		}
+		unicommtest.ExpectFrame(t, comm, "\n", message)
+		for deadline := time.Now().Add(time.Second); lazy.Established(); time.Sleep(5 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatal("peer close was not detected")
+			}
+		}
+	}
+	if len(accepted) != 2 {
+		t.Fatalf("accepted %d connections, want a reconnection after the peer close", len(accepted))
+	}
+
+	// The connection closed by the peer is still released
+	if err := comm.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	tcp, _ := unicomm.As[*unicommtcp.UnicommTCP](comm)
+	if tcp.Connection != nil {
+		t.Fatal("disconnect left the socket closed by the peer open")
+	}
+}
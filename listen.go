@@ -0,0 +1,134 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+var ErrLineBusy = errors.New("line busy, no idle period before the deadline")
+
+type ListenBeforeTalkOptions struct {
+	IdlePeriod time.Duration // Silence required before transmitting, defaults to 20ms
+	MaxWait    time.Duration // Deferral limit of a write, defaults to 1s
+	Backoff    time.Duration // Maximum random wait after a busy line, defaults to IdlePeriod
+}
+
+type ListenBeforeTalk struct {
+	*Buffered
+	Options ListenBeforeTalkOptions
+
+	deferrals atomic.Uint64
+	mutex     sync.Mutex // Keep one write listening at a time
+}
+
+/*
+Creates a collision-avoidance wrapper for lines shared by several
+masters, such as RS-232 splitters. Before transmitting, the line
+must stay silent for the idle period, otherwise the write is
+deferred by a random backoff and retried. Bytes heard while
+listening are kept for the next reads
+*/
+func NewListenBeforeTalk(comm Unicomm, options ListenBeforeTalkOptions) *ListenBeforeTalk {
+	if options.IdlePeriod == 0 {
+		options.IdlePeriod = 20 * time.Millisecond
+	}
+	if options.MaxWait == 0 {
+		options.MaxWait = time.Second
+	}
+	if options.Backoff == 0 {
+		options.Backoff = options.IdlePeriod
+	}
+	return &ListenBeforeTalk{
+		Buffered: NewBuffered(comm),
+		Options:  options,
+	}
+}
+
+/*
+Returns how many times a write found the line busy
+*/
+func (l *ListenBeforeTalk) Deferrals() uint64 {
+	return l.deferrals.Load()
+}
+
+/*
+Listens to the line for the idle period, returning false as
+soon as a byte is received
+*/
+func (l *ListenBeforeTalk) idle() (bool, error) {
+	deadline := time.Now().Add(l.Options.IdlePeriod)
+	backoff := unicommio.Backoff{Max: l.Options.IdlePeriod / 4}
+	for time.Now().Before(deadline) {
+		data, err := ReadAvailable(l.Buffered.Unicomm)
+		if err != nil {
+			return false, err
+		}
+		if len(data) > 0 {
+			l.Buffered.push(data)
+			return false, nil
+		}
+		backoff.Wait(deadline)
+	}
+	return true, nil
+}
+
+/*
+Waits for an idle line and transmits with the write function
+*/
+func (l *ListenBeforeTalk) talk(write func() error) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	deadline := time.Now().Add(l.Options.MaxWait)
+	for {
+		idle, err := l.idle()
+		if err != nil {
+			return err
+		}
+		if idle {
+			return write()
+		}
+		l.deferrals.Add(1)
+		if !time.Now().Before(deadline) {
+			return ErrLineBusy
+		}
+		time.Sleep(rand.N(l.Options.Backoff) + time.Millisecond)
+	}
+}
+
+/*
+Writes an array of bytes once the line is idle
+*/
+func (l *ListenBeforeTalk) Write(message []byte) error {
+	return l.talk(func() error {
+		return l.Buffered.Unicomm.Write(message)
+	})
+}
+
+/*
+Writes an array of bytes without the end delimiter once the
+line is idle
+*/
+func (l *ListenBeforeTalk) WriteRaw(message []byte) error {
+	return l.talk(func() error {
+		return writeRaw(l.Buffered.Unicomm, message)
+	})
+}
+
+/*
+Returns the wrapped instance
+*/
+func (l *ListenBeforeTalk) Unwrap() Unicomm {
+	return l.Buffered
+}
@@ -0,0 +1,75 @@
+package unicomm_test
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+/*
+Starts a server standing in for another master, talking on the
+line every 5ms for the given period
+*/
+func serveChatter(t *testing.T, period time.Duration) uint {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for start := time.Now(); time.Since(start) < period; {
+			conn.Write([]byte("x"))
+			time.Sleep(5 * time.Millisecond)
+		}
+		time.Sleep(time.Second)
+	}()
+	return uint(listener.Addr().(*net.TCPAddr).Port)
+}
+
+func TestListenBeforeTalk(t *testing.T) {
+	for _, test := range []struct {
+		maxWait time.Duration
+		err     error
+	}{
+		{maxWait: 50 * time.Millisecond, err: unicomm.ErrLineBusy},
+		{maxWait: time.Second, err: nil},
+	} {
+		port := serveChatter(t, 150*time.Millisecond)
+		comm := unicomm.New(unicomm.Options{
+			Protocol: unicomm.TCP,
+			TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+			ListenBeforeTalk: &unicomm.ListenBeforeTalkOptions{
+				IdlePeriod: 20 * time.Millisecond,
+				MaxWait:    test.maxWait,
+			},
+		})
+		if err := comm.Connect(); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		if err := comm.Write([]byte("CMD\n")); !errors.Is(err, test.err) {
+			t.Fatalf("write with max wait %v returned %v", test.maxWait, err)
+		}
+		lbt, _ := unicomm.As[*unicomm.ListenBeforeTalk](comm)
+		if lbt.Deferrals() == 0 {
+			t.Fatal("write was not deferred")
+		}
+		// The bytes heard while listening are kept for the reads
+		if data, err := comm.Read(1); err != nil || !strings.HasPrefix(string(data), "x") {
+			t.Fatalf("read returned %q, %v", data, err)
+		}
+		comm.Disconnect()
+	}
+}
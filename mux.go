@@ -0,0 +1,192 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+// Channel ID and payload length, both big endian
+const muxHeaderSize = 4
+
+type MuxOptions struct {
+	ReadTimeout time.Duration // Timeout of reads on virtual channels
+	RetryDelay  time.Duration // Wait after a failed read
+	OnError     func(error)   // Called for errors other than timeouts
+}
+
+type Mux struct {
+	Options MuxOptions
+
+	comm     Unicomm
+	channels map[uint16]*VirtualChannel
+	stream   []byte // Received bytes not decoded yet
+	unrouted atomic.Uint64
+	stop     chan struct{}
+	done     chan struct{}
+	writing  sync.Mutex // Keep header and payload of a frame together
+	mutex    sync.Mutex // Protect channels and running state
+}
+
+/*
+Creates a multiplexer that carries several virtual channels
+over one connection, e.g. to gateways that accept a single
+socket. Each frame starts with the channel ID and the payload
+length, as two big endian 16 bits integers
+*/
+func NewMux(comm Unicomm, options MuxOptions) *Mux {
+	if options.ReadTimeout == 0 {
+		options.ReadTimeout = 100 * time.Millisecond
+	}
+	if options.RetryDelay == 0 {
+		options.RetryDelay = 100 * time.Millisecond
+	}
+	return &Mux{
+		Options:  options,
+		comm:     comm,
+		channels: make(map[uint16]*VirtualChannel),
+	}
+}
+
+/*
+Returns the virtual channel with the given ID, creating it if
+needed
+*/
+func (m *Mux) Channel(id uint16) *VirtualChannel {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if channel, ok := m.channels[id]; ok {
+		return channel
+	}
+	channel := &VirtualChannel{
+		Name:    strconv.Itoa(int(id)),
+		comm:    m.comm,
+		timeout: m.Options.ReadTimeout,
+		write: func(message []byte) error {
+			return m.send(id, message)
+		},
+		notify: make(chan struct{}),
+	}
+	m.channels[id] = channel
+	return channel
+}
+
+/*
+Returns how many frames arrived for channels never opened
+*/
+func (m *Mux) Unrouted() uint64 {
+	return m.unrouted.Load()
+}
+
+/*
+Sends the message as one or more frames of the channel
+*/
+func (m *Mux) send(id uint16, message []byte) error {
+	m.writing.Lock()
+	defer m.writing.Unlock()
+
+	for len(message) > 0 {
+		payload := message[:min(len(message), 0xFFFF)]
+		message = message[len(payload):]
+
+		frame := make([]byte, muxHeaderSize, muxHeaderSize+len(payload))
+		binary.BigEndian.PutUint16(frame[0:], id)
+		binary.BigEndian.PutUint16(frame[2:], uint16(len(payload)))
+		frame = append(frame, payload...)
+		// The end delimiter of the backend would corrupt the next header
+		if err := writeRaw(m.comm, frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+Starts reading and routing frames
+*/
+func (m *Mux) Start() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.stop != nil {
+		return fmt.Errorf("multiplexer is already running")
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	go m.route(m.stop, m.done)
+	return nil
+}
+
+/*
+Stops reading and routing frames
+*/
+func (m *Mux) Stop() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.stop == nil {
+		return fmt.Errorf("multiplexer is not running")
+	}
+	close(m.stop)
+	<-m.done
+	m.stop = nil
+	return nil
+}
+
+/*
+Reads frames and delivers them to their channels until stopped
+*/
+func (m *Mux) route(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		data, err := guard(func() ([]byte, error) {
+			return m.comm.Read(unicommio.ReadChunkSize)
+		})
+		if err != nil {
+			if !isTimeout(err) {
+				report(m.Options.OnError, err)
+				time.Sleep(m.Options.RetryDelay)
+			}
+			continue
+		}
+		m.stream = append(m.stream, data...)
+
+		for len(m.stream) >= muxHeaderSize {
+			id := binary.BigEndian.Uint16(m.stream[0:])
+			size := muxHeaderSize + int(binary.BigEndian.Uint16(m.stream[2:]))
+			if len(m.stream) < size {
+				break
+			}
+			payload := m.stream[muxHeaderSize:size]
+
+			m.mutex.Lock()
+			channel, ok := m.channels[id]
+			m.mutex.Unlock()
+			if ok {
+				channel.deliver(payload)
+			} else {
+				m.unrouted.Add(1)
+			}
+			m.stream = m.stream[size:]
+		}
+	}
+}
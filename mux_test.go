@@ -0,0 +1,95 @@
+package unicomm_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestMuxChannels(t *testing.T) {
+	// The gateway echoes the frames, so each channel reads its own data
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions()})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	mux := unicomm.NewMux(comm, unicomm.MuxOptions{})
+	if err := mux.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer mux.Stop()
+
+	sensors, relays := mux.Channel(1), mux.Channel(2)
+	if err := sensors.Write([]byte("TEMP?\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := relays.Write([]byte("ON 3\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	unicommtest.ExpectFrame(t, relays, "\n", "ON 3\n")
+	unicommtest.ExpectFrame(t, sensors, "\n", "TEMP?\n")
+}
+
+func TestMuxIgnoresEndDelimiter(t *testing.T) {
+	// Frames are length-prefixed, the end delimiter must not follow them
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	options := server.TCPOptions()
+	options.EndDelimiter = "\r\n"
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: options})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	mux := unicomm.NewMux(comm, unicomm.MuxOptions{})
+	if err := mux.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer mux.Stop()
+
+	channel := mux.Channel(7)
+	for _, message := range []string{"A\n", "B\n"} {
+		if err := channel.Write([]byte(message)); err != nil {
+			t.Fatal(err)
+		}
+		unicommtest.ExpectFrame(t, channel, "\n", message)
+	}
+}
+
+func TestMuxChannelTimeout(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions()})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	mux := unicomm.NewMux(comm, unicomm.MuxOptions{ReadTimeout: 50 * time.Millisecond})
+	if err := mux.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer mux.Stop()
+
+	// A frame without the delimiter is returned with the timeout
+	channel := mux.Channel(1)
+	if err := channel.Write([]byte("PART")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := channel.ReadUntil("\n")
+	if !unicommio.IsTimeout(err) || !errors.Is(err, unicommio.ErrTimeout) || string(data) != "PART" {
+		t.Fatalf("ReadUntil = %q, %v, want the partial data with a timeout", data, err)
+	}
+
+	// The partial data was consumed, and reads time out without data
+	data, err = channel.Read(8)
+	if err != nil || len(data) != 0 {
+		t.Fatalf("Read = %q, %v, want no data and no error", data, err)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrMessageExpired = errors.New("queued message expired before delivery")
+	ErrOutboxFull     = errors.New("queued message discarded, outbox is full")
+)
+
+type OutboxOptions struct {
+	Capacity int           // Maximum queued messages, the oldest is discarded beyond it. Defaults to 256
+	TTL      time.Duration // Queued messages older than this are discarded, zero keeps them
+}
+
+type queuedMessage struct {
+	data   []byte
+	queued time.Time
+	raw    bool // Written without the end delimiter
+	done   func(error)
+}
+
+type Outbox struct {
+	Unicomm
+	Options OutboxOptions
+
+	queue []queuedMessage
+	mutex sync.Mutex // Protect the queue and keep flushes in order
+}
+
+/*
+Creates a wrapper that queues the writes issued while the
+connection is down and flushes them, in order, once it is
+established again
+*/
+func NewOutbox(comm Unicomm, options OutboxOptions) *Outbox {
+	if options.Capacity == 0 {
+		options.Capacity = 256
+	}
+	return &Outbox{
+		Unicomm: comm,
+		Options: options,
+	}
+}
+
+/*
+Returns the number of messages waiting for the connection
+*/
+func (o *Outbox) Pending() int {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return len(o.queue)
+}
+
+/*
+Writes the message or queues it while disconnected. Done, when
+not nil, is called with the result once the message is written,
+expired or discarded
+*/
+func (o *Outbox) Send(message []byte, done func(error)) error {
+	return o.send(queuedMessage{data: message, done: done})
+}
+
+/*
+Writes the message or queues a copy of it
+*/
+func (o *Outbox) send(message queuedMessage) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	done := message.done
+	if o.Unicomm.IsConnected() && o.flush() == nil {
+		err := o.write(message)
+		if done != nil {
+			done(err)
+		}
+		return err
+	}
+
+	if len(o.queue) >= o.Options.Capacity {
+		if o.queue[0].done != nil {
+			o.queue[0].done(ErrOutboxFull)
+		}
+		o.queue = o.queue[1:]
+	}
+	message.data = bytes.Clone(message.data)
+	message.queued = time.Now()
+	o.queue = append(o.queue, message)
+	return nil
+}
+
+/*
+Writes the message, queueing it while disconnected
+*/
+func (o *Outbox) Write(message []byte) error {
+	return o.Send(message, nil)
+}
+
+/*
+Writes the message without the end delimiter, queueing it while
+disconnected
+*/
+func (o *Outbox) WriteRaw(message []byte) error {
+	return o.send(queuedMessage{data: message, raw: true})
+}
+
+/*
+Writes a message through the wrapped instance
+*/
+func (o *Outbox) write(message queuedMessage) error {
+	if message.raw {
+		return writeRaw(o.Unicomm, message.data)
+	}
+	return o.Unicomm.Write(message.data)
+}
+
+/*
+Writes the queued messages in order, discarding the expired
+ones. Stops at the first failure, keeping it and the next ones
+queued. Must be called with the mutex locked
+*/
+func (o *Outbox) flush() error {
+	for len(o.queue) > 0 {
+		message := o.queue[0]
+		if o.Options.TTL > 0 && time.Since(message.queued) > o.Options.TTL {
+			o.queue = o.queue[1:]
+			if message.done != nil {
+				message.done(ErrMessageExpired)
+			}
+			continue
+		}
+		if err := o.write(message); err != nil {
+			return err
+		}
+		o.queue = o.queue[1:]
+		if message.done != nil {
+			message.done(nil)
+		}
+	}
+	return nil
+}
+
+/*
+Writes the queued messages if the connection is established
+*/
+func (o *Outbox) Flush() error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.flush()
+}
+
+/*
+Establishes the connection and flushes the queued messages.
+Failures to flush keep the messages queued and are not returned
+*/
+func (o *Outbox) Connect() error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if err := o.Unicomm.Connect(); err != nil {
+		return err
+	}
+	o.flush()
+	return nil
+}
+
+/*
+Returns the wrapped instance
+*/
+func (o *Outbox) Unwrap() Unicomm {
+	return o.Unicomm
+}
@@ -0,0 +1,39 @@
+package unicomm_test
+
+import (
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestOutboxFlushOnConnect(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	outbox := unicomm.NewOutbox(
+		unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions()}),
+		unicomm.OutboxOptions{Capacity: 2},
+	)
+
+	// Written while disconnected, the first message overflows the outbox
+	var results []error
+	for _, message := range []string{"SET 1\n", "SET 2\n", "SET 3\n"} {
+		err := outbox.Send([]byte(message), func(err error) { results = append(results, err) })
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if outbox.Pending() != 2 {
+		t.Fatalf("expected 2 pending messages, got %d", outbox.Pending())
+	}
+
+	if err := outbox.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer outbox.Disconnect()
+
+	unicommtest.ExpectFrame(t, outbox, "\n", "SET 2\n")
+	unicommtest.ExpectFrame(t, outbox, "\n", "SET 3\n")
+	if len(results) != 3 || results[0] != unicomm.ErrOutboxFull || results[1] != nil || results[2] != nil {
+		t.Fatalf("unexpected results %v", results)
+	}
+}
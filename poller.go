@@ -0,0 +1,297 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*
+A device polled by the scheduler. The command is queried on
+every poll, or Poll is called instead when set, one of them
+being required
+*/
+type PollTarget struct {
+	Name      string
+	Comm      Unicomm
+	Interval  time.Duration                 // Period of the polls, defaults to 1s
+	Jitter    time.Duration                 // Random delay added to each period, spreading devices apart
+	Command   []byte                        // Query sent on each poll
+	Delimiter string                        // Ends the responses, defaults to "\n"
+	Poll      func(*Client) ([]byte, error) // Custom poll, replaces the query
+	Handler   func(PollResult)              // Called with every result of the device
+}
+
+type PollResult struct {
+	Device   string
+	Data     []byte
+	Err      error
+	Time     time.Time     // Start of the poll
+	Duration time.Duration // Connection included, when it was needed
+}
+
+type SchedulerOptions struct {
+	Concurrency    int           // Polls running at once, defaults to 16
+	Buffer         int           // Capacity of the results channel, defaults to 256
+	ReconnectDelay time.Duration // Minimum time between connection attempts of a device, defaults to 5s
+}
+
+/*
+Polls many devices periodically, each one at its own interval,
+limiting how many polls run at once. Disconnected devices are
+connected before polling, and fatal errors close the connection
+so the next poll reconnects. Results are delivered to the handler
+of the device and on the results channel
+*/
+type Scheduler struct {
+	Options SchedulerOptions
+
+	targets map[string]*scheduled
+	results chan PollResult
+	slots   chan struct{} // Limit the polls running at once
+	dropped atomic.Uint64
+	running bool
+	stopped bool
+	wg      sync.WaitGroup
+	mutex   sync.Mutex // Protect targets and running state
+}
+
+type scheduled struct {
+	target  PollTarget
+	client  *Client
+	attempt time.Time // Last connection attempt
+	stop    chan struct{}
+}
+
+/*
+Creates a scheduler without devices
+*/
+func NewScheduler(options SchedulerOptions) *Scheduler {
+	if options.Concurrency == 0 {
+		options.Concurrency = 16
+	}
+	if options.Buffer == 0 {
+		options.Buffer = 256
+	}
+	if options.ReconnectDelay == 0 {
+		options.ReconnectDelay = 5 * time.Second
+	}
+	return &Scheduler{
+		Options: options,
+		targets: make(map[string]*scheduled),
+		results: make(chan PollResult, options.Buffer),
+		slots:   make(chan struct{}, options.Concurrency),
+	}
+}
+
+/*
+Returns the channel receiving the results of every device. When
+it is full, results are dropped instead of delaying the polls.
+The channel is closed by Stop
+*/
+func (s *Scheduler) Results() <-chan PollResult {
+	return s.results
+}
+
+/*
+Returns the number of results dropped because the channel was
+full
+*/
+func (s *Scheduler) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+/*
+Adds a device, polled right away when the scheduler is running
+*/
+func (s *Scheduler) Add(target PollTarget) error {
+	if target.Name == "" || target.Comm == nil {
+		return fmt.Errorf("invalid target: name and instance are required")
+	}
+	if len(target.Command) == 0 && target.Poll == nil {
+		return fmt.Errorf("invalid target: command or poll is required")
+	}
+	if target.Interval == 0 {
+		target.Interval = time.Second
+	}
+	if target.Delimiter == "" {
+		target.Delimiter = "\n"
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stopped {
+		return fmt.Errorf("scheduler is stopped")
+	}
+	if _, ok := s.targets[target.Name]; ok {
+		return fmt.Errorf("device %q is already scheduled", target.Name)
+	}
+	device := &scheduled{
+		target: target,
+		client: NewClient(target.Comm, target.Delimiter),
+		stop:   make(chan struct{}),
+	}
+	s.targets[target.Name] = device
+	if s.running {
+		s.launch(device)
+	}
+	return nil
+}
+
+/*
+Stops polling a device. A poll in progress still delivers its
+result, and the connection of the device is left as is
+*/
+func (s *Scheduler) Remove(name string) error {
+	s.mutex.Lock()
+	device, ok := s.targets[name]
+	if ok {
+		delete(s.targets, name)
+		close(device.stop)
+	}
+	s.mutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("device %q is not scheduled", name)
+	}
+	return nil
+}
+
+/*
+Starts polling the devices
+*/
+func (s *Scheduler) Start() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.running || s.stopped {
+		return fmt.Errorf("scheduler is already started")
+	}
+	s.running = true
+	for _, device := range s.targets {
+		s.launch(device)
+	}
+	return nil
+}
+
+/*
+Stops polling, waits for the polls in progress and closes the
+results channel. The connections of the devices are left as is
+and the scheduler cannot be started again
+*/
+func (s *Scheduler) Stop() error {
+	s.mutex.Lock()
+	if !s.running {
+		s.mutex.Unlock()
+		return fmt.Errorf("scheduler is not started")
+	}
+	s.running = false
+	s.stopped = true
+	for name, device := range s.targets {
+		close(device.stop)
+		delete(s.targets, name)
+	}
+	s.mutex.Unlock()
+
+	s.wg.Wait()
+	close(s.results)
+	return nil
+}
+
+/*
+Runs the polling loop of a device, must be called with the
+mutex locked
+*/
+func (s *Scheduler) launch(device *scheduled) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		// The first poll is also spread by the jitter
+		timer := time.NewTimer(jitter(device.target.Jitter))
+		defer timer.Stop()
+		for {
+			select {
+			case <-device.stop:
+				return
+			case <-timer.C:
+			}
+			select {
+			case <-device.stop:
+				return
+			case s.slots <- struct{}{}:
+			}
+			s.deliver(device, s.poll(device))
+			<-s.slots
+			timer.Reset(device.target.Interval + jitter(device.target.Jitter))
+		}
+	}()
+}
+
+/*
+Returns a random delay up to the jitter
+*/
+func jitter(maximum time.Duration) time.Duration {
+	if maximum <= 0 {
+		return 0
+	}
+	return rand.N(maximum)
+}
+
+/*
+Connects the device when needed and polls it once
+*/
+func (s *Scheduler) poll(device *scheduled) (result PollResult) {
+	target := device.target
+	result = PollResult{Device: target.Name, Time: time.Now()}
+	defer func() { result.Duration = time.Since(result.Time) }()
+
+	if !target.Comm.IsConnected() {
+		if wait := s.Options.ReconnectDelay - time.Since(device.attempt); wait > 0 {
+			result.Err = fmt.Errorf("device is disconnected, reconnecting in %v", wait.Round(time.Millisecond))
+			return result
+		}
+		device.attempt = time.Now()
+		if result.Err = target.Comm.Connect(); result.Err != nil {
+			return result
+		}
+	}
+
+	result.Data, result.Err = guard(func() ([]byte, error) {
+		if target.Poll != nil {
+			return target.Poll(device.client)
+		}
+		return device.client.QueryFresh(target.Command)
+	})
+	if Classify(result.Err) == FatalError && target.Comm.IsConnected() {
+		target.Comm.Disconnect()
+	}
+	return result
+}
+
+/*
+Delivers a result to the handler of the device and on the
+results channel
+*/
+func (s *Scheduler) deliver(device *scheduled, result PollResult) {
+	if device.target.Handler != nil {
+		guard(func() (any, error) {
+			device.target.Handler(result)
+			return nil, nil
+		})
+	}
+	select {
+	case s.results <- result:
+	default:
+		s.dropped.Add(1)
+	}
+}
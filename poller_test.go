@@ -0,0 +1,97 @@
+package unicomm_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestSchedulerPollsDevices(t *testing.T) {
+	scheduler := unicomm.NewScheduler(unicomm.SchedulerOptions{Concurrency: 1, ReconnectDelay: 20 * time.Millisecond})
+	var handled atomic.Int32
+	for _, name := range []string{"first", "second"} {
+		server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+			Handler: func(request []byte) []byte { return []byte(name + "\n") },
+		})
+		err := scheduler.Add(unicomm.PollTarget{
+			Name:     name,
+			Comm:     unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions()}),
+			Interval: 20 * time.Millisecond,
+			Jitter:   5 * time.Millisecond,
+			Command:  []byte("READ?\n"),
+			Handler:  func(unicomm.PollResult) { handled.Add(1) },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Nothing listens on the port, every poll fails to connect
+	unreachable := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: 1, DialTimeout: 50 * time.Millisecond},
+	})
+	dead := unicomm.PollTarget{Name: "dead", Comm: unreachable, Interval: 20 * time.Millisecond, Command: []byte("READ?\n")}
+	if err := scheduler.Add(dead); err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.Add(dead); err == nil {
+		t.Fatal("expected a duplicate device to be rejected")
+	}
+	if err := scheduler.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[string]int)
+	failures := 0
+	timeout := time.After(2 * time.Second)
+	for counts["first"] < 3 || counts["second"] < 3 || failures < 2 {
+		select {
+		case result := <-scheduler.Results():
+			if result.Device == "dead" {
+				if result.Err == nil {
+					t.Fatal("expected the unreachable device to fail")
+				}
+				failures++
+				continue
+			}
+			if result.Err != nil || string(result.Data) != result.Device+"\n" {
+				t.Fatalf("%s: got %q, %v", result.Device, result.Data, result.Err)
+			}
+			counts[result.Device]++
+		case <-timeout:
+			t.Fatalf("got %v and %d failures", counts, failures)
+		}
+	}
+
+	if err := scheduler.Remove("dead"); err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	for range scheduler.Results() {
+	}
+	if handled.Load() < 6 {
+		t.Fatalf("handlers called %d times", handled.Load())
+	}
+}
+
+func TestSchedulerRejectsTargetWithoutQuery(t *testing.T) {
+	scheduler := unicomm.NewScheduler(unicomm.SchedulerOptions{})
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: unicommtcp.TCPOptions{Host: "127.0.0.1", Port: 1}})
+
+	if err := scheduler.Add(unicomm.PollTarget{Name: "psu", Comm: comm}); err == nil {
+		t.Fatal("target without command nor poll was accepted")
+	}
+	poll := func(*unicomm.Client) ([]byte, error) { return nil, nil }
+	if err := scheduler.Add(unicomm.PollTarget{Name: "psu", Comm: comm, Poll: poll}); err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.Add(unicomm.PollTarget{Name: "dmm", Comm: comm, Command: []byte("READ?\n")}); err != nil {
+		t.Fatal(err)
+	}
+}
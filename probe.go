@@ -0,0 +1,51 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import "fmt"
+
+/*
+Implemented by instances able to check their link without
+transmitting, such as the modem lines of serial ports and a
+peek of TCP sockets
+*/
+type LinkProber interface {
+	ProbeLink() error
+}
+
+/*
+Checks the link without sending a byte to the device, returning
+why it is considered lost. Instances without ProbeLink are
+checked with IsConnected. Empty writes are never used, since some
+USB serial drivers block on them or report a misleading success
+*/
+func ProbeLink(comm Unicomm) error {
+	if prober, ok := As[LinkProber](comm); ok {
+		return prober.ProbeLink()
+	}
+	if !comm.IsConnected() {
+		return fmt.Errorf("there is no connection established")
+	}
+	return nil
+}
+
+/*
+Sends probe bytes that the device ignores, such as a lone NUL
+or ENQ, straight to the link without the end delimiter and below
+any transformation, so no partial command reaches the device. An
+empty probe transmits nothing and checks the link with ProbeLink
+*/
+func WriteProbe(comm Unicomm, probe []byte) error {
+	if len(probe) == 0 {
+		return ProbeLink(comm)
+	}
+	writer, ok := innermost(comm).(RawWriter)
+	if !ok {
+		return fmt.Errorf("writes without the end delimiter are not supported")
+	}
+	return writer.WriteRaw(probe)
+}
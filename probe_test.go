@@ -0,0 +1,141 @@
+package unicomm_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestProbeLinkDetectsPeerClose(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	closed := make(chan struct{})
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		<-closed
+		conn.Close()
+	}()
+
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: uint(listener.Addr().(*net.TCPAddr).Port)},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+	if err := unicomm.ProbeLink(comm); err != nil {
+		t.Fatal(err)
+	}
+
+	close(closed)
+	deadline := time.Now().Add(time.Second)
+	for unicomm.ProbeLink(comm) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("peer close not detected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if comm.IsConnected() {
+		t.Fatal("expected the instance to be disconnected")
+	}
+}
+
+func TestProbeDoesNotBlock(t *testing.T) {
+	// Writes to a pipe block until the other end reads, even empty ones
+	client, server := net.Pipe()
+	defer server.Close()
+	comm := unicommtcp.NewTCPFromConn(client, unicommtcp.TCPOptions{})
+	defer comm.Disconnect()
+
+	done := make(chan bool)
+	go func() { done <- comm.IsConnected() }()
+	select {
+	case connected := <-done:
+		if !connected {
+			t.Fatal("expected the pipe to be connected")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("IsConnected blocked")
+	}
+}
+
+func TestWriteProbeBypassesTransforms(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte { return nil },
+	})
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      server.TCPOptions(),
+		Text:     unicomm.TextOptions{Write: unicomm.NewlineCRLF},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	if err := unicomm.WriteProbe(comm, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := unicomm.WriteProbe(comm, []byte{0x05}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if received := server.Received(); string(received) != "\x05" {
+		t.Fatalf("server received %q", received)
+	}
+}
+
+func TestReconnectAfterPeerClose(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		// The first connection is closed by the peer, the second greets
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		conn.Close()
+		conn, err = listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("hello\n"))
+		time.Sleep(time.Second)
+	}()
+
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: uint(listener.Addr().(*net.TCPAddr).Port)},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	deadline := time.Now().Add(time.Second)
+	for comm.IsConnected() {
+		if time.Now().After(deadline) {
+			t.Fatal("peer close not detected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := comm.Connect(); err != nil {
+		t.Fatalf("reconnect after peer close failed: %v", err)
+	}
+	unicommtest.ExpectFrame(t, comm, "\n", "hello\n")
+}
@@ -0,0 +1,148 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommframe"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+/*
+Framing of a class of commands. Messages written with the
+profile end with the terminator and responses end with the
+delimiter, unless a codec frames both
+*/
+type Profile struct {
+	Terminator string     // Appended to the messages, empty sends them as they are
+	Delimiter  string     // Ends the responses
+	Codec      FrameCodec // Frames messages and responses, replaces the terminator and the delimiter
+}
+
+/*
+Connection with several named profiles, so commands of different
+classes can be framed differently without reconstructing the
+instance. Read, ReadUntil and Write keep the default framing
+*/
+type Profiled struct {
+	*Buffered
+	Profiles map[string]Profile
+
+	query sync.Mutex // Keeps queries atomic
+}
+
+/*
+Creates a wrapper selecting the framing of each operation from
+the named profiles
+*/
+func NewProfiled(comm Unicomm, profiles map[string]Profile) *Profiled {
+	return &Profiled{
+		Buffered: NewBuffered(comm),
+		Profiles: profiles,
+	}
+}
+
+/*
+Returns the profile with the name
+*/
+func (p *Profiled) profile(name string) (Profile, error) {
+	profile, ok := p.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile %q", name)
+	}
+	return profile, nil
+}
+
+/*
+Writes a message framed by the profile
+*/
+func (p *Profiled) WriteProfile(name string, message []byte) error {
+	profile, err := p.profile(name)
+	if err != nil {
+		return err
+	}
+	if profile.Codec != nil {
+		frame, err := encodeFrame(profile.Codec, message)
+		if err != nil {
+			return err
+		}
+		return p.Buffered.WriteRaw(frame)
+	}
+	if !bytes.HasSuffix(message, []byte(profile.Terminator)) {
+		message = append(bytes.Clone(message), profile.Terminator...)
+	}
+	return p.Buffered.WriteRaw(message)
+}
+
+/*
+Reads a response framed by the profile. Responses ended by a
+delimiter include it, while decoded frames return their payload
+*/
+func (p *Profiled) ReadProfile(name string) ([]byte, error) {
+	profile, err := p.profile(name)
+	if err != nil {
+		return nil, err
+	}
+	if profile.Codec != nil {
+		return p.readFrame(profile.Codec)
+	}
+	if profile.Delimiter == "" {
+		return nil, fmt.Errorf("profile %q has no delimiter", name)
+	}
+	return p.Buffered.ReadUntil(profile.Delimiter)
+}
+
+/*
+Reads until the pending bytes hold a complete frame. Partial
+frames are kept for the next read
+*/
+func (p *Profiled) readFrame(codec FrameCodec) ([]byte, error) {
+	p.Buffered.mutex.Lock()
+	defer p.Buffered.mutex.Unlock()
+
+	for {
+		payload, rest, err := codec.Decode(p.pending)
+		if !errors.Is(err, unicommframe.ErrIncomplete) {
+			p.pending = rest
+			return payload, err
+		}
+
+		data, err := p.Buffered.Unicomm.Read(unicommio.ReadChunkSize)
+		p.pending = append(p.pending, data...)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 {
+			return nil, unicommio.TimeoutError("read profile")
+		}
+	}
+}
+
+/*
+Writes a message and reads its response, both framed by the
+profile
+*/
+func (p *Profiled) QueryProfile(name string, message []byte) ([]byte, error) {
+	p.query.Lock()
+	defer p.query.Unlock()
+
+	if err := p.WriteProfile(name, message); err != nil {
+		return nil, err
+	}
+	return p.ReadProfile(name)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (p *Profiled) Unwrap() Unicomm {
+	return p.Buffered
+}
@@ -0,0 +1,81 @@
+package unicomm_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommframe"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestProfiles(t *testing.T) {
+	// Profiles write raw frames, also through the wrappers around them
+	for _, test := range []struct {
+		name      string
+		readUntil *unicomm.ReadUntilOptions
+		outbox    *unicomm.OutboxOptions
+	}{
+		{name: "Default"},
+		{name: "ReadUntil", readUntil: &unicomm.ReadUntilOptions{}},
+		{name: "Outbox", readUntil: &unicomm.ReadUntilOptions{}, outbox: &unicomm.OutboxOptions{}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			testProfiles(t, test.readUntil, test.outbox)
+		})
+	}
+}
+
+func testProfiles(t *testing.T, readUntil *unicomm.ReadUntilOptions, outbox *unicomm.OutboxOptions) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte {
+			switch {
+			case bytes.Equal(request, []byte("STAT?\r\n")):
+				return []byte("READY\r\n")
+			case bytes.Equal(request, []byte{0x00, 0x04, 'B', 'L', 'K', '?'}):
+				return []byte{0x00, 0x03, 0x01, '\r', '\n'}
+			}
+			return []byte("ERR\n")
+		},
+	})
+	tcp := server.TCPOptions()
+	tcp.ReadTimeout = time.Second
+	comm := unicomm.New(unicomm.Options{
+		Protocol:  unicomm.TCP,
+		TCP:       tcp,
+		Delimiter: "\n",
+		Profiles: map[string]unicomm.Profile{
+			"status":       {Terminator: "\r\n", Delimiter: "\r\n"},
+			"binary-block": {Codec: unicommframe.LengthPrefix{Size: 2}},
+		},
+		ReadUntil: readUntil,
+		Outbox:    outbox,
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	profiled, ok := unicomm.As[*unicomm.Profiled](comm)
+	if !ok {
+		t.Fatal("expected a profiled instance")
+	}
+	response, err := profiled.QueryProfile("status", []byte("STAT?"))
+	if err != nil || string(response) != "READY\r\n" {
+		t.Fatalf("got %q, %v", response, err)
+	}
+	response, err = profiled.QueryProfile("binary-block", []byte("BLK?"))
+	if err != nil || !bytes.Equal(response, []byte{0x01, '\r', '\n'}) {
+		t.Fatalf("got % X, %v", response, err)
+	}
+	if _, err := profiled.QueryProfile("missing", nil); err == nil {
+		t.Fatal("expected an unknown profile error")
+	}
+
+	// The default framing is kept by the other operations
+	if err := comm.Write([]byte("OTHER")); err != nil {
+		t.Fatal(err)
+	}
+	unicommtest.ExpectFrame(t, comm, "\n", "ERR\n")
+}
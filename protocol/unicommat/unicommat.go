@@ -0,0 +1,264 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommat
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+/*
+Transport used to exchange AT commands, usually a Unicomm
+serial instance without end delimiter
+*/
+type Transport interface {
+	ReadUntil(delimiter string) ([]byte, error)
+	Write(message []byte) error
+}
+
+type ATOptions struct {
+	// Lines starting with these prefixes are unsolicited result codes,
+	// routed to OnURC instead of the response of the current command,
+	// unless they answer it, e.g. +CREG: after AT+CREG?
+	URCPrefixes []string
+	OnURC       func(line string)
+}
+
+/*
+Returned when a command ends with a final result code other
+than OK or CONNECT. Code holds the number of +CME and +CMS
+errors, or -1 when there is none
+*/
+type ResultError struct {
+	Command string
+	Result  string
+	Code    int
+}
+
+type Modem struct {
+	Options ATOptions
+
+	transport Transport
+	mutex     sync.Mutex // Keep command and response together
+}
+
+const (
+	lineEnding = "\r\n"
+	promptSMS  = "> "
+	ctrlZ      = "\x1A"
+)
+
+// Final result codes that end a command successfully
+var successCodes = []string{"OK", "CONNECT"}
+
+// Final result codes that end a command with an error
+var errorCodes = []string{"ERROR", "NO CARRIER", "NO DIALTONE", "BUSY", "NO ANSWER"}
+
+// Default prefixes of unsolicited result codes
+var DefaultURCPrefixes = []string{
+	"RING", "+CMTI:", "+CMT:", "+CDS:", "+CREG:", "+CGREG:", "+CEREG:", "+CLIP:", "+CUSD:",
+}
+
+/*
+Describes the failed command and its result code
+*/
+func (re *ResultError) Error() string {
+	return fmt.Sprintf("command %q failed: %s", re.Command, re.Result)
+}
+
+/*
+Creates a modem that sends AT commands over the transport
+*/
+func NewModem(transport Transport, options ATOptions) *Modem {
+	if options.URCPrefixes == nil {
+		options.URCPrefixes = DefaultURCPrefixes
+	}
+	return &Modem{
+		Options:   options,
+		transport: transport,
+	}
+}
+
+/*
+Returns the prefix of the information lines answering an extended
+command, e.g. "+CREG:" for AT+CREG? or AT+CREG=2, or an empty
+string for basic commands
+*/
+func responsePrefix(command string) string {
+	name, ok := strings.CutPrefix(strings.ToUpper(command), "AT")
+	if !ok || !strings.HasPrefix(name, "+") {
+		return ""
+	}
+	if index := strings.IndexAny(name, "=?"); index >= 0 {
+		name = name[:index]
+	}
+	return name + ":"
+}
+
+/*
+Returns true if the line is an unsolicited result code and not
+the answer of the command in flight
+*/
+func (m *Modem) isURC(command string, line string) bool {
+	if prefix := responsePrefix(command); prefix != "" && strings.HasPrefix(line, prefix) {
+		return false
+	}
+	return slices.ContainsFunc(m.Options.URCPrefixes, func(prefix string) bool {
+		return strings.HasPrefix(line, prefix)
+	})
+}
+
+/*
+Routes an unsolicited result code to the handler
+*/
+func (m *Modem) route(line string) {
+	if m.Options.OnURC != nil {
+		m.Options.OnURC(line)
+	}
+}
+
+/*
+Parses a final result code, returning false for other lines
+*/
+func parseResult(command, line string) (bool, error) {
+	if slices.Contains(successCodes, line) || strings.HasPrefix(line, "CONNECT ") {
+		return true, nil
+	}
+	if slices.Contains(errorCodes, line) {
+		return true, &ResultError{Command: command, Result: line, Code: -1}
+	}
+	for _, prefix := range []string{"+CME ERROR:", "+CMS ERROR:"} {
+		if detail, ok := strings.CutPrefix(line, prefix); ok {
+			code, err := strconv.Atoi(strings.TrimSpace(detail))
+			if err != nil {
+				code = -1
+			}
+			return true, &ResultError{Command: command, Result: line, Code: code}
+		}
+	}
+	return false, nil
+}
+
+/*
+Reads the response lines until a final result code, skipping
+the echo and routing unsolicited result codes. Must be called
+with the mutex locked
+*/
+func (m *Modem) readResponse(command string) ([]string, error) {
+	var lines []string
+	for {
+		data, err := m.transport.ReadUntil(lineEnding)
+		if err != nil {
+			return lines, err
+		}
+		line := strings.TrimSpace(string(data))
+		switch {
+		case line == "" || line == command:
+			continue
+		case m.isURC(command, line):
+			m.route(line)
+			continue
+		}
+		if final, err := parseResult(command, line); final {
+			return lines, err
+		}
+		lines = append(lines, line)
+	}
+}
+
+/*
+Sends a command and returns the lines of its response, without
+the echo and the final result code. Responses ending with an
+error result code return a ResultError
+*/
+func (m *Modem) Command(command string) ([]string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if err := m.transport.Write([]byte(command + "\r")); err != nil {
+		return nil, err
+	}
+	return m.readResponse(command)
+}
+
+/*
+Sends a command that answers with the "> " prompt, such as
+AT+CMGS, then sends the payload terminated by Ctrl+Z and
+returns the response lines
+*/
+func (m *Modem) CommandWithPrompt(command string, payload string) ([]string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if err := m.transport.Write([]byte(command + "\r")); err != nil {
+		return nil, err
+	}
+	data, err := m.transport.ReadUntil(promptSMS)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(data), lineEnding) {
+		line = strings.TrimSpace(line)
+		if final, err := parseResult(command, line); final && err != nil {
+			return nil, err
+		}
+		if m.isURC(command, line) {
+			m.route(line)
+		}
+	}
+	if err := m.transport.Write([]byte(payload + ctrlZ)); err != nil {
+		return nil, err
+	}
+	return m.readResponse(command)
+}
+
+/*
+Sends a text message in text mode (AT+CMGF=1) and returns the
+message reference given by the network
+*/
+func (m *Modem) SendSMS(number string, text string) (int, error) {
+	if _, err := m.Command("AT+CMGF=1"); err != nil {
+		return 0, err
+	}
+	lines, err := m.CommandWithPrompt(fmt.Sprintf("AT+CMGS=%q", number), text)
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range lines {
+		if reference, ok := strings.CutPrefix(line, "+CMGS:"); ok {
+			return strconv.Atoi(strings.TrimSpace(reference))
+		}
+	}
+	return 0, fmt.Errorf("missing message reference in response %q", lines)
+}
+
+/*
+Reads the lines received while no command is running, routing
+unsolicited result codes, until the transport times out
+*/
+func (m *Modem) Poll() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for {
+		data, err := m.transport.ReadUntil(lineEnding)
+		if err != nil {
+			if unicommio.IsTimeout(err) {
+				return nil
+			}
+			return err
+		}
+		if line := strings.TrimSpace(string(data)); line != "" {
+			m.route(line)
+		}
+	}
+}
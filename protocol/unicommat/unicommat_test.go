@@ -0,0 +1,119 @@
+package unicommat_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommat"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+/*
+Transport that answers each command with the next canned reply
+*/
+type fakeTransport struct {
+	replies []string
+	pending []byte
+	written []string
+}
+
+func (ft *fakeTransport) Write(message []byte) error {
+	ft.written = append(ft.written, string(message))
+	ft.pending = append(ft.pending, ft.replies[0]...)
+	ft.replies = ft.replies[1:]
+	return nil
+}
+
+func (ft *fakeTransport) ReadUntil(delimiter string) ([]byte, error) {
+	index := bytes.Index(ft.pending, []byte(delimiter))
+	if index < 0 {
+		return nil, unicommio.TimeoutError("read until")
+	}
+	data := ft.pending[:index+len(delimiter)]
+	ft.pending = ft.pending[index+len(delimiter):]
+	return data, nil
+}
+
+func TestCommandWithURC(t *testing.T) {
+	var urcs []string
+	transport := &fakeTransport{replies: []string{
+		"AT+CSQ\r\r\n+CSQ: 21,99\r\n\r\n+CMTI: \"SM\",3\r\n\r\nOK\r\n",
+	}}
+	modem := unicommat.NewModem(transport, unicommat.ATOptions{
+		OnURC: func(line string) { urcs = append(urcs, line) },
+	})
+
+	lines, err := modem.Command("AT+CSQ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 || lines[0] != "+CSQ: 21,99" {
+		t.Fatalf("unexpected response %q", lines)
+	}
+	if len(urcs) != 1 || urcs[0] != "+CMTI: \"SM\",3" {
+		t.Fatalf("unexpected unsolicited codes %q", urcs)
+	}
+}
+
+func TestCommandError(t *testing.T) {
+	transport := &fakeTransport{replies: []string{"\r\n+CME ERROR: 10\r\n"}}
+	modem := unicommat.NewModem(transport, unicommat.ATOptions{})
+
+	_, err := modem.Command("AT+CPIN?")
+	var resultErr *unicommat.ResultError
+	if !errors.As(err, &resultErr) || resultErr.Code != 10 {
+		t.Fatalf("expected +CME ERROR 10, got %v", err)
+	}
+}
+
+func TestSendSMS(t *testing.T) {
+	transport := &fakeTransport{replies: []string{
+		"\r\nOK\r\n",
+		"\r\n> ",
+		"\r\n+CMGS: 42\r\n\r\nOK\r\n",
+	}}
+	modem := unicommat.NewModem(transport, unicommat.ATOptions{})
+
+	reference, err := modem.SendSMS("+5511999999999", "pump 3 stopped")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reference != 42 {
+		t.Fatalf("unexpected reference %d", reference)
+	}
+	if transport.written[2] != "pump 3 stopped\x1A" {
+		t.Fatalf("unexpected payload %q", transport.written[2])
+	}
+}
+
+func TestCommandAnsweredWithURCPrefix(t *testing.T) {
+	var urcs []string
+	transport := &fakeTransport{replies: []string{
+		"\r\n+CREG: 0,1\r\n\r\nOK\r\n",
+		"\r\nOK\r\n\r\n+CREG: 5\r\n",
+	}}
+	modem := unicommat.NewModem(transport, unicommat.ATOptions{
+		OnURC: func(line string) { urcs = append(urcs, line) },
+	})
+
+	// The solicited reply shares the prefix of the registration URC
+	lines, err := modem.Command("AT+CREG?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 || lines[0] != "+CREG: 0,1" || len(urcs) != 0 {
+		t.Fatalf("got response %q and unsolicited codes %q", lines, urcs)
+	}
+
+	// Once the command is done, the same prefix is unsolicited again
+	if _, err := modem.Command("AT"); err != nil {
+		t.Fatal(err)
+	}
+	if err := modem.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	if len(urcs) != 1 || urcs[0] != "+CREG: 5" {
+		t.Fatalf("unexpected unsolicited codes %q", urcs)
+	}
+}
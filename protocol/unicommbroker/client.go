@@ -0,0 +1,181 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommbroker
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const DefaultCallTimeout = 30 * time.Second
+
+type BrokerOptions struct {
+	Socket      string        // Path of the broker socket, defaults to DefaultSocket
+	Port        string        // Name of the port registered on the broker
+	CallTimeout time.Duration // Upper bound for each call, defaults to 30s
+}
+
+/*
+Client of a port shared by a broker, so several processes can
+use the same port. Timeouts and the end delimiter are those of
+the port on the broker
+*/
+type UnicommBroker struct {
+	Options    BrokerOptions
+	Connection net.Conn
+
+	mutex sync.Mutex // Keep each request with its response
+}
+
+/*
+Creates a client of a port shared by a broker
+*/
+func NewBroker(options BrokerOptions) *UnicommBroker {
+	if options.Socket == "" {
+		options.Socket = DefaultSocket
+	}
+	if options.CallTimeout == 0 {
+		options.CallTimeout = DefaultCallTimeout
+	}
+	return &UnicommBroker{Options: options}
+}
+
+/*
+Sends a request and waits for its response. The connection is
+closed when the socket fails
+*/
+func (ub *UnicommBroker) call(kind byte, field []byte, timeout time.Duration) ([]byte, error) {
+	ub.mutex.Lock()
+	defer ub.mutex.Unlock()
+
+	if ub.Connection == nil {
+		return nil, fmt.Errorf("there is no connection established")
+	}
+	ub.Connection.SetDeadline(time.Now().Add(timeout))
+	err := writeMessage(ub.Connection, kind, field)
+	var status byte
+	var fields [][]byte
+	if err == nil {
+		status, fields, err = readMessage(ub.Connection, 3)
+	}
+	if err != nil {
+		ub.Connection.Close()
+		ub.Connection = nil
+		return nil, err
+	}
+	if status != statusOK {
+		return fields[0], decodeError(string(fields[1]), fields[2])
+	}
+	return fields[0], nil
+}
+
+/*
+Connects to the broker and opens the port
+*/
+func (ub *UnicommBroker) Connect() error {
+	ub.mutex.Lock()
+	if ub.Connection != nil {
+		ub.mutex.Unlock()
+		return fmt.Errorf("there is a connection already established")
+	}
+	conn, err := net.DialTimeout("unix", ub.Options.Socket, ub.Options.CallTimeout)
+	if err != nil {
+		ub.mutex.Unlock()
+		return err
+	}
+	ub.Connection = conn
+	ub.mutex.Unlock()
+
+	if _, err := ub.call(opOpen, []byte(ub.Options.Port), ub.Options.CallTimeout); err != nil {
+		ub.Disconnect()
+		return err
+	}
+	return nil
+}
+
+/*
+Leaves the port, releasing it if acquired
+*/
+func (ub *UnicommBroker) Disconnect() error {
+	ub.mutex.Lock()
+	defer ub.mutex.Unlock()
+
+	if ub.Connection == nil {
+		return fmt.Errorf("there is no connection established")
+	}
+	err := ub.Connection.Close()
+	ub.Connection = nil
+	return err
+}
+
+/*
+Returns true if the broker is reachable and the port connected
+*/
+func (ub *UnicommBroker) IsConnected() bool {
+	data, err := ub.call(opIsConnected, nil, ub.Options.CallTimeout)
+	return err == nil && len(data) == 1
+}
+
+/*
+Reads a number of bytes from the port
+*/
+func (ub *UnicommBroker) Read(n uint) ([]byte, error) {
+	return ub.call(opRead, binary.BigEndian.AppendUint32(nil, uint32(n)), ub.Options.CallTimeout)
+}
+
+/*
+Reads data from the port until a target delimiter is found
+*/
+func (ub *UnicommBroker) ReadUntil(delimiter string) ([]byte, error) {
+	return ub.call(opReadUntil, []byte(delimiter), ub.Options.CallTimeout)
+}
+
+/*
+Reads everything currently buffered by the port
+*/
+func (ub *UnicommBroker) ReadAvailable() ([]byte, error) {
+	return ub.call(opReadAvailable, nil, ub.Options.CallTimeout)
+}
+
+/*
+Writes an array of bytes to the port, appending the end
+delimiter of the port
+*/
+func (ub *UnicommBroker) Write(message []byte) error {
+	_, err := ub.call(opWrite, message, ub.Options.CallTimeout)
+	return err
+}
+
+/*
+Writes an array of bytes to the port as it is
+*/
+func (ub *UnicommBroker) WriteRaw(message []byte) error {
+	_, err := ub.call(opWriteRaw, message, ub.Options.CallTimeout)
+	return err
+}
+
+/*
+Reserves the port, waiting up to the timeout for other clients
+to release it. Until Release or Disconnect, the operations of
+the other clients wait
+*/
+func (ub *UnicommBroker) Acquire(timeout time.Duration) error {
+	field := binary.BigEndian.AppendUint32(nil, uint32(timeout.Milliseconds()))
+	_, err := ub.call(opAcquire, field, ub.Options.CallTimeout+timeout)
+	return err
+}
+
+/*
+Frees the port reserved by Acquire
+*/
+func (ub *UnicommBroker) Release() error {
+	_, err := ub.call(opRelease, nil, ub.Options.CallTimeout)
+	return err
+}
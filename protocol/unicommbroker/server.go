@@ -0,0 +1,441 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommbroker
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+// Socket used when none is configured
+var DefaultSocket = filepath.Join(os.TempDir(), "unicomm-broker.sock")
+
+/*
+Port owned by the broker, usually a serial instance
+*/
+type Device interface {
+	Connect() error
+	Disconnect() error
+	IsConnected() bool
+	Read(n uint) ([]byte, error)
+	Write(message []byte) error
+}
+
+type ServerOptions struct {
+	Socket      string        // Path of the Unix socket, defaults to DefaultSocket
+	LockTimeout time.Duration // Longest wait for a port acquired by another client, defaults to 10s
+}
+
+/*
+Broker owning physical ports and sharing them with the clients
+of other processes through a local socket. Operations are
+serialized per port, and a client may acquire a port to run
+several operations without interleaving
+*/
+type Server struct {
+	Options ServerOptions
+
+	listener net.Listener
+	ports    map[string]*port
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+	mutex    sync.Mutex // Protect the ports and the connections
+}
+
+type port struct {
+	device   Device
+	clients  int
+	owner    *session      // Client that acquired the port, nil when free
+	released chan struct{} // Closed and replaced whenever the port is released
+	mutex    sync.Mutex    // Protect the clients and the owner
+	io       sync.Mutex    // Serialize the operations on the device
+}
+
+type session struct {
+	conn net.Conn
+	port *port
+}
+
+/*
+Creates a broker, ports are added with Register before Start
+*/
+func NewServer(options ServerOptions) *Server {
+	if options.Socket == "" {
+		options.Socket = DefaultSocket
+	}
+	if options.LockTimeout == 0 {
+		options.LockTimeout = 10 * time.Second
+	}
+	return &Server{
+		Options: options,
+		ports:   make(map[string]*port),
+		conns:   make(map[net.Conn]struct{}),
+	}
+}
+
+/*
+Shares a device under a name. The device is connected when its
+first client opens it and disconnected when the last one leaves
+*/
+func (s *Server) Register(name string, device Device) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.ports[name] = &port{device: device, released: make(chan struct{})}
+}
+
+/*
+Listens on the socket, replacing a stale socket left by a
+broker that did not stop cleanly
+*/
+func (s *Server) Start() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.listener != nil {
+		return fmt.Errorf("broker is already started")
+	}
+	if _, err := os.Stat(s.Options.Socket); err == nil {
+		conn, err := net.Dial("unix", s.Options.Socket)
+		if err == nil {
+			conn.Close()
+			return fmt.Errorf("another broker is listening on %s", s.Options.Socket)
+		}
+		os.Remove(s.Options.Socket)
+	}
+
+	listener, err := net.Listen("unix", s.Options.Socket)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	s.wg.Add(1)
+	go s.accept(listener)
+	return nil
+}
+
+/*
+Stops the broker, disconnecting the clients and the devices
+*/
+func (s *Server) Stop() error {
+	s.mutex.Lock()
+	if s.listener == nil {
+		s.mutex.Unlock()
+		return fmt.Errorf("broker is not started")
+	}
+	err := s.listener.Close()
+	s.listener = nil
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mutex.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+/*
+Returns the address of the socket
+*/
+func (s *Server) Addr() net.Addr {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+/*
+Accepts clients until the listener is closed
+*/
+func (s *Server) accept(listener net.Listener) {
+	defer s.wg.Done()
+
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			continue
+		}
+		s.mutex.Lock()
+		s.conns[conn] = struct{}{}
+		s.mutex.Unlock()
+
+		s.wg.Add(1)
+		go s.serve(&session{conn: conn})
+	}
+}
+
+/*
+Answers the requests of a client until it leaves
+*/
+func (s *Server) serve(client *session) {
+	defer s.wg.Done()
+	defer s.leave(client)
+
+	for {
+		kind, fields, err := readMessage(client.conn, 1)
+		if err != nil {
+			return
+		}
+		data, err := s.handle(client, kind, fields[0])
+		status, message, errorKind := statusOK, "", []byte(nil)
+		if err != nil {
+			status, message, errorKind = statusError, err.Error(), encodeError(err)
+		}
+		if err := writeMessage(client.conn, status, data, []byte(message), errorKind); err != nil {
+			return
+		}
+	}
+}
+
+/*
+Releases the port of a client that left, disconnecting the
+device when no client is left
+*/
+func (s *Server) leave(client *session) {
+	s.mutex.Lock()
+	delete(s.conns, client.conn)
+	s.mutex.Unlock()
+	client.conn.Close()
+
+	p := client.port
+	if p == nil {
+		return
+	}
+	p.release(client)
+
+	p.io.Lock()
+	defer p.io.Unlock()
+
+	p.mutex.Lock()
+	p.clients--
+	last := p.clients == 0
+	p.mutex.Unlock()
+	if last && p.device.IsConnected() {
+		p.device.Disconnect()
+	}
+}
+
+/*
+Runs a request of a client
+*/
+func (s *Server) handle(client *session, kind byte, field []byte) ([]byte, error) {
+	if kind == opOpen {
+		return nil, s.open(client, string(field))
+	}
+	p := client.port
+	if p == nil {
+		return nil, fmt.Errorf("there is no port opened")
+	}
+	deadline := time.Now().Add(s.Options.LockTimeout)
+
+	var data []byte
+	var err error
+	switch kind {
+	case opIsConnected:
+		// Answered without waiting for the port, which may be held by
+		// a long operation or acquired by another client
+		if p.device.IsConnected() {
+			data = []byte{1}
+		}
+	case opRead:
+		if len(field) != 4 {
+			return nil, fmt.Errorf("invalid read size")
+		}
+		doErr := p.do(client, deadline, func() {
+			data, err = p.device.Read(uint(binary.BigEndian.Uint32(field)))
+		})
+		err = errors.Join(doErr, err)
+	case opReadUntil:
+		doErr := p.do(client, deadline, func() {
+			data, err = readUntil(p.device, string(field))
+		})
+		err = errors.Join(doErr, err)
+	case opReadAvailable:
+		doErr := p.do(client, deadline, func() {
+			data, err = readAvailable(p.device)
+		})
+		err = errors.Join(doErr, err)
+	case opWrite:
+		doErr := p.do(client, deadline, func() {
+			err = p.device.Write(field)
+		})
+		err = errors.Join(doErr, err)
+	case opWriteRaw:
+		doErr := p.do(client, deadline, func() {
+			err = writeRaw(p.device, field)
+		})
+		err = errors.Join(doErr, err)
+	case opAcquire:
+		if len(field) == 4 {
+			timeout := time.Duration(binary.BigEndian.Uint32(field)) * time.Millisecond
+			deadline = time.Now().Add(timeout)
+		}
+		err = p.acquire(client, deadline)
+	case opRelease:
+		p.release(client)
+	default:
+		err = fmt.Errorf("unknown broker operation %d", kind)
+	}
+	return data, err
+}
+
+/*
+Attaches a client to a port, connecting the device for the
+first client
+*/
+func (s *Server) open(client *session, name string) error {
+	if client.port != nil {
+		return fmt.Errorf("there is a port already opened")
+	}
+	s.mutex.Lock()
+	p, ok := s.ports[name]
+	s.mutex.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown port %q", name)
+	}
+
+	p.io.Lock()
+	defer p.io.Unlock()
+
+	if !p.device.IsConnected() {
+		if err := p.device.Connect(); err != nil {
+			return err
+		}
+	}
+	p.mutex.Lock()
+	p.clients++
+	p.mutex.Unlock()
+	client.port = p
+	return nil
+}
+
+/*
+Waits until the port is free or owned by the client
+*/
+func (p *port) wait(client *session, deadline time.Time) error {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	for {
+		p.mutex.Lock()
+		if p.owner == nil || p.owner == client {
+			p.mutex.Unlock()
+			return nil
+		}
+		released := p.released
+		p.mutex.Unlock()
+
+		select {
+		case <-released:
+		case <-timer.C:
+			return fmt.Errorf("port is acquired by another client")
+		}
+	}
+}
+
+/*
+Runs an operation on the device once no other client owns the
+port
+*/
+func (p *port) do(client *session, deadline time.Time, operation func()) error {
+	for {
+		if err := p.wait(client, deadline); err != nil {
+			return err
+		}
+		p.io.Lock()
+		p.mutex.Lock()
+		allowed := p.owner == nil || p.owner == client
+		p.mutex.Unlock()
+		if allowed {
+			operation()
+			p.io.Unlock()
+			return nil
+		}
+		// Acquired by another client between the wait and the lock
+		p.io.Unlock()
+	}
+}
+
+/*
+Reserves the port for the client until it releases it or leaves
+*/
+func (p *port) acquire(client *session, deadline time.Time) error {
+	for {
+		if err := p.wait(client, deadline); err != nil {
+			return err
+		}
+		p.mutex.Lock()
+		if p.owner == nil || p.owner == client {
+			p.owner = client
+			p.mutex.Unlock()
+			return nil
+		}
+		p.mutex.Unlock()
+	}
+}
+
+/*
+Frees the port if the client owns it
+*/
+func (p *port) release(client *session) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.owner != client {
+		return
+	}
+	p.owner = nil
+	close(p.released)
+	p.released = make(chan struct{})
+}
+
+/*
+Reads what the device has buffered, falling back to a single
+read on devices unable to drain their buffer
+*/
+func readAvailable(device Device) ([]byte, error) {
+	if reader, ok := device.(interface{ ReadAvailable() ([]byte, error) }); ok {
+		return reader.ReadAvailable()
+	}
+	return device.Read(unicommio.ReadChunkSize)
+}
+
+/*
+Reads until the delimiter on devices reading delimited frames
+*/
+func readUntil(device Device, delimiter string) ([]byte, error) {
+	if reader, ok := device.(interface {
+		ReadUntil(delimiter string) ([]byte, error)
+	}); ok {
+		return reader.ReadUntil(delimiter)
+	}
+	return nil, fmt.Errorf("read until is not supported")
+}
+
+/*
+Writes without the end delimiter on devices supporting it
+*/
+func writeRaw(device Device, message []byte) error {
+	if writer, ok := device.(interface{ WriteRaw([]byte) error }); ok {
+		return writer.WriteRaw(message)
+	}
+	return fmt.Errorf("write raw is not supported")
+}
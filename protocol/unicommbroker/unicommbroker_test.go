@@ -0,0 +1,225 @@
+package unicommbroker_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommbroker"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestBrokerSharesPort(t *testing.T) {
+	device := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	options := device.TCPOptions()
+	options.ReadTimeout = 50 * time.Millisecond
+
+	socket := filepath.Join(t.TempDir(), "broker.sock")
+	server := unicommbroker.NewServer(unicommbroker.ServerOptions{Socket: socket})
+	server.Register("psu", unicommtcp.NewTCP(options))
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	first := unicommbroker.NewBroker(unicommbroker.BrokerOptions{Socket: socket, Port: "psu"})
+	second := unicommbroker.NewBroker(unicommbroker.BrokerOptions{Socket: socket, Port: "psu"})
+	for _, client := range []*unicommbroker.UnicommBroker{first, second} {
+		if err := client.Connect(); err != nil {
+			t.Fatal(err)
+		}
+		defer client.Disconnect()
+	}
+
+	// The second client waits while the first one holds the port
+	if err := first.Acquire(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	written := make(chan error, 1)
+	go func() { written <- second.Write([]byte("B\n")) }()
+
+	if err := first.Write([]byte("A\n")); err != nil {
+		t.Fatal(err)
+	}
+	unicommtest.ExpectFrame(t, first, "\n", "A\n")
+	select {
+	case err := <-written:
+		t.Fatalf("write of the second client was not held: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if err := first.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-written; err != nil {
+		t.Fatal(err)
+	}
+	unicommtest.ExpectFrame(t, second, "\n", "B\n")
+
+	// Timeouts of the port keep their partial data and meaning
+	if err := second.Write([]byte("PART")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := second.ReadUntil("\n")
+	if !errors.Is(err, unicommio.ErrTimeout) || string(data) != "PART" {
+		t.Fatalf("expected partial data with a timeout, got %q, %v", data, err)
+	}
+
+	unknown := unicommbroker.NewBroker(unicommbroker.BrokerOptions{Socket: socket, Port: "missing"})
+	if err := unknown.Connect(); err == nil {
+		t.Fatal("expected an unknown port error")
+	}
+}
+
+/*
+Device failing its operations with the configured errors, without
+WriteRaw
+*/
+type failingDevice struct {
+	readErr  error
+	writeErr error
+	writes   int
+	mutex    sync.Mutex
+}
+
+func (fd *failingDevice) Connect() error    { return nil }
+func (fd *failingDevice) Disconnect() error { return nil }
+func (fd *failingDevice) IsConnected() bool { return true }
+
+func (fd *failingDevice) Read(n uint) ([]byte, error) {
+	fd.mutex.Lock()
+	defer fd.mutex.Unlock()
+
+	return []byte("pa"), fd.readErr
+}
+
+func (fd *failingDevice) fail(readErr, writeErr error) {
+	fd.mutex.Lock()
+	defer fd.mutex.Unlock()
+
+	fd.readErr, fd.writeErr = readErr, writeErr
+}
+
+func (fd *failingDevice) Write(message []byte) error {
+	fd.mutex.Lock()
+	defer fd.mutex.Unlock()
+
+	fd.writes++
+	return fd.writeErr
+}
+
+/*
+Starts a broker with the device and returns a connected client
+*/
+func serveDevice(t *testing.T, device unicommbroker.Device, lockTimeout time.Duration) *unicommbroker.UnicommBroker {
+	socket := filepath.Join(t.TempDir(), "broker.sock")
+	server := unicommbroker.NewServer(unicommbroker.ServerOptions{Socket: socket, LockTimeout: lockTimeout})
+	server.Register("dev", device)
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Stop() })
+
+	client := unicommbroker.NewBroker(unicommbroker.BrokerOptions{Socket: socket, Port: "dev"})
+	if err := client.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Disconnect() })
+	return client
+}
+
+func TestBrokerErrorKinds(t *testing.T) {
+	device := &failingDevice{}
+	client := serveDevice(t, device, 0)
+
+	tests := []struct {
+		name  string
+		err   error
+		match error
+		class unicommio.ErrorClass
+	}{
+		{"eof", io.EOF, io.EOF, unicommio.FatalError},
+		{"wrapped eof", fmt.Errorf("port lost: %w", io.EOF), io.EOF, unicommio.FatalError},
+		{"closed", net.ErrClosed, net.ErrClosed, unicommio.FatalError},
+		{"errno", syscall.ENODEV, syscall.ENODEV, unicommio.FatalError},
+		{"timeout", unicommio.TimeoutError("read"), unicommio.ErrTimeout, unicommio.TemporaryError},
+		{"other", errors.New("parity error"), nil, unicommio.UnknownError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			device.fail(test.err, nil)
+			data, err := client.Read(2)
+			if string(data) != "pa" {
+				t.Fatalf("data = %q, want the partial data", data)
+			}
+			if err == nil || err.Error() != test.err.Error() {
+				t.Fatalf("Read = %v, want %v", err, test.err)
+			}
+			if test.match != nil && !errors.Is(err, test.match) {
+				t.Fatalf("Read = %v, does not match %v", err, test.match)
+			}
+			if class := unicommio.Classify(err); class != test.class {
+				t.Fatalf("class = %v, want %v", class, test.class)
+			}
+		})
+	}
+
+	partialErr := &unicommio.PartialWriteError{Written: 3, Expected: 8, Err: syscall.EPIPE}
+	device.fail(nil, partialErr)
+	err := client.Write([]byte("MEAS?"))
+	var partial *unicommio.PartialWriteError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Write = %v, want a partial write", err)
+	}
+	if partial.Written != 3 || partial.Expected != 8 || !errors.Is(err, syscall.EPIPE) {
+		t.Fatalf("partial write = %+v", partial)
+	}
+	if err.Error() != partialErr.Error() {
+		t.Fatalf("message = %q, want %q", err, partialErr)
+	}
+}
+
+func TestBrokerIsConnectedWhileAcquired(t *testing.T) {
+	device := &failingDevice{}
+	first := serveDevice(t, device, 2*time.Second)
+
+	second := unicommbroker.NewBroker(first.Options)
+	if err := second.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer second.Disconnect()
+
+	if err := first.Acquire(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	defer first.Release()
+
+	start := time.Now()
+	if !second.IsConnected() {
+		t.Fatal("IsConnected = false while the port is acquired")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("IsConnected waited %v for the acquired port", elapsed)
+	}
+}
+
+func TestBrokerWriteRawNotSupported(t *testing.T) {
+	device := &failingDevice{}
+	client := serveDevice(t, device, 0)
+
+	err := client.WriteRaw([]byte("RAW"))
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Fatalf("WriteRaw = %v, want a not supported error", err)
+	}
+	if device.writes != 0 {
+		t.Fatal("WriteRaw fell back to Write, appending the delimiter")
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommbroker
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+// Operations requested by the clients
+const (
+	opOpen byte = iota + 1
+	opIsConnected
+	opRead
+	opReadUntil
+	opReadAvailable
+	opWrite
+	opWriteRaw
+	opAcquire
+	opRelease
+)
+
+// Status of the responses
+const (
+	statusOK byte = iota
+	statusError
+)
+
+// Kinds of the errors sent to the clients, which decode them into
+// errors classified as those of the device
+const (
+	errorOther byte = iota
+	errorTimeout
+	errorEOF
+	errorClosed
+	errorErrno        // Followed by the error number
+	errorPartialWrite // Followed by the counts, the cause message and its kind
+)
+
+// Longest field accepted on the socket
+const maxFieldSize = 16 << 20
+
+/*
+Writes a message as its kind followed by its fields, each one
+prefixed by its length
+*/
+func writeMessage(w io.Writer, kind byte, fields ...[]byte) error {
+	size := 1
+	for _, field := range fields {
+		size += 4 + len(field)
+	}
+	message := make([]byte, 0, size)
+	message = append(message, kind)
+	for _, field := range fields {
+		message = binary.BigEndian.AppendUint32(message, uint32(len(field)))
+		message = append(message, field...)
+	}
+	_, err := w.Write(message)
+	return err
+}
+
+/*
+Reads a message with a number of fields
+*/
+func readMessage(r io.Reader, count int) (byte, [][]byte, error) {
+	var kind [1]byte
+	if _, err := io.ReadFull(r, kind[:]); err != nil {
+		return 0, nil, err
+	}
+
+	fields := make([][]byte, count)
+	for index := range fields {
+		var length [4]byte
+		if _, err := io.ReadFull(r, length[:]); err != nil {
+			return 0, nil, err
+		}
+		size := binary.BigEndian.Uint32(length[:])
+		if size > maxFieldSize {
+			return 0, nil, fmt.Errorf("broker field of %d bytes exceeds the limit", size)
+		}
+		fields[index] = make([]byte, size)
+		if _, err := io.ReadFull(r, fields[index]); err != nil {
+			return 0, nil, err
+		}
+	}
+	return kind[0], fields, nil
+}
+
+/*
+Error received from the broker, keeping the message of the
+device and matching the cause of its kind
+*/
+type remoteError struct {
+	message string
+	cause   error
+}
+
+/*
+Returns the message of the device
+*/
+func (re *remoteError) Error() string {
+	return re.message
+}
+
+/*
+Returns the cause of the kind, e.g. io.EOF
+*/
+func (re *remoteError) Unwrap() error {
+	return re.cause
+}
+
+/*
+Encodes the kind of an error, partial writes along with their
+counts and cause
+*/
+func encodeError(err error) []byte {
+	var partial *unicommio.PartialWriteError
+	var errno syscall.Errno
+
+	switch {
+	case errors.As(err, &partial):
+		kind := binary.BigEndian.AppendUint32([]byte{errorPartialWrite}, uint32(partial.Written))
+		kind = binary.BigEndian.AppendUint32(kind, uint32(partial.Expected))
+		if partial.Err == nil {
+			return kind
+		}
+		message := partial.Err.Error()
+		kind = binary.BigEndian.AppendUint32(kind, uint32(len(message)))
+		kind = append(kind, message...)
+		return append(kind, encodeError(partial.Err)...)
+	case errors.As(err, &errno):
+		return binary.BigEndian.AppendUint32([]byte{errorErrno}, uint32(errno))
+	case unicommio.IsTimeout(err):
+		return []byte{errorTimeout}
+	case errors.Is(err, io.EOF):
+		return []byte{errorEOF}
+	case errors.Is(err, net.ErrClosed), errors.Is(err, os.ErrClosed):
+		return []byte{errorClosed}
+	}
+	return []byte{errorOther}
+}
+
+/*
+Decodes an error received from the broker, kinds that are not
+known keeping only the message
+*/
+func decodeError(message string, kind []byte) error {
+	if len(kind) == 0 {
+		return errors.New(message)
+	}
+
+	switch kind[0] {
+	case errorPartialWrite:
+		if len(kind) < 9 {
+			break
+		}
+		partial := &unicommio.PartialWriteError{
+			Written:  int(binary.BigEndian.Uint32(kind[1:])),
+			Expected: int(binary.BigEndian.Uint32(kind[5:])),
+		}
+		if cause := kind[9:]; len(cause) >= 4 {
+			size := binary.BigEndian.Uint32(cause)
+			if uint32(len(cause)-4) >= size {
+				partial.Err = decodeError(string(cause[4:4+size]), cause[4+size:])
+			}
+		}
+		return partial
+	case errorErrno:
+		if len(kind) == 5 {
+			return &remoteError{message, syscall.Errno(binary.BigEndian.Uint32(kind[1:]))}
+		}
+	case errorTimeout:
+		return &remoteError{message, unicommio.ErrTimeout}
+	case errorEOF:
+		return &remoteError{message, io.EOF}
+	case errorClosed:
+		return &remoteError{message, net.ErrClosed}
+	}
+	return errors.New(message)
+}
@@ -0,0 +1,212 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommchecksum
+
+import (
+	"encoding/binary"
+	"hash"
+	"sync"
+)
+
+/*
+Parameters of a CRC-16 in the Rocksoft model. Reflected CRCs
+process the bits of each byte least significant first
+*/
+type Params16 struct {
+	Poly      uint16
+	Init      uint16
+	Reflected bool   // Input and output are reflected
+	XorOut    uint16 // Applied to the final value
+	BigEndian bool   // Byte order of the CRC on the link
+}
+
+type Table16 [256]uint16
+
+/*
+Streaming CRC-16, so large frames are checked as they arrive
+instead of recomputed
+*/
+type Hash16 interface {
+	hash.Hash
+	Sum16() uint16
+}
+
+var (
+	// CRC-16/MODBUS, sent low byte first
+	Modbus = Params16{Poly: 0x8005, Init: 0xFFFF, Reflected: true}
+
+	// CRC-16/CCITT-FALSE, also known as CRC-16/IBM-3740
+	CCITT = Params16{Poly: 0x1021, Init: 0xFFFF, BigEndian: true}
+
+	// CRC-16/XMODEM, used by XMODEM, YMODEM and ZMODEM
+	XModem = Params16{Poly: 0x1021, BigEndian: true}
+
+	// CRC-16/KERMIT, the reflected CCITT used by Kermit and X.25 links
+	Kermit = Params16{Poly: 0x1021, Reflected: true}
+)
+
+var tables16 sync.Map // Tables of the parameters already used
+
+/*
+Returns the lookup table of the parameters, built on first use
+and shared afterwards
+*/
+func MakeTable16(params Params16) *Table16 {
+	key := [2]any{params.Poly, params.Reflected}
+	if table, ok := tables16.Load(key); ok {
+		return table.(*Table16)
+	}
+
+	table := new(Table16)
+	for index := range table {
+		if params.Reflected {
+			poly := reflect16(params.Poly)
+			crc := uint16(index)
+			for range 8 {
+				if crc&1 != 0 {
+					crc = crc>>1 ^ poly
+				} else {
+					crc >>= 1
+				}
+			}
+			table[index] = crc
+			continue
+		}
+		crc := uint16(index) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ params.Poly
+			} else {
+				crc <<= 1
+			}
+		}
+		table[index] = crc
+	}
+	actual, _ := tables16.LoadOrStore(key, table)
+	return actual.(*Table16)
+}
+
+/*
+Returns the value with its 16 bits in reverse order
+*/
+func reflect16(value uint16) uint16 {
+	var reflected uint16
+	for range 16 {
+		reflected = reflected<<1 | value&1
+		value >>= 1
+	}
+	return reflected
+}
+
+/*
+Continues a CRC over more data. The CRC is the internal value,
+before XorOut is applied
+*/
+func update16(crc uint16, params Params16, table *Table16, data []byte) uint16 {
+	if params.Reflected {
+		for _, b := range data {
+			crc = crc>>8 ^ table[byte(crc)^b]
+		}
+		return crc
+	}
+	for _, b := range data {
+		crc = crc<<8 ^ table[byte(crc>>8)^b]
+	}
+	return crc
+}
+
+/*
+Returns the initial value of the register, reflected along with
+the input when needed
+*/
+func (p Params16) start() uint16 {
+	if p.Reflected {
+		return reflect16(p.Init)
+	}
+	return p.Init
+}
+
+/*
+Returns the CRC-16 of the data
+*/
+func CRC16(data []byte, params Params16) uint16 {
+	return update16(params.start(), params, MakeTable16(params), data) ^ params.XorOut
+}
+
+/*
+Appends the CRC-16 of the data in the byte order of the link
+*/
+func AppendCRC16(data []byte, params Params16) []byte {
+	return params.append(data, CRC16(data, params))
+}
+
+/*
+Appends a CRC in the byte order of the link
+*/
+func (p Params16) append(data []byte, crc uint16) []byte {
+	if p.BigEndian {
+		return binary.BigEndian.AppendUint16(data, crc)
+	}
+	return binary.LittleEndian.AppendUint16(data, crc)
+}
+
+type digest16 struct {
+	params Params16
+	table  *Table16
+	crc    uint16
+}
+
+/*
+Creates a streaming CRC-16. Sum appends the CRC in the byte
+order of the link
+*/
+func NewCRC16(params Params16) Hash16 {
+	return &digest16{params: params, table: MakeTable16(params), crc: params.start()}
+}
+
+/*
+Adds data to the running CRC
+*/
+func (d *digest16) Write(data []byte) (int, error) {
+	d.crc = update16(d.crc, d.params, d.table, data)
+	return len(data), nil
+}
+
+/*
+Returns the CRC of the data written so far
+*/
+func (d *digest16) Sum16() uint16 {
+	return d.crc ^ d.params.XorOut
+}
+
+/*
+Appends the CRC in the byte order of the link
+*/
+func (d *digest16) Sum(in []byte) []byte {
+	return d.params.append(in, d.Sum16())
+}
+
+/*
+Restarts the CRC
+*/
+func (d *digest16) Reset() {
+	d.crc = d.params.start()
+}
+
+/*
+Returns the size of the CRC in bytes
+*/
+func (d *digest16) Size() int {
+	return 2
+}
+
+/*
+Returns one, the CRC accepts writes of any size
+*/
+func (d *digest16) BlockSize() int {
+	return 1
+}
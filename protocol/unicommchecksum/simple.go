@@ -0,0 +1,126 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommchecksum
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Returns the Longitudinal Redundancy Check of Modbus ASCII, the
+two's complement of the sum of the bytes
+*/
+func LRC(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+	return -sum
+}
+
+/*
+Returns the XOR of the bytes, the checksum of NMEA 0183 and of
+many simple serial protocols
+*/
+func XOR(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum ^= b
+	}
+	return sum
+}
+
+/*
+Checks the checksum of an NMEA sentence such as
+"$GPGGA,...*47", computed over the characters between '$' (or
+'!') and '*'
+*/
+func VerifyNMEA(sentence string) error {
+	sentence = strings.TrimRight(sentence, "\r\n")
+	start := strings.IndexAny(sentence, "$!")
+	end := strings.LastIndexByte(sentence, '*')
+	if start < 0 || end < start || len(sentence) != end+3 {
+		return fmt.Errorf("invalid NMEA sentence %q", sentence)
+	}
+	expected := fmt.Sprintf("%02X", XOR([]byte(sentence[start+1:end])))
+	if !strings.EqualFold(sentence[end+1:], expected) {
+		return fmt.Errorf("NMEA checksum mismatch: got %s, expected %s", sentence[end+1:], expected)
+	}
+	return nil
+}
+
+type digest8 struct {
+	sum byte
+	lrc bool // Sum of the bytes instead of XOR
+}
+
+/*
+Creates a streaming LRC
+*/
+func NewLRC() Hash8 {
+	return &digest8{lrc: true}
+}
+
+/*
+Creates a streaming XOR checksum
+*/
+func NewXOR() Hash8 {
+	return &digest8{}
+}
+
+/*
+Adds data to the running checksum
+*/
+func (d *digest8) Write(data []byte) (int, error) {
+	for _, b := range data {
+		if d.lrc {
+			d.sum += b
+		} else {
+			d.sum ^= b
+		}
+	}
+	return len(data), nil
+}
+
+/*
+Returns the checksum of the data written so far
+*/
+func (d *digest8) Sum8() byte {
+	if d.lrc {
+		return -d.sum
+	}
+	return d.sum
+}
+
+/*
+Appends the checksum
+*/
+func (d *digest8) Sum(in []byte) []byte {
+	return append(in, d.Sum8())
+}
+
+/*
+Restarts the checksum
+*/
+func (d *digest8) Reset() {
+	d.sum = 0
+}
+
+/*
+Returns the size of the checksum in bytes
+*/
+func (d *digest8) Size() int {
+	return 1
+}
+
+/*
+Returns one, the checksum accepts writes of any size
+*/
+func (d *digest8) BlockSize() int {
+	return 1
+}
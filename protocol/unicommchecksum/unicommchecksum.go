@@ -0,0 +1,115 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommchecksum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"strings"
+)
+
+/*
+A checksum selectable by name. New returns a streaming hash
+whose Sum appends the checksum as sent on the link
+*/
+type Algorithm struct {
+	Name string
+	Size int // Bytes of the checksum on the link
+	New  func() hash.Hash
+}
+
+/*
+Streaming 8-bit checksum
+*/
+type Hash8 interface {
+	hash.Hash
+	Sum8() byte
+}
+
+var algorithms = []Algorithm{
+	{Name: "crc16-modbus", Size: 2, New: func() hash.Hash { return NewCRC16(Modbus) }},
+	{Name: "crc16-ccitt", Size: 2, New: func() hash.Hash { return NewCRC16(CCITT) }},
+	{Name: "crc16-xmodem", Size: 2, New: func() hash.Hash { return NewCRC16(XModem) }},
+	{Name: "crc16-kermit", Size: 2, New: func() hash.Hash { return NewCRC16(Kermit) }},
+	{Name: "crc32", Size: 4, New: func() hash.Hash { return crc32.NewIEEE() }},
+	{Name: "crc32c", Size: 4, New: func() hash.Hash { return crc32.New(crc32.MakeTable(crc32.Castagnoli)) }},
+	{Name: "lrc", Size: 1, New: func() hash.Hash { return NewLRC() }},
+	{Name: "xor", Size: 1, New: func() hash.Hash { return NewXOR() }},
+}
+
+/*
+Returns the algorithm with the given name, e.g. "crc16-modbus",
+"crc32" or "xor"
+*/
+func Lookup(name string) (Algorithm, error) {
+	for _, algorithm := range algorithms {
+		if strings.EqualFold(algorithm.Name, name) {
+			return algorithm, nil
+		}
+	}
+	return Algorithm{}, fmt.Errorf("unknown checksum %q", name)
+}
+
+/*
+Returns the names of the available algorithms
+*/
+func Names() []string {
+	names := make([]string, 0, len(algorithms))
+	for _, algorithm := range algorithms {
+		names = append(names, algorithm.Name)
+	}
+	return names
+}
+
+/*
+Returns the checksum of the data as sent on the link
+*/
+func (a Algorithm) Sum(data []byte) []byte {
+	digest := a.New()
+	digest.Write(data)
+	return digest.Sum(nil)
+}
+
+/*
+Appends the checksum of the data
+*/
+func (a Algorithm) Append(data []byte) []byte {
+	return append(data, a.Sum(data)...)
+}
+
+/*
+Splits the checksum off the end of the frame and checks it,
+returning the data before it
+*/
+func (a Algorithm) Verify(frame []byte) ([]byte, error) {
+	if len(frame) < a.Size {
+		return nil, fmt.Errorf("frame of %d bytes is shorter than the %s checksum", len(frame), a.Name)
+	}
+	data, received := frame[:len(frame)-a.Size], frame[len(frame)-a.Size:]
+	if expected := a.Sum(data); !bytes.Equal(received, expected) {
+		return nil, fmt.Errorf("%s mismatch: got %X, expected %X", a.Name, received, expected)
+	}
+	return data, nil
+}
+
+/*
+Returns the CRC-32 (IEEE) of the data, as used by Ethernet, zip
+and PNG
+*/
+func CRC32(data []byte) uint32 {
+	return crc32.ChecksumIEEE(data)
+}
+
+/*
+Appends the CRC-32 (IEEE) of the data, most significant byte first
+*/
+func AppendCRC32(data []byte) []byte {
+	return binary.BigEndian.AppendUint32(data, CRC32(data))
+}
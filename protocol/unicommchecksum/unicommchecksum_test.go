@@ -0,0 +1,83 @@
+package unicommchecksum_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommchecksum"
+)
+
+// Check values of the catalogue of parametrised CRC algorithms
+var check = []byte("123456789")
+
+func TestCRC16(t *testing.T) {
+	for name, test := range map[string]struct {
+		params   unicommchecksum.Params16
+		expected uint16
+	}{
+		"modbus": {unicommchecksum.Modbus, 0x4B37},
+		"ccitt":  {unicommchecksum.CCITT, 0x29B1},
+		"xmodem": {unicommchecksum.XModem, 0x31C3},
+		"kermit": {unicommchecksum.Kermit, 0x2189},
+	} {
+		if crc := unicommchecksum.CRC16(check, test.params); crc != test.expected {
+			t.Errorf("%s: got 0x%04X, want 0x%04X", name, crc, test.expected)
+		}
+
+		// Streaming in pieces gives the same value
+		digest := unicommchecksum.NewCRC16(test.params)
+		digest.Write(check[:4])
+		digest.Write(check[4:])
+		if crc := digest.Sum16(); crc != test.expected {
+			t.Errorf("%s streamed: got 0x%04X, want 0x%04X", name, crc, test.expected)
+		}
+	}
+
+	// Modbus sends the low byte first
+	request := []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0A}
+	if frame := unicommchecksum.AppendCRC16(request, unicommchecksum.Modbus); !bytes.Equal(frame[6:], []byte{0xC5, 0xCD}) {
+		t.Fatalf("got % X", frame)
+	}
+}
+
+func TestAlgorithms(t *testing.T) {
+	expected := map[string][]byte{
+		"crc16-modbus": {0x37, 0x4B},
+		"crc16-ccitt":  {0x29, 0xB1},
+		"crc16-xmodem": {0x31, 0xC3},
+		"crc32":        {0xCB, 0xF4, 0x39, 0x26},
+		"crc32c":       {0xE3, 0x06, 0x92, 0x83},
+		"lrc":          {0x23},
+		"xor":          {0x31},
+	}
+	for name, sum := range expected {
+		algorithm, err := unicommchecksum.Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := algorithm.Sum(check); !bytes.Equal(got, sum) {
+			t.Errorf("%s: got % X, want % X", name, got, sum)
+		}
+		frame := algorithm.Append(bytes.Clone(check))
+		if data, err := algorithm.Verify(frame); err != nil || !bytes.Equal(data, check) {
+			t.Errorf("%s: verify failed: %v", name, err)
+		}
+		frame[0] ^= 0x01
+		if _, err := algorithm.Verify(frame); err == nil {
+			t.Errorf("%s: corruption not detected", name)
+		}
+	}
+	if _, err := unicommchecksum.Lookup("md5"); err == nil {
+		t.Fatal("expected an unknown algorithm to be rejected")
+	}
+}
+
+func TestVerifyNMEA(t *testing.T) {
+	sentence := "$GPGGA,092750.000,5321.6802,N,00630.3372,W,1,8,1.03,61.7,M,55.2,M,,*76\r\n"
+	if err := unicommchecksum.VerifyNMEA(sentence); err != nil {
+		t.Fatal(err)
+	}
+	if err := unicommchecksum.VerifyNMEA("$GPGGA,092750.000*00"); err == nil {
+		t.Fatal("expected a mismatch")
+	}
+}
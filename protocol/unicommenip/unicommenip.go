@@ -0,0 +1,308 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommenip
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+/*
+Transport used to exchange encapsulation packets, usually a
+Unicomm TCP instance connected to port 44818 without delimiter
+*/
+type Transport interface {
+	Read(size uint) ([]byte, error)
+	Write(message []byte) error
+}
+
+type ENIPOptions struct {
+	Route   []byte // Route path for unconnected send, e.g. {0x01, slot}
+	Timeout uint16 // Timeout of the request in seconds, sent to the target
+}
+
+type Session struct {
+	Options ENIPOptions
+	Handle  uint32 // Session handle given by the target
+
+	transport Transport
+	mutex     sync.Mutex // Keep request and reply together
+}
+
+type DataType uint16
+
+const (
+	commandRegisterSession   = 0x0065
+	commandUnregisterSession = 0x0066
+	commandSendRRData        = 0x006F
+	headerSize               = 24
+	itemNullAddress          = 0x0000
+	itemUnconnectedData      = 0x00B2
+	serviceReadTag           = 0x4C
+	serviceWriteTag          = 0x4D
+	serviceUnconnectedSend   = 0x52
+	segmentSymbolic          = 0x91
+)
+
+const (
+	TypeBOOL  DataType = 0xC1
+	TypeSINT  DataType = 0xC2
+	TypeINT   DataType = 0xC3
+	TypeDINT  DataType = 0xC4
+	TypeLINT  DataType = 0xC5
+	TypeREAL  DataType = 0xCA
+	TypeLREAL DataType = 0xCB
+)
+
+/*
+Creates a new EtherNet/IP explicit messaging session over
+the transport
+*/
+func NewSession(transport Transport, options ENIPOptions) *Session {
+	if options.Timeout == 0 {
+		options.Timeout = 5
+	}
+	return &Session{
+		Options:   options,
+		transport: transport,
+	}
+}
+
+/*
+Reads exactly n bytes from the transport
+*/
+func (s *Session) readFull(n int) ([]byte, error) {
+	buffer := make([]byte, 0, n)
+	for len(buffer) < n {
+		data, err := s.transport.Read(uint(n - len(buffer)))
+		if err != nil {
+			return buffer, err
+		}
+		if len(data) == 0 {
+			return buffer, unicommio.TimeoutError("enip read")
+		}
+		buffer = append(buffer, data...)
+	}
+	return buffer, nil
+}
+
+/*
+Sends an encapsulation packet and returns the data of its
+reply, must be called with the mutex locked
+*/
+func (s *Session) exchange(command uint16, data []byte, reply bool) ([]byte, error) {
+	packet := make([]byte, headerSize, headerSize+len(data))
+	binary.LittleEndian.PutUint16(packet[0:], command)
+	binary.LittleEndian.PutUint16(packet[2:], uint16(len(data)))
+	binary.LittleEndian.PutUint32(packet[4:], s.Handle)
+	packet = append(packet, data...)
+
+	if err := s.transport.Write(packet); err != nil {
+		return nil, err
+	}
+	if !reply {
+		return nil, nil
+	}
+
+	header, err := s.readFull(headerSize)
+	if err != nil {
+		return nil, err
+	}
+	if got := binary.LittleEndian.Uint16(header[0:]); got != command {
+		return nil, fmt.Errorf("unexpected encapsulation command 0x%04X, expected 0x%04X", got, command)
+	}
+	if status := binary.LittleEndian.Uint32(header[8:]); status != 0 {
+		return nil, fmt.Errorf("encapsulation error 0x%08X", status)
+	}
+	if s.Handle == 0 {
+		s.Handle = binary.LittleEndian.Uint32(header[4:])
+	}
+	return s.readFull(int(binary.LittleEndian.Uint16(header[2:])))
+}
+
+/*
+Registers the session with the target
+*/
+func (s *Session) Register() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.Handle = 0
+	if _, err := s.exchange(commandRegisterSession, []byte{1, 0, 0, 0}, true); err != nil {
+		return err
+	}
+	if s.Handle == 0 {
+		return fmt.Errorf("target did not assign a session handle")
+	}
+	return nil
+}
+
+/*
+Unregisters the session, the target closes the connection
+afterwards
+*/
+func (s *Session) Unregister() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, err := s.exchange(commandUnregisterSession, nil, false)
+	s.Handle = 0
+	return err
+}
+
+/*
+Sends a CIP request as unconnected data and returns the CIP
+reply
+*/
+func (s *Session) SendRRData(request []byte) ([]byte, error) {
+	if s.Options.Route != nil {
+		request = s.unconnectedSend(request)
+	}
+
+	data := make([]byte, 16, 16+len(request))
+	binary.LittleEndian.PutUint16(data[4:], s.Options.Timeout)
+	binary.LittleEndian.PutUint16(data[6:], 2)
+	binary.LittleEndian.PutUint16(data[8:], itemNullAddress)
+	binary.LittleEndian.PutUint16(data[12:], itemUnconnectedData)
+	binary.LittleEndian.PutUint16(data[14:], uint16(len(request)))
+	data = append(data, request...)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Handle == 0 {
+		return nil, fmt.Errorf("there is no session registered")
+	}
+	reply, err := s.exchange(commandSendRRData, data, true)
+	if err != nil {
+		return nil, err
+	}
+	return parseItems(reply)
+}
+
+/*
+Wraps the request in an unconnected send to the connection
+manager, routing it through the configured path
+*/
+func (s *Session) unconnectedSend(request []byte) []byte {
+	message := []byte{serviceUnconnectedSend, 0x02, 0x20, 0x06, 0x24, 0x01, 0x0A, 0x0E}
+	message = binary.LittleEndian.AppendUint16(message, uint16(len(request)))
+	message = append(message, request...)
+	if len(request)%2 == 1 {
+		message = append(message, 0)
+	}
+	message = append(message, byte((len(s.Options.Route)+1)/2), 0)
+	message = append(message, s.Options.Route...)
+	if len(s.Options.Route)%2 == 1 {
+		message = append(message, 0)
+	}
+	return message
+}
+
+/*
+Returns the unconnected data item of a SendRRData reply
+*/
+func parseItems(reply []byte) ([]byte, error) {
+	if len(reply) < 8 {
+		return nil, fmt.Errorf("invalid send rr data reply")
+	}
+	count := int(binary.LittleEndian.Uint16(reply[6:]))
+	offset := 8
+	for range count {
+		if len(reply) < offset+4 {
+			return nil, fmt.Errorf("invalid send rr data reply")
+		}
+		itemType := binary.LittleEndian.Uint16(reply[offset:])
+		length := int(binary.LittleEndian.Uint16(reply[offset+2:]))
+		offset += 4
+		if len(reply) < offset+length {
+			return nil, fmt.Errorf("invalid send rr data reply")
+		}
+		if itemType == itemUnconnectedData {
+			return reply[offset : offset+length], nil
+		}
+		offset += length
+	}
+	return nil, fmt.Errorf("send rr data reply without unconnected data")
+}
+
+/*
+Encodes a tag name as a request path of symbolic segments
+*/
+func tagPath(name string) []byte {
+	path := make([]byte, 0)
+	for _, part := range strings.Split(name, ".") {
+		path = append(path, segmentSymbolic, byte(len(part)))
+		path = append(path, part...)
+		if len(part)%2 == 1 {
+			path = append(path, 0)
+		}
+	}
+	return path
+}
+
+/*
+Checks the general status of a CIP reply and returns its data
+*/
+func parseReply(service byte, reply []byte) ([]byte, error) {
+	if len(reply) < 4 || reply[0] != service|0x80 {
+		return nil, fmt.Errorf("invalid cip reply to service 0x%02X", service)
+	}
+	extended := 4 + 2*int(reply[3])
+	if len(reply) < extended {
+		return nil, fmt.Errorf("invalid cip reply to service 0x%02X", service)
+	}
+	if reply[2] != 0 {
+		return nil, fmt.Errorf("cip service 0x%02X failed with status 0x%02X", service, reply[2])
+	}
+	return reply[extended:], nil
+}
+
+/*
+Reads elements of a tag, returning its data type and raw
+little endian value
+*/
+func (s *Session) ReadTag(name string, elements uint16) (DataType, []byte, error) {
+	path := tagPath(name)
+	request := append([]byte{serviceReadTag, byte(len(path) / 2)}, path...)
+	request = binary.LittleEndian.AppendUint16(request, elements)
+
+	reply, err := s.SendRRData(request)
+	if err != nil {
+		return 0, nil, err
+	}
+	data, err := parseReply(serviceReadTag, reply)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < 2 {
+		return 0, nil, fmt.Errorf("read tag reply without data type")
+	}
+	return DataType(binary.LittleEndian.Uint16(data)), data[2:], nil
+}
+
+/*
+Writes elements of a tag from its raw little endian value
+*/
+func (s *Session) WriteTag(name string, dataType DataType, elements uint16, value []byte) error {
+	path := tagPath(name)
+	request := append([]byte{serviceWriteTag, byte(len(path) / 2)}, path...)
+	request = binary.LittleEndian.AppendUint16(request, uint16(dataType))
+	request = binary.LittleEndian.AppendUint16(request, elements)
+	request = append(request, value...)
+
+	reply, err := s.SendRRData(request)
+	if err != nil {
+		return err
+	}
+	_, err = parseReply(serviceWriteTag, reply)
+	return err
+}
@@ -0,0 +1,75 @@
+package unicommenip_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommenip"
+)
+
+/*
+Transport that answers each request with the next canned reply
+*/
+type fakeTransport struct {
+	replies [][]byte
+	pending []byte
+	written [][]byte
+}
+
+func (ft *fakeTransport) Write(message []byte) error {
+	ft.written = append(ft.written, message)
+	ft.pending = append(ft.pending, ft.replies[0]...)
+	ft.replies = ft.replies[1:]
+	return nil
+}
+
+func (ft *fakeTransport) Read(size uint) ([]byte, error) {
+	n := min(int(size), len(ft.pending))
+	data := ft.pending[:n]
+	ft.pending = ft.pending[n:]
+	return data, nil
+}
+
+func packet(command uint16, handle uint32, data []byte) []byte {
+	header := make([]byte, 24)
+	binary.LittleEndian.PutUint16(header[0:], command)
+	binary.LittleEndian.PutUint16(header[2:], uint16(len(data)))
+	binary.LittleEndian.PutUint32(header[4:], handle)
+	return append(header, data...)
+}
+
+func TestReadTag(t *testing.T) {
+	cip := []byte{0xCC, 0x00, 0x00, 0x00, 0xCA, 0x00}
+	cip = binary.LittleEndian.AppendUint32(cip, math.Float32bits(21.5))
+
+	items := []byte{0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0xB2, 0x00, byte(len(cip)), 0}
+	transport := &fakeTransport{replies: [][]byte{
+		packet(0x0065, 0x1234, []byte{1, 0, 0, 0}),
+		packet(0x006F, 0x1234, append(items, cip...)),
+	}}
+
+	session := unicommenip.NewSession(transport, unicommenip.ENIPOptions{})
+	if err := session.Register(); err != nil {
+		t.Fatal(err)
+	}
+	if session.Handle != 0x1234 {
+		t.Fatalf("unexpected session handle 0x%X", session.Handle)
+	}
+
+	dataType, value, err := session.ReadTag("Tank.Level", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dataType != unicommenip.TypeREAL || math.Float32frombits(binary.LittleEndian.Uint32(value)) != 21.5 {
+		t.Fatalf("unexpected value %v of type 0x%X", value, dataType)
+	}
+
+	// Read tag service with the path of both symbolic segments
+	request := transport.written[1][24+16:]
+	expected := []byte{0x4C, 0x07, 0x91, 0x04, 'T', 'a', 'n', 'k', 0x91, 0x05, 'L', 'e', 'v', 'e', 'l', 0x00, 0x01, 0x00}
+	if !bytes.Equal(request, expected) {
+		t.Fatalf("unexpected request % X", request)
+	}
+}
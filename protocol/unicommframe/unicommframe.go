@@ -0,0 +1,428 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommframe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommchecksum"
+)
+
+/*
+Returned by Decode when the stream does not hold a complete
+frame yet
+*/
+var ErrIncomplete = errors.New("incomplete frame")
+
+/*
+Converts payloads to frames on the link and back. Decode
+returns the first frame of the stream and the bytes after it.
+Codecs that reject a malformed frame should return the bytes
+after it as rest, so the stream can resynchronize
+*/
+type Codec interface {
+	Encode(payload []byte) []byte
+	Decode(stream []byte) (frame []byte, rest []byte, err error)
+}
+
+/*
+Frames end with a delimiter, which is not part of the payload.
+When an escape byte is set, payloads may contain the delimiter:
+the escape is inserted before every byte equal to the first byte
+of the delimiter and before every escape byte, so the delimiter
+never appears unescaped, even across the end of the payload
+*/
+type Delimiter struct {
+	Delimiter string
+	Escape    string // Single escape byte, e.g. "\x10", empty disables it
+}
+
+/*
+Appends a checksum to each payload before the inner codec frames
+it, and checks and strips it when decoding, e.g. a Modbus RTU
+style CRC inside SLIP frames
+*/
+type Checked struct {
+	Codec    Codec
+	Checksum unicommchecksum.Algorithm
+}
+
+/*
+Frames start with the payload length as an unsigned integer
+*/
+type LengthPrefix struct {
+	Size         int  // Bytes of the length field: 1, 2 or 4
+	LittleEndian bool // Byte order of the length field
+	MaxLength    int  // Longer frames are rejected, zero disables it
+
+	// Delimiter after the payload, for protocols that send both a
+	// length and an end delimiter. The length decides where the frame
+	// ends, so the delimiter may also appear inside the payload
+	Trailer string
+}
+
+/*
+Consistent Overhead Byte Stuffing, frames contain no zeros
+and end with a zero byte
+*/
+type COBS struct{}
+
+/*
+Serial Line Internet Protocol framing (RFC 1055)
+*/
+type SLIP struct{}
+
+/*
+Modbus ASCII framing: the payload (address, function code and
+data) is sent as uppercase hexadecimal after a ':', followed by
+its LRC and CRLF. Bytes outside the frames are skipped
+*/
+type ModbusASCII struct{}
+
+const (
+	slipEnd    byte = 0xC0
+	slipEsc    byte = 0xDB
+	slipEscEnd byte = 0xDC
+	slipEscEsc byte = 0xDD
+)
+
+/*
+Returns an error when the delimiter is empty, or when the escape
+is not a single byte or makes the delimiter ambiguous
+*/
+func (d Delimiter) Validate() error {
+	if d.Delimiter == "" {
+		return fmt.Errorf("invalid codec: delimiter is empty")
+	}
+	if d.Escape == "" {
+		return nil
+	}
+	if len(d.Escape) != 1 {
+		return fmt.Errorf("invalid codec: escape must be a single byte")
+	}
+	if strings.HasPrefix(d.Delimiter, d.Escape+d.Escape) {
+		return fmt.Errorf("invalid codec: delimiter must not begin with an escaped escape")
+	}
+	return nil
+}
+
+/*
+Appends the delimiter to the payload, escaping it when an escape
+byte is set. An invalid codec, see Validate, encodes no frame
+*/
+func (d Delimiter) Encode(payload []byte) []byte {
+	if d.Validate() != nil {
+		return nil
+	}
+	frame := make([]byte, 0, len(payload)+len(d.Delimiter))
+	if d.Escape == "" {
+		frame = append(frame, payload...)
+		return append(frame, d.Delimiter...)
+	}
+	escape, start := d.Escape[0], d.Delimiter[0]
+	for _, b := range payload {
+		if b == escape || b == start {
+			frame = append(frame, escape)
+		}
+		frame = append(frame, b)
+	}
+	return append(frame, d.Delimiter...)
+}
+
+/*
+Splits the stream after the first delimiter
+*/
+func (d Delimiter) Decode(stream []byte) ([]byte, []byte, error) {
+	if err := d.Validate(); err != nil {
+		return nil, stream, err
+	}
+	if d.Escape != "" {
+		return d.decodeEscaped(stream)
+	}
+	index := bytes.Index(stream, []byte(d.Delimiter))
+	if index < 0 {
+		return nil, stream, ErrIncomplete
+	}
+	return bytes.Clone(stream[:index]), stream[index+len(d.Delimiter):], nil
+}
+
+/*
+Splits the stream after the first unescaped delimiter and
+removes the escapes from the payload. The delimiter is matched
+first, so it may begin with the escape byte, e.g. DLE ETX
+*/
+func (d Delimiter) decodeEscaped(stream []byte) ([]byte, []byte, error) {
+	escape := d.Escape[0]
+	payload := make([]byte, 0, len(stream))
+	for i := 0; i < len(stream); i++ {
+		switch {
+		case bytes.HasPrefix(stream[i:], []byte(d.Delimiter)):
+			return payload, stream[i+len(d.Delimiter):], nil
+		case stream[i] == escape:
+			if i+1 == len(stream) {
+				return nil, stream, ErrIncomplete
+			}
+			i++
+			payload = append(payload, stream[i])
+		default:
+			payload = append(payload, stream[i])
+		}
+	}
+	return nil, stream, ErrIncomplete
+}
+
+/*
+Returns the size of the length field, two bytes by default
+*/
+func (lp LengthPrefix) size() int {
+	if lp.Size == 0 {
+		return 2
+	}
+	return lp.Size
+}
+
+/*
+Returns the byte order of the length field
+*/
+func (lp LengthPrefix) order() binary.ByteOrder {
+	if lp.LittleEndian {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
+
+/*
+Prepends the payload length to the payload
+*/
+func (lp LengthPrefix) Encode(payload []byte) []byte {
+	frame := make([]byte, lp.size(), lp.size()+len(payload))
+	switch lp.size() {
+	case 1:
+		frame[0] = byte(len(payload))
+	case 2:
+		lp.order().PutUint16(frame, uint16(len(payload)))
+	default:
+		lp.order().PutUint32(frame, uint32(len(payload)))
+	}
+	frame = append(frame, payload...)
+	return append(frame, lp.Trailer...)
+}
+
+/*
+Reads the length field and splits the stream after the payload
+*/
+func (lp LengthPrefix) Decode(stream []byte) ([]byte, []byte, error) {
+	size := lp.size()
+	if size != 1 && size != 2 && size != 4 {
+		return nil, stream, fmt.Errorf("invalid codec: length size must be 1, 2 or 4, got %d", size)
+	}
+	if len(stream) < size {
+		return nil, stream, ErrIncomplete
+	}
+
+	var length int
+	switch size {
+	case 1:
+		length = int(stream[0])
+	case 2:
+		length = int(lp.order().Uint16(stream))
+	default:
+		length = int(lp.order().Uint32(stream))
+	}
+	if lp.MaxLength > 0 && length > lp.MaxLength {
+		// The length field cannot be trusted, so the stream is dropped
+		return nil, nil, fmt.Errorf("invalid frame: length %d exceeds %d", length, lp.MaxLength)
+	}
+	end := size + length + len(lp.Trailer)
+	if len(stream) < end {
+		return nil, stream, ErrIncomplete
+	}
+	if !bytes.Equal(stream[size+length:end], []byte(lp.Trailer)) {
+		return nil, stream[end:], fmt.Errorf("invalid frame: missing trailer %q", lp.Trailer)
+	}
+	return bytes.Clone(stream[size : size+length]), stream[end:], nil
+}
+
+/*
+Encodes the payload without zeros and appends the zero delimiter
+*/
+func (COBS) Encode(payload []byte) []byte {
+	frame := make([]byte, 1, len(payload)+len(payload)/254+2)
+	code := 0 // Index of the current code byte
+	for _, b := range payload {
+		if b != 0 {
+			frame = append(frame, b)
+		}
+		if b == 0 || len(frame)-code == 0xFF {
+			frame[code] = byte(len(frame) - code)
+			code = len(frame)
+			frame = append(frame, 0)
+		}
+	}
+	frame[code] = byte(len(frame) - code)
+	return append(frame, 0)
+}
+
+/*
+Decodes the bytes before the first zero
+*/
+func (COBS) Decode(stream []byte) ([]byte, []byte, error) {
+	index := bytes.IndexByte(stream, 0)
+	if index < 0 {
+		return nil, stream, ErrIncomplete
+	}
+	encoded, rest := stream[:index], stream[index+1:]
+
+	payload := make([]byte, 0, len(encoded))
+	for i := 0; i < len(encoded); {
+		code := int(encoded[i])
+		if i+code > len(encoded) {
+			return nil, rest, fmt.Errorf("invalid COBS frame: code 0x%02X overruns the frame", code)
+		}
+		payload = append(payload, encoded[i+1:i+code]...)
+		i += code
+		if code < 0xFF && i < len(encoded) {
+			payload = append(payload, 0)
+		}
+	}
+	return payload, rest, nil
+}
+
+/*
+Escapes the payload and surrounds it with END bytes
+*/
+func (SLIP) Encode(payload []byte) []byte {
+	frame := make([]byte, 0, len(payload)+2)
+	frame = append(frame, slipEnd)
+	for _, b := range payload {
+		switch b {
+		case slipEnd:
+			frame = append(frame, slipEsc, slipEscEnd)
+		case slipEsc:
+			frame = append(frame, slipEsc, slipEscEsc)
+		default:
+			frame = append(frame, b)
+		}
+	}
+	return append(frame, slipEnd)
+}
+
+/*
+Decodes the next non-empty frame, skipping empty frames
+created by back-to-back END bytes
+*/
+func (SLIP) Decode(stream []byte) ([]byte, []byte, error) {
+	for len(stream) > 0 && stream[0] == slipEnd {
+		stream = stream[1:]
+	}
+	index := bytes.IndexByte(stream, slipEnd)
+	if index < 0 {
+		return nil, stream, ErrIncomplete
+	}
+	encoded, rest := stream[:index], stream[index+1:]
+
+	payload := make([]byte, 0, len(encoded))
+	for i := 0; i < len(encoded); i++ {
+		if encoded[i] != slipEsc {
+			payload = append(payload, encoded[i])
+			continue
+		}
+		if i+1 == len(encoded) {
+			return nil, rest, fmt.Errorf("invalid SLIP frame: truncated escape")
+		}
+		i++
+		switch encoded[i] {
+		case slipEscEnd:
+			payload = append(payload, slipEnd)
+		case slipEscEsc:
+			payload = append(payload, slipEsc)
+		default:
+			return nil, rest, fmt.Errorf("invalid SLIP frame: bad escape 0x%02X", encoded[i])
+		}
+	}
+	return payload, rest, nil
+}
+
+/*
+Returns the Longitudinal Redundancy Check of Modbus ASCII, the
+two's complement of the sum of the bytes
+*/
+func LRC(data []byte) byte {
+	return unicommchecksum.LRC(data)
+}
+
+/*
+Encodes the payload and its LRC as hexadecimal between ':' and
+CRLF
+*/
+func (ModbusASCII) Encode(payload []byte) []byte {
+	frame := make([]byte, 0, 2*len(payload)+5)
+	frame = append(frame, ':')
+	frame = append(frame, bytes.ToUpper(hex.AppendEncode(nil, append(bytes.Clone(payload), LRC(payload))))...)
+	return append(frame, "\r\n"...)
+}
+
+/*
+Decodes the first frame of the stream and checks its LRC. A
+frame interrupted by a new ':' is discarded, since the device
+restarted the message
+*/
+func (ModbusASCII) Decode(stream []byte) ([]byte, []byte, error) {
+	start := bytes.IndexByte(stream, ':')
+	if start < 0 {
+		return nil, stream, ErrIncomplete
+	}
+	end := bytes.Index(stream[start:], []byte("\r\n"))
+	if end < 0 {
+		return nil, stream, ErrIncomplete
+	}
+	encoded, rest := stream[start+1:start+end], stream[start+end+2:]
+
+	if restart := bytes.IndexByte(encoded, ':'); restart >= 0 {
+		return nil, stream[start+1+restart:], fmt.Errorf("invalid Modbus ASCII frame: interrupted by a new frame")
+	}
+	if len(encoded) < 4 || len(encoded)%2 != 0 {
+		return nil, rest, fmt.Errorf("invalid Modbus ASCII frame: bad length %d", len(encoded))
+	}
+	decoded := make([]byte, len(encoded)/2)
+	if _, err := hex.Decode(decoded, encoded); err != nil {
+		return nil, rest, fmt.Errorf("invalid Modbus ASCII frame: %w", err)
+	}
+	payload, lrc := decoded[:len(decoded)-1], decoded[len(decoded)-1]
+	if expected := LRC(payload); lrc != expected {
+		return nil, rest, fmt.Errorf("invalid Modbus ASCII frame: LRC 0x%02X, expected 0x%02X", lrc, expected)
+	}
+	return payload, rest, nil
+}
+
+/*
+Frames the payload followed by its checksum
+*/
+func (c Checked) Encode(payload []byte) []byte {
+	return c.Codec.Encode(c.Checksum.Append(slices.Clip(payload)))
+}
+
+/*
+Decodes the first frame of the stream and checks its checksum
+*/
+func (c Checked) Decode(stream []byte) ([]byte, []byte, error) {
+	frame, rest, err := c.Codec.Decode(stream)
+	if err != nil {
+		return nil, rest, err
+	}
+	payload, err := c.Checksum.Verify(frame)
+	if err != nil {
+		return nil, rest, fmt.Errorf("invalid frame: %w", err)
+	}
+	return payload, rest, nil
+}
@@ -0,0 +1,166 @@
+package unicommframe_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommchecksum"
+	"github.com/devicehub-go/unicomm/protocol/unicommframe"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	crc16, err := unicommchecksum.Lookup("crc16-modbus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	codecs := map[string]unicommframe.Codec{
+		"delimiter":      unicommframe.Delimiter{Delimiter: "\r\n"},
+		"escaped":        unicommframe.Delimiter{Delimiter: "\r\n", Escape: "\x10"},
+		"length-prefix":  unicommframe.LengthPrefix{Size: 2},
+		"length-trailer": unicommframe.LengthPrefix{Size: 4, Trailer: "\r\n"},
+		"cobs":           unicommframe.COBS{},
+		"slip":           unicommframe.SLIP{},
+		"modbus-ascii":   unicommframe.ModbusASCII{},
+		"checked-slip":   unicommframe.Checked{Codec: unicommframe.SLIP{}, Checksum: crc16},
+	}
+	long := bytes.Repeat([]byte{1, 2, 3}, 200)
+	payloads := [][]byte{
+		[]byte("hello"),
+		{0x00, 0x11, 0x00, 0x00, 0xC0, 0xDB},
+		long,
+		[]byte("binary\r\n\x10\r payload"),
+	}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			var stream []byte
+			for _, payload := range payloads {
+				stream = append(stream, codec.Encode(payload)...)
+			}
+
+			// A truncated stream must not produce a frame
+			if _, _, err := codec.Decode(stream[:1]); !errors.Is(err, unicommframe.ErrIncomplete) {
+				t.Fatalf("expected incomplete frame, got %v", err)
+			}
+
+			for _, payload := range payloads {
+				if name == "delimiter" && bytes.Contains(payload, []byte("\r\n")) {
+					continue
+				}
+				frame, rest, err := codec.Decode(stream)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(frame, payload) {
+					t.Fatalf("got % X, want % X", frame, payload)
+				}
+				stream = rest
+			}
+		})
+	}
+}
+
+func TestCOBSEncoding(t *testing.T) {
+	encoded := unicommframe.COBS{}.Encode([]byte{0x11, 0x22, 0x00, 0x33})
+	expected := []byte{0x03, 0x11, 0x22, 0x02, 0x33, 0x00}
+	if !bytes.Equal(encoded, expected) {
+		t.Fatalf("got % X, want % X", encoded, expected)
+	}
+}
+
+func TestModbusASCII(t *testing.T) {
+	codec := unicommframe.ModbusASCII{}
+	encoded := codec.Encode([]byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x01})
+	if string(encoded) != ":010300000001FB\r\n" {
+		t.Fatalf("got %q", encoded)
+	}
+
+	// Noise is skipped, a bad LRC is rejected and an interrupted frame
+	// resynchronizes on the next ':'
+	stream := []byte("\x00:0103000000010C\r\n:0103:010300000001FB\r\n")
+	if _, rest, err := codec.Decode(stream); err == nil {
+		t.Fatal("expected an LRC error")
+	} else {
+		stream = rest
+	}
+	if _, rest, err := codec.Decode(stream); err == nil {
+		t.Fatal("expected an interrupted frame")
+	} else {
+		stream = rest
+	}
+	frame, rest, err := codec.Decode(stream)
+	if err != nil || !bytes.Equal(frame, []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x01}) || len(rest) != 0 {
+		t.Fatalf("got % X, %q, %v", frame, rest, err)
+	}
+}
+
+func TestCheckedRejectsCorruption(t *testing.T) {
+	crc32, _ := unicommchecksum.Lookup("crc32")
+	codec := unicommframe.Checked{Codec: unicommframe.LengthPrefix{Size: 1}, Checksum: crc32}
+	frame := codec.Encode([]byte("reading"))
+	frame[3] ^= 0xFF
+	frame = append(frame, codec.Encode([]byte("next"))...)
+
+	if _, rest, err := codec.Decode(frame); err == nil || len(rest) == 0 {
+		t.Fatalf("expected a checksum error with the next frame as rest, got %v", err)
+	} else if payload, _, err := codec.Decode(rest); err != nil || string(payload) != "next" {
+		t.Fatalf("got %q, %v", payload, err)
+	}
+}
+
+func TestDelimiterEscape(t *testing.T) {
+	codecs := map[string]unicommframe.Delimiter{
+		"overlap": {Delimiter: "aa", Escape: "\\"},
+		"dle-etx": {Delimiter: "\x10\x03", Escape: "\x10"},
+	}
+	payloads := [][]byte{
+		[]byte("xa"),
+		[]byte("a"),
+		[]byte("aaa\\"),
+		{0x10},
+		{0x10, 0x03, 0x10},
+		{},
+	}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			var stream []byte
+			for _, payload := range payloads {
+				stream = append(stream, codec.Encode(payload)...)
+			}
+			for _, payload := range payloads {
+				frame, rest, err := codec.Decode(stream)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(frame, payload) {
+					t.Fatalf("got % X, want % X", frame, payload)
+				}
+				stream = rest
+			}
+			if len(stream) != 0 {
+				t.Fatalf("unexpected rest % X", stream)
+			}
+		})
+	}
+}
+
+func TestDelimiterInvalidEscape(t *testing.T) {
+	codecs := []unicommframe.Delimiter{
+		{Delimiter: "\r\n", Escape: "\x10\x10"},
+		{Delimiter: "\x10\x10", Escape: "\x10"},
+		{Delimiter: "", Escape: "\x10"},
+	}
+	for _, codec := range codecs {
+		if codec.Validate() == nil {
+			t.Fatalf("expected %+v to be invalid", codec)
+		}
+		if frame := codec.Encode([]byte("payload")); frame != nil {
+			t.Fatalf("expected no frame from %+v, got % X", codec, frame)
+		}
+		if _, _, err := codec.Decode([]byte("payload\r\n")); err == nil || errors.Is(err, unicommframe.ErrIncomplete) {
+			t.Fatalf("expected %+v to be rejected, got %v", codec, err)
+		}
+	}
+}
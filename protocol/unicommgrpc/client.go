@@ -0,0 +1,324 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommgrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const DefaultCallTimeout = 30 * time.Second
+
+type GRPCOptions struct {
+	Address     string            // Address of the edge agent, e.g. "edge-01:7000"
+	CallTimeout time.Duration     // Upper bound for each call, defaults to 30s
+	DialOptions []grpc.DialOption // Transport credentials and other options
+
+	// Opens a plaintext channel when DialOptions set no transport
+	// credentials, only fit for trusted networks
+	Insecure bool
+}
+
+type UnicommGRPC struct {
+	Options    GRPCOptions
+	Connection *grpc.ClientConn
+
+	mutex sync.Mutex // Protect the connection
+}
+
+/*
+Creates a new instance of Unicomm driving a device attached to
+a remote edge agent through gRPC
+*/
+func NewGRPC(options GRPCOptions) *UnicommGRPC {
+	if options.CallTimeout == 0 {
+		options.CallTimeout = DefaultCallTimeout
+	}
+	return &UnicommGRPC{
+		Options: options,
+	}
+}
+
+/*
+Error of the remote device, keeping its message and matching
+the cause of its kind
+*/
+type remoteError struct {
+	message string
+	cause   error
+}
+
+/*
+Returns the message of the device
+*/
+func (re *remoteError) Error() string {
+	return re.message
+}
+
+/*
+Returns the cause of the kind, e.g. io.EOF
+*/
+func (re *remoteError) Unwrap() error {
+	return re.cause
+}
+
+/*
+Rebuilds an error from its reason and metadata, nil when the
+reason is not known
+*/
+func fromReason(message, reason string, metadata map[string]string) error {
+	switch reason {
+	case reasonTimeout:
+		return &remoteError{message, unicommio.ErrTimeout}
+	case reasonEOF:
+		return &remoteError{message, io.EOF}
+	case reasonClosed:
+		return &remoteError{message, net.ErrClosed}
+	case reasonErrno:
+		errno, err := strconv.ParseUint(metadata["errno"], 10, 32)
+		if err != nil {
+			return nil
+		}
+		return &remoteError{message, syscall.Errno(errno)}
+	case reasonPartialWrite:
+		written, writtenErr := strconv.Atoi(metadata["written"])
+		expected, expectedErr := strconv.Atoi(metadata["expected"])
+		if writtenErr != nil || expectedErr != nil {
+			return nil
+		}
+		partial := &unicommio.PartialWriteError{Written: written, Expected: expected}
+		if cause, ok := metadata["cause"]; ok {
+			partial.Err = fromReason(cause, metadata["cause_reason"], map[string]string{"errno": metadata["cause_errno"]})
+			if partial.Err == nil {
+				partial.Err = errors.New(cause)
+			}
+		}
+		return partial
+	}
+	return nil
+}
+
+/*
+Converts a status returned by the agent back to the error of
+the device, keeping its message. Statuses without details, such
+as those of the channel, are rebuilt from their code
+*/
+func fromStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != ServiceName {
+			continue
+		}
+		if rebuilt := fromReason(st.Message(), info.GetReason(), info.GetMetadata()); rebuilt != nil {
+			return rebuilt
+		}
+	}
+
+	switch st.Code() {
+	case codes.DeadlineExceeded:
+		return &remoteError{st.Message(), unicommio.ErrTimeout}
+	case codes.Unavailable:
+		return &remoteError{st.Message(), net.ErrClosed}
+	case codes.Canceled:
+		return &remoteError{st.Message(), context.Canceled}
+	}
+	return errors.New(st.Message())
+}
+
+/*
+Calls a method of the service with the call timeout
+*/
+func (ug *UnicommGRPC) invoke(method string, in, out any) error {
+	return fromStatus(ug.call(method, in, out))
+}
+
+/*
+Calls a method of the service, returning the status as is
+*/
+func (ug *UnicommGRPC) call(method string, in, out any) error {
+	ug.mutex.Lock()
+	connection := ug.Connection
+	ug.mutex.Unlock()
+
+	if connection == nil {
+		return fmt.Errorf("there is no connection established")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ug.Options.CallTimeout)
+	defer cancel()
+
+	return connection.Invoke(ctx, "/"+ServiceName+"/"+method, in, out)
+}
+
+/*
+Calls a read method, returning the bytes the device read before
+an error along with it
+*/
+func (ug *UnicommGRPC) invokeRead(method string, in any) ([]byte, error) {
+	out := new(wrapperspb.BytesValue)
+	err := ug.call(method, in, out)
+	if err == nil {
+		return out.GetValue(), nil
+	}
+	if st, ok := status.FromError(err); ok {
+		for _, detail := range st.Details() {
+			if data, ok := detail.(*wrapperspb.BytesValue); ok {
+				return data.GetValue(), fromStatus(err)
+			}
+		}
+	}
+	return nil, fromStatus(err)
+}
+
+/*
+Returns true if the agent is reachable and its device connected
+*/
+func (ug *UnicommGRPC) IsConnected() bool {
+	out := new(wrapperspb.BoolValue)
+	if err := ug.invoke("IsConnected", &emptypb.Empty{}, out); err != nil {
+		return false
+	}
+	return out.GetValue()
+}
+
+/*
+Opens the channel with the agent and connects its device
+*/
+func (ug *UnicommGRPC) Connect() error {
+	if ug.Options.Address == "" {
+		return fmt.Errorf("invalid options: address is empty")
+	}
+
+	if len(ug.Options.DialOptions) == 0 && !ug.Options.Insecure {
+		return fmt.Errorf("invalid options: transport credentials are required, set DialOptions or Insecure")
+	}
+
+	ug.mutex.Lock()
+	if ug.Connection == nil {
+		// Credentials given in the dial options take precedence
+		options := ug.Options.DialOptions
+		if ug.Options.Insecure {
+			options = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, options...)
+		}
+		connection, err := grpc.NewClient(ug.Options.Address, options...)
+		if err != nil {
+			ug.mutex.Unlock()
+			return err
+		}
+		ug.Connection = connection
+	}
+	ug.mutex.Unlock()
+
+	return ug.invoke("Connect", &emptypb.Empty{}, &emptypb.Empty{})
+}
+
+/*
+Disconnects the device of the agent and closes the channel
+*/
+func (ug *UnicommGRPC) Disconnect() error {
+	err := ug.invoke("Disconnect", &emptypb.Empty{}, &emptypb.Empty{})
+
+	ug.mutex.Lock()
+	defer ug.mutex.Unlock()
+
+	if ug.Connection != nil {
+		ug.Connection.Close()
+		ug.Connection = nil
+	}
+	return err
+}
+
+/*
+Reads a number of bytes from the remote device
+*/
+func (ug *UnicommGRPC) Read(n uint) ([]byte, error) {
+	return ug.invokeRead("Read", wrapperspb.UInt32(uint32(n)))
+}
+
+/*
+Reads data from the remote device until a target delimiter is
+found. Partial data read before a timeout is returned with it
+*/
+func (ug *UnicommGRPC) ReadUntil(delimiter string) ([]byte, error) {
+	return ug.invokeRead("ReadUntil", wrapperspb.String(delimiter))
+}
+
+/*
+Writes an array of bytes to the remote device
+*/
+func (ug *UnicommGRPC) Write(message []byte) error {
+	return ug.invoke("Write", wrapperspb.Bytes(message), &emptypb.Empty{})
+}
+
+/*
+Writes an array of bytes to the remote device without the end
+delimiter, failing when the remote device does not support it
+*/
+func (ug *UnicommGRPC) WriteRaw(message []byte) error {
+	return ug.invoke("WriteRaw", wrapperspb.Bytes(message), &emptypb.Empty{})
+}
+
+/*
+Streams the frames ending with the delimiter to the handler
+until the context is cancelled or the stream fails
+*/
+func (ug *UnicommGRPC) Stream(ctx context.Context, delimiter string, handle func(frame []byte)) error {
+	ug.mutex.Lock()
+	connection := ug.Connection
+	ug.mutex.Unlock()
+
+	if connection == nil {
+		return fmt.Errorf("there is no connection established")
+	}
+	stream, err := connection.NewStream(ctx, &serviceDesc.Streams[0], "/"+ServiceName+"/Frames")
+	if err != nil {
+		return fromStatus(err)
+	}
+	if err := stream.SendMsg(wrapperspb.String(delimiter)); err != nil {
+		return fromStatus(err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return fromStatus(err)
+	}
+
+	for {
+		frame := new(wrapperspb.BytesValue)
+		err := stream.RecvMsg(frame)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return fromStatus(err)
+		}
+		handle(frame.GetValue())
+	}
+}
@@ -0,0 +1,258 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommgrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+/*
+Device exposed by the server, usually a Unicomm instance
+attached to the edge agent
+*/
+type Device interface {
+	Connect() error
+	Disconnect() error
+	IsConnected() bool
+	Read(n uint) ([]byte, error)
+	Write(message []byte) error
+}
+
+/*
+Implemented by devices reading delimited frames, required by
+ReadUntil and Frames
+*/
+type framer interface {
+	ReadUntil(delimiter string) ([]byte, error)
+}
+
+type Server struct {
+	device Device
+}
+
+/*
+Creates a server exposing the device through the service of
+unicomm.proto
+*/
+func NewServer(device Device) *Server {
+	return &Server{device: device}
+}
+
+/*
+Registers the service on a gRPC server
+*/
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	registrar.RegisterService(&serviceDesc, s)
+}
+
+// Reasons of the error details, which let the clients rebuild the
+// errors of the device
+const (
+	reasonTimeout      = "TIMEOUT"
+	reasonEOF          = "EOF"
+	reasonClosed       = "CLOSED"
+	reasonErrno        = "ERRNO"         // Error number in "errno"
+	reasonPartialWrite = "PARTIAL_WRITE" // Counts in "written" and "expected", cause in "cause_*"
+)
+
+/*
+Returns true if the error is a timeout of the device, without
+relying on its message
+*/
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, unicommio.ErrTimeout) || errors.Is(err, os.ErrDeadlineExceeded) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
+
+/*
+Describes the kind of an error as a reason and its metadata,
+empty for errors of no known kind
+*/
+func errorReason(err error) (string, map[string]string) {
+	var partial *unicommio.PartialWriteError
+	var errno syscall.Errno
+
+	switch {
+	case errors.As(err, &partial):
+		metadata := map[string]string{
+			"written":  strconv.Itoa(partial.Written),
+			"expected": strconv.Itoa(partial.Expected),
+		}
+		if partial.Err != nil {
+			reason, causeMetadata := errorReason(partial.Err)
+			metadata["cause"] = partial.Err.Error()
+			metadata["cause_reason"] = reason
+			metadata["cause_errno"] = causeMetadata["errno"]
+		}
+		return reasonPartialWrite, metadata
+	case errors.As(err, &errno):
+		return reasonErrno, map[string]string{"errno": strconv.FormatUint(uint64(errno), 10)}
+	case isTimeout(err):
+		return reasonTimeout, nil
+	case errors.Is(err, io.EOF):
+		return reasonEOF, nil
+	case errors.Is(err, net.ErrClosed), errors.Is(err, os.ErrClosed):
+		return reasonClosed, nil
+	}
+	return "", nil
+}
+
+/*
+Converts a device error to a status. The code follows the class
+of the error, timeouts being reported as deadline exceeded, and
+the details carry its kind so clients rebuild it
+*/
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var partial *unicommio.PartialWriteError
+	code := codes.Unknown
+	switch {
+	case errors.As(err, &partial):
+		code = codes.DataLoss
+	case isTimeout(err):
+		code = codes.DeadlineExceeded
+	case unicommio.IsTemporary(err):
+		code = codes.Aborted
+	case unicommio.IsFatal(err):
+		code = codes.Unavailable
+	}
+
+	st := status.New(code, err.Error())
+	reason, metadata := errorReason(err)
+	if reason == "" {
+		return st.Err()
+	}
+	detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   ServiceName,
+		Metadata: metadata,
+	})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+/*
+Converts a read error to a status carrying the bytes read before
+it, so partial data reaches the client as it does locally
+*/
+func toReadStatus(err error, data []byte) error {
+	if err == nil || len(data) == 0 {
+		return toStatus(err)
+	}
+	st, detailErr := status.Convert(toStatus(err)).WithDetails(wrapperspb.Bytes(data))
+	if detailErr != nil {
+		return toStatus(err)
+	}
+	return st.Err()
+}
+
+/*
+Establishes the connection with the device
+*/
+func (s *Server) connect(ctx context.Context, in *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, toStatus(s.device.Connect())
+}
+
+/*
+Closes the connection with the device
+*/
+func (s *Server) disconnect(ctx context.Context, in *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, toStatus(s.device.Disconnect())
+}
+
+/*
+Returns true if the device is connected
+*/
+func (s *Server) isConnected(ctx context.Context, in *emptypb.Empty) (*wrapperspb.BoolValue, error) {
+	return wrapperspb.Bool(s.device.IsConnected()), nil
+}
+
+/*
+Reads a number of bytes from the device
+*/
+func (s *Server) read(ctx context.Context, in *wrapperspb.UInt32Value) (*wrapperspb.BytesValue, error) {
+	data, err := s.device.Read(uint(in.GetValue()))
+	return wrapperspb.Bytes(data), toReadStatus(err, data)
+}
+
+/*
+Reads from the device until a delimiter is found
+*/
+func (s *Server) readUntil(ctx context.Context, in *wrapperspb.StringValue) (*wrapperspb.BytesValue, error) {
+	reader, ok := s.device.(framer)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "read until is not supported")
+	}
+	data, err := reader.ReadUntil(in.GetValue())
+	return wrapperspb.Bytes(data), toReadStatus(err, data)
+}
+
+/*
+Writes an array of bytes to the device
+*/
+func (s *Server) write(ctx context.Context, in *wrapperspb.BytesValue) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, toStatus(s.device.Write(in.GetValue()))
+}
+
+/*
+Writes an array of bytes to the device without the end delimiter
+*/
+func (s *Server) writeRaw(ctx context.Context, in *wrapperspb.BytesValue) (*emptypb.Empty, error) {
+	writer, ok := s.device.(interface{ WriteRaw(message []byte) error })
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "writes without the end delimiter are not supported")
+	}
+	return &emptypb.Empty{}, toStatus(writer.WriteRaw(in.GetValue()))
+}
+
+/*
+Streams the frames read from the device until the call is
+cancelled. Timeouts only mean that the device is silent, and
+the bytes read before them start the next frame
+*/
+func (s *Server) frames(in *wrapperspb.StringValue, stream grpc.ServerStream) error {
+	reader, ok := s.device.(framer)
+	if !ok {
+		return status.Error(codes.Unimplemented, "read until is not supported")
+	}
+	var partial []byte
+	for stream.Context().Err() == nil {
+		frame, err := reader.ReadUntil(in.GetValue())
+		if err != nil {
+			if isTimeout(err) {
+				partial = append(partial, frame...)
+				continue
+			}
+			return toStatus(err)
+		}
+		if err := stream.SendMsg(wrapperspb.Bytes(append(partial, frame...))); err != nil {
+			return err
+		}
+		partial = nil
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommgrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Full name of the service defined in unicomm.proto
+const ServiceName = "unicomm.v1.Unicomm"
+
+// Implemented by the Server, checked when the service is registered
+type handler interface {
+	frames(in *wrapperspb.StringValue, stream grpc.ServerStream) error
+}
+
+/*
+Describes the service of unicomm.proto. Since the messages are
+well-known types, it is written by hand instead of generated
+*/
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*handler)(nil),
+	Methods: []grpc.MethodDesc{
+		unaryMethod("Connect", func(s *Server, ctx context.Context, in *emptypb.Empty) (any, error) {
+			return s.connect(ctx, in)
+		}),
+		unaryMethod("Disconnect", func(s *Server, ctx context.Context, in *emptypb.Empty) (any, error) {
+			return s.disconnect(ctx, in)
+		}),
+		unaryMethod("IsConnected", func(s *Server, ctx context.Context, in *emptypb.Empty) (any, error) {
+			return s.isConnected(ctx, in)
+		}),
+		unaryMethod("Read", func(s *Server, ctx context.Context, in *wrapperspb.UInt32Value) (any, error) {
+			return s.read(ctx, in)
+		}),
+		unaryMethod("ReadUntil", func(s *Server, ctx context.Context, in *wrapperspb.StringValue) (any, error) {
+			return s.readUntil(ctx, in)
+		}),
+		unaryMethod("Write", func(s *Server, ctx context.Context, in *wrapperspb.BytesValue) (any, error) {
+			return s.write(ctx, in)
+		}),
+		unaryMethod("WriteRaw", func(s *Server, ctx context.Context, in *wrapperspb.BytesValue) (any, error) {
+			return s.writeRaw(ctx, in)
+		}),
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Frames",
+			ServerStreams: true,
+			Handler: func(srv any, stream grpc.ServerStream) error {
+				in := new(wrapperspb.StringValue)
+				if err := stream.RecvMsg(in); err != nil {
+					return err
+				}
+				return srv.(*Server).frames(in, stream)
+			},
+		},
+	},
+	Metadata: "unicomm.proto",
+}
+
+/*
+Describes a unary method whose request has the type T
+*/
+func unaryMethod[T any, PT interface{ *T }](name string, handle func(*Server, context.Context, PT) (any, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			in := PT(new(T))
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return handle(srv.(*Server), ctx, in)
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + ServiceName + "/" + name}
+			return interceptor(ctx, in, info, func(ctx context.Context, req any) (any, error) {
+				return handle(srv.(*Server), ctx, req.(PT))
+			})
+		},
+	}
+}
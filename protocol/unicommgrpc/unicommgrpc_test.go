@@ -0,0 +1,216 @@
+package unicommgrpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommgrpc"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+	"google.golang.org/grpc"
+)
+
+/*
+Starts an agent exposing a TCP device that echoes the data back
+*/
+func startAgent(t *testing.T) string {
+	device := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	options := device.TCPOptions()
+	options.ReadTimeout = 50 * time.Millisecond
+	return serveDevice(t, unicommtcp.NewTCP(options))
+}
+
+/*
+Starts an agent exposing the device
+*/
+func serveDevice(t *testing.T, device unicommgrpc.Device) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	unicommgrpc.NewServer(device).Register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+/*
+Device failing its operations with the configured errors
+*/
+type failingDevice struct {
+	mutex    sync.Mutex
+	readErr  error
+	writeErr error
+}
+
+func (fd *failingDevice) Connect() error    { return nil }
+func (fd *failingDevice) Disconnect() error { return nil }
+func (fd *failingDevice) IsConnected() bool { return true }
+
+func (fd *failingDevice) Read(n uint) ([]byte, error) {
+	fd.mutex.Lock()
+	defer fd.mutex.Unlock()
+
+	return []byte("pa"), fd.readErr
+}
+
+func (fd *failingDevice) Write(message []byte) error {
+	fd.mutex.Lock()
+	defer fd.mutex.Unlock()
+
+	return fd.writeErr
+}
+
+func (fd *failingDevice) fail(readErr, writeErr error) {
+	fd.mutex.Lock()
+	defer fd.mutex.Unlock()
+
+	fd.readErr, fd.writeErr = readErr, writeErr
+}
+
+func TestRemoteDevice(t *testing.T) {
+	comm := unicommgrpc.NewGRPC(unicommgrpc.GRPCOptions{Address: startAgent(t), Insecure: true})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	if !comm.IsConnected() {
+		t.Fatal("expected the remote device to be connected")
+	}
+	if err := comm.Write([]byte("MEAS?\n")); err != nil {
+		t.Fatal(err)
+	}
+	unicommtest.ExpectFrame(t, comm, "\n", "MEAS?\n")
+	if err := comm.WriteRaw([]byte("RAW\n")); err != nil {
+		t.Fatal(err)
+	}
+	unicommtest.ExpectFrame(t, comm, "\n", "RAW\n")
+
+	// Timeouts of the device keep their message on the client
+	if _, err := comm.ReadUntil("\n"); err == nil || err.Error() != "read until timeout" {
+		t.Fatalf("expected a read timeout, got %v", err)
+	}
+
+	// Partial data read before the timeout is transferred with it
+	if err := comm.Write([]byte("PART")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := comm.ReadUntil("\n")
+	if !errors.Is(err, unicommio.ErrTimeout) || string(data) != "PART" {
+		t.Fatalf("expected partial data with a timeout, got %q, %v", data, err)
+	}
+}
+
+func TestRemoteStream(t *testing.T) {
+	comm := unicommgrpc.NewGRPC(unicommgrpc.GRPCOptions{Address: startAgent(t), Insecure: true})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	frames := make(chan []byte, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- comm.Stream(ctx, "\n", func(frame []byte) { frames <- frame })
+	}()
+
+	if err := comm.Write([]byte("A\nB\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"A\n", "B\n"} {
+		select {
+		case frame := <-frames:
+			if string(frame) != want {
+				t.Fatalf("got %q, want %q", frame, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for %q", want)
+		}
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("unexpected stream end: %v", err)
+	}
+}
+
+func TestRequiresTransportCredentials(t *testing.T) {
+	comm := unicommgrpc.NewGRPC(unicommgrpc.GRPCOptions{Address: startAgent(t)})
+	if err := comm.Connect(); err == nil {
+		comm.Disconnect()
+		t.Fatal("plaintext channel opened without opting in")
+	}
+	if comm.Connection != nil {
+		t.Fatal("channel created without transport credentials")
+	}
+}
+
+func TestRemoteErrorKinds(t *testing.T) {
+	device := &failingDevice{}
+	comm := unicommgrpc.NewGRPC(unicommgrpc.GRPCOptions{Address: serveDevice(t, device), Insecure: true})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	tests := []struct {
+		name  string
+		err   error
+		match error
+		class unicommio.ErrorClass
+	}{
+		{"eof", io.EOF, io.EOF, unicommio.FatalError},
+		{"closed", fmt.Errorf("port: %w", net.ErrClosed), net.ErrClosed, unicommio.FatalError},
+		{"errno", syscall.ENODEV, syscall.ENODEV, unicommio.FatalError},
+		{"temporary", syscall.EAGAIN, syscall.EAGAIN, unicommio.TemporaryError},
+		{"timeout", unicommio.TimeoutError("read"), unicommio.ErrTimeout, unicommio.TemporaryError},
+		{"other", errors.New("parity error"), nil, unicommio.UnknownError},
+		// Messages ending like timeouts are not taken for them
+		{"timeout message", errors.New("heater timeout"), nil, unicommio.UnknownError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			device.fail(test.err, nil)
+			data, err := comm.Read(2)
+			if string(data) != "pa" {
+				t.Fatalf("data = %q, want the partial data", data)
+			}
+			if err == nil || err.Error() != test.err.Error() {
+				t.Fatalf("Read = %v, want %v", err, test.err)
+			}
+			if test.match != nil && !errors.Is(err, test.match) {
+				t.Fatalf("Read = %v, does not match %v", err, test.match)
+			}
+			if errors.Is(err, unicommio.ErrTimeout) != errors.Is(test.err, unicommio.ErrTimeout) {
+				t.Fatalf("Read = %v, timeout mismatch", err)
+			}
+			if class := unicommio.Classify(err); class != test.class {
+				t.Fatalf("class = %v, want %v", class, test.class)
+			}
+		})
+	}
+
+	partialErr := &unicommio.PartialWriteError{Written: 3, Expected: 8, Err: syscall.EPIPE}
+	device.fail(nil, partialErr)
+	err := comm.Write([]byte("MEAS?"))
+	var partial *unicommio.PartialWriteError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Write = %v, want a partial write", err)
+	}
+	if partial.Written != 3 || partial.Expected != 8 || !errors.Is(err, syscall.EPIPE) {
+		t.Fatalf("partial write = %+v", partial)
+	}
+	if err.Error() != partialErr.Error() {
+		t.Fatalf("message = %q, want %q", err, partialErr)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommi2c
+
+import (
+	"fmt"
+)
+
+// Pins of the low byte: SCL on AD0, SDA driven by AD1 and sampled by
+// AD2, which are wired together
+const (
+	i2cSCL     = 0x01
+	i2cSDA     = 0x02
+	i2cSDAIn   = 0x04
+	i2cOutputs = i2cSCL | i2cSDA
+	i2cHold    = 4 // Repetitions of each level to respect the bus timings
+)
+
+/*
+FT232H bridge, bit-banging the bus conditions through the MPSSE
+engine with open-drain outputs
+*/
+type ft232hI2C struct {
+	mpsse
+}
+
+/*
+Sets the pins to the levels for the hold period
+*/
+func setPins(commands []byte, levels, dirs byte) []byte {
+	for range i2cHold {
+		commands = append(commands, mpsseSetLowPins, levels, dirs)
+	}
+	return commands
+}
+
+/*
+Appends a start condition, also valid as a repeated start
+*/
+func i2cStart(commands []byte) []byte {
+	commands = setPins(commands, i2cSDA, i2cOutputs)
+	commands = setPins(commands, i2cSDA|i2cSCL, i2cOutputs)
+	commands = setPins(commands, i2cSCL, i2cOutputs)
+	return setPins(commands, 0, i2cOutputs)
+}
+
+/*
+Appends a stop condition, leaving the bus idle
+*/
+func i2cStop(commands []byte) []byte {
+	commands = setPins(commands, 0, i2cOutputs)
+	commands = setPins(commands, i2cSCL, i2cOutputs)
+	return setPins(commands, i2cSDA|i2cSCL, i2cOutputs)
+}
+
+/*
+Appends the byte and the sampling of its acknowledge bit, which
+adds one byte to the response
+*/
+func i2cWriteByte(commands []byte, value byte) []byte {
+	commands = append(commands, mpsseSetLowPins, 0, i2cOutputs)
+	commands = append(commands, mpsseWriteBytesFalling, 0, 0, value)
+	commands = append(commands, mpsseSetLowPins, 0, i2cSCL)
+	return append(commands, mpsseReadBitsRising, 0)
+}
+
+/*
+Appends the sampling of a byte, which adds one byte to the
+response, and its acknowledge bit, negated for the last byte
+*/
+func i2cReadByte(commands []byte, last bool) []byte {
+	ack := byte(0x00)
+	if last {
+		ack = 0xFF
+	}
+	commands = append(commands, mpsseSetLowPins, 0, i2cSCL)
+	commands = append(commands, mpsseReadBytesRising, 0, 0)
+	commands = append(commands, mpsseSetLowPins, 0, i2cOutputs)
+	return append(commands, mpsseWriteBitsFalling, 0, ack)
+}
+
+/*
+Configures the engine for I2C, where the three-phase clock keeps
+SDA valid while SCL is high
+*/
+func (f *ft232hI2C) setup(speed uint) error {
+	if err := f.configure(speed, true, i2cOutputs, i2cOutputs); err != nil {
+		return err
+	}
+	_, err := f.exchange([]byte{mpsseDriveZero, i2cOutputs | i2cSDAIn, 0x00}, 0)
+	return err
+}
+
+/*
+Writes data to the target device, optionally without the stop
+condition so a repeated start can follow
+*/
+func (f *ft232hI2C) write(address uint8, data []byte, stop bool) error {
+	commands := i2cStart(nil)
+	for index, value := range append([]byte{address << 1}, data...) {
+		response, err := f.exchange(i2cWriteByte(commands, value), 1)
+		if err != nil {
+			return err
+		}
+		if response[0]&0x01 != 0 {
+			f.exchange(i2cStop(nil), 0)
+			if index == 0 {
+				return fmt.Errorf("i2c write to 0x%02X failed: address not acknowledged", address)
+			}
+			return fmt.Errorf("i2c write to 0x%02X failed: byte %d not acknowledged", address, index-1)
+		}
+		commands = nil
+	}
+	if stop {
+		_, err := f.exchange(i2cStop(nil), 0)
+		return err
+	}
+	return nil
+}
+
+/*
+Reads n bytes from the target device, the start condition being
+the same whether repeated or not
+*/
+func (f *ft232hI2C) read(address uint8, n uint, repeated bool) ([]byte, error) {
+	response, err := f.exchange(i2cWriteByte(i2cStart(nil), address<<1|1), 1)
+	if err != nil {
+		return nil, err
+	}
+	if response[0]&0x01 != 0 {
+		f.exchange(i2cStop(nil), 0)
+		return nil, fmt.Errorf("i2c read from 0x%02X failed: address not acknowledged", address)
+	}
+
+	var commands []byte
+	for index := range n {
+		commands = i2cReadByte(commands, index == n-1)
+	}
+	return f.exchange(i2cStop(commands), int(n))
+}
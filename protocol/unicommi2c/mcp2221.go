@@ -0,0 +1,147 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommi2c
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+const (
+	reportSize       = 64
+	maxChunk         = 60
+	cmdStatus        = 0x10
+	cmdGetData       = 0x40
+	cmdWrite         = 0x90
+	cmdRead          = 0x91
+	cmdRepeatedRead  = 0x93
+	cmdWriteNoStop   = 0x94
+	statusCancel     = 0x10
+	statusSetSpeed   = 0x20
+	bridgeClock      = 12_000_000
+	getDataErrorMark = 0x41
+)
+
+/*
+MCP2221 bridge, driven by 64-byte HID reports
+*/
+type mcp2221 struct {
+	link    io.ReadWriter
+	timeout time.Duration
+}
+
+/*
+Sends a command report to the bridge and returns its response
+*/
+func (m *mcp2221) transfer(command []byte) ([]byte, error) {
+	// HID reports are prefixed by the report number, which is zero
+	report := make([]byte, reportSize+1)
+	copy(report[1:], command)
+
+	setDeadline(m.link, m.timeout)
+	if _, err := m.link.Write(report); err != nil {
+		return nil, err
+	}
+
+	response := make([]byte, reportSize)
+	if _, err := io.ReadFull(m.link, response); err != nil {
+		return nil, err
+	}
+	if response[0] != command[0] {
+		return nil, fmt.Errorf("unexpected bridge response 0x%02X to command 0x%02X", response[0], command[0])
+	}
+	return response, nil
+}
+
+/*
+Cancels any pending transfer and sets the bus speed, which the
+bridge derives from its 12MHz clock with an 8-bit divider
+*/
+func (m *mcp2221) setup(speed uint) error {
+	if speed == 0 || bridgeClock/speed < 4 || bridgeClock/speed-3 > 0xFF {
+		return fmt.Errorf("invalid options: bus speed of %d Hz is out of the range of the MCP2221", speed)
+	}
+	divider := bridgeClock/speed - 3
+
+	response, err := m.transfer([]byte{cmdStatus, 0, statusCancel, statusSetSpeed, byte(divider)})
+	if err != nil {
+		return err
+	}
+	if response[3] != statusSetSpeed {
+		return fmt.Errorf("bridge rejected the bus speed of %d Hz", speed)
+	}
+	return nil
+}
+
+/*
+Writes data to the target device, optionally without the stop
+condition so a repeated start can follow
+*/
+func (m *mcp2221) write(address uint8, data []byte, stop bool) error {
+	command := byte(cmdWriteNoStop)
+	if stop {
+		command = cmdWrite
+	}
+	// Every report of a transfer carries its total length, so the
+	// bridge appends the chunks to the same transaction
+	for offset := 0; offset < len(data) || offset == 0; offset += maxChunk {
+		chunk := data[offset:min(offset+maxChunk, len(data))]
+		report := append([]byte{
+			command, byte(len(data)), byte(len(data) >> 8), address << 1,
+		}, chunk...)
+
+		response, err := m.transfer(report)
+		if err != nil {
+			return err
+		}
+		if response[1] != 0 {
+			return fmt.Errorf("i2c write to 0x%02X failed", address)
+		}
+		if len(data) == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+/*
+Reads n bytes from the target device, using a repeated start
+when requested
+*/
+func (m *mcp2221) read(address uint8, n uint, repeated bool) ([]byte, error) {
+	command := byte(cmdRead)
+	if repeated {
+		command = cmdRepeatedRead
+	}
+	response, err := m.transfer([]byte{command, byte(n), byte(n >> 8), address<<1 | 1})
+	if err != nil {
+		return nil, err
+	}
+	if response[1] != 0 {
+		return nil, fmt.Errorf("i2c read from 0x%02X failed", address)
+	}
+
+	buffer := make([]byte, 0, n)
+	deadline := time.Now().Add(m.timeout)
+	for uint(len(buffer)) < n {
+		if time.Now().After(deadline) {
+			return buffer, unicommio.TimeoutError("i2c read")
+		}
+		response, err := m.transfer([]byte{cmdGetData})
+		if err != nil {
+			return buffer, err
+		}
+		if response[1] == getDataErrorMark || response[3] > maxChunk {
+			return buffer, fmt.Errorf("i2c read from 0x%02X failed", address)
+		}
+		buffer = append(buffer, response[4:4+response[3]]...)
+	}
+	return buffer, nil
+}
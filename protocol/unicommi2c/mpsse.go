@@ -0,0 +1,100 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommi2c
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	mpsseWriteBytesFalling = 0x11 // Bytes out on the falling edge, MSB first
+	mpsseWriteBytesRising  = 0x10 // Bytes out on the rising edge, MSB first
+	mpsseWriteBitsFalling  = 0x13 // Bits out on the falling edge, MSB first
+	mpsseReadBytesRising   = 0x20 // Bytes in on the rising edge, MSB first
+	mpsseReadBitsRising    = 0x22 // Bits in on the rising edge, MSB first
+	mpsseDuplexFalling     = 0x31 // Bytes out on the falling edge, in on the rising
+	mpsseDuplexRising      = 0x34 // Bytes out on the rising edge, in on the falling
+	mpsseSetLowPins        = 0x80
+	mpsseLoopbackOff       = 0x85
+	mpsseSetDivisor        = 0x86
+	mpsseSendImmediate     = 0x87
+	mpsseDivideBy5Off      = 0x8A
+	mpsseThreePhaseOn      = 0x8C
+	mpsseThreePhaseOff     = 0x8D
+	mpsseAdaptiveOff       = 0x97
+	mpsseDriveZero         = 0x9E
+	mpsseBadCommand        = 0xAB
+	mpsseBadCommandEcho    = 0xFA
+	mpsseClock             = 30_000_000 // 60MHz master clock halved by each period
+	mpsseMaxBlock          = 65536
+)
+
+/*
+MPSSE engine of a FTDI bridge, driven by command bytes on a link
+already set to MPSSE mode
+*/
+type mpsse struct {
+	link    io.ReadWriter
+	timeout time.Duration
+}
+
+/*
+Sends the commands and reads n bytes of response, flushing the
+bridge buffer when a response is expected
+*/
+func (m *mpsse) exchange(commands []byte, n int) ([]byte, error) {
+	if n > 0 {
+		commands = append(commands, mpsseSendImmediate)
+	}
+
+	setDeadline(m.link, m.timeout)
+	if _, err := m.link.Write(commands); err != nil {
+		return nil, err
+	}
+
+	response := make([]byte, n)
+	if _, err := io.ReadFull(m.link, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+/*
+Synchronizes with the engine and sets the clock, which the
+bridge derives from its master clock with a 16-bit divisor
+*/
+func (m *mpsse) configure(speed uint, threePhase bool, pins, dirs byte) error {
+	clock := uint(mpsseClock)
+	phase := byte(mpsseThreePhaseOff)
+	if threePhase {
+		clock, phase = clock*2/3, mpsseThreePhaseOn
+	}
+	if speed == 0 || clock/speed < 1 || clock/speed-1 > 0xFFFF {
+		return fmt.Errorf("invalid options: bus speed of %d Hz is out of the range of the FT232H", speed)
+	}
+	divisor := clock/speed - 1
+
+	// A bad command is echoed back, so data left by a previous
+	// session is not mistaken for a response
+	response, err := m.exchange([]byte{mpsseBadCommand}, 2)
+	if err != nil {
+		return err
+	}
+	if response[0] != mpsseBadCommandEcho || response[1] != mpsseBadCommand {
+		return fmt.Errorf("bridge is not in MPSSE mode, got 0x%02X 0x%02X", response[0], response[1])
+	}
+
+	_, err = m.exchange([]byte{
+		mpsseDivideBy5Off, mpsseAdaptiveOff, phase,
+		mpsseSetDivisor, byte(divisor), byte(divisor >> 8),
+		mpsseLoopbackOff,
+		mpsseSetLowPins, pins, dirs,
+	}, 0)
+	return err
+}
@@ -0,0 +1,205 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommi2c
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+type SPIOptions struct {
+	Mode    uint8         // Clock polarity and phase, from 0 to 3
+	Speed   uint          // Bus clock in Hz
+	Timeout time.Duration // Timeout of each bridge transaction
+
+	// Opens the link to a FT232H set to MPSSE mode, e.g. a D2XX or
+	// libftdi handle, since the kernel driver does not expose it
+	Open func() (io.ReadWriteCloser, error)
+}
+
+type UnicommSPI struct {
+	Options    SPIOptions
+	Connection io.ReadWriteCloser
+
+	engine mpsse
+	mutex  sync.Mutex // Protect bridge instance
+}
+
+// Pins of the low byte, with the chip select active low
+const (
+	spiSCK     = 0x01
+	spiMOSI    = 0x02
+	spiCS      = 0x08
+	spiOutputs = spiSCK | spiMOSI | spiCS
+)
+
+const (
+	defaultSPISpeed   = 1_000_000
+	defaultSPITimeout = 100 * time.Millisecond
+)
+
+/*
+Creates a new instance of Unicomm SPI communication through a
+FT232H USB bridge
+*/
+func NewSPI(options SPIOptions) *UnicommSPI {
+	if options.Speed == 0 {
+		options.Speed = defaultSPISpeed
+	}
+	if options.Timeout == 0 {
+		options.Timeout = defaultSPITimeout
+	}
+	return &UnicommSPI{
+		Options: options,
+	}
+}
+
+/*
+Returns the levels of the pins between transfers, with the clock
+at its polarity and the chip select released
+*/
+func (us *UnicommSPI) idle() byte {
+	if us.Options.Mode&0x02 != 0 {
+		return spiCS | spiSCK
+	}
+	return spiCS
+}
+
+/*
+Returns true if the bridge is opened
+*/
+func (us *UnicommSPI) IsConnected() bool {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	return us.Connection != nil
+}
+
+/*
+Opens the bridge and configures the bus speed
+*/
+func (us *UnicommSPI) Connect() error {
+	if us.Options.Mode > 3 {
+		return fmt.Errorf("invalid options: SPI mode must be from 0 to 3, got %d", us.Options.Mode)
+	}
+	if us.Options.Open == nil {
+		return fmt.Errorf("invalid options: the FT232H bridge requires Open")
+	}
+
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if us.Connection != nil {
+		return fmt.Errorf("there is a bridge already connected")
+	}
+
+	link, err := us.Options.Open()
+	if err != nil {
+		return err
+	}
+	engine := mpsse{link: link, timeout: us.Options.Timeout}
+	if err := engine.configure(us.Options.Speed, false, us.idle(), spiOutputs); err != nil {
+		link.Close()
+		return err
+	}
+	us.Connection = link
+	us.engine = engine
+	return nil
+}
+
+/*
+Closes the bridge
+*/
+func (us *UnicommSPI) Disconnect() error {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if us.Connection == nil {
+		return fmt.Errorf("there is no bridge connected")
+	}
+	if err := us.Connection.Close(); err != nil {
+		return err
+	}
+	us.Connection = nil
+	return nil
+}
+
+/*
+Clocks the message out with the chip select asserted, returning
+the bytes clocked in when duplex
+*/
+func (us *UnicommSPI) transfer(message []byte, duplex bool) ([]byte, error) {
+	// Modes 0 and 3 shift data out on the falling edge and sample
+	// it on the rising one, modes 1 and 2 the other way around
+	command, duplexCommand := byte(mpsseWriteBytesFalling), byte(mpsseDuplexFalling)
+	if us.Options.Mode == 1 || us.Options.Mode == 2 {
+		command, duplexCommand = mpsseWriteBytesRising, mpsseDuplexRising
+	}
+	if duplex {
+		command = duplexCommand
+	}
+
+	commands := []byte{mpsseSetLowPins, us.idle() &^ spiCS, spiOutputs}
+	for offset := 0; offset < len(message); offset += mpsseMaxBlock {
+		chunk := message[offset:min(offset+mpsseMaxBlock, len(message))]
+		commands = append(commands, command, byte(len(chunk)-1), byte((len(chunk)-1)>>8))
+		commands = append(commands, chunk...)
+	}
+	commands = append(commands, mpsseSetLowPins, us.idle(), spiOutputs)
+
+	if !duplex {
+		_, err := us.engine.exchange(commands, 0)
+		return nil, err
+	}
+	return us.engine.exchange(commands, len(message))
+}
+
+/*
+Writes the message while reading the same number of bytes, in a
+single assertion of the chip select
+*/
+func (us *UnicommSPI) Transfer(message []byte) ([]byte, error) {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if us.Connection == nil {
+		return nil, fmt.Errorf("there is no bridge connected")
+	}
+	return us.transfer(message, true)
+}
+
+/*
+Reads a number of bytes from the device, clocking out zeros
+*/
+func (us *UnicommSPI) Read(n uint) ([]byte, error) {
+	return us.Transfer(make([]byte, n))
+}
+
+/*
+Writes an array of bytes to the device, discarding the bytes
+clocked in
+*/
+func (us *UnicommSPI) Write(message []byte) error {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if us.Connection == nil {
+		return fmt.Errorf("there is no bridge connected")
+	}
+	_, err := us.transfer(message, false)
+	return err
+}
+
+/*
+Writes an array of bytes to the device, the same as Write since
+SPI transfers have no end delimiter
+*/
+func (us *UnicommSPI) WriteRaw(message []byte) error {
+	return us.Write(message)
+}
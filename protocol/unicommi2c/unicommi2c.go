@@ -0,0 +1,243 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommi2c
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+type Bridge uint8
+
+const (
+	MCP2221 Bridge = 0 // HID bridge, opened at DevicePath
+	FT232H  Bridge = 1 // MPSSE bridge, opened by Open
+)
+
+type I2COptions struct {
+	Bridge        Bridge
+	DevicePath    string        // HID device of a MCP2221, e.g. /dev/hidraw0
+	Address       uint8         // 7-bit address of the target device
+	RegisterWidth uint8         // Size of register addresses in bytes
+	Speed         uint          // Bus clock in Hz
+	Timeout       time.Duration // Timeout of each bridge transaction
+
+	// Opens the link to the bridge, required by the FT232H, e.g. a
+	// D2XX or libftdi handle set to MPSSE mode, since the kernel
+	// driver does not expose it. Defaults to opening DevicePath
+	Open func() (io.ReadWriteCloser, error)
+}
+
+type UnicommI2C struct {
+	Options    I2COptions
+	Connection io.ReadWriteCloser
+
+	bridge i2cBridge
+	mutex  sync.Mutex // Protect bridge instance
+}
+
+/*
+Bus operations of a USB bridge, called with the mutex locked
+*/
+type i2cBridge interface {
+	setup(speed uint) error
+	write(address uint8, data []byte, stop bool) error
+	read(address uint8, n uint, repeated bool) ([]byte, error)
+}
+
+const (
+	defaultI2CSpeed   = 100_000
+	defaultI2CTimeout = 100 * time.Millisecond
+)
+
+/*
+Creates a new instance of Unicomm I2C communication through a
+MCP2221 or a FT232H USB bridge
+*/
+func NewI2C(options I2COptions) *UnicommI2C {
+	if options.RegisterWidth == 0 {
+		options.RegisterWidth = 1
+	}
+	if options.Speed == 0 {
+		options.Speed = defaultI2CSpeed
+	}
+	if options.Timeout == 0 {
+		options.Timeout = defaultI2CTimeout
+	}
+	return &UnicommI2C{
+		Options: options,
+	}
+}
+
+/*
+Opens the link to a bridge with the function of the options, or
+the device at the path otherwise
+*/
+func openLink(open func() (io.ReadWriteCloser, error), path string) (io.ReadWriteCloser, error) {
+	if open != nil {
+		return open()
+	}
+	if path == "" {
+		return nil, fmt.Errorf("invalid options: device path is empty")
+	}
+	return os.OpenFile(path, os.O_RDWR, 0)
+}
+
+/*
+Sets the deadline of the next exchange on links supporting it,
+such as HID devices
+*/
+func setDeadline(link io.ReadWriter, timeout time.Duration) {
+	if device, ok := link.(interface{ SetDeadline(time.Time) error }); ok {
+		device.SetDeadline(time.Now().Add(timeout))
+	}
+}
+
+/*
+Returns true if the bridge is opened
+*/
+func (ui *UnicommI2C) IsConnected() bool {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	return ui.Connection != nil
+}
+
+/*
+Opens the bridge and configures the bus speed
+*/
+func (ui *UnicommI2C) Connect() error {
+	if ui.Options.Address > 0x7F {
+		return fmt.Errorf("invalid options: address must have 7 bits, got 0x%02X", ui.Options.Address)
+	}
+	if ui.Options.Bridge > FT232H {
+		return fmt.Errorf("invalid options: unknown bridge %d", ui.Options.Bridge)
+	}
+	if ui.Options.Bridge == FT232H && ui.Options.Open == nil {
+		return fmt.Errorf("invalid options: the FT232H bridge requires Open")
+	}
+
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	if ui.Connection != nil {
+		return fmt.Errorf("there is a bridge already connected")
+	}
+
+	link, err := openLink(ui.Options.Open, ui.Options.DevicePath)
+	if err != nil {
+		return err
+	}
+	var bridge i2cBridge = &mcp2221{link: link, timeout: ui.Options.Timeout}
+	if ui.Options.Bridge == FT232H {
+		bridge = &ft232hI2C{mpsse{link: link, timeout: ui.Options.Timeout}}
+	}
+	if err := bridge.setup(ui.Options.Speed); err != nil {
+		link.Close()
+		return err
+	}
+	ui.Connection = link
+	ui.bridge = bridge
+	return nil
+}
+
+/*
+Closes the bridge
+*/
+func (ui *UnicommI2C) Disconnect() error {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	if ui.Connection == nil {
+		return fmt.Errorf("there is no bridge connected")
+	}
+	if err := ui.Connection.Close(); err != nil {
+		return err
+	}
+	ui.Connection = nil
+	ui.bridge = nil
+	return nil
+}
+
+/*
+Encodes a register address using the configured width, most
+significant byte first
+*/
+func (ui *UnicommI2C) registerBytes(register uint) []byte {
+	encoded := make([]byte, ui.Options.RegisterWidth)
+	for index := range encoded {
+		shift := 8 * (len(encoded) - 1 - index)
+		encoded[index] = byte(register >> shift)
+	}
+	return encoded
+}
+
+/*
+Reads n bytes starting at the register of the target device
+*/
+func (ui *UnicommI2C) ReadRegister(register uint, n uint) ([]byte, error) {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	if ui.Connection == nil {
+		return nil, fmt.Errorf("there is no bridge connected")
+	}
+	if err := ui.bridge.write(ui.Options.Address, ui.registerBytes(register), false); err != nil {
+		return nil, err
+	}
+	return ui.bridge.read(ui.Options.Address, n, true)
+}
+
+/*
+Writes data starting at the register of the target device
+*/
+func (ui *UnicommI2C) WriteRegister(register uint, data []byte) error {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	if ui.Connection == nil {
+		return fmt.Errorf("there is no bridge connected")
+	}
+	return ui.bridge.write(ui.Options.Address, append(ui.registerBytes(register), data...), true)
+}
+
+/*
+Reads a number of bytes from the target device
+*/
+func (ui *UnicommI2C) Read(n uint) ([]byte, error) {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	if ui.Connection == nil {
+		return nil, fmt.Errorf("there is no bridge connected")
+	}
+	return ui.bridge.read(ui.Options.Address, n, false)
+}
+
+/*
+Writes an array of bytes to the target device
+*/
+func (ui *UnicommI2C) Write(message []byte) error {
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	if ui.Connection == nil {
+		return fmt.Errorf("there is no bridge connected")
+	}
+	return ui.bridge.write(ui.Options.Address, message, true)
+}
+
+/*
+Writes an array of bytes to the target device, the same as Write
+since I2C transfers have no end delimiter
+*/
+func (ui *UnicommI2C) WriteRaw(message []byte) error {
+	return ui.Write(message)
+}
@@ -0,0 +1,414 @@
+package unicommi2c_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommi2c"
+)
+
+/*
+Emulates a MCP2221 with a register device behind it
+*/
+type fakeMCP2221 struct {
+	address   uint8
+	divider   byte
+	registers [256]byte
+	pointer   byte
+	remaining int // Bytes left of the write transfer
+	pending   []byte
+	responses bytes.Buffer
+}
+
+func (f *fakeMCP2221) Write(report []byte) (int, error) {
+	if len(report) != 65 || report[0] != 0 {
+		return 0, fmt.Errorf("invalid report of %d bytes", len(report))
+	}
+	command := report[1:]
+	response := make([]byte, 64)
+	response[0] = command[0]
+
+	size := int(command[1]) | int(command[2])<<8
+	switch command[0] {
+	case 0x10:
+		if command[3] == 0x20 {
+			f.divider = command[4]
+			response[3] = 0x20
+		}
+	case 0x90, 0x94:
+		if command[3]>>1 != f.address {
+			response[1] = 1
+			break
+		}
+		// The first byte of a transfer selects the register
+		first := f.remaining == 0
+		if first {
+			f.remaining = size
+		}
+		data := command[4 : 4+min(f.remaining, 60)]
+		f.remaining -= len(data)
+		if first && len(data) > 0 {
+			f.pointer, data = data[0], data[1:]
+		}
+		for _, value := range data {
+			f.registers[f.pointer] = value
+			f.pointer++
+		}
+	case 0x91, 0x93:
+		if command[3]>>1 != f.address {
+			response[1] = 1
+			break
+		}
+		for range size {
+			f.pending = append(f.pending, f.registers[f.pointer])
+			f.pointer++
+		}
+	case 0x40:
+		chunk := f.pending[:min(60, len(f.pending))]
+		f.pending = f.pending[len(chunk):]
+		response[3] = byte(len(chunk))
+		copy(response[4:], chunk)
+	}
+	f.responses.Write(response)
+	return len(report), nil
+}
+
+func (f *fakeMCP2221) Read(buffer []byte) (int, error) {
+	return f.responses.Read(buffer)
+}
+
+func (f *fakeMCP2221) Close() error {
+	return nil
+}
+
+/*
+Emulates the MPSSE engine of a FT232H, recording the bytes clocked
+out and answering with the queued acknowledges and data
+*/
+type fakeMPSSE struct {
+	acks      []byte // Acknowledge bits sampled, zero when empty
+	data      []byte // Bytes sampled by the reads
+	divisor   int
+	pins      []byte // Levels of every pin update
+	written   []byte // Bytes clocked out
+	ackBits   []byte // Bits clocked out
+	commands  []byte // Data shifting commands
+	responses bytes.Buffer
+}
+
+func (f *fakeMPSSE) Write(stream []byte) (int, error) {
+	for index := 0; index < len(stream); {
+		command := stream[index]
+		index++
+		switch command {
+		case 0xAB:
+			f.responses.Write([]byte{0xFA, 0xAB})
+		case 0x8A, 0x97, 0x8C, 0x8D, 0x85, 0x87:
+		case 0x80:
+			f.pins = append(f.pins, stream[index])
+			index += 2
+		case 0x86:
+			f.divisor = int(stream[index]) | int(stream[index+1])<<8
+			index += 2
+		case 0x9E:
+			index += 2
+		case 0x22:
+			ack := byte(0)
+			if len(f.acks) > 0 {
+				ack, f.acks = f.acks[0], f.acks[1:]
+			}
+			f.responses.WriteByte(ack)
+			index++
+		case 0x13:
+			f.ackBits = append(f.ackBits, stream[index+1])
+			index += 2
+		case 0x20:
+			size := int(stream[index]) | int(stream[index+1])<<8 + 1
+			f.responses.Write(f.data[:size])
+			f.data = f.data[size:]
+			index += 2
+		case 0x10, 0x11, 0x31, 0x34:
+			size := int(stream[index]) | int(stream[index+1])<<8 + 1
+			data := stream[index+2 : index+2+size]
+			f.commands = append(f.commands, command)
+			f.written = append(f.written, data...)
+			if command == 0x31 || command == 0x34 {
+				for _, value := range data {
+					f.responses.WriteByte(^value)
+				}
+			}
+			index += 2 + size
+		default:
+			return 0, fmt.Errorf("unexpected command 0x%02X", command)
+		}
+	}
+	return len(stream), nil
+}
+
+func (f *fakeMPSSE) Read(buffer []byte) (int, error) {
+	return f.responses.Read(buffer)
+}
+
+func (f *fakeMPSSE) Close() error {
+	return nil
+}
+
+func opener(link io.ReadWriteCloser) func() (io.ReadWriteCloser, error) {
+	return func() (io.ReadWriteCloser, error) {
+		return link, nil
+	}
+}
+
+func TestMCP2221Registers(t *testing.T) {
+	bridge := &fakeMCP2221{address: 0x48}
+	i2c := unicommi2c.NewI2C(unicommi2c.I2COptions{
+		Address: 0x48,
+		Speed:   400_000,
+		Open:    opener(bridge),
+	})
+	if err := i2c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer i2c.Disconnect()
+
+	if bridge.divider != 27 {
+		t.Fatalf("divider = %d, want 27", bridge.divider)
+	}
+	payload := bytes.Repeat([]byte{0xA5, 0x5A}, 50)
+	if err := i2c.WriteRegister(0x10, payload); err != nil {
+		t.Fatal(err)
+	}
+	data, err := i2c.ReadRegister(0x10, uint(len(payload)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, payload) {
+		t.Fatalf("ReadRegister = % X, want % X", data, payload)
+	}
+}
+
+func TestMCP2221NotAcknowledged(t *testing.T) {
+	i2c := unicommi2c.NewI2C(unicommi2c.I2COptions{
+		Address: 0x20,
+		Open:    opener(&fakeMCP2221{address: 0x48}),
+	})
+	if err := i2c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer i2c.Disconnect()
+
+	if err := i2c.Write([]byte{0x01}); err == nil {
+		t.Fatal("Write to an absent device succeeded")
+	}
+	if _, err := i2c.Read(1); err == nil {
+		t.Fatal("Read from an absent device succeeded")
+	}
+}
+
+func TestBusSpeedRange(t *testing.T) {
+	tests := []struct {
+		bridge unicommi2c.Bridge
+		speed  uint
+		valid  bool
+	}{
+		{unicommi2c.MCP2221, 0, false},
+		{unicommi2c.MCP2221, 10_000, false},
+		{unicommi2c.MCP2221, 47_000, true},
+		{unicommi2c.MCP2221, 3_000_000, true},
+		{unicommi2c.MCP2221, 4_000_000, false},
+		{unicommi2c.FT232H, 0, false},
+		{unicommi2c.FT232H, 100, false},
+		{unicommi2c.FT232H, 400_000, true},
+		{unicommi2c.FT232H, 30_000_000, false},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d/%d", test.bridge, test.speed), func(t *testing.T) {
+			var link io.ReadWriteCloser = &fakeMCP2221{}
+			if test.bridge == unicommi2c.FT232H {
+				link = &fakeMPSSE{}
+			}
+			// Built directly so the zero speed is not defaulted
+			i2c := &unicommi2c.UnicommI2C{Options: unicommi2c.I2COptions{
+				Bridge: test.bridge,
+				Speed:  test.speed,
+				Open:   opener(link),
+			}}
+			err := i2c.Connect()
+			if test.valid && err != nil {
+				t.Fatal(err)
+			}
+			if !test.valid && (err == nil || !strings.Contains(err.Error(), "bus speed")) {
+				t.Fatalf("Connect = %v, want a bus speed error", err)
+			}
+			if i2c.IsConnected() != test.valid {
+				t.Fatalf("IsConnected = %v, want %v", i2c.IsConnected(), test.valid)
+			}
+		})
+	}
+}
+
+func TestFT232HRegisters(t *testing.T) {
+	bridge := &fakeMPSSE{data: []byte{0x12, 0x34}}
+	i2c := unicommi2c.NewI2C(unicommi2c.I2COptions{
+		Bridge:  unicommi2c.FT232H,
+		Address: 0x48,
+		Speed:   400_000,
+		Open:    opener(bridge),
+	})
+	if err := i2c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer i2c.Disconnect()
+
+	// Three-phase clock of 20MHz
+	if bridge.divisor != 49 {
+		t.Fatalf("divisor = %d, want 49", bridge.divisor)
+	}
+	if err := i2c.WriteRegister(0x01, []byte{0x60}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x90, 0x01, 0x60}; !bytes.Equal(bridge.written, want) {
+		t.Fatalf("written = % X, want % X", bridge.written, want)
+	}
+	if last := bridge.pins[len(bridge.pins)-1]; last != 0x03 {
+		t.Fatalf("bus left at 0x%02X, want idle", last)
+	}
+
+	bridge.written = nil
+	data, err := i2c.ReadRegister(0x00, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte{0x12, 0x34}) {
+		t.Fatalf("ReadRegister = % X", data)
+	}
+	if want := []byte{0x90, 0x00, 0x91}; !bytes.Equal(bridge.written, want) {
+		t.Fatalf("written = % X, want % X", bridge.written, want)
+	}
+	// Acknowledges every byte but the last
+	if want := []byte{0x00, 0xFF}; !bytes.Equal(bridge.ackBits, want) {
+		t.Fatalf("acknowledges = % X, want % X", bridge.ackBits, want)
+	}
+}
+
+func TestFT232HNotAcknowledged(t *testing.T) {
+	bridge := &fakeMPSSE{}
+	i2c := unicommi2c.NewI2C(unicommi2c.I2COptions{
+		Bridge:  unicommi2c.FT232H,
+		Address: 0x48,
+		Open:    opener(bridge),
+	})
+	if err := i2c.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer i2c.Disconnect()
+
+	bridge.acks = []byte{1}
+	err := i2c.Write([]byte{0x01, 0x02})
+	if err == nil || !strings.Contains(err.Error(), "address not acknowledged") {
+		t.Fatalf("Write = %v, want an address error", err)
+	}
+	if !bytes.Equal(bridge.written, []byte{0x90}) {
+		t.Fatalf("written = % X, want only the address", bridge.written)
+	}
+
+	bridge.acks, bridge.written = []byte{0, 0, 1}, nil
+	err = i2c.Write([]byte{0x01, 0x02})
+	if err == nil || !strings.Contains(err.Error(), "byte 1 not acknowledged") {
+		t.Fatalf("Write = %v, want a byte error", err)
+	}
+	if last := bridge.pins[len(bridge.pins)-1]; last != 0x03 {
+		t.Fatalf("bus left at 0x%02X, want idle", last)
+	}
+}
+
+func TestFT232HRequiresOpen(t *testing.T) {
+	i2c := unicommi2c.NewI2C(unicommi2c.I2COptions{Bridge: unicommi2c.FT232H})
+	if err := i2c.Connect(); err == nil {
+		t.Fatal("Connect without Open succeeded")
+	}
+}
+
+func TestSPITransfer(t *testing.T) {
+	tests := []struct {
+		mode    uint8
+		command byte
+		idle    byte
+	}{
+		{0, 0x31, 0x08},
+		{1, 0x34, 0x08},
+		{2, 0x34, 0x09},
+		{3, 0x31, 0x09},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprint("mode ", test.mode), func(t *testing.T) {
+			bridge := &fakeMPSSE{}
+			spi := unicommi2c.NewSPI(unicommi2c.SPIOptions{
+				Mode: test.mode,
+				Open: opener(bridge),
+			})
+			if err := spi.Connect(); err != nil {
+				t.Fatal(err)
+			}
+			defer spi.Disconnect()
+
+			if bridge.divisor != 29 {
+				t.Fatalf("divisor = %d, want 29", bridge.divisor)
+			}
+			bridge.pins = nil
+			response, err := spi.Transfer([]byte{0x9F, 0x00})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(response, []byte{0x60, 0xFF}) {
+				t.Fatalf("Transfer = % X", response)
+			}
+			if !bytes.Equal(bridge.commands, []byte{test.command}) {
+				t.Fatalf("commands = % X, want %02X", bridge.commands, test.command)
+			}
+			// Chip select asserted around the transfer only
+			if want := []byte{test.idle &^ 0x08, test.idle}; !bytes.Equal(bridge.pins, want) {
+				t.Fatalf("pins = % X, want % X", bridge.pins, want)
+			}
+		})
+	}
+}
+
+func TestSPIReadWrite(t *testing.T) {
+	bridge := &fakeMPSSE{}
+	spi := unicommi2c.NewSPI(unicommi2c.SPIOptions{Mode: 1, Open: opener(bridge)})
+	if err := spi.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer spi.Disconnect()
+
+	if err := spi.Write([]byte{0x06}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := spi.Read(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte{0xFF, 0xFF, 0xFF}) {
+		t.Fatalf("Read = % X", data)
+	}
+	if !bytes.Equal(bridge.commands, []byte{0x10, 0x34}) {
+		t.Fatalf("commands = % X", bridge.commands)
+	}
+	if !bytes.Equal(bridge.written, []byte{0x06, 0x00, 0x00, 0x00}) {
+		t.Fatalf("written = % X", bridge.written)
+	}
+}
+
+func TestSPIInvalidMode(t *testing.T) {
+	spi := unicommi2c.NewSPI(unicommi2c.SPIOptions{
+		Mode: 4,
+		Open: opener(&fakeMPSSE{}),
+	})
+	if err := spi.Connect(); err == nil {
+		t.Fatal("Connect with mode 4 succeeded")
+	}
+}
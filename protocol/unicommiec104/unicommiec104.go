@@ -0,0 +1,346 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommiec104
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+/*
+Transport used to exchange APDUs, usually a Unicomm TCP
+instance connected to port 2404 without delimiter
+*/
+type Transport interface {
+	Read(size uint) ([]byte, error)
+	Write(message []byte) error
+}
+
+type Format uint8
+
+const (
+	FormatI Format = 0 // Numbered information transfer
+	FormatS Format = 1 // Numbered supervisory acknowledgment
+	FormatU Format = 3 // Unnumbered control functions
+)
+
+type Function uint8
+
+const (
+	StartDTAct Function = 0x04
+	StartDTCon Function = 0x08
+	StopDTAct  Function = 0x10
+	StopDTCon  Function = 0x20
+	TestFRAct  Function = 0x40
+	TestFRCon  Function = 0x80
+)
+
+const (
+	startByte    = 0x68
+	controlSize  = 4
+	maxASDUSize  = 249
+	sequenceMask = 0x7FFF
+)
+
+/*
+Application protocol control information of an APDU, with
+the ASDU carried by I-frames
+*/
+type APDU struct {
+	Format   Format
+	SendSeq  uint16   // N(S) of I-frames
+	RecvSeq  uint16   // N(R) of I and S-frames
+	Function Function // Function of U-frames
+	ASDU     []byte
+}
+
+type LinkOptions struct {
+	K uint16 // Unacknowledged I-frames sent before blocking, defaults to 12
+	W uint16 // I-frames received before acknowledging, defaults to 8
+}
+
+type Link struct {
+	Options LinkOptions
+
+	transport Transport
+	sendSeq   uint16     // V(S), next send sequence number
+	recvSeq   uint16     // V(R), next expected receive sequence number
+	acked     uint16     // Last N(R) received from the peer
+	unacked   uint16     // I-frames received and not acknowledged yet
+	pending   []byte     // Bytes of an APDU interrupted by a timeout
+	mutex     sync.Mutex // Keep the sequence numbers consistent
+}
+
+/*
+Encodes the APDU with its start byte and length
+*/
+func (a APDU) Encode() ([]byte, error) {
+	if len(a.ASDU) > maxASDUSize {
+		return nil, fmt.Errorf("ASDU of %d bytes exceeds %d", len(a.ASDU), maxASDUSize)
+	}
+	frame := []byte{startByte, controlSize, 0, 0, 0, 0}
+	switch a.Format {
+	case FormatI:
+		frame[2] = byte(a.SendSeq << 1)
+		frame[3] = byte(a.SendSeq >> 7)
+		frame[4] = byte(a.RecvSeq << 1)
+		frame[5] = byte(a.RecvSeq >> 7)
+		frame[1] += byte(len(a.ASDU))
+		frame = append(frame, a.ASDU...)
+	case FormatS:
+		frame[2] = 0x01
+		frame[4] = byte(a.RecvSeq << 1)
+		frame[5] = byte(a.RecvSeq >> 7)
+	case FormatU:
+		frame[2] = 0x03 | byte(a.Function)
+	default:
+		return nil, fmt.Errorf("unknown APDU format %d", a.Format)
+	}
+	return frame, nil
+}
+
+/*
+Decodes the control field and the ASDU that follow the start
+byte and length
+*/
+func Decode(body []byte) (APDU, error) {
+	if len(body) < controlSize {
+		return APDU{}, fmt.Errorf("invalid APDU: %d bytes of control field", len(body))
+	}
+	switch {
+	case body[0]&0x01 == 0:
+		return APDU{
+			Format:  FormatI,
+			SendSeq: (uint16(body[0])>>1 | uint16(body[1])<<7) & sequenceMask,
+			RecvSeq: (uint16(body[2])>>1 | uint16(body[3])<<7) & sequenceMask,
+			ASDU:    body[controlSize:],
+		}, nil
+	case body[0]&0x03 == 0x01:
+		return APDU{
+			Format:  FormatS,
+			RecvSeq: (uint16(body[2])>>1 | uint16(body[3])<<7) & sequenceMask,
+		}, nil
+	default:
+		return APDU{Format: FormatU, Function: Function(body[0] &^ 0x03)}, nil
+	}
+}
+
+/*
+Creates the link layer of an IEC 60870-5-104 connection over
+the transport
+*/
+func NewLink(transport Transport, options LinkOptions) *Link {
+	if options.K == 0 {
+		options.K = 12
+	}
+	if options.W == 0 {
+		options.W = 8
+	}
+	return &Link{
+		Options:   options,
+		transport: transport,
+	}
+}
+
+/*
+Reads from the transport until n bytes are buffered. Bytes
+received before a timeout are kept, so the next read continues
+the same APDU
+*/
+func (l *Link) readFull(n int) error {
+	for len(l.pending) < n {
+		data, err := l.transport.Read(uint(n - len(l.pending)))
+		l.pending = append(l.pending, data...)
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return unicommio.TimeoutError("iec104 read")
+		}
+	}
+	return nil
+}
+
+/*
+Encodes and writes an APDU
+*/
+func (l *Link) write(apdu APDU) error {
+	frame, err := apdu.Encode()
+	if err != nil {
+		return err
+	}
+	return l.transport.Write(frame)
+}
+
+/*
+Reads the next APDU from the transport
+*/
+func (l *Link) read() (APDU, error) {
+	if err := l.readFull(2); err != nil {
+		return APDU{}, err
+	}
+	start, length := l.pending[0], int(l.pending[1])
+	if start != startByte || length < controlSize {
+		// The stream is misaligned, nothing buffered can be trusted
+		l.pending = nil
+		if start != startByte {
+			return APDU{}, fmt.Errorf("invalid APDU start byte 0x%02X", start)
+		}
+		return APDU{}, fmt.Errorf("invalid APDU length %d", length)
+	}
+	if err := l.readFull(2 + length); err != nil {
+		return APDU{}, err
+	}
+	body := bytes.Clone(l.pending[2 : 2+length])
+	l.pending = l.pending[2+length:]
+	return Decode(body)
+}
+
+/*
+Sends a U-frame and waits for its confirmation, answering the
+test frames received meanwhile
+*/
+func (l *Link) control(activation, confirmation Function) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if err := l.write(APDU{Format: FormatU, Function: activation}); err != nil {
+		return err
+	}
+	for {
+		apdu, err := l.read()
+		if err != nil {
+			return err
+		}
+		if apdu.Format == FormatU && apdu.Function == confirmation {
+			return nil
+		}
+		if apdu.Format == FormatU && apdu.Function == TestFRAct {
+			if err := l.write(APDU{Format: FormatU, Function: TestFRCon}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+/*
+Starts the data transfer with STARTDT and resets the sequence
+numbers
+*/
+func (l *Link) Start() error {
+	l.mutex.Lock()
+	l.sendSeq, l.recvSeq, l.acked, l.unacked = 0, 0, 0, 0
+	l.pending = nil
+	l.mutex.Unlock()
+
+	return l.control(StartDTAct, StartDTCon)
+}
+
+/*
+Stops the data transfer with STOPDT
+*/
+func (l *Link) Stop() error {
+	return l.control(StopDTAct, StopDTCon)
+}
+
+/*
+Sends a test frame and waits for its confirmation, to be
+called when the connection is idle for the t3 period
+*/
+func (l *Link) Test() error {
+	return l.control(TestFRAct, TestFRCon)
+}
+
+/*
+Returns the number of I-frames sent and not acknowledged by
+the peer, must be called with the mutex locked
+*/
+func (l *Link) outstanding() uint16 {
+	return (l.sendSeq - l.acked) & sequenceMask
+}
+
+/*
+Sends an ASDU in an I-frame, acknowledging the received ones
+*/
+func (l *Link) Send(asdu []byte) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.outstanding() >= l.Options.K {
+		return fmt.Errorf("%d I-frames are not acknowledged by the peer", l.outstanding())
+	}
+	err := l.write(APDU{Format: FormatI, SendSeq: l.sendSeq, RecvSeq: l.recvSeq, ASDU: asdu})
+	if err != nil {
+		return err
+	}
+	l.sendSeq = (l.sendSeq + 1) & sequenceMask
+	l.unacked = 0
+	return nil
+}
+
+/*
+Returns the next ASDU received. Test frames are answered and
+acknowledgments are tracked, and an S-frame is sent after every
+W received I-frames
+*/
+func (l *Link) Receive() ([]byte, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for {
+		apdu, err := l.read()
+		if err != nil {
+			return nil, err
+		}
+
+		switch apdu.Format {
+		case FormatU:
+			if apdu.Function == TestFRAct {
+				if err := l.write(APDU{Format: FormatU, Function: TestFRCon}); err != nil {
+					return nil, err
+				}
+			}
+		case FormatS:
+			l.acked = apdu.RecvSeq
+		case FormatI:
+			if apdu.SendSeq != l.recvSeq {
+				return nil, fmt.Errorf("sequence error: received N(S) %d, expected %d", apdu.SendSeq, l.recvSeq)
+			}
+			l.acked = apdu.RecvSeq
+			l.recvSeq = (l.recvSeq + 1) & sequenceMask
+			l.unacked++
+			if l.unacked >= l.Options.W {
+				if err := l.write(APDU{Format: FormatS, RecvSeq: l.recvSeq}); err != nil {
+					return nil, err
+				}
+				l.unacked = 0
+			}
+			return apdu.ASDU, nil
+		}
+	}
+}
+
+/*
+Acknowledges the received I-frames with an S-frame, to be
+called when no I-frame is sent within the t2 period
+*/
+func (l *Link) Acknowledge() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.unacked == 0 {
+		return nil
+	}
+	if err := l.write(APDU{Format: FormatS, RecvSeq: l.recvSeq}); err != nil {
+		return err
+	}
+	l.unacked = 0
+	return nil
+}
@@ -0,0 +1,99 @@
+package unicommiec104_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommiec104"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+/*
+Transport that delivers canned bytes and records the writes
+*/
+type fakeTransport struct {
+	pending []byte
+	written [][]byte
+}
+
+func (ft *fakeTransport) Write(message []byte) error {
+	ft.written = append(ft.written, message)
+	return nil
+}
+
+func (ft *fakeTransport) Read(size uint) ([]byte, error) {
+	n := min(int(size), len(ft.pending))
+	data := ft.pending[:n]
+	ft.pending = ft.pending[n:]
+	return data, nil
+}
+
+func TestAPDURoundTrip(t *testing.T) {
+	apdu := unicommiec104.APDU{
+		Format:  unicommiec104.FormatI,
+		SendSeq: 300,
+		RecvSeq: 17,
+		ASDU:    []byte{0x64, 0x01, 0x06, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x14},
+	}
+	frame, err := apdu.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frame[0] != 0x68 || int(frame[1]) != len(frame)-2 {
+		t.Fatalf("unexpected header % X", frame[:2])
+	}
+	decoded, err := unicommiec104.Decode(frame[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.SendSeq != 300 || decoded.RecvSeq != 17 || !bytes.Equal(decoded.ASDU, apdu.ASDU) {
+		t.Fatalf("unexpected APDU %+v", decoded)
+	}
+}
+
+func TestLinkReceive(t *testing.T) {
+	transport := &fakeTransport{}
+	link := unicommiec104.NewLink(transport, unicommiec104.LinkOptions{W: 1})
+
+	// A test frame from the peer followed by an I-frame
+	testFrame, _ := unicommiec104.APDU{Format: unicommiec104.FormatU, Function: unicommiec104.TestFRAct}.Encode()
+	iFrame, _ := unicommiec104.APDU{Format: unicommiec104.FormatI, ASDU: []byte{0x01, 0x02}}.Encode()
+	transport.pending = append(testFrame, iFrame...)
+
+	asdu, err := link.Receive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(asdu, []byte{0x01, 0x02}) {
+		t.Fatalf("unexpected ASDU % X", asdu)
+	}
+
+	// TESTFR con, then the S-frame acknowledging N(R) = 1
+	expected := [][]byte{{0x68, 0x04, 0x83, 0x00, 0x00, 0x00}, {0x68, 0x04, 0x01, 0x00, 0x02, 0x00}}
+	for i, frame := range expected {
+		if !bytes.Equal(transport.written[i], frame) {
+			t.Fatalf("write %d is % X, want % X", i, transport.written[i], frame)
+		}
+	}
+}
+
+func TestLinkReceiveAfterTimeout(t *testing.T) {
+	transport := &fakeTransport{}
+	link := unicommiec104.NewLink(transport, unicommiec104.LinkOptions{})
+
+	// The I-frame arrives in two parts with a timeout in between
+	iFrame, _ := unicommiec104.APDU{Format: unicommiec104.FormatI, ASDU: []byte{0x01, 0x02, 0x03}}.Encode()
+	transport.pending = bytes.Clone(iFrame[:5])
+	if _, err := link.Receive(); !unicommio.IsTimeout(err) {
+		t.Fatalf("expected a timeout, got %v", err)
+	}
+
+	transport.pending = bytes.Clone(iFrame[5:])
+	asdu, err := link.Receive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(asdu, []byte{0x01, 0x02, 0x03}) {
+		t.Fatalf("unexpected ASDU % X", asdu)
+	}
+}
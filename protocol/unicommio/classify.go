@@ -0,0 +1,84 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommio
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+type ErrorClass uint8
+
+const (
+	// Errors whose nature is not known, which are not retried
+	UnknownError ErrorClass = iota
+	// Transient conditions that may succeed when retried, such as
+	// EAGAIN, EINTR or timeouts
+	TemporaryError
+	// Conditions that will not recover without reconnecting, such as
+	// a removed device or a reset connection
+	FatalError
+)
+
+// Wait before retrying an operation that failed temporarily
+const RetryDelay = time.Millisecond
+
+/*
+Returns the name of the class
+*/
+func (ec ErrorClass) String() string {
+	switch ec {
+	case TemporaryError:
+		return "temporary"
+	case FatalError:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
+/*
+Classifies an error returned by a driver or a connection
+*/
+func Classify(err error) ErrorClass {
+	var netErr net.Error
+
+	switch {
+	case err == nil:
+		return UnknownError
+	case errors.Is(err, syscall.EAGAIN), errors.Is(err, syscall.EINTR),
+		errors.Is(err, syscall.EWOULDBLOCK), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.Is(err, ErrTimeout):
+		return TemporaryError
+	case errors.Is(err, syscall.ENXIO), errors.Is(err, syscall.ENODEV),
+		errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.EOF), errors.Is(err, os.ErrClosed), errors.Is(err, net.ErrClosed):
+		return FatalError
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return TemporaryError
+	}
+	return UnknownError
+}
+
+/*
+Returns true if the error is transient and the operation may
+be retried
+*/
+func IsTemporary(err error) bool {
+	return Classify(err) == TemporaryError
+}
+
+/*
+Returns true if the error will not recover without reconnecting
+*/
+func IsFatal(err error) bool {
+	return Classify(err) == FatalError
+}
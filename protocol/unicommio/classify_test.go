@@ -0,0 +1,52 @@
+package unicommio_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		err  error
+		want unicommio.ErrorClass
+	}{
+		{syscall.EAGAIN, unicommio.TemporaryError},
+		{&os.PathError{Op: "write", Path: "/dev/ttyUSB0", Err: syscall.EINTR}, unicommio.TemporaryError},
+		{os.ErrDeadlineExceeded, unicommio.TemporaryError},
+		{unicommio.TimeoutError("read until"), unicommio.TemporaryError},
+		{fmt.Errorf("write failed: %w", syscall.ENXIO), unicommio.FatalError},
+		{syscall.ECONNRESET, unicommio.FatalError},
+		{io.EOF, unicommio.FatalError},
+		{fmt.Errorf("checksum mismatch"), unicommio.UnknownError},
+	}
+	for _, c := range cases {
+		if got := unicommio.Classify(c.err); got != c.want {
+			t.Errorf("Classify(%v) = %s, want %s", c.err, got, c.want)
+		}
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{unicommio.TimeoutError("read until"), true},
+		{fmt.Errorf("expect %q: %w", "# ", unicommio.ErrTimeout), true},
+		{os.ErrDeadlineExceeded, true},
+		{&os.PathError{Op: "read", Path: "/dev/ttyUSB0", Err: os.ErrDeadlineExceeded}, true},
+		{fmt.Errorf("heater timeout"), false},
+		{io.EOF, false},
+	}
+	for _, c := range cases {
+		if got := unicommio.IsTimeout(c.err); got != c.want {
+			t.Errorf("IsTimeout(%v) = %t, want %t", c.err, got, c.want)
+		}
+	}
+}
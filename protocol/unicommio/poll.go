@@ -0,0 +1,69 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommio
+
+import (
+	"bytes"
+	"time"
+)
+
+type PollStrategy uint8
+
+const (
+	// Reads block in the driver until data arrives or the deadline
+	BlockingPoll PollStrategy = iota
+	// Reads return immediately and the loop sleeps while idle, with a
+	// backoff that grows up to the poll interval
+	AdaptivePoll
+)
+
+// Bytes requested from the driver on each read of a read loop
+const ReadChunkSize = 256
+
+/*
+Sleeps between empty polls, doubling the wait from 1ms up to
+the maximum interval
+*/
+type Backoff struct {
+	Max     time.Duration
+	current time.Duration
+}
+
+/*
+Sleeps for the current backoff without passing the deadline
+*/
+func (b *Backoff) Wait(deadline time.Time) {
+	if b.current == 0 {
+		b.current = time.Millisecond
+	}
+	time.Sleep(min(b.current, time.Until(deadline)))
+	b.current = min(2*b.current, b.Max)
+}
+
+/*
+Restarts the backoff after data is received
+*/
+func (b *Backoff) Reset() {
+	b.current = 0
+}
+
+/*
+Splits the buffer after the first delimiter, returning the
+frame and the bytes that follow it. An empty delimiter matches
+any non-empty buffer
+*/
+func SplitFrame(buffer []byte, delimiter string) (frame []byte, rest []byte, ok bool) {
+	if delimiter == "" {
+		return buffer, nil, len(buffer) > 0
+	}
+	index := bytes.Index(buffer, []byte(delimiter))
+	if index < 0 {
+		return nil, buffer, false
+	}
+	end := index + len(delimiter)
+	return buffer[:end], bytes.Clone(buffer[end:]), true
+}
@@ -0,0 +1,34 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommio
+
+import "sync"
+
+// Size of the scratch buffers handed out by the pool
+const PoolChunkSize = 4096
+
+var chunkPool = sync.Pool{
+	New: func() any {
+		chunk := make([]byte, PoolChunkSize)
+		return &chunk
+	},
+}
+
+/*
+Returns a scratch buffer of PoolChunkSize bytes, which must be
+given back with PutChunk once no slice of it is referenced
+*/
+func GetChunk() *[]byte {
+	return chunkPool.Get().(*[]byte)
+}
+
+/*
+Returns a scratch buffer to the pool
+*/
+func PutChunk(chunk *[]byte) {
+	chunkPool.Put(chunk)
+}
@@ -0,0 +1,58 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommio
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+)
+
+/*
+Matched by the errors of operations that did not complete
+before their timeout. Backends follow the same semantics, so
+drivers can recover without knowing the transport:
+
+  - Read completes as soon as any byte arrives. When none arrives
+    before the read timeout it returns an empty slice and a nil
+    error, so polling loops need no error handling. Transfers of a
+    known size, such as I2C, return the bytes transferred and a
+    timeout error instead
+  - ReadUntil returns the bytes received without the delimiter
+    and a timeout error. Those bytes are consumed, the next call
+    starts with the data received after them
+  - Errors other than timeouts also return the bytes received
+    before them, so no data is silently dropped
+*/
+var ErrTimeout = errors.New("timeout")
+
+/*
+Returns a timeout error of an operation, e.g. "read until
+timeout"
+*/
+func TimeoutError(operation string) error {
+	return fmt.Errorf("%s %w", operation, ErrTimeout)
+}
+
+/*
+Returns true if the error is a timeout of a backend, of a
+network connection or a timeout received from a remote agent.
+Only errors wrapping ErrTimeout or reporting a timeout through
+their type match, whatever their message
+*/
+func IsTimeout(err error) bool {
+	var netErr net.Error
+
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, ErrTimeout), errors.Is(err, os.ErrDeadlineExceeded):
+		return true
+	}
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
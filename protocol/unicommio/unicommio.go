@@ -0,0 +1,37 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommio
+
+import "fmt"
+
+/*
+Returned when a write transmits only part of the message. The
+bytes committed to the link are reported so callers know the
+exact state of the stream
+*/
+type PartialWriteError struct {
+	Written  int   // Bytes committed to the link
+	Expected int   // Size of the message
+	Err      error // Cause of the interruption
+}
+
+/*
+Describes the partial write and its cause
+*/
+func (pwe *PartialWriteError) Error() string {
+	if pwe.Err == nil {
+		return fmt.Sprintf("writed %d bytes, expected %d", pwe.Written, pwe.Expected)
+	}
+	return fmt.Sprintf("writed %d bytes, expected %d: %v", pwe.Written, pwe.Expected, pwe.Err)
+}
+
+/*
+Returns the cause of the interruption
+*/
+func (pwe *PartialWriteError) Unwrap() error {
+	return pwe.Err
+}
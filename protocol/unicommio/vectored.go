@@ -0,0 +1,48 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommio
+
+/*
+Returns the total size of the buffers
+*/
+func VectorSize(buffers [][]byte) int {
+	size := 0
+	for _, buffer := range buffers {
+		size += len(buffer)
+	}
+	return size
+}
+
+/*
+Copies the data into the buffers in order, returning the number
+of bytes copied
+*/
+func Scatter(buffers [][]byte, data []byte) int {
+	copied := 0
+	for _, buffer := range buffers {
+		if copied == len(data) {
+			break
+		}
+		copied += copy(buffer, data[copied:])
+	}
+	return copied
+}
+
+/*
+Returns the buffers left to fill after the first n bytes,
+dropping the filled ones
+*/
+func Advance(buffers [][]byte, n int) [][]byte {
+	for len(buffers) > 0 && n >= len(buffers[0]) {
+		n -= len(buffers[0])
+		buffers = buffers[1:]
+	}
+	if len(buffers) > 0 && n > 0 {
+		buffers = append([][]byte{buffers[0][n:]}, buffers[1:]...)
+	}
+	return buffers
+}
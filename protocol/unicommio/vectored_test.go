@@ -0,0 +1,27 @@
+package unicommio_test
+
+import (
+	"testing"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+func TestScatterAdvance(t *testing.T) {
+	first, second := make([]byte, 2), make([]byte, 3)
+	buffers := [][]byte{{}, first, second}
+	if n := unicommio.Scatter(buffers, []byte("abc")); n != 3 || string(first) != "ab" || second[0] != 'c' {
+		t.Fatalf("scattered %d bytes: %q %q", n, first, second)
+	}
+
+	rest := unicommio.Advance(buffers, 3)
+	if len(rest) != 1 || len(rest[0]) != 2 || unicommio.VectorSize(rest) != 2 {
+		t.Fatalf("got %d buffers left", len(rest))
+	}
+	rest[0][0] = 'd'
+	if second[1] != 'd' {
+		t.Fatal("advanced buffer does not share the memory")
+	}
+	if rest := unicommio.Advance(buffers, 5); len(rest) != 0 {
+		t.Fatalf("got %d buffers left", len(rest))
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type ControlLine uint8
+
+/*
+A single step of a control line sequence. The line is set to
+the given level and then the step waits for the given time.
+Steps without a line only wait
+*/
+type ControlStep struct {
+	Line  ControlLine
+	Level bool
+	Wait  time.Duration
+}
+
+const (
+	NoLine ControlLine = iota
+	DTR
+	RTS
+)
+
+/*
+Returns the name of the control line
+*/
+func (cl ControlLine) String() string {
+	switch cl {
+	case DTR:
+		return "DTR"
+	case RTS:
+		return "RTS"
+	}
+	return "none"
+}
+
+/*
+Runs a control line sequence on the port, e.g. to reset
+boards into a known state before communicating
+*/
+func runControlSequence(port Port, steps []ControlStep) error {
+	for index, step := range steps {
+		var err error
+		switch step.Line {
+		case DTR:
+			err = port.SetDTR(step.Level)
+		case RTS:
+			err = port.SetRTS(step.Level)
+		}
+		if err != nil {
+			return fmt.Errorf("control sequence step %d (%s) failed: %w", index, step.Line, err)
+		}
+		time.Sleep(step.Wait)
+	}
+	return nil
+}
+
+type LineLevel uint8
+
+const (
+	KeepLine     LineLevel = 0 // Left as set by the driver when opening
+	AssertLine   LineLevel = 1
+	DeassertLine LineLevel = 2
+)
+
+/*
+Levels of the control lines set on every open, including the
+reconnections. Some USB CDC-ACM devices do not transmit until DTR
+is asserted, while boards wired for auto-reset need it deasserted
+*/
+type LineOptions struct {
+	DTR    LineLevel
+	RTS    LineLevel
+	Settle time.Duration // Wait after setting the lines, before the connect sequence
+}
+
+/*
+Replaces the line options for the USB devices matching its IDs
+*/
+type LineOverride struct {
+	VID          string // USB vendor ID in hex, empty matches any
+	PID          string // USB product ID in hex, empty matches any
+	SerialNumber string // Empty matches any
+	Lines        LineOptions
+}
+
+/*
+Returns true if no line is changed on open
+*/
+func (lo LineOptions) IsZero() bool {
+	return lo.DTR == KeepLine && lo.RTS == KeepLine && lo.Settle == 0
+}
+
+/*
+Returns true if the override applies to the port
+*/
+func (lo LineOverride) matches(port *PortDetails) bool {
+	if !port.IsUSB {
+		return false
+	}
+	if lo.VID != "" && !strings.EqualFold(lo.VID, port.VID) {
+		return false
+	}
+	if lo.PID != "" && !strings.EqualFold(lo.PID, port.PID) {
+		return false
+	}
+	return lo.SerialNumber == "" || lo.SerialNumber == port.SerialNumber
+}
+
+/*
+Returns the line options of the port, from the first override
+matching its USB details or the default ones
+*/
+func (so SerialOptions) LinesFor(port *PortDetails) LineOptions {
+	for _, override := range so.LineOverrides {
+		if override.matches(port) {
+			return override.Lines
+		}
+	}
+	return so.Lines
+}
+
+/*
+Returns the line options of the port name, looking up its USB
+details only when there are overrides
+*/
+func lineOptionsFor(portName string, options SerialOptions) LineOptions {
+	if len(options.LineOverrides) == 0 {
+		return options.Lines
+	}
+	ports, err := detailedPorts()
+	if err != nil {
+		return options.Lines
+	}
+	target := CanonicalPortName(portName)
+	for _, port := range ports {
+		if CanonicalPortName(port.Name) == target {
+			return options.LinesFor(port)
+		}
+	}
+	return options.Lines
+}
+
+/*
+Sets the control lines of a freshly opened port
+*/
+func applyLines(port Port, options LineOptions) error {
+	if options.IsZero() {
+		return nil
+	}
+	steps := make([]ControlStep, 0, 2)
+	for _, line := range []struct {
+		line  ControlLine
+		level LineLevel
+	}{{DTR, options.DTR}, {RTS, options.RTS}} {
+		if line.level != KeepLine {
+			steps = append(steps, ControlStep{Line: line.line, Level: line.level == AssertLine})
+		}
+	}
+	if err := runControlSequence(port, steps); err != nil {
+		return err
+	}
+	time.Sleep(options.Settle)
+	return nil
+}
@@ -0,0 +1,71 @@
+package unicommserial_test
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+)
+
+func TestConnectSequence(t *testing.T) {
+	port := &linesPort{}
+	comm := unicommserial.NewSerial(unicommserial.SerialOptions{
+		PortName: "fake",
+		Lines:    unicommserial.LineOptions{DTR: unicommserial.AssertLine},
+		ConnectSequence: []unicommserial.ControlStep{
+			{Line: unicommserial.DTR, Level: false, Wait: 50 * time.Millisecond},
+			{Line: unicommserial.RTS, Level: true, Wait: 30 * time.Millisecond},
+			{Line: unicommserial.DTR, Level: true},
+			{Wait: 40 * time.Millisecond},
+		},
+		Open: func(portName string) (unicommserial.Port, error) { return port, nil },
+	})
+	start := time.Now()
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+	elapsed := time.Since(start)
+
+	// The lines of the options are set first, then the steps in order
+	if got := strings.Join(port.levels, " "); got != "DTR=true DTR=false RTS=true DTR=true" {
+		t.Fatalf("levels set %q", got)
+	}
+	for index, wait := range []time.Duration{0, 50 * time.Millisecond, 30 * time.Millisecond} {
+		if gap := port.times[index+1].Sub(port.times[index]); gap < wait {
+			t.Fatalf("step %d set after %v, want at least %v", index+1, gap, wait)
+		}
+	}
+	// A step without a line only waits
+	if elapsed < 120*time.Millisecond {
+		t.Fatalf("connected after %v, before the sequence ended", elapsed)
+	}
+}
+
+func TestConnectSequenceFailure(t *testing.T) {
+	port := &linesPort{dtrErr: syscall.ENOTTY}
+	comm := unicommserial.NewSerial(unicommserial.SerialOptions{
+		PortName: "fake",
+		ConnectSequence: []unicommserial.ControlStep{
+			{Line: unicommserial.RTS, Level: true},
+			{Line: unicommserial.DTR, Level: false},
+			{Line: unicommserial.RTS, Level: false},
+		},
+		Open: func(portName string) (unicommserial.Port, error) { return port, nil },
+	})
+
+	// The sequence stops at the failed step, which is named
+	err := comm.Connect()
+	if err == nil || !strings.Contains(err.Error(), "step 1 (DTR)") || !errors.Is(err, syscall.ENOTTY) {
+		t.Fatalf("expected the failure of step 1, got %v", err)
+	}
+	if got := strings.Join(port.levels, " "); got != "RTS=true" {
+		t.Fatalf("levels set %q, want only the steps before the failure", got)
+	}
+	if comm.Connection != nil {
+		t.Fatal("port kept open after the sequence failed")
+	}
+}
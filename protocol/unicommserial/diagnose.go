@@ -0,0 +1,132 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.bug.st/serial"
+)
+
+type FailureCause uint8
+
+const (
+	CauseUnknown    FailureCause = iota
+	CauseNotFound                // The port does not exist, other ports do
+	CauseNoDriver                // No serial port exists at all
+	CausePermission              // The user may not open the port
+	CauseBusy                    // Another process holds the port
+	CauseNotSerial               // The path is not a serial port
+)
+
+/*
+Enriched error of a failed connect, with the cause of the
+failure and a suggested fix for the operator
+*/
+type PortError struct {
+	Port  string
+	Cause FailureCause
+	Hint  string // Suggested fix, may be empty
+	Err   error  // Error returned by the driver
+}
+
+/*
+Returns the name of the cause
+*/
+func (fc FailureCause) String() string {
+	switch fc {
+	case CauseNotFound:
+		return "not found"
+	case CauseNoDriver:
+		return "no driver"
+	case CausePermission:
+		return "permission denied"
+	case CauseBusy:
+		return "busy"
+	case CauseNotSerial:
+		return "not a serial port"
+	default:
+		return "unknown"
+	}
+}
+
+/*
+Describes the failure and its suggested fix
+*/
+func (pe *PortError) Error() string {
+	message := fmt.Sprintf("%s: %v (%s)", pe.Port, pe.Err, pe.Cause)
+	if pe.Hint != "" {
+		message += ": " + pe.Hint
+	}
+	return message
+}
+
+/*
+Returns the error of the driver
+*/
+func (pe *PortError) Unwrap() error {
+	return pe.Err
+}
+
+/*
+Finds the cause of an error opening a port and suggests a fix.
+Errors already diagnosed and configuration errors are returned
+as they are
+*/
+func Diagnose(portName string, err error) error {
+	var portErr *PortError
+	if err == nil || errors.As(err, &portErr) {
+		return err
+	}
+
+	diagnosed := &PortError{Port: portName, Err: err}
+	var driverErr *serial.PortError
+	switch {
+	case errors.Is(err, ErrPortNotAvailable):
+		diagnosed.Cause, diagnosed.Hint = diagnoseMissing()
+	case errors.As(err, &driverErr) && driverErr.Code() == serial.PermissionDenied:
+		diagnosed.Cause = CausePermission
+		diagnosed.Hint = permissionHint(portName)
+	case errors.As(err, &driverErr) && driverErr.Code() == serial.PortBusy,
+		strings.Contains(err.Error(), "locked by another process"):
+		diagnosed.Cause = CauseBusy
+		diagnosed.Hint = busyHint(portName)
+	case errors.As(err, &driverErr) && driverErr.Code() == serial.PortNotFound:
+		diagnosed.Cause, diagnosed.Hint = diagnoseMissing()
+	case errors.As(err, &driverErr) && driverErr.Code() == serial.InvalidSerialPort:
+		diagnosed.Cause = CauseNotSerial
+		diagnosed.Hint = "check that the path is the serial adapter and not another device"
+	default:
+		return err
+	}
+	return diagnosed
+}
+
+/*
+Tells a missing port from missing drivers by listing the
+ports the system knows
+*/
+func diagnoseMissing() (FailureCause, string) {
+	ports, err := serial.GetPortsList()
+	if err != nil || len(ports) == 0 {
+		return CauseNoDriver, "no serial port was detected, check the cable and that the driver " +
+			"of the adapter (FTDI, CP210x, CH340...) is installed"
+	}
+	return CauseNotFound, "available ports: " + strings.Join(ports, ", ")
+}
+
+/*
+Suggests how to release a port held by another process
+*/
+func busyHint(portName string) string {
+	if holder := portHolder(portName); holder != "" {
+		return "the port is held by " + holder + ", close it or stop the service"
+	}
+	return "close the other program using the port, such as a terminal or ModemManager"
+}
@@ -0,0 +1,73 @@
+//go:build linux
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+/*
+Suggests joining the group owning the device, usually dialout
+*/
+func permissionHint(portName string) string {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(portName, &stat); err != nil {
+		return "run with permission to open the port"
+	}
+	gid := strconv.FormatUint(uint64(stat.Gid), 10)
+	group, err := user.LookupGroupId(gid)
+	if err != nil {
+		return "run with permission to open the port"
+	}
+
+	current, err := user.Current()
+	if err == nil {
+		groups, _ := current.GroupIds()
+		if slices.Contains(groups, gid) {
+			return fmt.Sprintf("the user is in the %q group but the session is not, log in again", group.Name)
+		}
+	}
+	return fmt.Sprintf("add the user to the %q group (sudo usermod -aG %s $USER) and log in again",
+		group.Name, group.Name)
+}
+
+/*
+Returns the command and PID of a process with the port open,
+found by scanning the file descriptors in /proc
+*/
+func portHolder(portName string) string {
+	target, err := filepath.EvalSymlinks(portName)
+	if err != nil {
+		return ""
+	}
+	processes, _ := filepath.Glob("/proc/[0-9]*")
+	for _, process := range processes {
+		pid := filepath.Base(process)
+		if pid == strconv.Itoa(os.Getpid()) {
+			continue
+		}
+		descriptors, _ := os.ReadDir(filepath.Join(process, "fd"))
+		for _, descriptor := range descriptors {
+			link, err := os.Readlink(filepath.Join(process, "fd", descriptor.Name()))
+			if err != nil || link != target {
+				continue
+			}
+			command, _ := os.ReadFile(filepath.Join(process, "comm"))
+			return fmt.Sprintf("%s (PID %s)", strings.TrimSpace(string(command)), pid)
+		}
+	}
+	return ""
+}
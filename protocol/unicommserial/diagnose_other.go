@@ -0,0 +1,23 @@
+//go:build !linux
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+/*
+Suggests running with permission to open the port
+*/
+func permissionHint(portName string) string {
+	return "run with permission to open the port"
+}
+
+/*
+Finding the process holding a port is only supported on Linux
+*/
+func portHolder(portName string) string {
+	return ""
+}
@@ -0,0 +1,34 @@
+package unicommserial_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+)
+
+func TestDiagnose(t *testing.T) {
+	comm := unicommserial.NewSerial(unicommserial.SerialOptions{PortName: "/dev/ttyMISSING0"})
+	err := comm.Connect()
+
+	var portErr *unicommserial.PortError
+	if !errors.As(err, &portErr) || !errors.Is(err, unicommserial.ErrPortNotAvailable) {
+		t.Fatalf("expected a diagnosed missing port, got %v", err)
+	}
+	if portErr.Cause != unicommserial.CauseNotFound && portErr.Cause != unicommserial.CauseNoDriver {
+		t.Fatalf("unexpected cause %s", portErr.Cause)
+	}
+	if portErr.Hint == "" {
+		t.Fatal("expected a suggested fix")
+	}
+
+	// Diagnosed errors are not wrapped twice, unrelated ones are kept
+	if again := unicommserial.Diagnose("/dev/ttyMISSING0", err); again != err {
+		t.Fatalf("expected the same error, got %v", again)
+	}
+	unrelated := fmt.Errorf("invalid options")
+	if got := unicommserial.Diagnose("/dev/ttyUSB0", unrelated); got != unrelated {
+		t.Fatalf("expected the error as is, got %v", got)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !darwin || cgo
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import "go.bug.st/serial/enumerator"
+
+/*
+Lists the ports with their USB details
+*/
+func detailedPorts() ([]*PortDetails, error) {
+	ports, err := enumerator.GetDetailedPortsList()
+	if err != nil {
+		return nil, err
+	}
+	details := make([]*PortDetails, 0, len(ports))
+	for _, port := range ports {
+		details = append(details, &PortDetails{
+			Name:         port.Name,
+			IsUSB:        port.IsUSB,
+			VID:          port.VID,
+			PID:          port.PID,
+			SerialNumber: port.SerialNumber,
+			Product:      port.Product,
+		})
+	}
+	return details, nil
+}
@@ -0,0 +1,27 @@
+//go:build darwin && !cgo
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import "go.bug.st/serial"
+
+/*
+USB details require cgo on macOS, so only the port names are
+listed and candidates can be matched by pattern only
+*/
+func detailedPorts() ([]*PortDetails, error) {
+	names, err := serial.GetPortsList()
+	if err != nil {
+		return nil, err
+	}
+	details := make([]*PortDetails, 0, len(names))
+	for _, name := range names {
+		details = append(details, &PortDetails{Name: name})
+	}
+	return details, nil
+}
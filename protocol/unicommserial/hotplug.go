@@ -0,0 +1,151 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type HotplugOptions struct {
+	Interval    time.Duration // Polling interval of the ports list
+	AutoConnect bool          // Connect on arrival and disconnect on removal
+
+	// Lists the port names instead of the driver, e.g. for test
+	// doubles. Device paths missing from the list are then absent
+	List func() ([]string, error)
+}
+
+type HotplugEvent struct {
+	PortName string
+	Present  bool      // True when the device appeared
+	Time     time.Time // When the change was detected
+	Err      error     // Error of the automatic connect, if any
+}
+
+type HotplugWatcher struct {
+	Options HotplugOptions
+
+	serial *UnicommSerial
+	events chan HotplugEvent
+	stop   chan struct{}
+	done   chan struct{}
+	mutex  sync.Mutex // Protect running state
+}
+
+/*
+Creates a watcher that detects when the configured port
+appears or disappears
+*/
+func NewHotplugWatcher(serial *UnicommSerial, options HotplugOptions) *HotplugWatcher {
+	if options.Interval == 0 {
+		options.Interval = time.Second
+	}
+	return &HotplugWatcher{
+		Options: options,
+		serial:  serial,
+		events:  make(chan HotplugEvent, 16),
+	}
+}
+
+/*
+Returns the channel where hotplug events are delivered. Events
+are dropped if the channel is full
+*/
+func (hw *HotplugWatcher) Events() <-chan HotplugEvent {
+	return hw.events
+}
+
+/*
+Starts polling the port
+*/
+func (hw *HotplugWatcher) Start() error {
+	hw.mutex.Lock()
+	defer hw.mutex.Unlock()
+
+	if hw.stop != nil {
+		return fmt.Errorf("hotplug watcher is already running")
+	}
+	// Taken before returning, so every change after Start is reported
+	present, _ := hw.isPresent(hw.serial.Options.PortName)
+	hw.stop = make(chan struct{})
+	hw.done = make(chan struct{})
+	go hw.run(present, hw.stop, hw.done)
+	return nil
+}
+
+/*
+Stops polling the port
+*/
+func (hw *HotplugWatcher) Stop() error {
+	hw.mutex.Lock()
+	defer hw.mutex.Unlock()
+
+	if hw.stop == nil {
+		return fmt.Errorf("hotplug watcher is not running")
+	}
+	close(hw.stop)
+	<-hw.done
+	hw.stop = nil
+	return nil
+}
+
+/*
+Polls the port until stopped, emitting an event for each change
+of its presence
+*/
+func (hw *HotplugWatcher) run(present bool, stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	portName := hw.serial.Options.PortName
+	ticker := time.NewTicker(hw.Options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		current, err := hw.isPresent(portName)
+		if err != nil || current == present {
+			continue
+		}
+		present = current
+
+		event := HotplugEvent{PortName: portName, Present: present, Time: time.Now()}
+		if hw.Options.AutoConnect {
+			if present {
+				event.Err = hw.serial.Connect()
+			} else {
+				hw.serial.closeStale()
+			}
+		}
+
+		select {
+		case hw.events <- event:
+		default:
+		}
+	}
+}
+
+/*
+Returns true if the port is present, according to the lister
+of the options when there is one
+*/
+func (hw *HotplugWatcher) isPresent(portName string) (bool, error) {
+	if hw.Options.List == nil {
+		return isPortPresent(portName)
+	}
+	names, err := hw.Options.List()
+	if err != nil {
+		return false, err
+	}
+	return isListed(names, portName), nil
+}
@@ -0,0 +1,90 @@
+package unicommserial_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+)
+
+/*
+Ports list changed by the test while the watcher polls it
+*/
+type fakePorts struct {
+	mutex sync.Mutex
+	names []string
+}
+
+func (fp *fakePorts) set(names ...string) {
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+
+	fp.names = names
+}
+
+func (fp *fakePorts) list() ([]string, error) {
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+
+	return slices.Clone(fp.names), nil
+}
+
+/*
+Waits for the next hotplug event
+*/
+func nextHotplug(t *testing.T, watcher *unicommserial.HotplugWatcher) unicommserial.HotplugEvent {
+	t.Helper()
+
+	select {
+	case event := <-watcher.Events():
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("no hotplug event")
+	}
+	return unicommserial.HotplugEvent{}
+}
+
+func TestHotplugWatcher(t *testing.T) {
+	ports := &fakePorts{}
+	ports.set("/dev/ttyS0")
+	comm := unicommserial.NewSerial(unicommserial.SerialOptions{
+		PortName: "/dev/ttyUSB0",
+		Open:     func(portName string) (unicommserial.Port, error) { return &linesPort{}, nil },
+	})
+	watcher := unicommserial.NewHotplugWatcher(comm, unicommserial.HotplugOptions{
+		Interval:    10 * time.Millisecond,
+		AutoConnect: true,
+		List:        ports.list,
+	})
+	if err := watcher.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Stop()
+
+	// Other ports coming and going are ignored
+	ports.set("/dev/ttyS0", "/dev/ttyS1")
+	ports.set("/dev/ttyS0", "/dev/ttyS1", "/dev/ttyUSB0")
+	event := nextHotplug(t, watcher)
+	if event.PortName != "/dev/ttyUSB0" || !event.Present || event.Err != nil {
+		t.Fatalf("unexpected event %+v, want the port added", event)
+	}
+	if comm.Connection == nil {
+		t.Fatal("port not connected on arrival")
+	}
+
+	ports.set("/dev/ttyS0")
+	event = nextHotplug(t, watcher)
+	if event.PortName != "/dev/ttyUSB0" || event.Present {
+		t.Fatalf("unexpected event %+v, want the port removed", event)
+	}
+	if comm.Connection != nil {
+		t.Fatal("port still connected after removal")
+	}
+	select {
+	case event := <-watcher.Events():
+		t.Fatalf("unexpected event %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+/*
+Adapts a stream to the Port interface. Control lines and break
+signals are not supported, and the read timeout is enforced only
+when the stream has read deadlines, e.g. a net.Conn
+*/
+type streamPort struct {
+	io.ReadWriteCloser
+	timeout time.Duration
+	closed  atomic.Bool
+}
+
+/*
+Creates a Unicomm Serial instance over a port opened by the
+caller, which is already connected. The read timeout of the
+options is applied to the port
+*/
+func NewSerialFromPort(port Port, options SerialOptions) *UnicommSerial {
+	us := NewSerial(options)
+	port.SetReadTimeout(us.Options.ReadTimeout)
+	us.Connection = port
+	return us
+}
+
+/*
+Creates a Unicomm Serial instance over any stream, such as a
+proxied connection or a test pipe, reusing the framing and
+timeout logic of the serial backend
+*/
+func NewSerialFromStream(stream io.ReadWriteCloser, options SerialOptions) *UnicommSerial {
+	return NewSerialFromPort(&streamPort{ReadWriteCloser: stream}, options)
+}
+
+/*
+Reads from the stream, returning no data on timeout as the
+serial ports do
+*/
+func (sp *streamPort) Read(p []byte) (int, error) {
+	if conn, ok := sp.ReadWriteCloser.(interface{ SetReadDeadline(time.Time) error }); ok {
+		if sp.timeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(sp.timeout))
+		} else {
+			conn.SetReadDeadline(time.Time{})
+		}
+	}
+	n, err := sp.ReadWriteCloser.Read(p)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return n, nil
+	}
+	return n, err
+}
+
+/*
+Writes to the stream. Empty writes, used to check the connection,
+are answered locally since synchronous streams would block on them
+*/
+func (sp *streamPort) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		if sp.closed.Load() {
+			return 0, os.ErrClosed
+		}
+		return 0, nil
+	}
+	return sp.ReadWriteCloser.Write(p)
+}
+
+/*
+Closes the stream
+*/
+func (sp *streamPort) Close() error {
+	sp.closed.Store(true)
+	return sp.ReadWriteCloser.Close()
+}
+
+/*
+Sets the timeout applied to each read
+*/
+func (sp *streamPort) SetReadTimeout(timeout time.Duration) error {
+	sp.timeout = max(timeout, 0)
+	return nil
+}
+
+/*
+Streams have no line settings, so the mode is ignored
+*/
+func (sp *streamPort) SetMode(mode *serial.Mode) error {
+	return nil
+}
+
+/*
+Streams write synchronously, so there is nothing to drain
+*/
+func (sp *streamPort) Drain() error {
+	return nil
+}
+
+/*
+Streams have no driver buffers to reset
+*/
+func (sp *streamPort) ResetInputBuffer() error {
+	return nil
+}
+
+/*
+Streams have no driver buffers to reset
+*/
+func (sp *streamPort) ResetOutputBuffer() error {
+	return nil
+}
+
+/*
+Control lines are not available on streams
+*/
+func (sp *streamPort) SetDTR(level bool) error {
+	return fmt.Errorf("control lines are not supported by the stream")
+}
+
+/*
+Control lines are not available on streams
+*/
+func (sp *streamPort) SetRTS(level bool) error {
+	return fmt.Errorf("control lines are not supported by the stream")
+}
+
+/*
+Returned when reading the modem lines of a stream
+*/
+var errNoModemStatus = errors.New("modem status is not supported by the stream")
+
+/*
+Modem status is not available on streams
+*/
+func (sp *streamPort) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	return nil, errNoModemStatus
+}
+
+/*
+Break conditions are not available on streams
+*/
+func (sp *streamPort) Break(duration time.Duration) error {
+	return fmt.Errorf("break is not supported by the stream")
+}
@@ -0,0 +1,196 @@
+package unicommserial_test
+
+import (
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+)
+
+func TestSerialFromStream(t *testing.T) {
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	comm := unicommserial.NewSerialFromStream(local, unicommserial.SerialOptions{
+		PortName:    "pipe",
+		ReadTimeout: 50 * time.Millisecond,
+	})
+	defer comm.Disconnect()
+
+	go remote.Write([]byte("READY\r\n"))
+	frame, err := comm.ReadUntil("\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(frame) != "READY\r\n" {
+		t.Fatalf("got %q", frame)
+	}
+
+	// The deadline of the pipe enforces the read timeout
+	if _, err := comm.ReadUntil("\r\n"); err == nil {
+		t.Fatal("expected a timeout without data")
+	}
+}
+
+func TestSerialReadInto(t *testing.T) {
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	comm := unicommserial.NewSerialFromStream(local, unicommserial.SerialOptions{
+		PortName:    "pipe",
+		ReadTimeout: 50 * time.Millisecond,
+	})
+	defer comm.Disconnect()
+
+	// The bytes after the delimiter are kept pending by ReadUntil
+	go remote.Write([]byte("READY\r\nTEMP 21.5"))
+	if frame, err := comm.ReadUntil("\r\n"); err != nil || string(frame) != "READY\r\n" {
+		t.Fatalf("ReadUntil = %q, %v", frame, err)
+	}
+
+	// Pending bytes are read first, into the same buffer every time
+	buffer := make([]byte, 4)
+	for _, expected := range []string{"TEMP", " 21.", "5"} {
+		n, err := comm.ReadInto(buffer)
+		if err != nil || string(buffer[:n]) != expected {
+			t.Fatalf("ReadInto = %q, %v, want %q", buffer[:n], err, expected)
+		}
+	}
+
+	// Then the port is read, nothing before the timeout is not an error
+	n, err := comm.ReadInto(buffer)
+	if n != 0 || err != nil {
+		t.Fatalf("ReadInto = %d, %v, want 0 and no error on timeout", n, err)
+	}
+	go remote.Write([]byte("OK"))
+	if n, err := comm.ReadInto(buffer); err != nil || string(buffer[:n]) != "OK" {
+		t.Fatalf("ReadInto = %q, %v", buffer[:n], err)
+	}
+}
+
+func TestSerialMaskHighBit(t *testing.T) {
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	comm := unicommserial.NewSerialFromStream(local, unicommserial.SerialOptions{
+		PortName:    "pipe",
+		ReadTimeout: 50 * time.Millisecond,
+		MaskHighBit: true,
+	})
+	defer comm.Disconnect()
+
+	// The parity bit of a 7E1 line read as 8N1 arrives as the high bit
+	go remote.Write([]byte{'S' | 0x80, ' ', '1' | 0x80, '2', '.', '5', 'g', '\r', '\n' | 0x80})
+	frame, err := comm.ReadUntil("\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(frame) != "S 12.5g\r\n" {
+		t.Fatalf("got %q", frame)
+	}
+}
+
+func TestSerialWriteDropsPendingBytes(t *testing.T) {
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	comm := unicommserial.NewSerialFromStream(local, unicommserial.SerialOptions{
+		PortName:    "pipe",
+		ReadTimeout: 200 * time.Millisecond,
+	})
+	defer comm.Disconnect()
+
+	// The stale line after the first reply is kept by ReadUntil
+	go remote.Write([]byte("OK\r\nSTALE\r\n"))
+	if frame, err := comm.ReadUntil("\r\n"); err != nil || string(frame) != "OK\r\n" {
+		t.Fatalf("got %q, %v", frame, err)
+	}
+
+	go func() {
+		command := make([]byte, 5)
+		remote.Read(command)
+		remote.Write([]byte("FRESH\r\n"))
+	}()
+	if err := comm.WriteRaw([]byte("QRY\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	frame, err := comm.ReadUntil("\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(frame) != "FRESH\r\n" {
+		t.Fatalf("got %q, want the reply to the new command", frame)
+	}
+}
+
+func TestSerialWriteTimeoutHoldsPort(t *testing.T) {
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	comm := unicommserial.NewSerialFromStream(local, unicommserial.SerialOptions{
+		PortName:     "pipe",
+		WriteTimeout: 100 * time.Millisecond,
+	})
+	defer comm.Disconnect()
+
+	// The device drains one chunk at a time, much slower than the timeout
+	var received atomic.Int64
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		chunk := make([]byte, 16)
+		for {
+			remote.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+			n, err := remote.Read(chunk)
+			received.Add(int64(n))
+			if err != nil {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+
+	err := comm.WriteRaw(make([]byte, 1000))
+	var partial *unicommio.PartialWriteError
+	if !errors.As(err, &partial) || !unicommio.IsTimeout(err) {
+		t.Fatalf("expected a partial write timeout, got %v", err)
+	}
+	<-done
+	if int64(partial.Written) != received.Load() {
+		t.Fatalf("reported %d bytes written, the device received %d", partial.Written, received.Load())
+	}
+}
+
+func TestSerialReconfigureWhileReading(t *testing.T) {
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	comm := unicommserial.NewSerialFromStream(local, unicommserial.SerialOptions{
+		PortName:    "pipe",
+		ReadTimeout: 20 * time.Millisecond,
+	})
+	defer comm.Disconnect()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for timeout := time.Millisecond; ; timeout++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			comm.Reconfigure(unicommserial.SerialOptions{ReadTimeout: 10*time.Millisecond + timeout%10})
+		}
+	}()
+
+	// The race detector reports options read outside the lock
+	for range 5 {
+		comm.ReadUntil("\r\n")
+		comm.ReadAtLeast(1, 4)
+	}
+}
@@ -0,0 +1,186 @@
+package unicommserial_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+	"github.com/devicehub-go/unicomm/unicommtest"
+	"go.bug.st/serial"
+)
+
+/*
+Port recording the levels set on its control lines, whose modem
+status fails once the device is unplugged
+*/
+type linesPort struct {
+	mutex     sync.Mutex
+	levels    []string
+	times     []time.Time // When each level was set
+	breaks    []time.Duration
+	dtrErr    error
+	unplugged bool
+}
+
+func (lp *linesPort) Read(p []byte) (int, error)                 { return 0, nil }
+func (lp *linesPort) Write(p []byte) (int, error)                { return len(p), nil }
+func (lp *linesPort) Close() error                               { return nil }
+func (lp *linesPort) SetMode(mode *serial.Mode) error            { return nil }
+func (lp *linesPort) SetReadTimeout(timeout time.Duration) error { return nil }
+func (lp *linesPort) Drain() error                               { return nil }
+func (lp *linesPort) ResetInputBuffer() error                    { return nil }
+func (lp *linesPort) ResetOutputBuffer() error                   { return nil }
+
+func (lp *linesPort) Break(duration time.Duration) error {
+	lp.mutex.Lock()
+	defer lp.mutex.Unlock()
+
+	lp.breaks = append(lp.breaks, duration)
+	return nil
+}
+
+func (lp *linesPort) SetDTR(level bool) error {
+	lp.mutex.Lock()
+	defer lp.mutex.Unlock()
+
+	if lp.dtrErr != nil {
+		return lp.dtrErr
+	}
+	lp.levels = append(lp.levels, fmt.Sprintf("DTR=%t", level))
+	lp.times = append(lp.times, time.Now())
+	return nil
+}
+
+func (lp *linesPort) SetRTS(level bool) error {
+	lp.mutex.Lock()
+	defer lp.mutex.Unlock()
+
+	lp.levels = append(lp.levels, fmt.Sprintf("RTS=%t", level))
+	lp.times = append(lp.times, time.Now())
+	return nil
+}
+
+func (lp *linesPort) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	lp.mutex.Lock()
+	defer lp.mutex.Unlock()
+
+	if lp.unplugged {
+		return nil, syscall.EIO
+	}
+	return &serial.ModemStatusBits{}, nil
+}
+
+func TestLinesOnOpen(t *testing.T) {
+	pty := unicommtest.NewPTY(t)
+	options := unicommserial.SerialOptions{
+		PortName: pty.PortName,
+		Lines:    unicommserial.LineOptions{Settle: 50 * time.Millisecond},
+	}
+
+	// Only the settle time, no line is touched
+	comm := unicommserial.NewSerial(options)
+	start := time.Now()
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("connected after %v, before the settle time", elapsed)
+	}
+	comm.Disconnect()
+
+	options.Lines.RTS = 7
+	if err := options.Validate(); err == nil {
+		t.Fatal("expected an unknown line level to be rejected")
+	}
+}
+
+func TestLinesFailure(t *testing.T) {
+	port := &linesPort{dtrErr: syscall.ENOTTY}
+	comm := unicommserial.NewSerial(unicommserial.SerialOptions{
+		PortName: "fake",
+		Lines:    unicommserial.LineOptions{DTR: unicommserial.AssertLine},
+		Open:     func(portName string) (unicommserial.Port, error) { return port, nil },
+	})
+
+	// A failure names the line and leaves the port closed
+	err := comm.Connect()
+	if err == nil || !strings.Contains(err.Error(), "(DTR)") || !errors.Is(err, syscall.ENOTTY) {
+		t.Fatalf("expected the DTR failure, got %v", err)
+	}
+	if comm.Connection != nil {
+		t.Fatal("port kept open after the lines failed")
+	}
+}
+
+func TestLinesOnReconnect(t *testing.T) {
+	var ports []*linesPort
+	comm := unicommserial.NewSerial(unicommserial.SerialOptions{
+		PortName: "fake",
+		Lines: unicommserial.LineOptions{
+			DTR: unicommserial.AssertLine,
+			RTS: unicommserial.DeassertLine,
+		},
+		Open: func(portName string) (unicommserial.Port, error) {
+			ports = append(ports, &linesPort{})
+			return ports[len(ports)-1], nil
+		},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	// The device is unplugged and plugged back, so the port opened
+	// again gets the same levels
+	ports[0].mutex.Lock()
+	ports[0].unplugged = true
+	ports[0].mutex.Unlock()
+	if comm.IsConnected() {
+		t.Fatal("expected the unplugged port to be disconnected")
+	}
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ports) != 2 {
+		t.Fatalf("opened %d ports, want a reconnection", len(ports))
+	}
+	for index, port := range ports {
+		if got := strings.Join(port.levels, " "); got != "DTR=true RTS=false" {
+			t.Fatalf("open %d set %q", index, got)
+		}
+	}
+}
+
+func TestLinesFor(t *testing.T) {
+	options := unicommserial.SerialOptions{
+		Lines: unicommserial.LineOptions{DTR: unicommserial.AssertLine},
+		LineOverrides: []unicommserial.LineOverride{
+			{VID: "2341", PID: "0043", SerialNumber: "A1", Lines: unicommserial.LineOptions{RTS: unicommserial.AssertLine}},
+			{VID: "2341", Lines: unicommserial.LineOptions{DTR: unicommserial.DeassertLine}},
+			{PID: "EA60", Lines: unicommserial.LineOptions{Settle: time.Second}},
+		},
+	}
+	tests := []struct {
+		name string
+		port unicommserial.PortDetails
+		want unicommserial.LineOptions
+	}{
+		{"first match wins", unicommserial.PortDetails{IsUSB: true, VID: "2341", PID: "0043", SerialNumber: "A1"}, options.LineOverrides[0].Lines},
+		{"serial number differs", unicommserial.PortDetails{IsUSB: true, VID: "2341", PID: "0043", SerialNumber: "B2"}, options.LineOverrides[1].Lines},
+		{"IDs ignore case", unicommserial.PortDetails{IsUSB: true, VID: "10c4", PID: "ea60"}, options.LineOverrides[2].Lines},
+		{"no match", unicommserial.PortDetails{IsUSB: true, VID: "0403", PID: "6001"}, options.Lines},
+		{"not USB", unicommserial.PortDetails{VID: "2341", PID: "0043"}, options.Lines},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := options.LinesFor(&test.port); got != test.want {
+				t.Fatalf("LinesFor = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd || dragonfly
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+/*
+Takes an advisory exclusive lock on the device, which is
+honored by other processes using flock on the same port
+*/
+func lockPort(portName string) (*os.File, error) {
+	file, err := os.OpenFile(portName, os.O_RDONLY|syscall.O_NONBLOCK|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("port is locked by another process")
+	}
+	return file, nil
+}
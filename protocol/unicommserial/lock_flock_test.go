@@ -0,0 +1,46 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd || dragonfly
+
+package unicommserial_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+)
+
+func TestExclusiveLock(t *testing.T) {
+	// Any file can be locked, the port itself is opened by the fake
+	portName := filepath.Join(t.TempDir(), "ttyUSB0")
+	if err := os.WriteFile(portName, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	options := unicommserial.SerialOptions{
+		PortName:  portName,
+		Exclusive: true,
+		Open:      func(portName string) (unicommserial.Port, error) { return &linesPort{}, nil },
+	}
+	first, second := unicommserial.NewSerial(options), unicommserial.NewSerial(options)
+
+	if err := first.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	err := second.Connect()
+	if err == nil || !strings.Contains(err.Error(), "locked by another process") {
+		t.Fatalf("expected the second lock to fail, got %v", err)
+	}
+	if second.Connection != nil {
+		t.Fatal("port opened without the lock")
+	}
+
+	// Disconnecting releases the lock
+	if err := first.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Connect(); err != nil {
+		t.Fatalf("expected the lock to be released, got %v", err)
+	}
+	second.Disconnect()
+}
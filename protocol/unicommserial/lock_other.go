@@ -0,0 +1,19 @@
+//go:build !(linux || darwin || freebsd || openbsd || netbsd || dragonfly)
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import "os"
+
+/*
+Ports are always opened exclusively by the operating system,
+so there is no additional lock to take
+*/
+func lockPort(portName string) (*os.File, error) {
+	return nil, nil
+}
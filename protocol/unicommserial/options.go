@@ -0,0 +1,116 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultBaudRate     = 9600
+	DefaultDataBits     = 8
+	DefaultReadTimeout  = 100 * time.Millisecond
+	DefaultWriteTimeout = 100 * time.Millisecond
+	DefaultPollInterval = 10 * time.Millisecond
+)
+
+/*
+Returns a copy of the options with the zero values replaced
+by the defaults: 9600 baud, 8 data bits, no parity, one stop
+bit, 100ms read and write timeouts and blocking reads (10ms
+maximum sleep when the adaptive strategy is chosen)
+*/
+func (so SerialOptions) Defaults() SerialOptions {
+	if so.BaudRate == 0 {
+		so.BaudRate = DefaultBaudRate
+	}
+	if so.DataBits == 0 {
+		so.DataBits = DefaultDataBits
+	}
+	if so.ReadTimeout == 0 {
+		so.ReadTimeout = DefaultReadTimeout
+	}
+	if so.WriteTimeout == 0 {
+		so.WriteTimeout = DefaultWriteTimeout
+	}
+	if so.PollInterval == 0 {
+		so.PollInterval = DefaultPollInterval
+	}
+	return so
+}
+
+/*
+Returns an error describing the first invalid option
+*/
+func (so SerialOptions) Validate() error {
+	if so.PortName == "" && !so.Rescan.Enabled {
+		return fmt.Errorf("invalid options: port name is empty")
+	}
+	if so.BaudRate <= 0 {
+		return fmt.Errorf("invalid options: baud rate must be positive, got %d", so.BaudRate)
+	}
+	if so.DataBits < 5 || so.DataBits > 8 {
+		return fmt.Errorf("invalid options: data bits must be between 5 and 8, got %d", so.DataBits)
+	}
+	if so.Parity < NoParity || so.Parity > SpaceParity {
+		return fmt.Errorf("invalid options: unknown parity %d", so.Parity)
+	}
+	if so.StopBits < OneStopBit || so.StopBits > TwoStopBits {
+		return fmt.Errorf("invalid options: unknown stop bits %d", so.StopBits)
+	}
+	if so.StopBits == OnePointFiveStopBits && so.DataBits != 5 {
+		return fmt.Errorf("invalid options: 1.5 stop bits requires 5 data bits, got %d", so.DataBits)
+	}
+	if so.PollStrategy > AdaptivePoll {
+		return fmt.Errorf("invalid options: unknown poll strategy %d", so.PollStrategy)
+	}
+	if so.ReadTimeout < 0 || so.WriteTimeout < 0 || so.PollInterval < 0 {
+		return fmt.Errorf("invalid options: timeouts must not be negative")
+	}
+	if so.Lines.DTR > DeassertLine || so.Lines.RTS > DeassertLine {
+		return fmt.Errorf("invalid options: unknown line level")
+	}
+	for _, override := range so.LineOverrides {
+		if override.Lines.DTR > DeassertLine || override.Lines.RTS > DeassertLine {
+			return fmt.Errorf("invalid options: unknown line level in the override of %s:%s", override.VID, override.PID)
+		}
+	}
+	return so.Terminal.Validate()
+}
+
+/*
+Returns how long a character takes on the line, counting the
+start bit, the data bits, the parity bit and the stop bits
+*/
+func (so SerialOptions) CharacterTime() time.Duration {
+	so = so.Defaults()
+	bits := 1 + float64(so.DataBits)
+	if so.Parity != NoParity {
+		bits++
+	}
+	switch so.StopBits {
+	case OnePointFiveStopBits:
+		bits += 1.5
+	case TwoStopBits:
+		bits += 2
+	default:
+		bits++
+	}
+	return time.Duration(bits * float64(time.Second) / float64(so.BaudRate))
+}
+
+/*
+Returns the silence ending a Modbus RTU frame, which is 3.5
+character times, fixed at 1.75ms above 19200 baud
+*/
+func (so SerialOptions) ModbusSilence() time.Duration {
+	if so.Defaults().BaudRate > 19200 {
+		return 1750 * time.Microsecond
+	}
+	return so.CharacterTime() * 7 / 2
+}
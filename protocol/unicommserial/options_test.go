@@ -0,0 +1,90 @@
+package unicommserial_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+)
+
+func TestSerialOptionsDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		options unicommserial.SerialOptions
+		want    unicommserial.SerialOptions
+	}{
+		{
+			"zero values",
+			unicommserial.SerialOptions{PortName: "/dev/ttyUSB0"},
+			unicommserial.SerialOptions{
+				PortName:     "/dev/ttyUSB0",
+				BaudRate:     9600,
+				DataBits:     8,
+				ReadTimeout:  100 * time.Millisecond,
+				WriteTimeout: 100 * time.Millisecond,
+				PollInterval: 10 * time.Millisecond,
+			},
+		},
+		{
+			"values kept",
+			unicommserial.SerialOptions{BaudRate: 115200, DataBits: 7, ReadTimeout: time.Second, WriteTimeout: time.Second, PollInterval: time.Millisecond},
+			unicommserial.SerialOptions{BaudRate: 115200, DataBits: 7, ReadTimeout: time.Second, WriteTimeout: time.Second, PollInterval: time.Millisecond},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.options.Defaults()
+			if got.PortName != test.want.PortName || got.BaudRate != test.want.BaudRate || got.DataBits != test.want.DataBits ||
+				got.ReadTimeout != test.want.ReadTimeout || got.WriteTimeout != test.want.WriteTimeout || got.PollInterval != test.want.PollInterval {
+				t.Fatalf("Defaults = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSerialOptionsValidate(t *testing.T) {
+	valid := unicommserial.SerialOptions{PortName: "/dev/ttyUSB0"}.Defaults()
+	tests := []struct {
+		name   string
+		change func(options *unicommserial.SerialOptions)
+		err    string // Part of the error, empty when valid
+	}{
+		{"defaults", func(options *unicommserial.SerialOptions) {}, ""},
+		{"empty port name", func(options *unicommserial.SerialOptions) { options.PortName = "" }, "port name"},
+		{"rescan without port name", func(options *unicommserial.SerialOptions) {
+			options.PortName = ""
+			options.Rescan.Enabled = true
+		}, ""},
+		{"zero baud rate", func(options *unicommserial.SerialOptions) { options.BaudRate = 0 }, "baud rate"},
+		{"9 data bits", func(options *unicommserial.SerialOptions) { options.DataBits = 9 }, "data bits"},
+		{"unknown parity", func(options *unicommserial.SerialOptions) { options.Parity = 9 }, "parity"},
+		{"unknown stop bits", func(options *unicommserial.SerialOptions) { options.StopBits = 9 }, "stop bits"},
+		{"1.5 stop bits with 8 data bits", func(options *unicommserial.SerialOptions) {
+			options.StopBits = unicommserial.OnePointFiveStopBits
+		}, "1.5 stop bits"},
+		{"1.5 stop bits with 5 data bits", func(options *unicommserial.SerialOptions) {
+			options.StopBits = unicommserial.OnePointFiveStopBits
+			options.DataBits = 5
+		}, ""},
+		{"negative timeout", func(options *unicommserial.SerialOptions) { options.ReadTimeout = -time.Second }, "negative"},
+		{"unknown poll strategy", func(options *unicommserial.SerialOptions) { options.PollStrategy = 9 }, "poll strategy"},
+		{"unknown line level", func(options *unicommserial.SerialOptions) { options.Lines.DTR = 9 }, "line level"},
+		{"canonical mode with VMIN", func(options *unicommserial.SerialOptions) {
+			options.Terminal = unicommserial.TerminalOptions{Mode: unicommserial.CanonicalMode, MinBytes: 1}
+		}, "raw mode"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := valid
+			test.change(&options)
+			err := options.Validate()
+			if test.err == "" && err != nil {
+				t.Fatalf("Validate = %v, want valid options", err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Fatalf("Validate = %v, want an error about %s", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+/*
+Checks the link without transmitting a byte, by reading the
+status of the modem lines. The request fails once the device is
+unplugged, while an empty write may block or succeed on some USB
+drivers. Drivers without modem lines, such as pseudo terminals,
+and injected streams, are assumed to be connected
+*/
+func (us *UnicommSerial) ProbeLink() error {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if us.Connection == nil {
+		return fmt.Errorf("there is no port connected")
+	}
+
+	return probeLines(us.Connection)
+}
+
+/*
+Reads the modem lines of the port, ignoring the errors of
+drivers without them
+*/
+func probeLines(port Port) error {
+	_, err := port.GetModemStatusBits()
+	if err == nil || errors.Is(err, errNoModemStatus) || errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EINVAL) {
+		return nil
+	}
+	return fmt.Errorf("serial link lost: %w", err)
+}
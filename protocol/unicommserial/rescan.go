@@ -0,0 +1,113 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+/*
+Returned by Connect when the port is not present
+*/
+var ErrPortNotAvailable = errors.New("port is not available")
+
+type PortDetails struct {
+	Name         string
+	IsUSB        bool
+	VID          string // USB vendor ID in hex
+	PID          string // USB product ID in hex
+	SerialNumber string
+	Product      string
+}
+
+type RescanOptions struct {
+	Enabled bool
+	Pattern string // Glob matched against the port name, e.g. "/dev/ttyUSB*"
+	VID     string // USB vendor ID in hex, empty matches any
+	PID     string // USB product ID in hex, empty matches any
+
+	// Verifies that the connected port is the expected device, e.g.
+	// by querying its identification. Nil accepts the first port
+	Probe func(serial *UnicommSerial) error
+
+	// Lists the ports instead of the driver, e.g. for test doubles
+	Enumerate func() ([]*PortDetails, error)
+}
+
+/*
+Returns true if the port matches the pattern and USB IDs
+*/
+func (ro RescanOptions) matches(port *PortDetails) bool {
+	if ro.Pattern != "" {
+		if matched, _ := filepath.Match(ro.Pattern, port.Name); !matched {
+			return false
+		}
+	}
+	if ro.VID != "" && !strings.EqualFold(ro.VID, port.VID) {
+		return false
+	}
+	if ro.PID != "" && !strings.EqualFold(ro.PID, port.PID) {
+		return false
+	}
+	return true
+}
+
+/*
+Returns the ports that match the rescan options, in the order
+reported by the operating system
+*/
+func Candidates(options RescanOptions) ([]*PortDetails, error) {
+	enumerate := options.Enumerate
+	if enumerate == nil {
+		enumerate = detailedPorts
+	}
+	ports, err := enumerate()
+	if err != nil {
+		return nil, fmt.Errorf("was not possible to list the ports: %w", err)
+	}
+	candidates := make([]*PortDetails, 0, len(ports))
+	for _, port := range ports {
+		if options.matches(port) {
+			candidates = append(candidates, port)
+		}
+	}
+	return candidates, nil
+}
+
+/*
+Tries each candidate port until one connects and passes the
+probe. The port name is updated to the accepted candidate, so
+reconnections go straight to it
+*/
+func (us *UnicommSerial) rescan(cause error) error {
+	candidates, err := Candidates(us.Options.Rescan)
+	if err != nil {
+		return errors.Join(cause, err)
+	}
+
+	errs := []error{cause}
+	for _, candidate := range candidates {
+		if err := us.open(candidate.Name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", candidate.Name, err))
+			continue
+		}
+		if probe := us.Options.Rescan.Probe; probe != nil {
+			if err := probe(us); err != nil {
+				us.closeStale()
+				us.pending = nil
+				errs = append(errs, fmt.Errorf("%s: probe failed: %w", candidate.Name, err))
+				continue
+			}
+		}
+		us.Options.PortName = candidate.Name
+		return nil
+	}
+	return fmt.Errorf("no candidate port accepted: %w", errors.Join(errs...))
+}
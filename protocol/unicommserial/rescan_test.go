@@ -0,0 +1,89 @@
+package unicommserial_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+)
+
+/*
+Ports reported by the enumerator of the tests
+*/
+func enumeratePorts() ([]*unicommserial.PortDetails, error) {
+	return []*unicommserial.PortDetails{
+		{Name: "/dev/ttyS0"},
+		{Name: "/dev/ttyUSB1", IsUSB: true, VID: "0403", PID: "6001", SerialNumber: "A1"},
+		{Name: "/dev/ttyACM0", IsUSB: true, VID: "2341", PID: "0043"},
+		{Name: "/dev/ttyUSB2", IsUSB: true, VID: "0403", PID: "6015", SerialNumber: "B2"},
+	}, nil
+}
+
+func TestCandidates(t *testing.T) {
+	tests := []struct {
+		name    string
+		options unicommserial.RescanOptions
+		want    []string
+	}{
+		{"all ports", unicommserial.RescanOptions{}, []string{"/dev/ttyS0", "/dev/ttyUSB1", "/dev/ttyACM0", "/dev/ttyUSB2"}},
+		{"pattern", unicommserial.RescanOptions{Pattern: "/dev/ttyUSB*"}, []string{"/dev/ttyUSB1", "/dev/ttyUSB2"}},
+		{"vendor", unicommserial.RescanOptions{VID: "0403"}, []string{"/dev/ttyUSB1", "/dev/ttyUSB2"}},
+		{"vendor and product", unicommserial.RescanOptions{VID: "0403", PID: "6015"}, []string{"/dev/ttyUSB2"}},
+		{"IDs ignore case", unicommserial.RescanOptions{VID: "2341", PID: "0043"}, []string{"/dev/ttyACM0"}},
+		{"no match", unicommserial.RescanOptions{Pattern: "/dev/ttyACM*", VID: "0403"}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.options.Enumerate = enumeratePorts
+			candidates, err := unicommserial.Candidates(test.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, candidate := range candidates {
+				names = append(names, candidate.Name)
+			}
+			if !slices.Equal(names, test.want) {
+				t.Fatalf("candidates %q, want %q", names, test.want)
+			}
+		})
+	}
+}
+
+func TestRescan(t *testing.T) {
+	// The configured port is gone, the first candidate is another device
+	var opened []string
+	comm := unicommserial.NewSerial(unicommserial.SerialOptions{
+		PortName: "/dev/ttyUSB0",
+		Open: func(portName string) (unicommserial.Port, error) {
+			if portName == "/dev/ttyUSB0" {
+				return nil, unicommserial.ErrPortNotAvailable
+			}
+			opened = append(opened, portName)
+			return &linesPort{}, nil
+		},
+		Rescan: unicommserial.RescanOptions{
+			Enabled:   true,
+			VID:       "0403",
+			Enumerate: enumeratePorts,
+			Probe: func(serial *unicommserial.UnicommSerial) error {
+				if opened[len(opened)-1] != "/dev/ttyUSB2" {
+					return errors.New("unexpected identification")
+				}
+				return nil
+			},
+		},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	if !slices.Equal(opened, []string{"/dev/ttyUSB1", "/dev/ttyUSB2"}) {
+		t.Fatalf("opened %q, want the candidates in order", opened)
+	}
+	if comm.Options.PortName != "/dev/ttyUSB2" {
+		t.Fatalf("port name %q, want the accepted candidate", comm.Options.PortName)
+	}
+}
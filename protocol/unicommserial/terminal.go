@@ -0,0 +1,67 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import (
+	"fmt"
+	"time"
+)
+
+type TerminalMode uint8
+
+const (
+	// Bytes are delivered as they arrive, the default
+	RawMode TerminalMode = iota
+	// The kernel buffers each line and delivers it whole, so
+	// line-oriented devices are read with one call per line
+	CanonicalMode
+)
+
+/*
+Line discipline of the port on Unix. In raw mode, MinBytes and
+InterByteTimeout set VMIN and VTIME: reads wake up once MinBytes
+bytes arrived, or once the line stayed silent for the inter-byte
+timeout after the first byte. Zero values keep the driver
+defaults
+*/
+type TerminalOptions struct {
+	Mode             TerminalMode
+	LineEnd          byte          // Extra end of line in canonical mode (VEOL), e.g. '\r'
+	MinBytes         uint8         // VMIN in raw mode
+	InterByteTimeout time.Duration // VTIME in raw mode, in steps of 100ms up to 25.5s
+}
+
+/*
+Returns true if the options change the driver defaults
+*/
+func (to TerminalOptions) isSet() bool {
+	return to != TerminalOptions{}
+}
+
+/*
+Returns an error describing the first invalid option
+*/
+func (to TerminalOptions) Validate() error {
+	if to.Mode > CanonicalMode {
+		return fmt.Errorf("invalid options: unknown terminal mode %d", to.Mode)
+	}
+	if to.Mode == CanonicalMode && (to.MinBytes != 0 || to.InterByteTimeout != 0) {
+		return fmt.Errorf("invalid options: VMIN and VTIME only apply to raw mode")
+	}
+	if to.InterByteTimeout < 0 || to.InterByteTimeout > 25500*time.Millisecond {
+		return fmt.Errorf("invalid options: inter-byte timeout must be between 0 and 25.5s")
+	}
+	return nil
+}
+
+/*
+Returns the inter-byte timeout in tenths of a second, rounding
+up so a short timeout is not disabled
+*/
+func (to TerminalOptions) vtime() uint8 {
+	return uint8((to.InterByteTimeout + 99*time.Millisecond) / (100 * time.Millisecond))
+}
@@ -0,0 +1,21 @@
+//go:build !(linux || darwin || freebsd || openbsd || netbsd || dragonfly)
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import "fmt"
+
+/*
+Line disciplines only exist on Unix
+*/
+func applyTerminal(portName string, options TerminalOptions) error {
+	if !options.isSet() {
+		return nil
+	}
+	return fmt.Errorf("terminal modes are only supported on Unix")
+}
@@ -0,0 +1,41 @@
+package unicommserial_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestCanonicalMode(t *testing.T) {
+	pty := unicommtest.NewPTY(t)
+	comm := unicommserial.NewSerial(unicommserial.SerialOptions{
+		PortName:    pty.PortName,
+		BaudRate:    115200,
+		ReadTimeout: 100 * time.Millisecond,
+		Terminal:    unicommserial.TerminalOptions{Mode: unicommserial.CanonicalMode, LineEnd: '\r'},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	// The kernel holds the line until it ends
+	if _, err := pty.Device.Write([]byte("PART")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := comm.Read(64)
+	if err != nil || len(data) != 0 {
+		t.Fatalf("expected the partial line to be held, got %q, %v", data, err)
+	}
+	if _, err := pty.Device.Write([]byte("IAL\rNEXT\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"PARTIAL\r", "NEXT\n"} {
+		data, err := comm.Read(64)
+		if err != nil || string(data) != line {
+			t.Fatalf("got %q, %v, want %q", data, err, line)
+		}
+	}
+}
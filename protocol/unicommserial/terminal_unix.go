@@ -0,0 +1,64 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd || dragonfly
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommserial
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+/*
+Applies the line discipline to the port. Terminal settings
+belong to the device, so they are changed through a separate
+descriptor and apply to the one used by the driver
+*/
+func applyTerminal(portName string, options TerminalOptions) error {
+	if !options.isSet() {
+		return nil
+	}
+	file, err := os.OpenFile(portName, os.O_RDWR|syscall.O_NONBLOCK|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var termios syscall.Termios
+	if err := termiosIoctl(file, ioctlGetTermios, &termios); err != nil {
+		return err
+	}
+	switch options.Mode {
+	case CanonicalMode:
+		termios.Lflag |= syscall.ICANON
+		termios.Cc[syscall.VEOL] = options.LineEnd
+	default:
+		termios.Lflag &^= syscall.ICANON
+		termios.Cc[syscall.VMIN] = options.MinBytes
+		termios.Cc[syscall.VTIME] = options.vtime()
+	}
+	return termiosIoctl(file, ioctlSetTermios, &termios)
+}
+
+/*
+Gets or sets the terminal settings of the file
+*/
+func termiosIoctl(file *os.File, request uintptr, termios *syscall.Termios) error {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errno syscall.Errno
+	conn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(unsafe.Pointer(termios)))
+	})
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -1,24 +1,31 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 22nd, 2025
-Last update: September 23rd, 2025
+Last update: October 15th, 2026
 */
 
 package unicommserial
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
 	"go.bug.st/serial"
 )
 
 type Port = serial.Port
 type Parity = serial.Parity
 type StopBits = serial.StopBits
+type PollStrategy = unicommio.PollStrategy
 
 type SerialOptions struct {
 	PortName       string
@@ -31,13 +38,46 @@ type SerialOptions struct {
 	StartDelimiter string
 	EndDelimiter   string
 	RetryConnect   bool
+
+	// Control line steps executed right after the port is opened
+	ConnectSequence []ControlStep
+
+	// Levels of DTR and RTS set on every open before the connect
+	// sequence, replaced by the first override matching the device
+	Lines         LineOptions
+	LineOverrides []LineOverride
+
+	// Takes an advisory lock (flock) on the port while connected, on
+	// top of the TIOCEXCL flag always set by the driver on Unix
+	Exclusive bool
+
+	// How read loops wait for data and the longest sleep between
+	// polls when the adaptive strategy is used
+	PollStrategy PollStrategy
+	PollInterval time.Duration
+
+	// Candidate ports tried when the configured port is missing
+	Rescan RescanOptions
+
+	// Opens the port instead of the driver, e.g. for adapters with
+	// their own library or test doubles. The mode is set afterwards
+	Open func(portName string) (Port, error)
+
+	// Clears the high bit of received bytes, for 7-bit devices whose
+	// parity bit arrives embedded in the data, e.g. 7E1 read as 8N1
+	MaskHighBit bool
+
+	// Raw or canonical mode and VMIN/VTIME of the port, Unix only
+	Terminal TerminalOptions
 }
 
 type UnicommSerial struct {
 	Options    SerialOptions
 	Connection Port
 
-	mutex sync.Mutex // Protect port instance
+	lock    *os.File   // Advisory lock of the port, when exclusive
+	pending []byte     // Bytes received after the last delimiter
+	mutex   sync.Mutex // Protect port instance
 }
 
 const (
@@ -54,31 +94,105 @@ const (
 	TwoStopBits
 )
 
+const (
+	BlockingPoll = unicommio.BlockingPoll
+	AdaptivePoll = unicommio.AdaptivePoll
+)
+
 /*
 Creates a new instance of Unicomm Serial communication
 */
 func NewSerial(options SerialOptions) *UnicommSerial {
-	if options.ReadTimeout == 0 {
-		options.ReadTimeout = 100 * time.Millisecond
+	return &UnicommSerial{
+		Options: options.Defaults(),
 	}
-	if options.WriteTimeout == 0 {
-		options.WriteTimeout = 100 * time.Millisecond
+}
+
+/*
+Applies the options that can change while connected, which are
+the timeouts and the delimiters. Other options are ignored and
+only take effect on a new instance
+*/
+func (us *UnicommSerial) Reconfigure(options SerialOptions) {
+	options = options.Defaults()
+
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	us.Options.ReadTimeout = options.ReadTimeout
+	us.Options.WriteTimeout = options.WriteTimeout
+	us.Options.StartDelimiter = options.StartDelimiter
+	us.Options.EndDelimiter = options.EndDelimiter
+	if us.Connection != nil {
+		us.Connection.SetReadTimeout(options.ReadTimeout)
 	}
-	return &UnicommSerial{
-		Options: options,
+}
+
+/*
+Returns the canonical form of a port name, so it can be compared
+with the names returned by the ports list. On Windows the device
+namespace prefix (\\.\) is removed, which is required for COM10
+and above, while on Unix symbolic links such as the udev entries
+in /dev/serial/by-id are resolved to the real device
+*/
+func CanonicalPortName(portName string) string {
+	if runtime.GOOS == "windows" {
+		return strings.ToUpper(strings.TrimPrefix(portName, `\\.\`))
+	}
+	if resolved, err := filepath.EvalSymlinks(portName); err == nil {
+		return resolved
+	}
+	return portName
+}
+
+/*
+Returns true if the port name points to a character device that
+exists, even when the enumerator does not report it
+*/
+func isDevicePath(portName string) bool {
+	if runtime.GOOS == "windows" {
+		return false
+	}
+	info, err := os.Stat(portName)
+	if err != nil {
+		return false
 	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+/*
+Returns true if the port is reported by the enumerator or its
+device path exists
+*/
+func isPortPresent(portName string) (bool, error) {
+	available, err := serial.GetPortsList()
+	if err != nil {
+		return false, fmt.Errorf("was not possible to validate the port")
+	}
+	return isListed(available, portName) || isDevicePath(portName), nil
+}
+
+/*
+Returns true if the port is one of the listed names, compared
+in their canonical form
+*/
+func isListed(names []string, portName string) bool {
+	target := CanonicalPortName(portName)
+	return slices.ContainsFunc(names, func(name string) bool {
+		return CanonicalPortName(name) == target
+	})
 }
 
 /*
 Returns true if the target port is available
 */
 func (us *UnicommSerial) isPortAvailable(portName string) error {
-	available, err := serial.GetPortsList()
+	present, err := isPortPresent(portName)
 	if err != nil {
-		return fmt.Errorf("was not possible to validate the port")
+		return err
 	}
-	if !slices.Contains(available, portName) {
-		return fmt.Errorf("port is not available")
+	if !present {
+		return ErrPortNotAvailable
 	}
 
 	port, err := serial.Open(portName, &serial.Mode{})
@@ -90,27 +204,44 @@ func (us *UnicommSerial) isPortAvailable(portName string) error {
 }
 
 /*
-Returns true if the serial port is connected
+Returns true if the serial port is connected, checked through
+the modem lines without transmitting
 */
 func (us *UnicommSerial) IsConnected() bool {
-	if us.Connection == nil {
-		return false
-	}
+	return us.ProbeLink() == nil
+}
 
+/*
+Establishes a connection with the desired serial port. A port
+whose link was lost is closed and opened again
+*/
+func (us *UnicommSerial) Connect() error {
+	// Reconfigure may change the options meanwhile, so a copy is used
 	us.mutex.Lock()
-	defer us.mutex.Unlock()
+	options := us.Options
+	connected := us.Connection != nil && probeLines(us.Connection) == nil
+	us.mutex.Unlock()
 
-	if _, err := us.Connection.Write([]byte{}); err != nil {
-		return false
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	if connected {
+		return fmt.Errorf("there is a port already connected")
 	}
-	return true
+	// A port whose link was lost is released to open it again
+	us.closeStale()
+
+	err := us.open(options.PortName)
+	if errors.Is(err, ErrPortNotAvailable) && options.Rescan.Enabled {
+		err = us.rescan(err)
+	}
+	return Diagnose(options.PortName, err)
 }
 
 /*
-Establishes a connection with the desired serial port
+Opens the port and runs the connect sequence
 */
-func (us *UnicommSerial) Connect() error {
-	portName := us.Options.PortName
+func (us *UnicommSerial) open(portName string) error {
 	serialMode := &serial.Mode{
 		BaudRate: us.Options.BaudRate,
 		Parity:   us.Options.Parity,
@@ -118,43 +249,161 @@ func (us *UnicommSerial) Connect() error {
 		StopBits: us.Options.StopBits,
 	}
 
-	if us.IsConnected() {
-		return fmt.Errorf("there is a port already connected")
-	}
-	if err := us.isPortAvailable(portName); err != nil {
-		return err
+	if us.Options.Open == nil {
+		if err := us.isPortAvailable(portName); err != nil {
+			return err
+		}
 	}
 
 	us.mutex.Lock()
 	defer us.mutex.Unlock()
 
-	port, err := serial.Open(portName, serialMode)
+	if us.Options.Exclusive {
+		lock, err := lockPort(portName)
+		if err != nil {
+			return err
+		}
+		us.lock = lock
+	}
+
+	port, err := us.openPort(portName, serialMode)
 	if err != nil {
 		us.Connection = nil
+		us.unlock()
+		return err
+	}
+	if err := applyTerminal(portName, us.Options.Terminal); err != nil {
+		port.Close()
+		us.unlock()
 		return err
 	}
 	port.SetReadTimeout(us.Options.ReadTimeout)
 
+	// Newer drivers can also enforce the write timeout themselves
+	if writer, ok := port.(interface{ SetWriteTimeout(time.Duration) error }); ok {
+		writer.SetWriteTimeout(us.Options.WriteTimeout)
+	}
+
+	lines := lineOptionsFor(portName, us.Options)
+	if err := applyLines(port, lines); err != nil {
+		port.Close()
+		us.unlock()
+		return err
+	}
+	if err := runControlSequence(port, us.Options.ConnectSequence); err != nil {
+		port.Close()
+		us.unlock()
+		return err
+	}
+
 	us.Connection = port
 	return nil
 }
 
 /*
-Closes the connection with the current serial port
+Opens the port through the driver or the opener of the options
 */
-func (us *UnicommSerial) Disconnect() error {
-	if !us.IsConnected() {
+func (us *UnicommSerial) openPort(portName string, mode *serial.Mode) (Port, error) {
+	if us.Options.Open == nil {
+		return serial.Open(portName, mode)
+	}
+	port, err := us.Options.Open(portName)
+	if err != nil {
+		return nil, err
+	}
+	if err := port.SetMode(mode); err != nil {
+		port.Close()
+		return nil, err
+	}
+	return port, nil
+}
+
+/*
+Releases the handle of a port that was removed, since its
+connection check fails and Disconnect refuses to close it
+*/
+func (us *UnicommSerial) closeStale() {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if us.Connection != nil {
+		us.Connection.Close()
+		us.Connection = nil
+	}
+	us.pending = nil
+	us.unlock()
+}
+
+/*
+Releases the advisory lock of the port, if any
+*/
+func (us *UnicommSerial) unlock() {
+	if us.lock != nil {
+		us.lock.Close()
+		us.lock = nil
+	}
+}
+
+/*
+Sends a break condition for the given duration, used to wake
+LIN devices and to enter some bootloaders
+*/
+func (us *UnicommSerial) SendBreak(duration time.Duration) error {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if us.Connection == nil {
+		return fmt.Errorf("there is no port connected")
+	}
+
+	return us.Connection.Break(duration)
+}
+
+/*
+Sets the level of the DataTerminalReady line
+*/
+func (us *UnicommSerial) SetDTR(level bool) error {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if us.Connection == nil {
 		return fmt.Errorf("there is no port connected")
 	}
 
+	return us.Connection.SetDTR(level)
+}
+
+/*
+Sets the level of the RequestToSend line
+*/
+func (us *UnicommSerial) SetRTS(level bool) error {
 	us.mutex.Lock()
 	defer us.mutex.Unlock()
 
+	if us.Connection == nil {
+		return fmt.Errorf("there is no port connected")
+	}
+
+	return us.Connection.SetRTS(level)
+}
+
+/*
+Closes the connection with the current serial port
+*/
+func (us *UnicommSerial) Disconnect() error {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if us.Connection == nil {
+		return fmt.Errorf("there is no port connected")
+	}
+
 	if err := us.Connection.Close(); err != nil {
 		return err
 	}
 
 	us.Connection = nil
+	us.unlock()
 	return nil
 }
 
@@ -163,104 +412,298 @@ Reads a number of bytes from the serial port
 */
 func (us *UnicommSerial) Read(n uint) ([]byte, error) {
 	buffer := make([]byte, n)
+	nReaded, err := us.ReadInto(buffer)
+	return buffer[:nReaded], err
+}
 
-	if !us.IsConnected() {
-		return nil, fmt.Errorf("there is no port connected")
+/*
+Reads into a buffer provided by the caller, so polling loops
+can reuse it instead of allocating on every read
+*/
+func (us *UnicommSerial) ReadInto(buffer []byte) (int, error) {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if us.Connection == nil {
+		return 0, fmt.Errorf("there is no port connected")
 	}
 
-	us.mutex.Lock()
+	if len(us.pending) > 0 {
+		nReaded := copy(buffer, us.pending)
+		us.pending = us.pending[nReaded:]
+		return nReaded, nil
+	}
+	return us.read(buffer)
+}
+
+/*
+Reads from the port, masking the high bit of the received bytes
+when configured
+*/
+func (us *UnicommSerial) read(buffer []byte) (int, error) {
 	nReaded, err := us.Connection.Read(buffer)
-	us.mutex.Unlock()
+	if us.Options.MaskHighBit {
+		for index := range buffer[:nReaded] {
+			buffer[index] &= 0x7F
+		}
+	}
+	return nReaded, err
+}
 
-	if err != nil {
-		return nil, err
+/*
+Reads everything currently buffered by the driver without
+waiting for the read timeout
+*/
+func (us *UnicommSerial) ReadAvailable() ([]byte, error) {
+	buffer := make([]byte, 0)
+	scratch := unicommio.GetChunk()
+	defer unicommio.PutChunk(scratch)
+	chunk := *scratch
+
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	if us.Connection == nil {
+		return nil, fmt.Errorf("there is no port connected")
+	}
+
+	us.Connection.SetReadTimeout(0)
+	defer us.Connection.SetReadTimeout(us.Options.ReadTimeout)
+
+	buffer = append(buffer, us.pending...)
+	us.pending = nil
+	for {
+		nReaded, err := us.read(chunk)
+		if err != nil {
+			return buffer, err
+		}
+		if nReaded == 0 {
+			return buffer, nil
+		}
+		buffer = append(buffer, chunk[:nReaded]...)
 	}
-	return buffer[:nReaded], nil
 }
 
 /*
 Reads data from the serial port until a target
-delimiter is found
+delimiter is found. Data is read in chunks and the bytes
+received after the delimiter are kept for the next read
 */
 func (us *UnicommSerial) ReadUntil(endDelimiter string) ([]byte, error) {
-	buffer := make([]byte, 0)
-	singleByte := make([]byte, 1)
-	errorChan := make(chan error, 1)
-	resultChan := make(chan []byte, 1)
+	scratch := unicommio.GetChunk()
+	defer unicommio.PutChunk(scratch)
+	chunk := (*scratch)[:unicommio.ReadChunkSize]
+
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
 
-	if !us.IsConnected() {
+	if us.Connection == nil {
 		return nil, fmt.Errorf("there is no port connected")
 	}
+	deadline := time.Now().Add(us.Options.ReadTimeout)
+	backoff := unicommio.Backoff{Max: us.Options.PollInterval}
+
+	buffer := us.pending
+	us.pending = nil
+
+	if us.Options.PollStrategy == AdaptivePoll {
+		us.Connection.SetReadTimeout(0)
+	}
+	defer us.Connection.SetReadTimeout(us.Options.ReadTimeout)
+
+	for {
+		if frame, rest, ok := unicommio.SplitFrame(buffer, endDelimiter); ok {
+			us.pending = rest
+			return frame, nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return buffer, unicommio.TimeoutError("read until")
+		}
+		if us.Options.PollStrategy == BlockingPoll {
+			us.Connection.SetReadTimeout(remaining)
+		}
+
+		nReaded, err := us.read(chunk)
+		buffer = append(buffer, chunk[:nReaded]...)
+		if unicommio.IsTemporary(err) {
+			time.Sleep(unicommio.RetryDelay)
+			continue
+		}
+		if err != nil {
+			return buffer, err
+		}
+		if nReaded == 0 {
+			if us.Options.PollStrategy == AdaptivePoll {
+				backoff.Wait(deadline)
+			}
+			continue
+		}
+		backoff.Reset()
+	}
+}
+
+/*
+Reads at least minimum and at most maximum bytes, asking the
+driver for everything up to maximum on each read, so messages of
+a known size arrive in as few system calls as possible. Bytes
+received before the read timeout are returned along with it
+*/
+func (us *UnicommSerial) ReadAtLeast(minimum uint, maximum uint) ([]byte, error) {
+	if maximum < minimum {
+		return nil, fmt.Errorf("invalid sizes: maximum %d is lower than minimum %d", maximum, minimum)
+	}
 
 	us.mutex.Lock()
 	defer us.mutex.Unlock()
 
-	go func() {
-		for {
-			nReaded, err := us.Connection.Read(singleByte)
-			if err != nil {
-				errorChan <- err
-				return
-			}
-			if nReaded == 1 {
-				buffer = append(buffer, singleByte...)
-			}
-			if nReaded == 0 || strings.Contains(string(buffer), endDelimiter) {
-				resultChan <- buffer
-				return
-			}
+	if us.Connection == nil {
+		return nil, fmt.Errorf("there is no port connected")
+	}
+	deadline := time.Now().Add(us.Options.ReadTimeout)
+	backoff := unicommio.Backoff{Max: us.Options.PollInterval}
+
+	buffer := make([]byte, maximum)
+	nReaded := copy(buffer, us.pending)
+	us.pending = us.pending[nReaded:]
+
+	if us.Options.PollStrategy == AdaptivePoll {
+		us.Connection.SetReadTimeout(0)
+	}
+	defer us.Connection.SetReadTimeout(us.Options.ReadTimeout)
+
+	for nReaded < int(minimum) {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return buffer[:nReaded], unicommio.TimeoutError("read at least")
+		}
+		if us.Options.PollStrategy == BlockingPoll {
+			us.Connection.SetReadTimeout(remaining)
 		}
-	}()
 
-	select {
-	case err := <-errorChan:
-		return nil, err
-	case result := <-resultChan:
-		return result, nil
-	case <-time.After(us.Options.ReadTimeout):
-		return buffer, fmt.Errorf("read until timeout")
+		nChunk, err := us.read(buffer[nReaded:])
+		nReaded += nChunk
+		if unicommio.IsTemporary(err) {
+			time.Sleep(unicommio.RetryDelay)
+			continue
+		}
+		if err != nil {
+			return buffer[:nReaded], err
+		}
+		if nChunk == 0 {
+			if us.Options.PollStrategy == AdaptivePoll {
+				backoff.Wait(deadline)
+			}
+			continue
+		}
+		backoff.Reset()
 	}
+	return buffer[:nReaded], nil
 }
 
 /*
-Writes an array of bytes to the serial port
+Returns how many bytes the port transmits in about 10ms, so
+a write interrupted by the timeout stops shortly after it
 */
-func (us *UnicommSerial) Write(message []byte) error {
-	var errorChan = make(chan error)
+func (us *UnicommSerial) writeChunkSize() int {
+	// Each character takes about 10 bits with start, parity and stop bits
+	return max(us.Options.BaudRate/10/100, 16)
+}
+
+/*
+Returns the end delimiter appended by Write
+*/
+func (us *UnicommSerial) EndDelimiter() string {
+	us.mutex.Lock()
+	defer us.mutex.Unlock()
+
+	return us.Options.EndDelimiter
+}
 
+/*
+Writes an array of bytes to the serial port, appending the end
+delimiter when the message does not end with it
+*/
+func (us *UnicommSerial) Write(message []byte) error {
 	msgStr := string(message)
+	us.mutex.Lock()
 	endDelimiter := us.Options.EndDelimiter
+	us.mutex.Unlock()
 
-	if !us.IsConnected() {
-		return fmt.Errorf("there is no port connected")
-	}
 	if endDelimiter != "" && !strings.HasSuffix(msgStr, endDelimiter) {
 		message = append(message, []byte(endDelimiter)...)
 	}
+	return us.WriteRaw(message)
+}
 
-	timer := time.NewTimer(us.Options.WriteTimeout)
-	defer timer.Stop()
+/*
+Writes an array of bytes to the serial port as is, without the
+end delimiter. The message is sent in small chunks, so the write
+stops shortly after the write timeout expires, and the port is
+held until then. When the write is interrupted, a
+PartialWriteError reports the bytes committed to the port
+*/
+func (us *UnicommSerial) WriteRaw(message []byte) error {
+	var nWrited atomic.Int64
+	var stopped atomic.Bool
+	errorChan := make(chan error, 1)
 
 	us.mutex.Lock()
 	defer us.mutex.Unlock()
 
+	if us.Connection == nil {
+		return fmt.Errorf("there is no port connected")
+	}
+
+	timer := time.NewTimer(us.Options.WriteTimeout)
+	defer timer.Stop()
+
+	// Bytes kept from the last reply would be read as the new one
+	us.pending = nil
+
 	go func() {
 		us.Connection.ResetInputBuffer()
 		us.Connection.ResetOutputBuffer()
-		nWrited, err := us.Connection.Write(message)
-		if nWrited != len(message) {
-			errorChan <- fmt.Errorf("writed %d bytes, expected %d", nWrited, len(message))
-			return
+
+		// Temporary errors, common on USB adapters of loaded systems,
+		// are retried until the write timeout stops the loop
+		chunkSize := us.writeChunkSize()
+		for offset := 0; offset < len(message) && !stopped.Load(); {
+			chunk := message[offset:min(offset+chunkSize, len(message))]
+			n, err := us.Connection.Write(chunk)
+			nWrited.Add(int64(n))
+			offset += n
+			if unicommio.IsTemporary(err) {
+				time.Sleep(unicommio.RetryDelay)
+				continue
+			}
+			if err != nil {
+				errorChan <- err
+				return
+			}
+			if n != len(chunk) {
+				break
+			}
 		}
-		errorChan <- err
+		errorChan <- nil
 	}()
 
-	for {
-		select {
-		case err := <-errorChan:
-			return err
-		case <-timer.C:
-			return fmt.Errorf("write timeout")
+	select {
+	case err := <-errorChan:
+		if written := int(nWrited.Load()); written != len(message) {
+			return &unicommio.PartialWriteError{Written: written, Expected: len(message), Err: err}
+		}
+		return nil
+	case <-timer.C:
+		// The port is released only after the last chunk, so the next
+		// operation does not overlap the bytes still being written
+		stopped.Store(true)
+		<-errorChan
+		return &unicommio.PartialWriteError{
+			Written:  int(nWrited.Load()),
+			Expected: len(message),
+			Err:      unicommio.TimeoutError("write"),
 		}
 	}
 }
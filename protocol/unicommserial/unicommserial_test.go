@@ -0,0 +1,75 @@
+package unicommserial_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+)
+
+func TestCanonicalPortName(t *testing.T) {
+	windows := runtime.GOOS == "windows"
+
+	// A link in the place of /dev/serial/by-id, pointing to the device
+	dir := t.TempDir()
+	device := filepath.Join(dir, "ttyUSB0")
+	byID := filepath.Join(dir, "usb-FTDI_FT232R_A1-if00-port0")
+	if !windows {
+		if err := os.WriteFile(device, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(device, byID); err != nil {
+			t.Fatal(err)
+		}
+		device, _ = filepath.EvalSymlinks(device)
+	}
+
+	tests := []struct {
+		name     string
+		portName string
+		want     string
+		windows  bool // Only checked on Windows, names are kept elsewhere
+	}{
+		{"device path", device, device, false},
+		{"by-id link", byID, device, false},
+		{"missing path", "/dev/ttyUSB9", "/dev/ttyUSB9", false},
+		{"COM port", "COM3", "COM3", true},
+		{"device namespace", `\\.\COM10`, "COM10", true},
+		{"lower case", `\\.\com12`, "COM12", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.windows != windows {
+				t.Skipf("not applicable on %s", runtime.GOOS)
+			}
+			if got := unicommserial.CanonicalPortName(test.portName); got != test.want {
+				t.Fatalf("CanonicalPortName(%q) = %q, want %q", test.portName, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSendBreak(t *testing.T) {
+	port := &linesPort{}
+	comm := unicommserial.NewSerial(unicommserial.SerialOptions{
+		PortName: "fake",
+		Open:     func(portName string) (unicommserial.Port, error) { return port, nil },
+	})
+	if err := comm.SendBreak(time.Millisecond); err == nil {
+		t.Fatal("expected a break without a port to fail")
+	}
+
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+	if err := comm.SendBreak(25 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if len(port.breaks) != 1 || port.breaks[0] != 25*time.Millisecond {
+		t.Fatalf("breaks sent %v, want one of 25ms", port.breaks)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtcp
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultNetwork         = "tcp"
+	DefaultReadTimeout     = 100 * time.Millisecond
+	DefaultWriteTimeout    = 100 * time.Millisecond
+	DefaultMaxReadDuration = 5 * time.Second
+	DefaultDialTimeout     = 500 * time.Millisecond
+	DefaultFallbackDelay   = 250 * time.Millisecond
+)
+
+/*
+Returns a copy of the options with the zero values replaced
+by the defaults: dual-stack network, 100ms read and write
+timeouts, 5s limit of a read, 500ms dial timeout and 250ms
+fallback delay
+*/
+func (to TCPOptions) Defaults() TCPOptions {
+	if to.Network == "" {
+		to.Network = DefaultNetwork
+	}
+	if to.ReadTimeout == 0 {
+		to.ReadTimeout = DefaultReadTimeout
+	}
+	if to.WriteTimeout == 0 {
+		to.WriteTimeout = DefaultWriteTimeout
+	}
+	if to.MaxReadDuration == 0 {
+		to.MaxReadDuration = DefaultMaxReadDuration
+	}
+	if to.DialTimeout == 0 {
+		to.DialTimeout = DefaultDialTimeout
+	}
+	if to.FallbackDelay == 0 {
+		to.FallbackDelay = DefaultFallbackDelay
+	}
+	return to
+}
+
+/*
+Returns an error describing the first invalid option
+*/
+func (to TCPOptions) Validate() error {
+	if to.Host == "" {
+		return fmt.Errorf("invalid options: host is empty")
+	}
+	if to.Port == 0 || to.Port > 65535 {
+		return fmt.Errorf("invalid options: port must be between 1 and 65535, got %d", to.Port)
+	}
+	switch to.Network {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return fmt.Errorf("invalid options: unknown network %q", to.Network)
+	}
+	if to.ReadTimeout < 0 || to.WriteTimeout < 0 || to.DialTimeout < 0 || to.MaxReadDuration < 0 {
+		return fmt.Errorf("invalid options: timeouts must not be negative")
+	}
+	if to.BurstBytes > 0 && to.MaxBytesPerSecond == 0 {
+		return fmt.Errorf("invalid options: burst bytes requires a rate limit")
+	}
+	return nil
+}
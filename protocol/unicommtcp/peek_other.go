@@ -0,0 +1,19 @@
+//go:build !(linux || darwin || freebsd || openbsd || netbsd || dragonfly)
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtcp
+
+import "net"
+
+/*
+Peeking is not available, the connection is only checked
+locally
+*/
+func peekSocket(conn net.Conn) error {
+	return nil
+}
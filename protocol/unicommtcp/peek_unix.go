@@ -0,0 +1,54 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd || dragonfly
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtcp
+
+import (
+	"io"
+	"net"
+	"syscall"
+	"time"
+)
+
+/*
+Peeks one byte of the socket without waiting or consuming it.
+Returns io.EOF when the peer closed the connection. Connections
+not backed by a socket are not checked
+*/
+func peekSocket(conn net.Conn) error {
+	socket, ok := conn.(syscall.Conn)
+	if !ok {
+		return nil
+	}
+	raw, err := socket.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	// An expired deadline of the last read would fail the peek
+	conn.SetReadDeadline(time.Time{})
+
+	var peekErr error
+	buffer := make([]byte, 1)
+	err = raw.Read(func(fd uintptr) bool {
+		nPeeked, _, err := syscall.Recvfrom(int(fd), buffer, syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+		switch {
+		case err == syscall.EAGAIN:
+			// Nothing received, the connection is open
+		case err != nil:
+			peekErr = err
+		case nPeeked == 0:
+			peekErr = io.EOF
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return peekErr
+}
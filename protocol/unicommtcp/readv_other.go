@@ -0,0 +1,19 @@
+//go:build !(linux || darwin || freebsd || openbsd || netbsd || dragonfly)
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtcp
+
+import "net"
+
+/*
+Reads into the first buffer, which must not be empty, as
+readv is not available
+*/
+func readVectored(conn net.Conn, buffers [][]byte) (int, error) {
+	return conn.Read(buffers[0])
+}
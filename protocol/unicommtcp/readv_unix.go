@@ -0,0 +1,68 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd || dragonfly
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtcp
+
+import (
+	"io"
+	"net"
+	"syscall"
+	"unsafe"
+)
+
+// Buffers accepted by a single readv, the minimum IOV_MAX of POSIX
+const maxIovecs = 16
+
+/*
+Reads into several buffers with a single readv system call.
+Connections not backed by a socket, such as tunnels, are read
+into the first buffer, which must not be empty
+*/
+func readVectored(conn net.Conn, buffers [][]byte) (int, error) {
+	socket, ok := conn.(syscall.Conn)
+	if !ok {
+		return conn.Read(buffers[0])
+	}
+	raw, err := socket.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	iovecs := make([]syscall.Iovec, 0, min(len(buffers), maxIovecs))
+	for _, buffer := range buffers {
+		if len(buffer) == 0 {
+			continue
+		}
+		if len(iovecs) == maxIovecs {
+			break
+		}
+		iovec := syscall.Iovec{Base: &buffer[0]}
+		iovec.SetLen(len(buffer))
+		iovecs = append(iovecs, iovec)
+	}
+	if len(iovecs) == 0 {
+		return 0, nil
+	}
+
+	var nReaded uintptr
+	var errno syscall.Errno
+	err = raw.Read(func(fd uintptr) bool {
+		nReaded, _, errno = syscall.Syscall(syscall.SYS_READV, fd, uintptr(unsafe.Pointer(&iovecs[0])), uintptr(len(iovecs)))
+		// Not ready yet, the runtime waits for the socket or the deadline
+		return errno != syscall.EAGAIN
+	})
+	switch {
+	case err != nil:
+		return 0, err
+	case errno != 0:
+		return 0, errno
+	case nReaded == 0:
+		return 0, io.EOF
+	}
+	return int(nReaded), nil
+}
@@ -0,0 +1,49 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtcp
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+/*
+Called by a shared poller with the data received on a watched
+connection, or with the error ending the watch, such as io.EOF
+when the peer closed the connection. Handlers of every connection
+share the goroutines of the poller and must not block
+*/
+type ReadyHandler func(data []byte, err error)
+
+/*
+Returns the connection to watch, clearing its read deadline since
+an expired one would fail every read
+*/
+func (ut *UnicommTCP) watchable() (net.Conn, error) {
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
+
+	if ut.Connection == nil {
+		return nil, fmt.Errorf("there is no port connected")
+	}
+	ut.Connection.SetReadDeadline(time.Time{})
+	return ut.Connection, nil
+}
+
+/*
+Removes and returns the bytes received after the last delimiter,
+which are delivered before the data of the poller
+*/
+func (ut *UnicommTCP) takePending() []byte {
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
+
+	pending := ut.pending
+	ut.pending = nil
+	return pending
+}
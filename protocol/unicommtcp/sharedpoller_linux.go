@@ -0,0 +1,263 @@
+//go:build linux
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtcp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"syscall"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+// Readiness events handled by each wait
+const maxPollEvents = 128
+
+/*
+Readiness-driven engine for gateways with hundreds of TCP links.
+A single goroutine waits on an epoll instance for every watched
+connection and reads the ones ready, instead of a goroutine
+blocked on each connection. Writes still go through the instance
+*/
+type SharedPoller struct {
+	epoll   int
+	wake    [2]int // Pipe interrupting the wait on Close
+	watched map[int32]*watchedConn
+	fds     map[*UnicommTCP]int32
+	closed  bool
+	done    chan struct{}
+	mutex   sync.Mutex // Protect the watched connections
+}
+
+type watchedConn struct {
+	tcp         *UnicommTCP
+	raw         syscall.RawConn
+	handler     ReadyHandler
+	dispatching sync.WaitGroup // Read and handler call in progress
+}
+
+/*
+Creates a shared poller and starts its goroutine
+*/
+func NewSharedPoller() (*SharedPoller, error) {
+	epoll, err := syscall.EpollCreate1(syscall.EPOLL_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the poller: %w", err)
+	}
+	sp := &SharedPoller{
+		epoll:   epoll,
+		watched: make(map[int32]*watchedConn),
+		fds:     make(map[*UnicommTCP]int32),
+		done:    make(chan struct{}),
+	}
+	if err := syscall.Pipe2(sp.wake[:], syscall.O_NONBLOCK|syscall.O_CLOEXEC); err != nil {
+		syscall.Close(epoll)
+		return nil, fmt.Errorf("failed to create the poller: %w", err)
+	}
+	event := syscall.EpollEvent{Events: syscall.EPOLLIN, Fd: int32(sp.wake[0])}
+	if err := syscall.EpollCtl(epoll, syscall.EPOLL_CTL_ADD, sp.wake[0], &event); err != nil {
+		sp.release()
+		return nil, fmt.Errorf("failed to create the poller: %w", err)
+	}
+	go sp.run()
+	return sp, nil
+}
+
+/*
+Delivers the data received on the connection to the handler
+until Unwatch, the connection fails or the peer closes it. The
+connection must not be read by other means meanwhile, and must be
+unwatched before it is disconnected
+*/
+func (sp *SharedPoller) Watch(tcp *UnicommTCP, handler ReadyHandler) error {
+	conn, err := tcp.watchable()
+	if err != nil {
+		return err
+	}
+	socket, ok := conn.(syscall.Conn)
+	if !ok {
+		return fmt.Errorf("connection is not a socket and cannot be watched")
+	}
+	raw, err := socket.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var fd int32
+	raw.Control(func(descriptor uintptr) { fd = int32(descriptor) })
+
+	// Bytes kept by the last ReadUntil come first
+	if pending := tcp.takePending(); len(pending) > 0 {
+		handler(pending, nil)
+	}
+
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	if sp.closed {
+		return fmt.Errorf("poller is closed")
+	}
+	if _, ok := sp.fds[tcp]; ok {
+		return fmt.Errorf("connection is already watched")
+	}
+	event := syscall.EpollEvent{Events: syscall.EPOLLIN | syscall.EPOLLRDHUP, Fd: fd}
+	if err := syscall.EpollCtl(sp.epoll, syscall.EPOLL_CTL_ADD, int(fd), &event); err != nil {
+		return fmt.Errorf("failed to watch the connection: %w", err)
+	}
+	sp.watched[fd] = &watchedConn{tcp: tcp, raw: raw, handler: handler}
+	sp.fds[tcp] = fd
+	return nil
+}
+
+/*
+Stops delivering the data of the connection and waits for a read
+in progress, so the connection can be read directly again. Must
+not be called by the handler of the connection
+*/
+func (sp *SharedPoller) Unwatch(tcp *UnicommTCP) error {
+	sp.mutex.Lock()
+	fd, ok := sp.fds[tcp]
+	var watched *watchedConn
+	if ok {
+		watched = sp.watched[fd]
+		sp.remove(tcp)
+	}
+	sp.mutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("connection is not watched")
+	}
+	watched.dispatching.Wait()
+	return nil
+}
+
+/*
+Returns the number of watched connections
+*/
+func (sp *SharedPoller) Len() int {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	return len(sp.fds)
+}
+
+/*
+Stops the poller and unwatches every connection, leaving them
+connected
+*/
+func (sp *SharedPoller) Close() error {
+	sp.mutex.Lock()
+	if sp.closed {
+		sp.mutex.Unlock()
+		return fmt.Errorf("poller is already closed")
+	}
+	sp.closed = true
+	for tcp := range sp.fds {
+		sp.remove(tcp)
+	}
+	sp.mutex.Unlock()
+
+	syscall.Write(sp.wake[1], []byte{0})
+	<-sp.done
+	sp.release()
+	return nil
+}
+
+/*
+Closes the descriptors of the poller
+*/
+func (sp *SharedPoller) release() {
+	syscall.Close(sp.wake[0])
+	syscall.Close(sp.wake[1])
+	syscall.Close(sp.epoll)
+}
+
+/*
+Removes the connection from the epoll instance, must be called
+with the mutex locked
+*/
+func (sp *SharedPoller) remove(tcp *UnicommTCP) {
+	fd := sp.fds[tcp]
+	// Fails when the connection was already closed, which removed it
+	syscall.EpollCtl(sp.epoll, syscall.EPOLL_CTL_DEL, int(fd), nil)
+	delete(sp.watched, fd)
+	delete(sp.fds, tcp)
+}
+
+/*
+Waits for the ready connections until the poller is closed
+*/
+func (sp *SharedPoller) run() {
+	defer close(sp.done)
+
+	events := make([]syscall.EpollEvent, maxPollEvents)
+	buffer := make([]byte, unicommio.ReadChunkSize)
+	for {
+		nEvents, err := syscall.EpollWait(sp.epoll, events, -1)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			return
+		}
+		for _, event := range events[:nEvents] {
+			if event.Fd == int32(sp.wake[0]) {
+				return
+			}
+			sp.dispatch(event.Fd, buffer)
+		}
+	}
+}
+
+/*
+Reads a ready connection without blocking and delivers the data
+or the error ending the watch to its handler
+*/
+func (sp *SharedPoller) dispatch(fd int32, buffer []byte) {
+	sp.mutex.Lock()
+	watched, ok := sp.watched[fd]
+	if ok {
+		// Taken with the mutex, so Unwatch either removes the
+		// connection first or waits for the dispatch
+		watched.dispatching.Add(1)
+	}
+	sp.mutex.Unlock()
+	if !ok {
+		return
+	}
+	defer watched.dispatching.Done()
+
+	var nReaded int
+	var readErr error
+	err := watched.raw.Read(func(descriptor uintptr) bool {
+		nReaded, readErr = syscall.Read(int(descriptor), buffer)
+		return true
+	})
+	if err == nil {
+		err = readErr
+	}
+	switch {
+	case err == syscall.EAGAIN:
+		// Spurious wakeup, the data was read meanwhile
+		return
+	case err == nil && nReaded == 0:
+		err = io.EOF
+	}
+	if err != nil {
+		sp.mutex.Lock()
+		if sp.watched[fd] == watched {
+			sp.remove(watched.tcp)
+		}
+		sp.mutex.Unlock()
+		watched.handler(nil, err)
+		return
+	}
+	watched.handler(bytes.Clone(buffer[:nReaded]), nil)
+}
@@ -0,0 +1,168 @@
+//go:build !linux
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtcp
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+/*
+Delivers the data of many TCP links to their handlers. Without
+epoll, each watched connection is read by its own goroutine
+*/
+type SharedPoller struct {
+	watched map[*UnicommTCP]*watchedConn
+	closed  bool
+	wg      sync.WaitGroup
+	mutex   sync.Mutex // Protect the watched connections
+}
+
+type watchedConn struct {
+	conn net.Conn
+	stop chan struct{}
+	done chan struct{} // Closed once the goroutine returns
+}
+
+/*
+Creates a shared poller
+*/
+func NewSharedPoller() (*SharedPoller, error) {
+	return &SharedPoller{watched: make(map[*UnicommTCP]*watchedConn)}, nil
+}
+
+/*
+Delivers the data received on the connection to the handler
+until Unwatch, the connection fails or the peer closes it. The
+connection must not be read by other means meanwhile, and must be
+unwatched before it is disconnected
+*/
+func (sp *SharedPoller) Watch(tcp *UnicommTCP, handler ReadyHandler) error {
+	conn, err := tcp.watchable()
+	if err != nil {
+		return err
+	}
+
+	// Bytes kept by the last ReadUntil come first
+	if pending := tcp.takePending(); len(pending) > 0 {
+		handler(pending, nil)
+	}
+
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	if sp.closed {
+		return fmt.Errorf("poller is closed")
+	}
+	if _, ok := sp.watched[tcp]; ok {
+		return fmt.Errorf("connection is already watched")
+	}
+	watched := &watchedConn{conn: conn, stop: make(chan struct{}), done: make(chan struct{})}
+	sp.watched[tcp] = watched
+	sp.wg.Add(1)
+	go sp.read(tcp, handler, watched)
+	return nil
+}
+
+/*
+Reads the connection until it is unwatched or fails
+*/
+func (sp *SharedPoller) read(tcp *UnicommTCP, handler ReadyHandler, watched *watchedConn) {
+	defer sp.wg.Done()
+	defer close(watched.done)
+
+	buffer := make([]byte, unicommio.ReadChunkSize)
+	for {
+		nReaded, err := watched.conn.Read(buffer)
+		select {
+		case <-watched.stop:
+			return
+		default:
+		}
+		if nReaded > 0 {
+			handler(bytes.Clone(buffer[:nReaded]), nil)
+		}
+		if err != nil {
+			sp.mutex.Lock()
+			if sp.watched[tcp] == watched {
+				delete(sp.watched, tcp)
+			}
+			sp.mutex.Unlock()
+			handler(nil, err)
+			return
+		}
+	}
+}
+
+/*
+Stops delivering the data of the connection and waits for its
+goroutine, so the connection can be read directly again. Must not
+be called by the handler of the connection
+*/
+func (sp *SharedPoller) Unwatch(tcp *UnicommTCP) error {
+	sp.mutex.Lock()
+	watched, ok := sp.watched[tcp]
+	if ok {
+		sp.remove(tcp)
+	}
+	sp.mutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("connection is not watched")
+	}
+	<-watched.done
+	return nil
+}
+
+/*
+Stops the goroutine of the connection, must be called with the
+mutex locked
+*/
+func (sp *SharedPoller) remove(tcp *UnicommTCP) {
+	watched := sp.watched[tcp]
+	close(watched.stop)
+	delete(sp.watched, tcp)
+	// Interrupts the blocked read
+	watched.conn.SetReadDeadline(time.Now())
+}
+
+/*
+Returns the number of watched connections
+*/
+func (sp *SharedPoller) Len() int {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	return len(sp.watched)
+}
+
+/*
+Stops the poller and unwatches every connection, leaving them
+connected
+*/
+func (sp *SharedPoller) Close() error {
+	sp.mutex.Lock()
+	if sp.closed {
+		sp.mutex.Unlock()
+		return fmt.Errorf("poller is already closed")
+	}
+	sp.closed = true
+	for tcp := range sp.watched {
+		sp.remove(tcp)
+	}
+	sp.mutex.Unlock()
+
+	sp.wg.Wait()
+	return nil
+}
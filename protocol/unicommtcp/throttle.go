@@ -0,0 +1,66 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtcp
+
+import (
+	"sync"
+	"time"
+)
+
+type tokenBucket struct {
+	rate   float64 // Tokens (bytes) added per second
+	burst  float64 // Maximum amount of tokens stored
+	tokens float64
+	last   time.Time
+
+	mutex sync.Mutex
+}
+
+/*
+Creates a token bucket that allows bytesPerSecond bytes to be
+sent on average, with bursts up to burst bytes
+*/
+func newTokenBucket(bytesPerSecond uint, burst uint) *tokenBucket {
+	if burst == 0 {
+		burst = bytesPerSecond
+	}
+	return &tokenBucket{
+		rate:   float64(bytesPerSecond),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+/*
+Reserves n tokens from the bucket and returns how long the
+caller must wait before sending the reserved bytes
+*/
+func (tb *tokenBucket) reserve(n int) time.Duration {
+	tb.mutex.Lock()
+	defer tb.mutex.Unlock()
+
+	now := time.Now()
+	tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+	if tb.tokens > tb.burst {
+		tb.tokens = tb.burst
+	}
+	tb.last = now
+
+	tb.tokens -= float64(n)
+	if tb.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+}
+
+/*
+Returns the largest chunk that can be sent at once
+*/
+func (tb *tokenBucket) chunkSize() int {
+	return max(int(tb.burst), 1)
+}
@@ -0,0 +1,553 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/unicomm/credentials"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+// First line sent through a tunnel, followed by the device ID and the token
+const tunnelHello = "UNICOMM-TUNNEL"
+
+var ErrTunnelDown = errors.New("tunnel is down, reconnecting")
+
+type TunnelOptions struct {
+	TCPOptions                  // Rendezvous server and timeouts of the tunnel
+	DeviceID      string        // Identifies the device on the rendezvous server
+	Token         string        // Shared secret checked by the rendezvous server
+	RetryInterval time.Duration // Wait between reconnection attempts, defaults to 5s
+
+	// Resolves the token at each dial, e.g. from a vault, instead of
+	// Token. The token resolved is wiped once sent
+	Credentials credentials.Provider
+
+	// Encrypts the tunnel and authenticates the rendezvous server,
+	// nil sends the token and the data in clear text
+	TLS *tls.Config
+}
+
+/*
+Outbound connection of a device behind NAT to a rendezvous
+server. Commands sent by the server are read from the tunnel
+and responses are written back, and the connection is dialed
+again whenever it drops until Disconnect
+*/
+type UnicommTunnel struct {
+	Options TunnelOptions
+
+	current *UnicommTCP // nil while the tunnel is down
+	stop    chan struct{}
+	done    chan struct{}
+	wake    chan struct{} // Requests an immediate reconnection
+	mutex   sync.Mutex    // Protect the connection and the keeper
+}
+
+/*
+Rendezvous server accepting the tunnels of devices behind NAT.
+Drivers reach a device through Dial, which returns a standard
+TCP instance over its tunnel
+*/
+type Rendezvous struct {
+	Token string // Required in the hello of the tunnels, empty accepts any
+
+	listener net.Listener
+	tunnels  map[string]net.Conn
+	arrived  chan struct{} // Closed and replaced whenever a tunnel arrives
+	mutex    sync.Mutex    // Protect the tunnels
+}
+
+/*
+Creates a reverse tunnel client for a device behind NAT
+*/
+func NewTunnel(options TunnelOptions) *UnicommTunnel {
+	options.TCPOptions = options.TCPOptions.Defaults()
+	if options.RetryInterval == 0 {
+		options.RetryInterval = 5 * time.Second
+	}
+	return &UnicommTunnel{Options: options}
+}
+
+/*
+Returns the token of the options, or the one resolved by the
+credentials provider when set
+*/
+func (ut *UnicommTunnel) token() (credentials.Credentials, error) {
+	if ut.Options.Credentials == nil {
+		return credentials.Credentials{Token: ut.Options.Token}, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ut.Options.DialTimeout)
+	defer cancel()
+
+	resolved, err := credentials.Resolve(ctx, ut.Options.Credentials)
+	if err != nil {
+		return credentials.Credentials{}, err
+	}
+	if strings.ContainsAny(resolved.Token, " \n") {
+		resolved.Wipe()
+		return credentials.Credentials{}, fmt.Errorf("invalid tunnel token")
+	}
+	return resolved, nil
+}
+
+/*
+Dials the rendezvous server and identifies the device
+*/
+func (ut *UnicommTunnel) dial() (*UnicommTCP, error) {
+	secret, err := ut.token()
+	if err != nil {
+		return nil, err
+	}
+	defer secret.Wipe()
+
+	tcp := NewTCP(ut.Options.TCPOptions)
+	if err := tcp.Connect(); err != nil {
+		return nil, err
+	}
+	if ut.Options.TLS != nil {
+		conn := tls.Client(tcp.Connection, ut.Options.TLS)
+		conn.SetDeadline(time.Now().Add(ut.Options.DialTimeout))
+		err := conn.Handshake()
+		conn.SetDeadline(time.Time{})
+		if err != nil {
+			tcp.Disconnect()
+			return nil, err
+		}
+		tcp.Connection = conn
+	}
+
+	hello := fmt.Sprintf("%s %s %s\n", tunnelHello, ut.Options.DeviceID, secret.Token)
+	if _, err := tcp.Connection.Write([]byte(hello)); err != nil {
+		tcp.Disconnect()
+		return nil, err
+	}
+	tcp.Connection.SetReadDeadline(time.Now().Add(ut.Options.DialTimeout))
+	reply, err := readLine(tcp.Connection)
+	tcp.Connection.SetReadDeadline(time.Time{})
+	if err != nil {
+		tcp.Disconnect()
+		return nil, err
+	}
+	if reply != "OK" {
+		tcp.Disconnect()
+		return nil, fmt.Errorf("tunnel rejected: %s", reply)
+	}
+	return tcp, nil
+}
+
+/*
+Establishes the tunnel and keeps it up until Disconnect
+*/
+func (ut *UnicommTunnel) Connect() error {
+	if strings.ContainsAny(ut.Options.DeviceID, " \n") || ut.Options.DeviceID == "" {
+		return fmt.Errorf("invalid tunnel device ID %q", ut.Options.DeviceID)
+	}
+	if strings.ContainsAny(ut.Options.Token, " \n") {
+		return fmt.Errorf("invalid tunnel token")
+	}
+
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
+
+	if ut.stop != nil {
+		return fmt.Errorf("there is a connection already established")
+	}
+	tcp, err := ut.dial()
+	if err != nil {
+		return err
+	}
+	ut.current = tcp
+	ut.stop = make(chan struct{})
+	ut.done = make(chan struct{})
+	ut.wake = make(chan struct{}, 1)
+	go ut.keep(ut.stop, ut.done, ut.wake)
+	return nil
+}
+
+/*
+Dials the tunnel again whenever it is down
+*/
+func (ut *UnicommTunnel) keep(stop chan struct{}, done chan struct{}, wake chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(ut.Options.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-wake:
+		case <-ticker.C:
+		}
+
+		if ut.IsConnected() {
+			continue
+		}
+		tcp, err := ut.dial()
+		if err != nil {
+			continue
+		}
+
+		// Disconnect may have been called while dialing
+		ut.mutex.Lock()
+		if ut.stop == stop && ut.current == nil {
+			ut.current, tcp = tcp, nil
+		}
+		ut.mutex.Unlock()
+		if tcp != nil {
+			tcp.Disconnect()
+		}
+	}
+}
+
+/*
+Closes the tunnel and stops reconnecting
+*/
+func (ut *UnicommTunnel) Disconnect() error {
+	ut.mutex.Lock()
+	if ut.stop == nil {
+		ut.mutex.Unlock()
+		return fmt.Errorf("there is no connection established")
+	}
+	close(ut.stop)
+	done := ut.done
+	ut.stop = nil
+	current := ut.current
+	ut.current = nil
+	ut.mutex.Unlock()
+
+	<-done
+	if current != nil {
+		return current.Disconnect()
+	}
+	return nil
+}
+
+/*
+Returns true if the tunnel is up
+*/
+func (ut *UnicommTunnel) IsConnected() bool {
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
+
+	return ut.current != nil
+}
+
+/*
+Returns the connection of the tunnel, or ErrTunnelDown
+*/
+func (ut *UnicommTunnel) connection() (*UnicommTCP, error) {
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
+
+	if ut.current == nil {
+		if ut.stop == nil {
+			return nil, fmt.Errorf("there is no port connected")
+		}
+		return nil, ErrTunnelDown
+	}
+	return ut.current, nil
+}
+
+/*
+Drops the connection after a fatal error, so the keeper dials
+the tunnel again
+*/
+func (ut *UnicommTunnel) check(tcp *UnicommTCP, err error) {
+	if !unicommio.IsFatal(err) {
+		return
+	}
+
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
+
+	if ut.current != tcp {
+		return
+	}
+	tcp.Connection.Close()
+	ut.current = nil
+	select {
+	case ut.wake <- struct{}{}:
+	default:
+	}
+}
+
+/*
+Reads a number of bytes from the tunnel
+*/
+func (ut *UnicommTunnel) Read(n uint) ([]byte, error) {
+	tcp, err := ut.connection()
+	if err != nil {
+		return nil, err
+	}
+	data, err := tcp.Read(n)
+	ut.check(tcp, err)
+	return data, err
+}
+
+/*
+Reads data from the tunnel until a target delimiter is found
+*/
+func (ut *UnicommTunnel) ReadUntil(delimiter string) ([]byte, error) {
+	tcp, err := ut.connection()
+	if err != nil {
+		return nil, err
+	}
+	data, err := tcp.ReadUntil(delimiter)
+	ut.check(tcp, err)
+	return data, err
+}
+
+/*
+Reads everything currently buffered without waiting for the
+read timeout
+*/
+func (ut *UnicommTunnel) ReadAvailable() ([]byte, error) {
+	tcp, err := ut.connection()
+	if err != nil {
+		return nil, err
+	}
+	data, err := tcp.ReadAvailable()
+	ut.check(tcp, err)
+	return data, err
+}
+
+/*
+Writes an array of bytes to the tunnel
+*/
+func (ut *UnicommTunnel) Write(message []byte) error {
+	tcp, err := ut.connection()
+	if err != nil {
+		return err
+	}
+	err = tcp.Write(message)
+	ut.check(tcp, err)
+	return err
+}
+
+/*
+Writes an array of bytes through the tunnel as is, without the
+end delimiter
+*/
+func (ut *UnicommTunnel) WriteRaw(message []byte) error {
+	tcp, err := ut.connection()
+	if err != nil {
+		return err
+	}
+	err = tcp.WriteRaw(message)
+	ut.check(tcp, err)
+	return err
+}
+
+/*
+Starts a rendezvous server listening on the address, e.g.
+":7000". The tokens are received in clear text, so untrusted
+networks need ListenRendezvousTLS
+*/
+func ListenRendezvous(address string, token string) (*Rendezvous, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	return serveRendezvous(listener, token), nil
+}
+
+/*
+Starts a rendezvous server accepting tunnels over TLS, which
+the devices dial with the TLS option
+*/
+func ListenRendezvousTLS(address string, token string, config *tls.Config) (*Rendezvous, error) {
+	listener, err := tls.Listen("tcp", address, config)
+	if err != nil {
+		return nil, err
+	}
+	return serveRendezvous(listener, token), nil
+}
+
+/*
+Accepts the tunnels arriving at the listener
+*/
+func serveRendezvous(listener net.Listener, token string) *Rendezvous {
+	rendezvous := &Rendezvous{
+		Token:    token,
+		listener: listener,
+		tunnels:  make(map[string]net.Conn),
+		arrived:  make(chan struct{}),
+	}
+	go rendezvous.accept()
+	return rendezvous
+}
+
+/*
+Returns the address the server is listening on
+*/
+func (r *Rendezvous) Addr() net.Addr {
+	return r.listener.Addr()
+}
+
+/*
+Accepts tunnels until the server is closed
+*/
+func (r *Rendezvous) accept() {
+	for {
+		conn, err := r.listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			continue
+		}
+		go r.handshake(conn)
+	}
+}
+
+/*
+Reads the hello of a tunnel and registers it under its device
+ID, replacing a previous tunnel of the same device
+*/
+func (r *Rendezvous) handshake(conn net.Conn) {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := readLine(conn)
+	conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	fields := strings.Split(line, " ")
+	if len(fields) != 3 || fields[0] != tunnelHello || fields[1] == "" {
+		conn.Write([]byte("ERR invalid hello\n"))
+		conn.Close()
+		return
+	}
+	// Compared in constant time, so the token is not guessed from the
+	// time taken to reject it
+	if r.Token != "" && subtle.ConstantTimeCompare([]byte(fields[2]), []byte(r.Token)) != 1 {
+		conn.Write([]byte("ERR invalid token\n"))
+		conn.Close()
+		return
+	}
+	if _, err := conn.Write([]byte("OK\n")); err != nil {
+		conn.Close()
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if previous, ok := r.tunnels[fields[1]]; ok {
+		previous.Close()
+	}
+	r.tunnels[fields[1]] = conn
+	close(r.arrived)
+	r.arrived = make(chan struct{})
+}
+
+/*
+Reads a line byte by byte, so no data sent after it is consumed
+*/
+func readLine(conn net.Conn) (string, error) {
+	var line []byte
+	buffer := make([]byte, 1)
+	for len(line) < 256 {
+		if _, err := conn.Read(buffer); err != nil {
+			return "", err
+		}
+		if buffer[0] == '\n' {
+			return strings.TrimSpace(string(line)), nil
+		}
+		line = append(line, buffer[0])
+	}
+	return "", fmt.Errorf("tunnel line too long")
+}
+
+/*
+Returns the IDs of the devices with a tunnel waiting
+*/
+func (r *Rendezvous) Devices() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	devices := make([]string, 0, len(r.tunnels))
+	for device := range r.tunnels {
+		devices = append(devices, device)
+	}
+	return devices
+}
+
+/*
+Takes the tunnel of a device, waiting up to the timeout for it
+to arrive, and returns a TCP instance over it. The options set
+the timeouts and the delimiter, the address is ignored. When the
+tunnel drops, the device dials again and Dial must be called to
+reach it
+*/
+func (r *Rendezvous) Dial(deviceID string, options TCPOptions, timeout time.Duration) (*UnicommTCP, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		r.mutex.Lock()
+		conn, ok := r.tunnels[deviceID]
+		delete(r.tunnels, deviceID)
+		arrived := r.arrived
+		r.mutex.Unlock()
+
+		// Tunnels closed by the device while waiting are discarded,
+		// the device dials a new one
+		if ok && tunnelAlive(conn) {
+			return NewTCPFromConn(conn, options), nil
+		}
+		if ok {
+			conn.Close()
+			continue
+		}
+		select {
+		case <-arrived:
+		case <-deadline.C:
+			return nil, fmt.Errorf("device %q has no tunnel", deviceID)
+		}
+	}
+}
+
+/*
+Returns false if the device closed the tunnel, peeking below
+the TLS layer of encrypted tunnels
+*/
+func tunnelAlive(conn net.Conn) bool {
+	if encrypted, ok := conn.(*tls.Conn); ok {
+		conn = encrypted.NetConn()
+	}
+	return peekSocket(conn) == nil
+}
+
+/*
+Stops accepting tunnels and closes the ones waiting
+*/
+func (r *Rendezvous) Close() error {
+	err := r.listener.Close()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for device, conn := range r.tunnels {
+		conn.Close()
+		delete(r.tunnels, device)
+	}
+	return err
+}
@@ -1,88 +1,157 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 23rd, 2025
-Last update: September 23rd, 2025
+Last update: October 15th, 2026
 */
 
 package unicommtcp
 
 import (
+	"errors"
 	"fmt"
 	"net"
+	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
 )
 
 type TCPOptions struct {
-	Host         string
-	Port         uint
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	EndDelimiter string
+	Host              string
+	Port              uint
+	ReadTimeout       time.Duration // Maximum silence between received chunks
+	WriteTimeout      time.Duration
+	MaxReadDuration   time.Duration // Upper bound for a ReadUntil, defaults to 5s
+	EndDelimiter      string
+	MaxBytesPerSecond uint // Outbound rate limit, zero disables it
+	BurstBytes        uint // Bytes allowed in a burst, defaults to one second
+
+	Network       string        // "tcp" (dual-stack), "tcp4" or "tcp6"
+	DialTimeout   time.Duration // Timeout to establish the connection
+	FallbackDelay time.Duration // Wait before racing the other address family
 }
 
 type UnicommTCP struct {
 	Options    TCPOptions
 	Connection net.Conn
 
-	mutex sync.Mutex
+	mutex      sync.Mutex
+	writeMutex sync.Mutex // Keep the chunks of a message together, without blocking reads
+	limiter    *tokenBucket
+	pending    []byte // Bytes received after the last delimiter
 }
 
 /*
 Creates a new instance of Unicomm TCP communication
 */
 func NewTCP(options TCPOptions) *UnicommTCP {
-	if options.ReadTimeout == 0 {
-		options.ReadTimeout = 100 * time.Millisecond
-	}
-	if options.WriteTimeout == 0 {
-		options.WriteTimeout = 100 * time.Millisecond
-	}
-	return &UnicommTCP{
+	options = options.Defaults()
+	tcp := &UnicommTCP{
 		Options: options,
 	}
+	if options.MaxBytesPerSecond > 0 {
+		tcp.limiter = newTokenBucket(options.MaxBytesPerSecond, options.BurstBytes)
+	}
+	return tcp
 }
 
 /*
-Returns true if TCP connection is established
+Creates a Unicomm TCP instance over a connection established
+by the caller, e.g. through a proxy or a TLS tunnel, reusing the
+framing and timeout logic of the TCP backend
+*/
+func NewTCPFromConn(conn net.Conn, options TCPOptions) *UnicommTCP {
+	tcp := NewTCP(options)
+	tcp.Connection = conn
+	return tcp
+}
+
+/*
+Applies the options that can change while connected, which are
+the timeouts and the delimiter. Other options are ignored and
+only take effect on a new instance
+*/
+func (ut *UnicommTCP) Reconfigure(options TCPOptions) {
+	options = options.Defaults()
+
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
+
+	ut.Options.ReadTimeout = options.ReadTimeout
+	ut.Options.WriteTimeout = options.WriteTimeout
+	ut.Options.MaxReadDuration = options.MaxReadDuration
+	ut.Options.EndDelimiter = options.EndDelimiter
+}
+
+/*
+Returns true if TCP connection is established and was not
+closed by the peer, checked without transmitting
 */
 func (ut *UnicommTCP) IsConnected() bool {
-	if ut.Connection == nil {
-		return false
-	}
+	return ut.ProbeLink() == nil
+}
 
+/*
+Checks the connection without transmitting a byte. Sockets are
+peeked without consuming data, which reports a close or a reset
+of the peer, while connections established by the caller over
+other transports are only checked locally
+*/
+func (ut *UnicommTCP) ProbeLink() error {
 	ut.mutex.Lock()
 	defer ut.mutex.Unlock()
 
-	if _, err := ut.Connection.Write([]byte{}); err != nil {
-		return false
+	if ut.Connection == nil {
+		return fmt.Errorf("there is no port connected")
+	}
+
+	if len(ut.pending) > 0 {
+		return nil
+	}
+	if err := peekSocket(ut.Connection); err != nil {
+		return fmt.Errorf("tcp link lost: %w", err)
 	}
-	return true
+	return nil
 }
 
 /*
-Establishes a connection with the desired host and port
+Establishes a connection with the desired host and port. A
+connection closed by the peer is released and dialed again
 */
 func (ut *UnicommTCP) Connect() error {
-	var url string
-	host := ut.Options.Host
-	port := ut.Options.Port
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
 
-	if ut.IsConnected() {
-		return fmt.Errorf("there is a connection already established")
+	// Reconfigure may change the options meanwhile, so a copy is used
+	options := ut.Options
+	if err := options.Validate(); err != nil {
+		return err
 	}
 
-	if strings.Contains(host, ":") {
-		url = fmt.Sprintf("[%s]:%d", host, port)
-	} else {
-		url = fmt.Sprintf("%s:%d", host, port)
+	// IPv6 literals may be given with brackets and zone, e.g. [fe80::1%eth0]
+	host := strings.TrimSuffix(strings.TrimPrefix(options.Host, "["), "]")
+	address := net.JoinHostPort(host, strconv.FormatUint(uint64(options.Port), 10))
+
+	// Hosts resolving to both address families are dialed using happy
+	// eyeballs, racing the fallback family after the fallback delay
+	dialer := net.Dialer{
+		Timeout:       options.DialTimeout,
+		FallbackDelay: options.FallbackDelay,
 	}
 
-	ut.mutex.Lock()
-	defer ut.mutex.Unlock()
+	if ut.Connection != nil {
+		if len(ut.pending) > 0 || peekSocket(ut.Connection) == nil {
+			return fmt.Errorf("there is a connection already established")
+		}
+		// The peer closed the connection, which is released to dial again
+		ut.Connection.Close()
+		ut.Connection = nil
+	}
 
-	connection, err := net.DialTimeout("tcp", url, 500*time.Millisecond)
+	connection, err := dialer.Dial(options.Network, address)
 	if err != nil {
 		ut.Connection = nil
 		return err
@@ -96,13 +165,13 @@ func (ut *UnicommTCP) Connect() error {
 Closes the connection with the current TCP server
 */
 func (ut *UnicommTCP) Disconnect() error {
-	if !ut.IsConnected() {
-		return fmt.Errorf("there is no connection established")
-	}
-
 	ut.mutex.Lock()
 	defer ut.mutex.Unlock()
 
+	if ut.Connection == nil {
+		return fmt.Errorf("there is no connection established")
+	}
+
 	if err := ut.Connection.Close(); err != nil {
 		return err
 	}
@@ -116,95 +185,246 @@ Reads a number of bytes from the TCP server
 */
 func (ut *UnicommTCP) Read(n uint) ([]byte, error) {
 	buffer := make([]byte, n)
+	nReaded, err := ut.ReadInto(buffer)
+	return buffer[:nReaded], err
+}
 
-	if !ut.IsConnected() {
-		return nil, fmt.Errorf("there is no port connected")
+/*
+Reads into a buffer provided by the caller, so polling loops
+can reuse it instead of allocating on every read. Like serial
+ports, nothing received before the read timeout is not an error
+*/
+func (ut *UnicommTCP) ReadInto(buffer []byte) (int, error) {
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
+
+	if ut.Connection == nil {
+		return 0, fmt.Errorf("there is no port connected")
+	}
+
+	if len(ut.pending) > 0 {
+		nReaded := copy(buffer, ut.pending)
+		ut.pending = ut.pending[nReaded:]
+		return nReaded, nil
 	}
 
-	ut.mutex.Lock()
 	timeout := time.Now().Add(ut.Options.ReadTimeout)
 	ut.Connection.SetReadDeadline(timeout)
 	nReaded, err := ut.Connection.Read(buffer)
-	ut.mutex.Unlock()
-
-	if err != nil {
-		return nil, err
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return nReaded, nil
 	}
-	return buffer[:nReaded], nil
+	return nReaded, err
 }
 
 /*
-Reads data from the TCP server until a target
-delimiter is found
+Reads everything currently buffered by the operating system
+without waiting for the read timeout
 */
-func (ut *UnicommTCP) ReadUntil(endDelimiter string) ([]byte, error) {
+func (ut *UnicommTCP) ReadAvailable() ([]byte, error) {
 	buffer := make([]byte, 0)
-	singleByte := make([]byte, 1)
-	errorChan := make(chan error, 1)
-	resultChan := make(chan []byte, 1)
+	scratch := unicommio.GetChunk()
+	defer unicommio.PutChunk(scratch)
+	chunk := *scratch
 
-	if !ut.IsConnected() {
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
+
+	if ut.Connection == nil {
 		return nil, fmt.Errorf("there is no port connected")
 	}
 
+	buffer = append(buffer, ut.pending...)
+	ut.pending = nil
+	for {
+		// A deadline in the past would fail before reading buffered data
+		ut.Connection.SetReadDeadline(time.Now().Add(time.Millisecond))
+		nReaded, err := ut.Connection.Read(chunk)
+		buffer = append(buffer, chunk[:nReaded]...)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return buffer, nil
+		}
+		if err != nil {
+			return buffer, err
+		}
+	}
+}
+
+/*
+Returns when a read must end according to MaxReadDuration, so a
+peer trickling bytes without the delimiter cannot hold the
+connection forever
+*/
+func (ut *UnicommTCP) readLimit() time.Time {
+	if ut.Options.MaxReadDuration > 0 {
+		return time.Now().Add(ut.Options.MaxReadDuration)
+	}
+	return time.Time{}
+}
+
+/*
+Returns the deadline of the next chunk. The deadline is refreshed
+on every chunk, so slow responses that keep arriving are not cut
+off before the limit
+*/
+func (ut *UnicommTCP) chunkDeadline(limit time.Time) time.Time {
+	deadline := time.Now().Add(ut.Options.ReadTimeout)
+	if !limit.IsZero() && deadline.After(limit) {
+		return limit
+	}
+	return deadline
+}
+
+/*
+Reads data from the TCP server until a target
+delimiter is found. Data is read in chunks and the bytes
+received after the delimiter are kept for the next read.
+The read timeout applies to the silence between chunks
+*/
+func (ut *UnicommTCP) ReadUntil(endDelimiter string) ([]byte, error) {
+	scratch := unicommio.GetChunk()
+	defer unicommio.PutChunk(scratch)
+	chunk := (*scratch)[:unicommio.ReadChunkSize]
+
 	ut.mutex.Lock()
 	defer ut.mutex.Unlock()
 
-	timeout := time.Now().Add(ut.Options.ReadTimeout)
-	ut.Connection.SetReadDeadline(timeout)
+	if ut.Connection == nil {
+		return nil, fmt.Errorf("there is no port connected")
+	}
 
-	go func() {
-		for {
-			nReaded, err := ut.Connection.Read(singleByte)
-			if err != nil {
-				errorChan <- err
-				return
-			}
-			if nReaded == 1 {
-				buffer = append(buffer, singleByte...)
-			}
-			if nReaded == 0 || strings.Contains(string(buffer), endDelimiter) {
-				resultChan <- buffer
-				return
-			}
+	buffer := ut.pending
+	ut.pending = nil
+
+	limit := ut.readLimit()
+	for {
+		if frame, rest, ok := unicommio.SplitFrame(buffer, endDelimiter); ok {
+			ut.pending = rest
+			return frame, nil
 		}
-	}()
 
-	select {
-	case err := <-errorChan:
-		return nil, err
-	case result := <-resultChan:
-		return result, nil
-	case <-time.After(ut.Options.ReadTimeout):
-		return buffer, fmt.Errorf("read until timeout")
+		ut.Connection.SetReadDeadline(ut.chunkDeadline(limit))
+		nReaded, err := ut.Connection.Read(chunk)
+		buffer = append(buffer, chunk[:nReaded]...)
+		if err == nil {
+			continue
+		}
+
+		// The last chunk may complete the frame along with the error
+		if frame, rest, ok := unicommio.SplitFrame(buffer, endDelimiter); ok {
+			ut.pending = rest
+			return frame, nil
+		}
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return buffer, unicommio.TimeoutError("read until")
+		}
+		return buffer, err
 	}
 }
 
 /*
-Writes an array of bytes to the serial port
+Returns the end delimiter appended by Write
+*/
+func (ut *UnicommTCP) EndDelimiter() string {
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
+
+	return ut.Options.EndDelimiter
+}
+
+/*
+Writes an array of bytes to the TCP server, appending the end
+delimiter when the message does not end with it
 */
 func (ut *UnicommTCP) Write(message []byte) error {
 	msgStr := string(message)
+	ut.mutex.Lock()
 	endDelimiter := ut.Options.EndDelimiter
+	ut.mutex.Unlock()
 
-	if !ut.IsConnected() {
-		return fmt.Errorf("there is no port connected")
-	}
 	if endDelimiter != "" && !strings.HasSuffix(msgStr, endDelimiter) {
 		message = append(message, []byte(endDelimiter)...)
 	}
+	return ut.WriteRaw(message)
+}
+
+/*
+Writes an array of bytes to the TCP server as is, without the
+end delimiter. Short writes are continued until the write
+timeout, and when the message is not sent completely a
+PartialWriteError reports the bytes committed. Writes wait for
+the rate limit without holding the lock of the reads
+*/
+func (ut *UnicommTCP) WriteRaw(message []byte) error {
+	ut.writeMutex.Lock()
+	defer ut.writeMutex.Unlock()
 
 	ut.mutex.Lock()
-	timeout := time.Now().Add(ut.Options.WriteTimeout)
-	ut.Connection.SetReadDeadline(timeout)
-	nWrited, err := ut.Connection.Write(message)
+	conn, timeout := ut.Connection, ut.Options.WriteTimeout
 	ut.mutex.Unlock()
-
-	if err != nil {
-		return err
+	if conn == nil {
+		return fmt.Errorf("there is no port connected")
 	}
+
+	nWrited, err := ut.write(conn, message, timeout)
+
 	if nWrited != len(message) {
-		return fmt.Errorf("writed %d bytes, expected %d", nWrited, len(message))
+		return &unicommio.PartialWriteError{Written: nWrited, Expected: len(message), Err: err}
 	}
-	return nil
+	return err
+}
+
+/*
+Sends the message through the connection, splitting it in chunks
+that respect the outbound rate limit when it is configured
+*/
+func (ut *UnicommTCP) write(conn net.Conn, message []byte, timeout time.Duration) (int, error) {
+	// An expired write deadline would also fail the connection check
+	defer conn.SetWriteDeadline(time.Time{})
+
+	if ut.limiter == nil {
+		return writeFull(conn, message, time.Now().Add(timeout))
+	}
+
+	nWrited := 0
+	chunkSize := ut.limiter.chunkSize()
+	for nWrited < len(message) {
+		chunk := message[nWrited:min(nWrited+chunkSize, len(message))]
+		time.Sleep(ut.limiter.reserve(len(chunk)))
+
+		n, err := writeFull(conn, chunk, time.Now().Add(timeout))
+		nWrited += n
+		if err != nil {
+			return nWrited, err
+		}
+	}
+	return nWrited, nil
+}
+
+/*
+Writes the data, continuing after short writes and temporary
+errors as long as the deadline has not expired. Other errors
+stop the write when the connection makes no progress
+*/
+func writeFull(conn net.Conn, data []byte, deadline time.Time) (int, error) {
+	nWrited := 0
+	for nWrited < len(data) {
+		conn.SetWriteDeadline(deadline)
+		n, err := conn.Write(data[nWrited:])
+		nWrited += n
+		if err == nil {
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nWrited, err
+		}
+		if unicommio.IsTemporary(err) {
+			time.Sleep(unicommio.RetryDelay)
+			continue
+		}
+		if n == 0 {
+			return nWrited, err
+		}
+	}
+	return nWrited, nil
 }
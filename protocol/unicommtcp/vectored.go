@@ -0,0 +1,86 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtcp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+/*
+Reads at least minimum and at most maximum bytes, asking the
+socket for everything up to maximum on each read, so messages of
+a known size arrive in as few system calls as possible. Bytes
+received before a timeout are returned along with it
+*/
+func (ut *UnicommTCP) ReadAtLeast(minimum uint, maximum uint) ([]byte, error) {
+	if maximum < minimum {
+		return nil, fmt.Errorf("invalid sizes: maximum %d is lower than minimum %d", maximum, minimum)
+	}
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
+
+	if ut.Connection == nil {
+		return nil, fmt.Errorf("there is no port connected")
+	}
+
+	buffer := make([]byte, maximum)
+	nReaded := copy(buffer, ut.pending)
+	ut.pending = ut.pending[nReaded:]
+
+	limit := ut.readLimit()
+	for nReaded < int(minimum) {
+		ut.Connection.SetReadDeadline(ut.chunkDeadline(limit))
+		nChunk, err := ut.Connection.Read(buffer[nReaded:])
+		nReaded += nChunk
+		if err == nil || nReaded >= int(minimum) {
+			continue
+		}
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return buffer[:nReaded], unicommio.TimeoutError("read at least")
+		}
+		return buffer[:nReaded], err
+	}
+	return buffer[:nReaded], nil
+}
+
+/*
+Fills the buffers in order, e.g. a fixed-size header and its
+payload, with a single readv system call when the data is
+already waiting. Returns the number of bytes read, which is less
+than the size of the buffers only along with an error
+*/
+func (ut *UnicommTCP) ReadVectored(buffers [][]byte) (int, error) {
+	ut.mutex.Lock()
+	defer ut.mutex.Unlock()
+
+	if ut.Connection == nil {
+		return 0, fmt.Errorf("there is no port connected")
+	}
+
+	size := unicommio.VectorSize(buffers)
+	nReaded := unicommio.Scatter(buffers, ut.pending)
+	ut.pending = ut.pending[nReaded:]
+
+	limit := ut.readLimit()
+	for nReaded < size {
+		ut.Connection.SetReadDeadline(ut.chunkDeadline(limit))
+		nChunk, err := readVectored(ut.Connection, unicommio.Advance(buffers, nReaded))
+		nReaded += nChunk
+		if err == nil || nReaded == size {
+			continue
+		}
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return nReaded, unicommio.TimeoutError("read vectored")
+		}
+		return nReaded, err
+	}
+	return nReaded, nil
+}
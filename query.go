@@ -0,0 +1,262 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Client struct {
+	Unicomm
+	Delimiter string          // Delimiter that ends each response
+	Cache     *ResponseCache  // Responses of idempotent queries, optional
+	Monitor   *LatencyMonitor // Records the latency of queries, optional
+
+	latency time.Duration // Round trip of the last query
+	mutex   sync.Mutex    // Keep write and read of a query together
+}
+
+/*
+Creates a client that performs request/response transactions
+over a Unicomm instance. Queries are recorded by the latency
+monitor of the instance, if any
+*/
+func NewClient(comm Unicomm, delimiter string) *Client {
+	monitor, _ := As[*LatencyMonitor](comm)
+	return &Client{
+		Unicomm:   comm,
+		Delimiter: delimiter,
+		Monitor:   monitor,
+	}
+}
+
+/*
+Records the latency of a query, failed ones included
+*/
+func (c *Client) observe(start time.Time, err error) {
+	if c.Monitor != nil {
+		c.Monitor.Observe(OpQuery, time.Since(start), err)
+	}
+}
+
+/*
+Writes a command and reads the response until the delimiter.
+No other query can interleave between the write and the read
+*/
+func (c *Client) Query(command []byte) ([]byte, error) {
+	if c.Cache != nil {
+		if response, ok := c.Cache.lookup(string(command)); ok {
+			return response, nil
+		}
+	}
+	return c.QueryFresh(command)
+}
+
+/*
+Sends a query bypassing the cache, refreshing the cached
+response when the command is cacheable
+*/
+func (c *Client) QueryFresh(command []byte) ([]byte, error) {
+	response, _, err := c.QueryTimed(command)
+	if err == nil && c.Cache != nil {
+		c.Cache.store(string(command), response)
+	}
+	return response, err
+}
+
+/*
+Sends a query and also returns its round-trip latency, measured
+from the start of the write to the end of the response
+*/
+func (c *Client) QueryTimed(command []byte) ([]byte, time.Duration, error) {
+	response, _, roundTrip, err := c.queryTimed(command)
+	return response, roundTrip, err
+}
+
+/*
+Sends a query and returns when its write started along with its
+round trip, both taken once no other query holds the client
+*/
+func (c *Client) queryTimed(command []byte) ([]byte, time.Time, time.Duration, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	start := time.Now()
+	if err := c.Unicomm.Write(command); err != nil {
+		c.observe(start, err)
+		return nil, start, 0, err
+	}
+	response, err := ReadUntil(c.Unicomm, c.Delimiter)
+	c.observe(start, err)
+	if err != nil {
+		return response, start, 0, err
+	}
+	c.latency = time.Since(start)
+	return response, start, c.latency, nil
+}
+
+/*
+A command of a batch. Timeout bounds the wait for its response,
+which may exceed the read timeout of the instance, and zero
+waits for a single read timeout
+*/
+type Command struct {
+	Data       []byte
+	Delimiter  string // Overrides the delimiter of the client
+	Timeout    time.Duration
+	NoResponse bool // The command is only written
+}
+
+type Response struct {
+	Data    []byte
+	Latency time.Duration
+}
+
+/*
+Sends the commands in order under one transaction, so no other
+query interleaves. The batch stops at the first failure, returning
+the responses received so far along with the error
+*/
+func (c *Client) QueryBatch(commands []Command) ([]Response, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	responses := make([]Response, 0, len(commands))
+	for index, command := range commands {
+		start := time.Now()
+		if err := c.Unicomm.Write(command.Data); err != nil {
+			return responses, fmt.Errorf("command %d failed: %w", index, err)
+		}
+		if command.NoResponse {
+			responses = append(responses, Response{})
+			continue
+		}
+
+		delimiter := command.Delimiter
+		if delimiter == "" {
+			delimiter = c.Delimiter
+		}
+		data, err := c.readResponse(delimiter, start.Add(command.Timeout))
+		c.observe(start, err)
+		if err != nil {
+			return responses, fmt.Errorf("command %d failed: %w", index, err)
+		}
+		c.latency = time.Since(start)
+		responses = append(responses, Response{Data: data, Latency: c.latency})
+	}
+	return responses, nil
+}
+
+/*
+Reads a response until the delimiter, retrying after read
+timeouts until the deadline. Must be called with the mutex locked
+*/
+func (c *Client) readResponse(delimiter string, deadline time.Time) ([]byte, error) {
+	buffered := NewBuffered(c.Unicomm)
+	for {
+		data, err := buffered.ReadUntil(delimiter)
+		if err == nil || !isTimeout(err) || !time.Now().Before(deadline) {
+			return data, err
+		}
+		buffered.Unread(data)
+	}
+}
+
+/*
+Returns the round-trip latency of the last successful query
+*/
+func (c *Client) Latency() time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.latency
+}
+
+/*
+Sends a query and returns the response without the delimiter
+*/
+func (c *Client) QueryBytes(command []byte) ([]byte, error) {
+	response, err := c.Query(command)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(response, []byte(c.Delimiter)), nil
+}
+
+/*
+Sends a query and returns the response without the delimiter
+and surrounding whitespaces
+*/
+func (c *Client) QueryString(command string) (string, error) {
+	response, err := c.QueryBytes([]byte(command))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(response)), nil
+}
+
+/*
+Sends a query and parses the response as a float number
+*/
+func (c *Client) QueryFloat(command string) (float64, error) {
+	response, err := c.QueryString(command)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseFloat(response, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid float response %q: %w", response, err)
+	}
+	return value, nil
+}
+
+/*
+Sends a query and parses the response as a decimal integer, or
+as hexadecimal with the 0x prefix. Leading zeros are decimal
+*/
+func (c *Client) QueryInt(command string) (int64, error) {
+	response, err := c.QueryString(command)
+	if err != nil {
+		return 0, err
+	}
+	value, err := parseInt(response)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer response %q: %w", response, err)
+	}
+	return value, nil
+}
+
+/*
+Parses a signed integer in base 10, or in base 16 when the digits
+begin with 0x
+*/
+func parseInt(text string) (int64, error) {
+	digits := strings.TrimLeft(text, "+-")
+	if len(digits) > 2 && (digits[:2] == "0x" || digits[:2] == "0X") {
+		return strconv.ParseInt(text[:len(text)-len(digits)]+digits[2:], 16, 64)
+	}
+	return strconv.ParseInt(text, 10, 64)
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (c *Client) WriteRaw(message []byte) error {
+	return writeRaw(c.Unicomm, message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (c *Client) Unwrap() Unicomm {
+	return c.Unicomm
+}
@@ -0,0 +1,79 @@
+package unicomm_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestQueryBatch(t *testing.T) {
+	// The gain calibration takes longer than the read timeout
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte {
+			command := strings.TrimSpace(string(request))
+			if command == "CAL:GAIN" {
+				time.Sleep(150 * time.Millisecond)
+			}
+			if command == "FAIL" {
+				return nil
+			}
+			return []byte(command + " OK\n")
+		},
+	})
+	options := server.TCPOptions()
+	options.ReadTimeout = 50 * time.Millisecond
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: options})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	client := unicomm.NewClient(comm, "\n")
+	responses, err := client.QueryBatch([]unicomm.Command{
+		{Data: []byte("CAL:ZERO\n")},
+		{Data: []byte("CAL:GAIN\n"), Timeout: time.Second},
+		{Data: []byte("FAIL\n")},
+		{Data: []byte("CAL:SAVE\n")},
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "command 2 failed") {
+		t.Fatalf("expected the third command to fail, got %v", err)
+	}
+	if len(responses) != 2 || string(responses[1].Data) != "CAL:GAIN OK\n" {
+		t.Fatalf("unexpected responses %q", responses)
+	}
+	if received := string(server.Received()); strings.Contains(received, "CAL:SAVE") {
+		t.Fatalf("commands after the failure were sent: %q", received)
+	}
+}
+
+func TestQueryInt(t *testing.T) {
+	responses := map[string]string{
+		"A": "010", "B": "09", "C": "0x1F", "D": "-5", "E": "-0x10", "F": "1.5", "G": "0x",
+	}
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte {
+			return []byte(responses[strings.TrimSpace(string(request))] + "\n")
+		},
+	})
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions()})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	client := unicomm.NewClient(comm, "\n")
+	for command, want := range map[string]int64{"A": 10, "B": 9, "C": 31, "D": -5, "E": -16} {
+		value, err := client.QueryInt(command + "\n")
+		if err != nil || value != want {
+			t.Fatalf("%s: expected %d, got %d, %v", responses[command], want, value, err)
+		}
+	}
+	for _, command := range []string{"F", "G"} {
+		if value, err := client.QueryInt(command + "\n"); err == nil {
+			t.Fatalf("%s: expected an error, got %d", responses[command], value)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+type OverflowPolicy uint8
+
+type ReaderOptions struct {
+	Delimiter  string         // Delimiter that ends each frame
+	QueueSize  uint           // Maximum number of queued frames
+	Overflow   OverflowPolicy // What to do when the queue is full
+	RetryDelay time.Duration  // Wait after a failed read
+	OnError    func(error)    // Called for errors other than timeouts
+	Recovery   RecoveryPolicy // What to do after a panic while reading
+
+	// Sampling applied before delivery, to protect slow consumers
+	// from high-rate sensors
+	KeepEvery uint    // Delivers one of every N frames, zero keeps all
+	MaxRate   float64 // Maximum frames delivered per second, zero disables it
+
+	// Filters applied before sampling, so consumers only receive the
+	// frames they care about
+	Include []FrameMatcher // Frames must match one of these, when any
+	Exclude []FrameMatcher // Frames matching any of these are discarded
+
+	// Handling of the frame cut when the connection is lost, nil
+	// keeps the partial frame to be completed after the reconnect
+	Reassembly *ReassemblyOptions
+}
+
+/*
+Resynchronizes the frames after the connection is lost, e.g.
+while a session reconnects. The partial frame received before is
+delivered with ErrFrameCut or discarded, and the bytes received
+after the reconnect are discarded up to the next start delimiter,
+since the beginning of their frame was lost
+*/
+type ReassemblyOptions struct {
+	StartDelimiter string       // Begins each frame, required
+	KeepPartial    bool         // Delivers the partial frame with ErrFrameCut instead of discarding it
+	OnDiscard      func([]byte) // Called with the bytes discarded, so data loggers know what was lost
+}
+
+type QueueStats struct {
+	Depth    int    // Frames waiting to be consumed
+	Capacity int    // Maximum number of queued frames
+	Received uint64 // Frames read from the device
+	Dropped  uint64 // Frames discarded by the overflow policy
+	Sampled  uint64 // Frames discarded by sampling
+	Filtered uint64 // Frames discarded by the filters
+	Cut      uint64 // Frames cut by a lost connection
+	Lost     uint64 // Bytes discarded while resynchronizing
+}
+
+/*
+Frame read from the device along with the context it was
+received in
+*/
+type Frame struct {
+	Payload   []byte
+	Raw       []byte    // Bytes received on the link, framing included
+	Started   time.Time // When the first byte was received, zero if unknown
+	Received  time.Time // When the end of the frame was received
+	Delimiter string    // Delimiter that ended the frame, if any
+	Err       error     // Validation failure of the frame, nil if valid
+}
+
+type BackgroundReader struct {
+	Options ReaderOptions
+
+	comm     *Buffered
+	queue    chan Frame
+	stop     chan struct{}
+	done     chan struct{}
+	received atomic.Uint64
+	dropped  atomic.Uint64
+	sampled  atomic.Uint64
+	filtered atomic.Uint64
+	cut      atomic.Uint64
+	lost     atomic.Uint64
+	resync   bool       // Waiting for a start delimiter after a lost connection
+	started  time.Time  // Arrival of the first byte of the next frame, zero if none yet
+	counter  uint64     // Frames seen by the sampler
+	last     time.Time  // Delivery time of the last sampled frame
+	mutex    sync.Mutex // Protect start and stop
+}
+
+/*
+Error of the partial frames delivered when the connection was
+lost before their end
+*/
+var ErrFrameCut = errors.New("frame cut by a lost connection")
+
+const (
+	DropOldest OverflowPolicy = 0
+	DropNewest OverflowPolicy = 1
+	Block      OverflowPolicy = 2
+)
+
+/*
+Returns true if the frame passed validation
+*/
+func (f Frame) Valid() bool {
+	return f.Err == nil
+}
+
+/*
+Creates a reader that continuously reads delimited frames from
+a Unicomm instance and buffers them in a bounded queue
+*/
+func NewBackgroundReader(comm Unicomm, options ReaderOptions) *BackgroundReader {
+	if options.QueueSize == 0 {
+		options.QueueSize = 64
+	}
+	if options.RetryDelay == 0 {
+		options.RetryDelay = 100 * time.Millisecond
+	}
+	return &BackgroundReader{
+		Options: options,
+		comm:    NewBuffered(comm),
+		queue:   make(chan Frame, options.QueueSize),
+	}
+}
+
+/*
+Returns true if the error was caused by a read or write
+timeout
+*/
+func isTimeout(err error) bool {
+	return unicommio.IsTimeout(err)
+}
+
+/*
+Starts reading frames in background
+*/
+func (br *BackgroundReader) Start() error {
+	br.mutex.Lock()
+	defer br.mutex.Unlock()
+
+	if br.stop != nil {
+		return fmt.Errorf("background reader is already running")
+	}
+	br.stop = make(chan struct{})
+	br.done = make(chan struct{})
+	go br.run(br.stop, br.done)
+	return nil
+}
+
+/*
+Stops reading frames and waits for the current read to finish.
+Frames already queued can still be consumed
+*/
+func (br *BackgroundReader) Stop() error {
+	br.mutex.Lock()
+	defer br.mutex.Unlock()
+
+	if br.stop == nil {
+		return fmt.Errorf("background reader is not running")
+	}
+	close(br.stop)
+	<-br.done
+	br.stop = nil
+	return nil
+}
+
+/*
+Returns the channel where complete frames are delivered
+*/
+func (br *BackgroundReader) Frames() <-chan Frame {
+	return br.queue
+}
+
+/*
+Waits for the next frame up to the timeout
+*/
+func (br *BackgroundReader) Next(timeout time.Duration) (Frame, error) {
+	select {
+	case frame := <-br.queue:
+		return frame, nil
+	case <-time.After(timeout):
+		return Frame{}, unicommio.TimeoutError("next frame")
+	}
+}
+
+/*
+Returns the queue depth and the frame counters
+*/
+func (br *BackgroundReader) Stats() QueueStats {
+	return QueueStats{
+		Depth:    len(br.queue),
+		Capacity: cap(br.queue),
+		Received: br.received.Load(),
+		Dropped:  br.dropped.Load(),
+		Sampled:  br.sampled.Load(),
+		Filtered: br.filtered.Load(),
+		Cut:      br.cut.Load(),
+		Lost:     br.lost.Load(),
+	}
+}
+
+/*
+Reads frames until the reader is stopped
+*/
+func (br *BackgroundReader) run(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		if br.resync && !br.synchronize() {
+			continue
+		}
+
+		payload, err := guard(func() ([]byte, error) {
+			if err := br.awaitFrame(); err != nil {
+				return nil, err
+			}
+			return br.comm.ReadUntil(br.Options.Delimiter)
+		})
+		received := time.Now()
+		if err != nil {
+			if br.linkLost(err) {
+				br.started = time.Time{}
+				br.cutFrame(payload, received, stop)
+			} else {
+				// Partial frames are kept to be completed by the next read
+				br.comm.Unread(payload)
+			}
+			if !isTimeout(err) {
+				report(br.Options.OnError, err)
+				var panicErr *PanicError
+				if errors.As(err, &panicErr) && br.Options.Recovery == RecoverStop {
+					return
+				}
+				time.Sleep(br.Options.RetryDelay)
+			}
+			continue
+		}
+		started := br.started
+		br.started = time.Time{}
+		br.received.Add(1)
+		if !passFilters(payload, br.Options.Include, br.Options.Exclude) {
+			br.filtered.Add(1)
+			continue
+		}
+		if !br.sample(received) {
+			br.sampled.Add(1)
+			continue
+		}
+		br.enqueue(Frame{
+			Payload:   payload,
+			Raw:       payload,
+			Started:   started,
+			Received:  received,
+			Delimiter: br.Options.Delimiter,
+		}, stop)
+	}
+}
+
+/*
+Waits for the first byte of the next frame on its own, so the
+time it arrived is known. Returns the timeout error when none
+arrived before the read timeout
+*/
+func (br *BackgroundReader) awaitFrame() error {
+	if !br.started.IsZero() {
+		return nil
+	}
+	data, err := br.comm.Peek(1)
+	if len(data) == 0 {
+		return err
+	}
+	br.started = time.Now()
+	return nil
+}
+
+/*
+Returns true if the error means the connection was lost and
+frames must be resynchronized
+*/
+func (br *BackgroundReader) linkLost(err error) bool {
+	if br.Options.Reassembly == nil || isTimeout(err) {
+		return false
+	}
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		return false
+	}
+	return Classify(err) == FatalError || !br.comm.IsConnected()
+}
+
+/*
+Delivers or discards the partial frame received before the
+connection was lost, and starts resynchronizing
+*/
+func (br *BackgroundReader) cutFrame(partial []byte, received time.Time, stop chan struct{}) {
+	br.resync = true
+	if len(partial) == 0 {
+		return
+	}
+	br.cut.Add(1)
+	if !br.Options.Reassembly.KeepPartial {
+		br.discard(partial)
+		return
+	}
+	br.enqueue(Frame{
+		Payload:   partial,
+		Raw:       partial,
+		Received:  received,
+		Delimiter: br.Options.Delimiter,
+		Err:       ErrFrameCut,
+	}, stop)
+}
+
+/*
+Discards the bytes received before the next start delimiter.
+Returns true once it is found, leaving it to begin the next frame
+*/
+func (br *BackgroundReader) synchronize() bool {
+	start := br.Options.Reassembly.StartDelimiter
+	data, err := guard(func() ([]byte, error) {
+		return br.comm.ReadUntil(start)
+	})
+	switch {
+	case err == nil:
+		br.discard(data[:len(data)-len(start)])
+		br.comm.Unread([]byte(start))
+		br.resync = false
+		return true
+	case isTimeout(err):
+		// The start delimiter may be split with the next read
+		keep := max(len(data)-len(start)+1, 0)
+		br.discard(data[:keep])
+		br.comm.Unread(data[keep:])
+	default:
+		br.discard(data)
+		report(br.Options.OnError, err)
+		time.Sleep(br.Options.RetryDelay)
+	}
+	return false
+}
+
+/*
+Counts the bytes lost while resynchronizing and reports them
+*/
+func (br *BackgroundReader) discard(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	br.lost.Add(uint64(len(data)))
+	if onDiscard := br.Options.Reassembly.OnDiscard; onDiscard != nil {
+		guard(func() (any, error) {
+			onDiscard(data)
+			return nil, nil
+		})
+	}
+}
+
+/*
+Returns true if the frame passes the decimation and the rate
+limit. Only called by the reading goroutine
+*/
+func (br *BackgroundReader) sample(received time.Time) bool {
+	br.counter++
+	if every := uint64(br.Options.KeepEvery); every > 1 && (br.counter-1)%every != 0 {
+		return false
+	}
+	if br.Options.MaxRate > 0 {
+		interval := time.Duration(float64(time.Second) / br.Options.MaxRate)
+		if !br.last.IsZero() && received.Sub(br.last) < interval {
+			return false
+		}
+		br.last = received
+	}
+	return true
+}
+
+/*
+Adds a frame to the queue applying the overflow policy
+*/
+func (br *BackgroundReader) enqueue(frame Frame, stop chan struct{}) {
+	for {
+		select {
+		case br.queue <- frame:
+			return
+		default:
+		}
+
+		switch br.Options.Overflow {
+		case DropNewest:
+			br.dropped.Add(1)
+			return
+		case DropOldest:
+			select {
+			case <-br.queue:
+				br.dropped.Add(1)
+			default:
+			}
+		case Block:
+			select {
+			case br.queue <- frame:
+			case <-stop:
+				br.dropped.Add(1)
+			}
+			return
+		}
+	}
+}
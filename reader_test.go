@@ -0,0 +1,241 @@
+package unicomm_test
+
+import (
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+func TestBackgroundReaderDropOldest(t *testing.T) {
+	port := serveOnce(t, []byte("1\n2\n3\n4\n5\n"))
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	reader := unicomm.NewBackgroundReader(comm, unicomm.ReaderOptions{
+		Delimiter: "\n",
+		QueueSize: 2,
+		Overflow:  unicomm.DropOldest,
+	})
+	reader.Start()
+	time.Sleep(200 * time.Millisecond)
+	reader.Stop()
+
+	stats := reader.Stats()
+	if stats.Received != 5 || stats.Dropped != 3 || stats.Depth != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	for _, expected := range []string{"4\n", "5\n"} {
+		frame, err := reader.Next(time.Second)
+		if err != nil || string(frame.Payload) != expected {
+			t.Fatalf("next returned %q, %v", frame.Payload, err)
+		}
+		if frame.Received.IsZero() {
+			t.Fatal("frame without receive timestamp")
+		}
+	}
+}
+
+func TestBackgroundReaderFilters(t *testing.T) {
+	port := serveOnce(t, []byte("$GPGGA,1\n$GPRMC,2\n$GPRMC,V\n$GPGSV,3\n"))
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	reader := unicomm.NewBackgroundReader(comm, unicomm.ReaderOptions{
+		Delimiter: "\n",
+		Include:   []unicomm.FrameMatcher{unicomm.MatchPrefix("$GPRMC")},
+		Exclude:   []unicomm.FrameMatcher{unicomm.MatchRegexp(regexp.MustCompile(`,V\n$`))},
+	})
+	reader.Start()
+	time.Sleep(200 * time.Millisecond)
+	reader.Stop()
+
+	if stats := reader.Stats(); stats.Received != 4 || stats.Filtered != 3 || stats.Depth != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if frame, err := reader.Next(time.Second); err != nil || string(frame.Payload) != "$GPRMC,2\n" {
+		t.Fatalf("next returned %q, %v", frame.Payload, err)
+	}
+}
+
+/*
+Returns the payloads of the queued frames
+*/
+func queuedPayloads(reader *unicomm.BackgroundReader) []string {
+	var payloads []string
+	for reader.Stats().Depth > 0 {
+		frame, _ := reader.Next(time.Second)
+		payloads = append(payloads, string(frame.Payload))
+	}
+	return payloads
+}
+
+func TestBackgroundReaderKeepEvery(t *testing.T) {
+	port := serveOnce(t, []byte("1\n2\n3\n4\n5\n6\n7\n"))
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	reader := unicomm.NewBackgroundReader(comm, unicomm.ReaderOptions{Delimiter: "\n", KeepEvery: 3})
+	reader.Start()
+	time.Sleep(200 * time.Millisecond)
+	reader.Stop()
+
+	// The first frame of every three is delivered
+	if got := strings.Join(queuedPayloads(reader), ""); got != "1\n4\n7\n" {
+		t.Fatalf("delivered %q, want frames 1, 4 and 7", got)
+	}
+	if stats := reader.Stats(); stats.Received != 7 || stats.Sampled != 4 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestBackgroundReaderMaxRate(t *testing.T) {
+	// Bursts arrive 150ms apart, while the rate allows one frame every 100ms
+	port := serveChunks(t, 150*time.Millisecond, []byte("1\n2\n3\n"), []byte("4\n5\n"), []byte("6\n"))
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	reader := unicomm.NewBackgroundReader(comm, unicomm.ReaderOptions{Delimiter: "\n", MaxRate: 10})
+	reader.Start()
+	time.Sleep(500 * time.Millisecond)
+	reader.Stop()
+
+	// Only the first frame of each burst is delivered
+	if got := strings.Join(queuedPayloads(reader), ""); got != "1\n4\n6\n" {
+		t.Fatalf("delivered %q, want frames 1, 4 and 6", got)
+	}
+	if stats := reader.Stats(); stats.Received != 6 || stats.Sampled != 3 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestBackgroundReaderStarted(t *testing.T) {
+	// The first frame is split by a pause longer than the read timeout,
+	// the second one arrives along with its end
+	port := serveChunks(t, 150*time.Millisecond, []byte("TEMP"), []byte(" 21.5\nHUM"), []byte(" 40\n"))
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port, ReadTimeout: 50 * time.Millisecond},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	reader := unicomm.NewBackgroundReader(comm, unicomm.ReaderOptions{Delimiter: "\n"})
+	reader.Start()
+	defer reader.Stop()
+
+	first, err := reader.Next(2 * time.Second)
+	if err != nil || string(first.Payload) != "TEMP 21.5\n" {
+		t.Fatalf("next returned %q, %v", first.Payload, err)
+	}
+	if first.Started.IsZero() || first.Received.Sub(first.Started) < 100*time.Millisecond {
+		t.Fatalf("frame started at %v and received at %v, want the first byte before the pause", first.Started, first.Received)
+	}
+
+	// The second frame started when its first bytes came with the first one
+	second, err := reader.Next(2 * time.Second)
+	if err != nil || string(second.Payload) != "HUM 40\n" {
+		t.Fatalf("next returned %q, %v", second.Payload, err)
+	}
+	if second.Started.Before(first.Received) || second.Received.Sub(second.Started) < 100*time.Millisecond {
+		t.Fatalf("frame started at %v and received at %v, want the arrival of %q", second.Started, second.Received, "HUM")
+	}
+}
+
+func TestBackgroundReaderResynchronizes(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		// The link drops in the middle of a frame, and the device goes
+		// on sending it after the reconnect
+		for _, payload := range []string{"$A,1\n$B,2", "2,9\n$C,3\n"} {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte(payload))
+			if payload[0] != '$' {
+				time.Sleep(time.Second)
+			}
+			conn.Close()
+		}
+	}()
+
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: uint(listener.Addr().(*net.TCPAddr).Port)},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	var discarded []byte
+	reader := unicomm.NewBackgroundReader(comm, unicomm.ReaderOptions{
+		Delimiter:  "\n",
+		RetryDelay: 10 * time.Millisecond,
+		Reassembly: &unicomm.ReassemblyOptions{
+			StartDelimiter: "$",
+			KeepPartial:    true,
+			OnDiscard:      func(data []byte) { discarded = append(discarded, data...) },
+		},
+	})
+	reader.Start()
+
+	for comm.IsConnected() {
+		time.Sleep(time.Millisecond)
+	}
+	comm.Disconnect()
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []struct {
+		payload string
+		err     error
+	}{{"$A,1\n", nil}, {"$B,2", unicomm.ErrFrameCut}, {"$C,3\n", nil}} {
+		frame, err := reader.Next(time.Second)
+		if err != nil || string(frame.Payload) != expected.payload || frame.Err != expected.err {
+			t.Fatalf("next returned %q (%v), %v", frame.Payload, frame.Err, err)
+		}
+	}
+	reader.Stop()
+
+	stats := reader.Stats()
+	if stats.Cut != 1 || stats.Lost != 4 || string(discarded) != "2,9\n" {
+		t.Fatalf("unexpected stats %+v, discarded %q", stats, discarded)
+	}
+}
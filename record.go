@@ -0,0 +1,236 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+type RecordOptions struct {
+	Size      int                // Bytes of each record, sync word included, required
+	Sync      []byte             // Begins each record, empty when only the validator checks the alignment
+	Validate  func([]byte) error // Checks a whole record, e.g. its checksum, nil accepts any record
+	Timeout   time.Duration      // Wait for a whole record, defaults to 1s
+	OnDiscard func([]byte)       // Called with the bytes discarded while resynchronizing
+}
+
+type RecordStats struct {
+	Records   uint64 // Whole records delivered
+	Resyncs   uint64 // Times the stream was found misaligned
+	Discarded uint64 // Bytes discarded while resynchronizing
+}
+
+/*
+Wrapper for devices streaming fixed-size binary records. A record
+is accepted when it begins with the sync word and passes the
+validator; otherwise the stream is misaligned and the bytes are
+discarded up to the next sync word, or one at a time without it,
+so only whole, validated records are delivered
+*/
+type RecordFramed struct {
+	Unicomm
+	Options RecordOptions
+
+	buffer  []byte // Received bytes not part of a record yet
+	pending []byte // Record bytes not consumed by Read yet
+	aligned bool   // Last record was valid, so a failure starts a resync
+	stats   RecordStats
+	mutex   sync.Mutex // Protect the buffers and the statistics
+}
+
+/*
+Creates a wrapper reading fixed-size records from the instance.
+Writes are not changed
+*/
+func NewRecordFramed(comm Unicomm, options RecordOptions) *RecordFramed {
+	if options.Timeout == 0 {
+		options.Timeout = time.Second
+	}
+	return &RecordFramed{
+		Unicomm: comm,
+		Options: options,
+		aligned: true,
+	}
+}
+
+/*
+Returns the counters of records and resynchronizations
+*/
+func (rf *RecordFramed) Stats() RecordStats {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	return rf.stats
+}
+
+/*
+Returns nil if the record begins with the sync word and passes
+the validator
+*/
+func (rf *RecordFramed) check(record []byte) error {
+	if !bytes.HasPrefix(record, rf.Options.Sync) {
+		return fmt.Errorf("invalid record: missing sync word")
+	}
+	if rf.Options.Validate == nil {
+		return nil
+	}
+	_, err := guard(func() (any, error) {
+		return nil, rf.Options.Validate(record)
+	})
+	return err
+}
+
+/*
+Discards the bytes up to the next candidate sync word, at least
+one, must be called with the mutex locked
+*/
+func (rf *RecordFramed) skip() {
+	n := 1
+	if sync := rf.Options.Sync; len(sync) > 0 {
+		if index := bytes.Index(rf.buffer[1:], sync); index >= 0 {
+			n = index + 1
+		} else {
+			// The sync word may be split with the next read
+			n = max(len(rf.buffer)-len(sync)+1, 1)
+		}
+	}
+	discarded := bytes.Clone(rf.buffer[:n])
+	rf.buffer = rf.buffer[n:]
+	rf.stats.Discarded += uint64(n)
+	if onDiscard := rf.Options.OnDiscard; onDiscard != nil {
+		guard(func() (any, error) {
+			onDiscard(discarded)
+			return nil, nil
+		})
+	}
+}
+
+/*
+Reads the next whole, validated record, must be called with the
+mutex locked. Bytes received before the timeout are kept for the
+next call
+*/
+func (rf *RecordFramed) readRecord() (Frame, error) {
+	if rf.Options.Size <= 0 || rf.Options.Size < len(rf.Options.Sync) {
+		return Frame{}, fmt.Errorf("invalid record size %d", rf.Options.Size)
+	}
+
+	var started time.Time
+	deadline := time.Now().Add(rf.Options.Timeout)
+	for {
+		if len(rf.buffer) >= rf.Options.Size {
+			record := rf.buffer[:rf.Options.Size]
+			if rf.check(record) == nil {
+				rf.buffer = rf.buffer[rf.Options.Size:]
+				rf.aligned = true
+				rf.stats.Records++
+				record = bytes.Clone(record)
+				return Frame{Payload: record, Raw: record, Started: started, Received: time.Now()}, nil
+			}
+			if rf.aligned {
+				rf.aligned = false
+				rf.stats.Resyncs++
+			}
+			rf.skip()
+			continue
+		}
+		if time.Now().After(deadline) {
+			return Frame{}, unicommio.TimeoutError("read record")
+		}
+
+		data, err := rf.Unicomm.Read(uint(rf.Options.Size - len(rf.buffer)))
+		if len(data) > 0 && started.IsZero() {
+			started = time.Now()
+		}
+		rf.buffer = append(rf.buffer, data...)
+		if err != nil && !isTimeout(err) {
+			return Frame{}, err
+		}
+	}
+}
+
+/*
+Reads the next record. The remainder of a record partially
+consumed by Read is returned first, without raw bytes
+*/
+func (rf *RecordFramed) ReadRecord() (Frame, error) {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	if len(rf.pending) > 0 {
+		payload := rf.pending
+		rf.pending = nil
+		return Frame{Payload: payload, Received: time.Now()}, nil
+	}
+	return rf.readRecord()
+}
+
+/*
+Reads up to n bytes of whole records
+*/
+func (rf *RecordFramed) Read(n uint) ([]byte, error) {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	if len(rf.pending) == 0 {
+		frame, err := rf.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		rf.pending = frame.Payload
+	}
+	size := min(int(n), len(rf.pending))
+	data := bytes.Clone(rf.pending[:size])
+	rf.pending = rf.pending[size:]
+	return data, nil
+}
+
+/*
+Reads record data until a target delimiter is found
+*/
+func (rf *RecordFramed) ReadUntil(delimiter string) ([]byte, error) {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	for {
+		if index := bytes.Index(rf.pending, []byte(delimiter)); index >= 0 {
+			data := bytes.Clone(rf.pending[:index+len(delimiter)])
+			rf.pending = rf.pending[index+len(delimiter):]
+			return data, nil
+		}
+		frame, err := rf.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		rf.pending = append(rf.pending, frame.Payload...)
+	}
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (rf *RecordFramed) WriteRaw(message []byte) error {
+	return writeRaw(rf.Unicomm, message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (rf *RecordFramed) Unwrap() Unicomm {
+	return rf.Unicomm
+}
+
+/*
+Stops the lookup of data capabilities, which would bypass the
+buffered records
+*/
+func (rf *RecordFramed) transformsData() {}
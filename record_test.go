@@ -0,0 +1,77 @@
+package unicomm_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+/*
+Builds an 8-byte record: sync word, 5 bytes of payload and their sum
+*/
+func sumRecord(payload string) []byte {
+	data := append([]byte{0xAA, 0x55}, payload...)
+	var sum byte
+	for _, b := range payload {
+		sum += byte(b)
+	}
+	return append(data, sum)
+}
+
+func TestRecordFramedResynchronizes(t *testing.T) {
+	corrupted := sumRecord("BBBBB")
+	corrupted[4] ^= 0xFF
+
+	var stream []byte
+	stream = append(stream, 0x00, 0xFF, 0xAA)
+	stream = append(stream, sumRecord("AAAAA")...)
+	stream = append(stream, corrupted...)
+	stream = append(stream, sumRecord("CCCCC")...)
+	stream = append(stream, sumRecord("DDDDD")...)
+
+	port := serveOnce(t, stream)
+	var discarded []byte
+	comm := unicomm.NewRecordFramed(unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+	}), unicomm.RecordOptions{
+		Size: 8,
+		Sync: []byte{0xAA, 0x55},
+		Validate: func(data []byte) error {
+			var sum byte
+			for _, b := range data[2:7] {
+				sum += b
+			}
+			if sum != data[7] {
+				return fmt.Errorf("checksum 0x%02X, expected 0x%02X", data[7], sum)
+			}
+			return nil
+		},
+		OnDiscard: func(data []byte) { discarded = append(discarded, data...) },
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	for _, payload := range []string{"AAAAA", "CCCCC", "DDDDD"} {
+		frame, err := comm.ReadRecord()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(frame.Payload, sumRecord(payload)) {
+			t.Fatalf("got %X, want %X", frame.Payload, sumRecord(payload))
+		}
+	}
+
+	stats := comm.Stats()
+	if stats.Records != 3 || stats.Resyncs != 2 || stats.Discarded != 11 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if !bytes.Equal(discarded, append([]byte{0x00, 0xFF, 0xAA}, corrupted...)) {
+		t.Fatalf("discarded %X", discarded)
+	}
+}
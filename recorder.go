@@ -0,0 +1,320 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type DumpFormat uint8
+
+const (
+	DumpCSV  DumpFormat = 0
+	DumpJSON DumpFormat = 1
+)
+
+type RecorderOptions struct {
+	MaxEntries int // Oldest entries are discarded beyond this, defaults to 10000
+
+	// Receives every entry as a JSON line, e.g. a RotatingFile for
+	// always-on tracing, nil disables it
+	Trace io.Writer
+}
+
+type RecordEntry struct {
+	Time      time.Time    `json:"time"`
+	Direction TapDirection `json:"-"`
+	Data      []byte       `json:"data"`
+	Err       string       `json:"error,omitempty"`
+}
+
+type SessionStats struct {
+	Started          time.Time `json:"started"`
+	Connects         uint64    `json:"connects"`
+	Disconnects      uint64    `json:"disconnects"`
+	Reads            uint64    `json:"reads"`
+	Writes           uint64    `json:"writes"`
+	Errors           uint64    `json:"errors"`
+	BytesReceived    uint64    `json:"bytes_received"`
+	BytesTransmitted uint64    `json:"bytes_transmitted"`
+	Dropped          uint64    `json:"dropped_entries"` // Entries discarded by the limit
+	TraceErrors      uint64    `json:"trace_errors"`    // Entries that failed to be traced
+}
+
+// Entry exported with the direction as text
+type jsonEntry struct {
+	RecordEntry
+	Direction string `json:"direction"`
+}
+
+type Recorder struct {
+	Unicomm
+	Options RecorderOptions
+
+	entries []RecordEntry
+	stats   SessionStats
+	mutex   sync.Mutex // Protect entries and statistics
+}
+
+/*
+Creates a middleware that records the traffic and statistics
+of the connection, so the session can be exported for support
+*/
+func NewRecorder(comm Unicomm, options RecorderOptions) *Recorder {
+	if options.MaxEntries == 0 {
+		options.MaxEntries = 10000
+	}
+	return &Recorder{
+		Unicomm: comm,
+		Options: options,
+		stats:   SessionStats{Started: time.Now()},
+	}
+}
+
+/*
+Stores an entry and updates the statistics
+*/
+func (r *Recorder) record(direction TapDirection, data []byte, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entry := RecordEntry{
+		Time:      time.Now(),
+		Direction: direction,
+		Data:      bytes.Clone(data),
+	}
+	if direction == Transmitted {
+		r.stats.Writes++
+		if err == nil {
+			r.stats.BytesTransmitted += uint64(len(data))
+		}
+	} else {
+		r.stats.Reads++
+		r.stats.BytesReceived += uint64(len(data))
+	}
+	if err != nil {
+		r.stats.Errors++
+		entry.Err = err.Error()
+	}
+
+	if len(r.entries) >= r.Options.MaxEntries {
+		r.entries = r.entries[1:]
+		r.stats.Dropped++
+	}
+	r.entries = append(r.entries, entry)
+	r.trace(entry)
+}
+
+/*
+Appends an entry to the trace as a JSON line
+*/
+func (r *Recorder) trace(entry RecordEntry) {
+	if r.Options.Trace == nil {
+		return
+	}
+	line, err := json.Marshal(jsonEntry{entry, entry.Direction.String()})
+	if err == nil {
+		_, err = r.Options.Trace.Write(append(line, '\n'))
+	}
+	if err != nil {
+		r.stats.TraceErrors++
+	}
+}
+
+/*
+Returns a copy of the recorded entries, oldest first
+*/
+func (r *Recorder) Entries() []RecordEntry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]RecordEntry(nil), r.entries...)
+}
+
+/*
+Returns the statistics of the session
+*/
+func (r *Recorder) Stats() SessionStats {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.stats
+}
+
+/*
+Discards the recorded entries and restarts the statistics
+*/
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.entries = nil
+	r.stats = SessionStats{Started: time.Now()}
+}
+
+/*
+Establishes the connection
+*/
+func (r *Recorder) Connect() error {
+	err := r.Unicomm.Connect()
+
+	r.mutex.Lock()
+	r.stats.Connects++
+	if err != nil {
+		r.stats.Errors++
+	}
+	r.mutex.Unlock()
+	return err
+}
+
+/*
+Closes the connection
+*/
+func (r *Recorder) Disconnect() error {
+	err := r.Unicomm.Disconnect()
+
+	r.mutex.Lock()
+	r.stats.Disconnects++
+	if err != nil {
+		r.stats.Errors++
+	}
+	r.mutex.Unlock()
+	return err
+}
+
+/*
+Reads a number of bytes
+*/
+func (r *Recorder) Read(n uint) ([]byte, error) {
+	data, err := r.Unicomm.Read(n)
+	r.record(Received, data, err)
+	return data, err
+}
+
+/*
+Reads data until a target delimiter is found
+*/
+func (r *Recorder) ReadUntil(delimiter string) ([]byte, error) {
+	data, err := ReadUntil(r.Unicomm, delimiter)
+	r.record(Received, data, err)
+	return data, err
+}
+
+/*
+Reads everything currently buffered by the instance
+*/
+func (r *Recorder) ReadAvailable() ([]byte, error) {
+	data, err := ReadAvailable(r.Unicomm)
+	r.record(Received, data, err)
+	return data, err
+}
+
+/*
+Writes an array of bytes
+*/
+func (r *Recorder) Write(message []byte) error {
+	data := bytes.Clone(message)
+	err := r.Unicomm.Write(message)
+	r.record(Transmitted, data, err)
+	return err
+}
+
+/*
+Exports the statistics and the recorded traffic. The CSV
+format has one row per entry followed by the statistics as
+name and value rows, while JSON holds both in one object
+*/
+func (r *Recorder) DumpSession(w io.Writer, format DumpFormat) error {
+	entries := r.Entries()
+	stats := r.Stats()
+
+	switch format {
+	case DumpCSV:
+		return dumpCSV(w, entries, stats)
+	case DumpJSON:
+		return dumpJSON(w, entries, stats)
+	default:
+		return fmt.Errorf("unknown dump format %d", format)
+	}
+}
+
+/*
+Writes the session as CSV
+*/
+func dumpCSV(w io.Writer, entries []RecordEntry, stats SessionStats) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"time", "direction", "length", "hex", "text", "error"})
+	for _, entry := range entries {
+		writer.Write([]string{
+			entry.Time.Format(time.RFC3339Nano),
+			entry.Direction.String(),
+			strconv.Itoa(len(entry.Data)),
+			hex.EncodeToString(entry.Data),
+			strconv.Quote(string(entry.Data)),
+			entry.Err,
+		})
+	}
+
+	writer.Write(nil)
+	writer.Write([]string{"statistic", "value"})
+	rows := [][]string{
+		{"started", stats.Started.Format(time.RFC3339Nano)},
+		{"connects", strconv.FormatUint(stats.Connects, 10)},
+		{"disconnects", strconv.FormatUint(stats.Disconnects, 10)},
+		{"reads", strconv.FormatUint(stats.Reads, 10)},
+		{"writes", strconv.FormatUint(stats.Writes, 10)},
+		{"errors", strconv.FormatUint(stats.Errors, 10)},
+		{"bytes_received", strconv.FormatUint(stats.BytesReceived, 10)},
+		{"bytes_transmitted", strconv.FormatUint(stats.BytesTransmitted, 10)},
+		{"dropped_entries", strconv.FormatUint(stats.Dropped, 10)},
+		{"trace_errors", strconv.FormatUint(stats.TraceErrors, 10)},
+	}
+	writer.WriteAll(rows)
+	return writer.Error()
+}
+
+/*
+Writes the session as an indented JSON object
+*/
+func dumpJSON(w io.Writer, entries []RecordEntry, stats SessionStats) error {
+	traffic := make([]jsonEntry, 0, len(entries))
+	for _, entry := range entries {
+		traffic = append(traffic, jsonEntry{entry, entry.Direction.String()})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Statistics SessionStats `json:"statistics"`
+		Traffic    []jsonEntry  `json:"traffic"`
+	}{stats, traffic})
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (r *Recorder) WriteRaw(message []byte) error {
+	data := bytes.Clone(message)
+	err := writeRaw(r.Unicomm, message)
+	r.record(Transmitted, data, err)
+	return err
+}
+
+/*
+Returns the wrapped instance
+*/
+func (r *Recorder) Unwrap() Unicomm {
+	return r.Unicomm
+}
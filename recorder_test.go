@@ -0,0 +1,58 @@
+package unicomm_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+func TestRecorderDumpSession(t *testing.T) {
+	port := serveOnce(t, []byte("OK\n"))
+	recorder := unicomm.NewRecorder(unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+	}), unicomm.RecorderOptions{})
+	if err := recorder.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer recorder.Disconnect()
+
+	if err := recorder.Write([]byte("PING\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recorder.ReadUntil("\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := recorder.Stats()
+	if stats.BytesTransmitted != 5 || stats.BytesReceived != 3 {
+		t.Fatalf("unexpected statistics %+v", stats)
+	}
+
+	var csvDump bytes.Buffer
+	if err := recorder.DumpSession(&csvDump, unicomm.DumpCSV); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(csvDump.String(), ",TX,5,50494e470a,") {
+		t.Fatalf("missing transmitted row in:\n%s", csvDump.String())
+	}
+
+	var jsonDump bytes.Buffer
+	if err := recorder.DumpSession(&jsonDump, unicomm.DumpJSON); err != nil {
+		t.Fatal(err)
+	}
+	var session struct {
+		Statistics unicomm.SessionStats
+		Traffic    []struct{ Direction string }
+	}
+	if err := json.Unmarshal(jsonDump.Bytes(), &session); err != nil {
+		t.Fatal(err)
+	}
+	if len(session.Traffic) != 2 || session.Traffic[1].Direction != "RX" {
+		t.Fatalf("unexpected traffic %+v", session.Traffic)
+	}
+}
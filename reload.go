@@ -0,0 +1,394 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+/*
+Returns the current options from a configuration source, such
+as a file or a remote service
+*/
+type ConfigProvider func() (Options, error)
+
+type ReloadAction uint8
+
+const (
+	ReloadApplied ReloadAction = 0 // Changes applied to the live connection
+	ReloadStaged  ReloadAction = 1 // Changes waiting for the next reconnect
+	ReloadSwapped ReloadAction = 2 // Staged changes applied on reconnect
+	ReloadFailed  ReloadAction = 3 // The provider returned an error
+)
+
+type ReloadEvent struct {
+	Action  ReloadAction
+	Options Options // Options applied or staged
+	Err     error
+	Time    time.Time
+}
+
+type ReloadOptions struct {
+	Provider ConfigProvider
+	Interval time.Duration     // Polling period of the provider, defaults to 30s
+	OnEvent  func(ReloadEvent) // Called for every change and failure
+}
+
+type Reloader struct {
+	Options ReloadOptions
+
+	comm    Unicomm
+	current Options
+	staged  *Options
+	stop    chan struct{}
+	done    chan struct{}
+	mutex   sync.Mutex // Keep operations and reloads apart
+	running sync.Mutex // Protect start and stop
+}
+
+/*
+Returns the name of the action
+*/
+func (ra ReloadAction) String() string {
+	switch ra {
+	case ReloadApplied:
+		return "applied"
+	case ReloadStaged:
+		return "staged"
+	case ReloadSwapped:
+		return "swapped"
+	case ReloadFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("ReloadAction(%d)", ra)
+	}
+}
+
+/*
+Creates a Unicomm instance whose options can be reloaded without
+downtime. Timeouts, delimiters and keep-warm settings are applied
+live, while the other changes are staged and applied when the
+instance reconnects
+*/
+func NewReloader(options Options, reload ReloadOptions) *Reloader {
+	if reload.Interval == 0 {
+		reload.Interval = 30 * time.Second
+	}
+	return &Reloader{
+		Options: reload,
+		comm:    New(options),
+		current: options,
+	}
+}
+
+/*
+Returns the options in use and the staged ones, if any
+*/
+func (r *Reloader) Current() (Options, *Options) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.current, r.staged
+}
+
+/*
+Compares the options and applies or stages the changes. Options
+equal to the ones in use clear any staged change
+*/
+func (r *Reloader) Reload(options Options) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !sameValue(reflect.ValueOf(withLiveFields(options, r.current)), reflect.ValueOf(r.current)) {
+		if r.staged == nil || !sameValue(reflect.ValueOf(options), reflect.ValueOf(*r.staged)) {
+			r.staged = &options
+			r.emit(ReloadEvent{Action: ReloadStaged, Options: options})
+		}
+		return
+	}
+
+	r.staged = nil
+	if sameValue(reflect.ValueOf(options), reflect.ValueOf(r.current)) {
+		return
+	}
+	r.apply(options)
+	r.emit(ReloadEvent{Action: ReloadApplied, Options: options})
+}
+
+/*
+Polls the provider once and reloads its options
+*/
+func (r *Reloader) Poll() error {
+	options, err := guard(r.Options.Provider)
+	if err != nil {
+		r.mutex.Lock()
+		r.emit(ReloadEvent{Action: ReloadFailed, Err: err})
+		r.mutex.Unlock()
+		return err
+	}
+	r.Reload(options)
+	return nil
+}
+
+/*
+Starts polling the provider in background
+*/
+func (r *Reloader) Watch() error {
+	r.running.Lock()
+	defer r.running.Unlock()
+
+	if r.Options.Provider == nil {
+		return fmt.Errorf("there is no configuration provider")
+	}
+	if r.stop != nil {
+		return fmt.Errorf("reloader is already watching")
+	}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.watch(r.stop, r.done)
+	return nil
+}
+
+/*
+Stops polling the provider
+*/
+func (r *Reloader) Stop() error {
+	r.running.Lock()
+	defer r.running.Unlock()
+
+	if r.stop == nil {
+		return fmt.Errorf("reloader is not watching")
+	}
+	close(r.stop)
+	<-r.done
+	r.stop = nil
+	return nil
+}
+
+/*
+Polls the provider on every interval until stopped
+*/
+func (r *Reloader) watch(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(r.Options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			r.Poll()
+		}
+	}
+}
+
+/*
+Notifies an event, must be called with the mutex locked
+*/
+func (r *Reloader) emit(event ReloadEvent) {
+	event.Time = time.Now()
+	if r.Options.OnEvent != nil {
+		// Panics of the hook must not leave the options locked
+		guard(func() (any, error) {
+			r.Options.OnEvent(event)
+			return nil, nil
+		})
+	}
+}
+
+/*
+Returns the options with the fields that can change while
+connected copied from the reference
+*/
+func withLiveFields(options Options, reference Options) Options {
+	options.Delimiter = reference.Delimiter
+	options.Serial.ReadTimeout = reference.Serial.ReadTimeout
+	options.Serial.WriteTimeout = reference.Serial.WriteTimeout
+	options.Serial.StartDelimiter = reference.Serial.StartDelimiter
+	options.Serial.EndDelimiter = reference.Serial.EndDelimiter
+	options.TCP.ReadTimeout = reference.TCP.ReadTimeout
+	options.TCP.WriteTimeout = reference.TCP.WriteTimeout
+	options.TCP.MaxReadDuration = reference.TCP.MaxReadDuration
+	options.TCP.EndDelimiter = reference.TCP.EndDelimiter
+
+	// Keep-warm is live only while it stays enabled, since enabling or
+	// disabling it changes the wrappers
+	if options.KeepWarm.Interval > 0 && reference.KeepWarm.Interval > 0 {
+		options.KeepWarm = reference.KeepWarm
+	}
+	return options
+}
+
+/*
+Applies the live fields to the instance, must be called with
+the mutex locked
+*/
+func (r *Reloader) apply(options Options) {
+	if serial, ok := As[*unicommserial.UnicommSerial](r.comm); ok {
+		serial.Reconfigure(options.Serial)
+	}
+	if tcp, ok := As[*unicommtcp.UnicommTCP](r.comm); ok {
+		tcp.Reconfigure(options.TCP)
+	}
+	if keepWarm, ok := As[*KeepWarm](r.comm); ok {
+		keepWarm.SetOptions(options.KeepWarm)
+	}
+	r.current = options
+}
+
+/*
+Returns true if both values are deeply equal. Unlike
+reflect.DeepEqual, functions are equal when they point to
+the same code, so callbacks returned by the provider on every
+poll are not reported as changes
+*/
+func sameValue(a, b reflect.Value) bool {
+	if a.IsValid() != b.IsValid() {
+		return false
+	}
+	if !a.IsValid() {
+		return true
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Func:
+		return a.Pointer() == b.Pointer()
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return sameValue(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for index := range a.NumField() {
+			if !sameValue(a.Field(index), b.Field(index)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for index := range a.Len() {
+			if !sameValue(a.Index(index), b.Index(index)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for _, key := range a.MapKeys() {
+			if !sameValue(a.MapIndex(key), b.MapIndex(key)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a.Equal(b)
+	}
+}
+
+/*
+Establishes the connection. Staged options are applied first,
+replacing the instance while it is disconnected
+*/
+func (r *Reloader) Connect() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.staged != nil && !r.comm.IsConnected() {
+		r.comm = New(*r.staged)
+		r.current = *r.staged
+		r.staged = nil
+		r.emit(ReloadEvent{Action: ReloadSwapped, Options: r.current})
+	}
+	return r.comm.Connect()
+}
+
+/*
+Closes the connection
+*/
+func (r *Reloader) Disconnect() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.comm.Disconnect()
+}
+
+/*
+Returns true if the connection is established
+*/
+func (r *Reloader) IsConnected() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.comm.IsConnected()
+}
+
+/*
+Reads a number of bytes
+*/
+func (r *Reloader) Read(n uint) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.comm.Read(n)
+}
+
+/*
+Reads data until a target delimiter is found
+*/
+func (r *Reloader) ReadUntil(delimiter string) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return ReadUntil(r.comm, delimiter)
+}
+
+/*
+Writes an array of bytes
+*/
+func (r *Reloader) Write(message []byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.comm.Write(message)
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (r *Reloader) WriteRaw(message []byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return writeRaw(r.comm, message)
+}
+
+/*
+Returns the instance in use, which is replaced when staged
+options are applied
+*/
+func (r *Reloader) Unwrap() Unicomm {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.comm
+}
@@ -0,0 +1,61 @@
+package unicomm_test
+
+import (
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestReloaderOptions(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	options := unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions()}
+
+	var events []unicomm.ReloadEvent
+	reloader := unicomm.NewReloader(options, unicomm.ReloadOptions{
+		OnEvent: func(event unicomm.ReloadEvent) { events = append(events, event) },
+	})
+	if err := reloader.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer reloader.Disconnect()
+
+	// Delimiters are applied to the live connection
+	live := options
+	live.TCP.EndDelimiter = "\n"
+	reloader.Reload(live)
+	if err := reloader.Write([]byte("PING")); err != nil {
+		t.Fatal(err)
+	}
+	unicommtest.ExpectFrame(t, reloader, "\n", "PING\n")
+
+	// A new host requires reconnecting, so it waits for the next connect
+	moved := live
+	moved.TCP.Host = "localhost"
+	reloader.Reload(moved)
+	if current, staged := reloader.Current(); current.TCP.Host == "localhost" || staged == nil {
+		t.Fatal("expected the host change to be staged")
+	}
+
+	if err := reloader.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloader.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	tcp, _ := unicomm.As[*unicommtcp.UnicommTCP](reloader)
+	if tcp.Options.Host != "localhost" || tcp.Options.EndDelimiter != "\n" {
+		t.Fatalf("staged options not applied: %+v", tcp.Options)
+	}
+
+	want := []unicomm.ReloadAction{unicomm.ReloadApplied, unicomm.ReloadStaged, unicomm.ReloadSwapped}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for index, event := range events {
+		if event.Action != want[index] {
+			t.Fatalf("event %d is %s, want %s", index, event.Action, want[index])
+		}
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type RotationOptions struct {
+	Path     string        // File written, rotated files are placed beside it
+	MaxSize  int64         // Rotates when the file would exceed it, zero disables it
+	Interval time.Duration // Rotates on each interval boundary (UTC), e.g. 24h for daily
+	Compress bool          // Compresses the rotated files with gzip
+
+	// Retention of the rotated files, zero values disable each limit
+	MaxFiles int
+	MaxAge   time.Duration
+}
+
+type RotatingFile struct {
+	Options RotationOptions
+
+	file   *os.File
+	size   int64
+	expiry time.Time  // Next interval boundary
+	mutex  sync.Mutex // Protect the file
+}
+
+// Layout of the timestamp appended to rotated files, sortable by name
+const rotationLayout = "20060102-150405.000000000"
+
+/*
+Opens a file that rotates by size and time, so always-on traces
+at remote sites do not fill the disk
+*/
+func NewRotatingFile(options RotationOptions) (*RotatingFile, error) {
+	if options.Path == "" {
+		return nil, fmt.Errorf("invalid options: path is empty")
+	}
+	if options.MaxSize < 0 || options.Interval < 0 || options.MaxFiles < 0 || options.MaxAge < 0 {
+		return nil, fmt.Errorf("invalid options: limits must not be negative")
+	}
+	rf := &RotatingFile{Options: options}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+/*
+Opens the file for appending and computes the next rotation
+*/
+func (rf *RotatingFile) open() error {
+	file, err := os.OpenFile(rf.Options.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	rf.file = file
+	rf.size = info.Size()
+	if rf.Options.Interval > 0 {
+		rf.expiry = time.Now().UTC().Truncate(rf.Options.Interval).Add(rf.Options.Interval)
+	}
+	return nil
+}
+
+/*
+Writes data to the file, rotating it first when the size or
+the interval limit is reached
+*/
+func (rf *RotatingFile) Write(data []byte) (int, error) {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	if rf.file == nil {
+		return 0, os.ErrClosed
+	}
+	overflow := rf.Options.MaxSize > 0 && rf.size > 0 && rf.size+int64(len(data)) > rf.Options.MaxSize
+	expired := !rf.expiry.IsZero() && !time.Now().Before(rf.expiry)
+	if overflow || expired {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(data)
+	rf.size += int64(n)
+	return n, err
+}
+
+/*
+Closes the current file and starts a new one
+*/
+func (rf *RotatingFile) Rotate() error {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	if rf.file == nil {
+		return os.ErrClosed
+	}
+	return rf.rotate()
+}
+
+/*
+Renames the current file with a timestamp, compresses it when
+configured and applies the retention limits. The file is opened
+again even when the rename fails, so a failed rotation does not
+stop the writes
+*/
+func (rf *RotatingFile) rotate() error {
+	err := rf.file.Close()
+	rf.file = nil
+
+	rotated := rf.Options.Path + "." + time.Now().UTC().Format(rotationLayout)
+	if err == nil {
+		err = os.Rename(rf.Options.Path, rotated)
+	}
+	if openErr := rf.open(); openErr != nil {
+		return errors.Join(err, openErr)
+	}
+	if err != nil {
+		return err
+	}
+	if rf.Options.Compress {
+		if err := compressFile(rotated); err != nil {
+			return err
+		}
+	}
+	return rf.prune()
+}
+
+/*
+Removes rotated files beyond the retention limits, oldest first
+*/
+func (rf *RotatingFile) prune() error {
+	matches, err := filepath.Glob(rf.Options.Path + ".*")
+	if err != nil {
+		return err
+	}
+	prefix := rf.Options.Path + "."
+	rotated := slices.DeleteFunc(matches, func(name string) bool {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz")
+		_, err := time.Parse(rotationLayout, stamp)
+		return err != nil
+	})
+	slices.Sort(rotated)
+
+	for index, name := range rotated {
+		expired := false
+		if rf.Options.MaxAge > 0 {
+			if info, err := os.Stat(name); err == nil {
+				expired = time.Since(info.ModTime()) > rf.Options.MaxAge
+			}
+		}
+		excess := rf.Options.MaxFiles > 0 && index < len(rotated)-rf.Options.MaxFiles
+		if expired || excess {
+			if err := os.Remove(name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/*
+Replaces a file with its gzip compressed version
+*/
+func compressFile(name string) error {
+	source, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := os.Create(name + ".gz")
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(target)
+	if _, err := io.Copy(writer, source); err != nil {
+		target.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		target.Close()
+		return err
+	}
+	if err := target.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
+
+/*
+Closes the current file
+*/
+func (rf *RotatingFile) Close() error {
+	rf.mutex.Lock()
+	defer rf.mutex.Unlock()
+
+	if rf.file == nil {
+		return os.ErrClosed
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
@@ -0,0 +1,143 @@
+package unicomm_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trace.jsonl")
+	file, err := unicomm.NewRotatingFile(unicomm.RotationOptions{
+		Path:     path,
+		MaxSize:  32,
+		Compress: true,
+		MaxFiles: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	// Every line fills the file, so each write rotates the previous one
+	line := []byte(strings.Repeat("x", 31) + "\n")
+	for range 5 {
+		if _, err := file.Write(line); err != nil {
+			t.Fatal(err)
+		}
+		if err := file.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rotated, err := filepath.Glob(path + ".*.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotated) != 2 {
+		t.Fatalf("expected 2 rotated files, got %v", rotated)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != 0 {
+		t.Fatalf("expected an empty current file, got %v", err)
+	}
+}
+
+func TestRotatingFileMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trace.jsonl")
+	file, err := unicomm.NewRotatingFile(unicomm.RotationOptions{Path: path, MaxSize: 32})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	// Two lines fill the file, the third one starts a new file
+	line := []byte(strings.Repeat("x", 15) + "\n")
+	for range 5 {
+		if _, err := file.Write(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rotated, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotated) != 2 {
+		t.Fatalf("expected 2 rotated files, got %v", rotated)
+	}
+	for _, name := range append(rotated, path) {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := int64(32)
+		if name == path {
+			want = 16
+		}
+		if info.Size() != want {
+			t.Fatalf("unexpected size %d of %s", info.Size(), name)
+		}
+	}
+}
+
+func TestRotatingFileInterval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trace.jsonl")
+	file, err := unicomm.NewRotatingFile(unicomm.RotationOptions{Path: path, Interval: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write([]byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if _, err := file.Write([]byte("second\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotated) != 1 {
+		t.Fatalf("expected 1 rotated file, got %v", rotated)
+	}
+	if data, err := os.ReadFile(rotated[0]); err != nil || string(data) != "first\n" {
+		t.Fatalf("unexpected rotated file %q, %v", data, err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "second\n" {
+		t.Fatalf("unexpected current file %q, %v", data, err)
+	}
+}
+
+func TestRotatingFileRenameFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trace.jsonl")
+	file, err := unicomm.NewRotatingFile(unicomm.RotationOptions{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	// A file removed by the operator cannot be renamed
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Rotate(); err == nil {
+		t.Fatal("expected the rotation to fail")
+	}
+	if _, err := file.Write([]byte("after\n")); err != nil {
+		t.Fatalf("expected writes to continue, got %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "after\n" {
+		t.Fatalf("unexpected file %q, %v", data, err)
+	}
+}
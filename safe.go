@@ -0,0 +1,155 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+/*
+Tells a background goroutine what to do after recovering from
+a panic, which is always reported through its error callback
+*/
+type RecoveryPolicy uint8
+
+const (
+	RecoverContinue RecoveryPolicy = 0 // Keep running
+	RecoverStop     RecoveryPolicy = 1 // Stop the goroutine
+)
+
+/*
+Returned instead of a panic raised by user-supplied hooks,
+codecs or middleware
+*/
+type PanicError struct {
+	Value any    // Value given to panic
+	Stack []byte // Stack trace of the panicking goroutine
+}
+
+type Safe struct {
+	Unicomm
+}
+
+/*
+Describes the panic with its stack trace
+*/
+func (pe *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v\n%s", pe.Value, pe.Stack)
+}
+
+/*
+Converts a panic into a PanicError, must be deferred
+*/
+func recoverError(err *error) {
+	if value := recover(); value != nil {
+		*err = &PanicError{Value: value, Stack: debug.Stack()}
+	}
+}
+
+/*
+Runs an operation returning its panic, if any, as an error
+*/
+func guard[T any](operation func() (T, error)) (value T, err error) {
+	defer recoverError(&err)
+	return operation()
+}
+
+/*
+Calls an error callback, ignoring the panics it raises since
+there is nowhere left to report them
+*/
+func report(onError func(error), err error) {
+	if onError == nil {
+		return
+	}
+	defer func() { recover() }()
+	onError(err)
+}
+
+/*
+Creates a wrapper that returns the panics of the wrapped chain,
+such as those of codecs and user hooks, as PanicError instead of
+crashing the caller
+*/
+func NewSafe(comm Unicomm) *Safe {
+	return &Safe{Unicomm: comm}
+}
+
+/*
+Establishes the connection
+*/
+func (s *Safe) Connect() (err error) {
+	defer recoverError(&err)
+	return s.Unicomm.Connect()
+}
+
+/*
+Closes the connection
+*/
+func (s *Safe) Disconnect() (err error) {
+	defer recoverError(&err)
+	return s.Unicomm.Disconnect()
+}
+
+/*
+Returns true if the connection is established, and false when
+the check panics
+*/
+func (s *Safe) IsConnected() bool {
+	connected, err := guard(func() (bool, error) {
+		return s.Unicomm.IsConnected(), nil
+	})
+	return connected && err == nil
+}
+
+/*
+Reads a number of bytes
+*/
+func (s *Safe) Read(n uint) (data []byte, err error) {
+	defer recoverError(&err)
+	return s.Unicomm.Read(n)
+}
+
+/*
+Reads data until a target delimiter is found
+*/
+func (s *Safe) ReadUntil(delimiter string) (data []byte, err error) {
+	defer recoverError(&err)
+	return ReadUntil(s.Unicomm, delimiter)
+}
+
+/*
+Reads everything currently buffered by the instance
+*/
+func (s *Safe) ReadAvailable() (data []byte, err error) {
+	defer recoverError(&err)
+	return ReadAvailable(s.Unicomm)
+}
+
+/*
+Writes an array of bytes
+*/
+func (s *Safe) Write(message []byte) (err error) {
+	defer recoverError(&err)
+	return s.Unicomm.Write(message)
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (s *Safe) WriteRaw(message []byte) (err error) {
+	defer recoverError(&err)
+	return writeRaw(s.Unicomm, message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (s *Safe) Unwrap() Unicomm {
+	return s.Unicomm
+}
@@ -0,0 +1,68 @@
+package unicomm_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+type panickyTransform struct{}
+
+func (panickyTransform) Encode(payload []byte) []byte {
+	panic("encode failed")
+}
+
+func (panickyTransform) Decode(data []byte) ([]byte, error) {
+	if string(data) == "bad" {
+		panic("decode failed")
+	}
+	return data, nil
+}
+
+func TestRecoverPanics(t *testing.T) {
+	port := serveOnce(t, []byte("bad\ngood\n"))
+	comm := unicomm.New(unicomm.Options{
+		Protocol:      unicomm.TCP,
+		TCP:           unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+		Delimiter:     "\n",
+		Transforms:    []unicomm.PayloadTransform{panickyTransform{}},
+		RecoverPanics: true,
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	var panicErr *unicomm.PanicError
+	if err := comm.Write([]byte("ping\n")); !errors.As(err, &panicErr) || panicErr.Value != "encode failed" {
+		t.Fatalf("write returned %v", err)
+	}
+
+	var mutex sync.Mutex
+	var reported []error
+	reader := unicomm.NewBackgroundReader(comm, unicomm.ReaderOptions{
+		Delimiter: "\n",
+		OnError: func(err error) {
+			mutex.Lock()
+			reported = append(reported, err)
+			mutex.Unlock()
+			panic("callback failed too")
+		},
+	})
+	reader.Start()
+	defer reader.Stop()
+
+	frame, err := reader.Next(time.Second)
+	if err != nil || string(frame.Payload) != "good\n" {
+		t.Fatalf("next returned %q, %v", frame.Payload, err)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(reported) != 1 || !errors.As(reported[0], &panicErr) || len(panicErr.Stack) == 0 {
+		t.Fatalf("unexpected errors %v", reported)
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+/*
+Duration that can be written as text, e.g. "250ms" or "2s",
+in JSON and YAML scripts
+*/
+type StepDuration time.Duration
+
+/*
+A single step of a script. The line is sent first, then the
+output is read until the expected pattern (a regular
+expression) and finally the step waits for the delay
+*/
+type ScriptStep struct {
+	Name    string       `json:"name" yaml:"name"`
+	Send    string       `json:"send" yaml:"send"`
+	Expect  string       `json:"expect" yaml:"expect"`
+	Timeout StepDuration `json:"timeout" yaml:"timeout"`
+	Delay   StepDuration `json:"delay" yaml:"delay"`
+	Capture string       `json:"capture" yaml:"capture"` // Stores the output under this name
+}
+
+type Script struct {
+	Name    string        `json:"name" yaml:"name"`
+	Timeout StepDuration  `json:"timeout" yaml:"timeout"` // Default timeout of the steps
+	Expect  ExpectOptions `json:"-" yaml:"-"`
+	Steps   []ScriptStep  `json:"steps" yaml:"steps"`
+}
+
+type ScriptResult struct {
+	Outputs  []string          // Output of each executed step
+	Captures map[string]string // Outputs of the steps with a capture name
+}
+
+/*
+Parses a duration written as text
+*/
+func (sd *StepDuration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*sd = StepDuration(duration)
+	return nil
+}
+
+/*
+Formats the duration as text
+*/
+func (sd StepDuration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(sd).String()), nil
+}
+
+/*
+Loads a script from its JSON definition
+*/
+func LoadScript(reader io.Reader) (Script, error) {
+	var script Script
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&script); err != nil {
+		return Script{}, fmt.Errorf("invalid script: %w", err)
+	}
+	return script, nil
+}
+
+/*
+Loads a script from its YAML definition, with the same fields
+as the JSON one
+*/
+func LoadScriptYAML(reader io.Reader) (Script, error) {
+	var script Script
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&script); err != nil {
+		return Script{}, fmt.Errorf("invalid script: %w", err)
+	}
+	return script, nil
+}
+
+/*
+Executes the steps in order over the Unicomm instance, stopping
+at the first failure. The result contains the outputs of the
+steps executed until then
+*/
+func (s Script) Run(comm Unicomm) (ScriptResult, error) {
+	console := NewExpect(comm, s.Expect)
+	result := ScriptResult{Captures: make(map[string]string)}
+
+	for index, step := range s.Steps {
+		var output string
+		var err error
+
+		timeout := time.Duration(step.Timeout)
+		if timeout == 0 {
+			timeout = time.Duration(s.Timeout)
+		}
+		if timeout == 0 {
+			timeout = time.Second
+		}
+
+		if step.Send != "" {
+			err = console.Send(step.Send)
+		}
+		if err == nil && step.Expect != "" {
+			output, err = console.Expect(step.Expect, timeout)
+		}
+		result.Outputs = append(result.Outputs, output)
+		if step.Capture != "" {
+			result.Captures[step.Capture] = output
+		}
+		if err != nil {
+			return result, fmt.Errorf("script step %d (%s) failed: %w", index, step.Name, err)
+		}
+		time.Sleep(time.Duration(step.Delay))
+	}
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package unicomm_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestLoadScriptYAML(t *testing.T) {
+	script, err := unicomm.LoadScriptYAML(strings.NewReader(`
+name: provision
+timeout: 2s
+steps:
+  - name: ping
+    send: PING
+    expect: "\r?\n"
+    capture: reply
+  - send: DONE
+    expect: DONE
+    timeout: 500ms
+    delay: 10ms
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if script.Name != "provision" || len(script.Steps) != 2 {
+		t.Fatalf("unexpected script %+v", script)
+	}
+	if time.Duration(script.Timeout) != 2*time.Second || time.Duration(script.Steps[1].Timeout) != 500*time.Millisecond {
+		t.Fatalf("durations were not parsed: %+v", script)
+	}
+
+	// The loaded script runs as the JSON one does
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions()})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+	result, err := script.Run(comm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Captures["reply"] != "PING" {
+		t.Fatalf("captures = %q", result.Captures)
+	}
+
+	if _, err := unicomm.LoadScriptYAML(strings.NewReader("name: x\nretries: 3\n")); err == nil {
+		t.Fatal("expected an unknown field to be rejected")
+	}
+	if _, err := unicomm.LoadScriptYAML(strings.NewReader("timeout: soon\n")); err == nil {
+		t.Fatal("expected an invalid duration to be rejected")
+	}
+}
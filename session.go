@@ -0,0 +1,436 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+type SessionState uint8
+
+const (
+	SessionStopped    SessionState = 0 // Not started or stopped
+	SessionConnecting SessionState = 1 // Connecting or waiting to reconnect
+	SessionReady      SessionState = 2 // Connected and healthy
+	SessionFailed     SessionState = 3 // Gave up reconnecting, until resumed
+)
+
+/*
+Periodic check of the device. The command is queried and the
+response must contain Expect, or Check is called instead when
+set. Without both, only the connection is checked
+*/
+type HealthOptions struct {
+	Interval  time.Duration       // Period of the checks, defaults to 5s
+	Command   []byte              // Query sent to the device
+	Expect    string              // Required in the response
+	Check     func(*Client) error // Custom check, replaces the query
+	Failures  int                 // Consecutive failures before reconnecting, defaults to 1
+	Delimiter string              // Ends the responses, defaults to Transport.Delimiter or "\n"
+}
+
+/*
+Exponential backoff between connection attempts
+*/
+type ReconnectPolicy struct {
+	InitialDelay time.Duration // Defaults to 1s
+	MaxDelay     time.Duration // Defaults to 30s
+	Multiplier   float64       // Growth of the delay, defaults to 2
+	MaxAttempts  int           // Attempts before giving up, zero never gives up
+	MaxDuration  time.Duration // Total time retrying before giving up, zero never gives up
+}
+
+type SessionOptions struct {
+	Transport Options // Transport, warm-up and middleware of the device
+	Health    HealthOptions
+	Reconnect ReconnectPolicy
+	Logger    *slog.Logger       // Nil disables logging
+	OnState   func(SessionState) // Called on every state change
+	OnFailure func(error)        // Called when reconnecting gives up, e.g. to page operators
+}
+
+type SessionMetrics struct {
+	State          SessionState
+	Since          time.Time // When the current state started
+	Connects       uint64    // Successful connections, warm-up included
+	Attempts       uint64    // Failed connection attempts
+	HealthFailures uint64
+	LastError      error
+	Latency        map[Operation]LatencyHistogram
+}
+
+/*
+Managed device combining a transport, its warm-up commands, a
+health check, a reconnect policy, logging and metrics
+*/
+type Session struct {
+	Options SessionOptions
+
+	comm    Unicomm
+	client  *Client
+	monitor *LatencyMonitor
+	metrics SessionMetrics
+	ready   chan struct{} // Closed while the session is ready
+	stop    chan struct{}
+	done    chan struct{}
+	mutex   sync.Mutex // Protect the state and the metrics
+	running sync.Mutex // Protect start and stop
+}
+
+/*
+Returns the name of the state
+*/
+func (ss SessionState) String() string {
+	switch ss {
+	case SessionStopped:
+		return "stopped"
+	case SessionConnecting:
+		return "connecting"
+	case SessionReady:
+		return "ready"
+	case SessionFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("SessionState(%d)", ss)
+	}
+}
+
+/*
+Creates a session for the device. Latency metrics are always
+collected, with the default buckets unless configured
+*/
+func NewSession(options SessionOptions) *Session {
+	if options.Transport.Latency == nil {
+		options.Transport.Latency = &LatencyOptions{}
+	}
+	if options.Health.Interval == 0 {
+		options.Health.Interval = 5 * time.Second
+	}
+	if options.Health.Failures == 0 {
+		options.Health.Failures = 1
+	}
+	if options.Health.Delimiter == "" {
+		options.Health.Delimiter = options.Transport.Delimiter
+	}
+	if options.Health.Delimiter == "" {
+		options.Health.Delimiter = "\n"
+	}
+	if options.Reconnect.InitialDelay == 0 {
+		options.Reconnect.InitialDelay = time.Second
+	}
+	if options.Reconnect.MaxDelay == 0 {
+		options.Reconnect.MaxDelay = 30 * time.Second
+	}
+	if options.Reconnect.Multiplier == 0 {
+		options.Reconnect.Multiplier = 2
+	}
+	if options.Logger != nil && !options.Transport.Metadata.IsZero() {
+		options.Logger = options.Logger.With("device", options.Transport.Metadata)
+	}
+
+	comm := New(options.Transport)
+	monitor, _ := As[*LatencyMonitor](comm)
+	return &Session{
+		Options: options,
+		comm:    comm,
+		client:  NewClient(comm, options.Health.Delimiter),
+		monitor: monitor,
+		metrics: SessionMetrics{Since: time.Now()},
+		ready:   make(chan struct{}),
+	}
+}
+
+/*
+Returns the instance of the device, for drivers reading and
+writing directly
+*/
+func (s *Session) Comm() Unicomm {
+	return s.comm
+}
+
+/*
+Returns the client shared with the health checks, so driver
+queries never interleave with them
+*/
+func (s *Session) Client() *Client {
+	return s.client
+}
+
+/*
+Returns the current state
+*/
+func (s *Session) State() SessionState {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.metrics.State
+}
+
+/*
+Returns the counters of the session and the latency histograms
+of the device
+*/
+func (s *Session) Metrics() SessionMetrics {
+	s.mutex.Lock()
+	metrics := s.metrics
+	s.mutex.Unlock()
+
+	if s.monitor != nil {
+		metrics.Latency = s.monitor.Histograms()
+	}
+	return metrics
+}
+
+/*
+Logs a message when a logger is configured
+*/
+func (s *Session) log(level slog.Level, message string, args ...any) {
+	if s.Options.Logger != nil {
+		s.Options.Logger.Log(context.Background(), level, message, args...)
+	}
+}
+
+/*
+Changes the state and notifies it, on the event bus of the
+transport too
+*/
+func (s *Session) setState(state SessionState) {
+	s.mutex.Lock()
+	if s.metrics.State == state {
+		s.mutex.Unlock()
+		return
+	}
+	if state == SessionReady {
+		close(s.ready)
+	} else if s.metrics.State == SessionReady {
+		s.ready = make(chan struct{})
+	}
+	s.metrics.State = state
+	s.metrics.Since = time.Now()
+	s.mutex.Unlock()
+
+	s.log(slog.LevelInfo, "session state changed", "state", state.String())
+	s.Options.Transport.Events.Publish(Event{
+		Kind:   EventState,
+		Source: s.Options.Transport.Metadata.String(),
+		State:  state.String(),
+	})
+	if s.Options.OnState != nil {
+		s.Options.OnState(state)
+	}
+}
+
+/*
+Records an error in the metrics
+*/
+func (s *Session) fail(err error, update func(*SessionMetrics)) {
+	s.mutex.Lock()
+	s.metrics.LastError = err
+	update(&s.metrics)
+	s.mutex.Unlock()
+}
+
+/*
+Starts connecting to the device in background. The session
+reconnects by itself until stopped
+*/
+func (s *Session) Start() error {
+	s.running.Lock()
+	defer s.running.Unlock()
+
+	if s.stop != nil {
+		return fmt.Errorf("session is already started")
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	s.setState(SessionConnecting)
+	go s.run(s.stop, s.done)
+	return nil
+}
+
+/*
+Stops the session and disconnects from the device
+*/
+func (s *Session) Stop() error {
+	s.running.Lock()
+	defer s.running.Unlock()
+
+	if s.stop == nil {
+		return fmt.Errorf("session is not started")
+	}
+	close(s.stop)
+	<-s.done
+	s.stop = nil
+
+	if s.comm.IsConnected() {
+		s.comm.Disconnect()
+	}
+	s.setState(SessionStopped)
+	return nil
+}
+
+/*
+Restarts the connection attempts of a session that gave up,
+with the reconnect policy starting over
+*/
+func (s *Session) Resume() error {
+	s.running.Lock()
+	defer s.running.Unlock()
+
+	if s.stop == nil || s.State() != SessionFailed {
+		return fmt.Errorf("session has not failed")
+	}
+	<-s.done
+	s.done = make(chan struct{})
+	s.setState(SessionConnecting)
+	go s.run(s.stop, s.done)
+	return nil
+}
+
+/*
+Waits for the session to be ready
+*/
+func (s *Session) WaitReady(timeout time.Duration) error {
+	s.mutex.Lock()
+	ready := s.ready
+	s.mutex.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("session not ready after %v", timeout)
+	}
+}
+
+/*
+Connects and supervises the device until stopped
+*/
+func (s *Session) run(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	for {
+		if !s.connect(stop) {
+			return
+		}
+		s.setState(SessionReady)
+		if !s.supervise(stop) {
+			return
+		}
+		s.setState(SessionConnecting)
+		if s.comm.IsConnected() {
+			s.comm.Disconnect()
+		}
+	}
+}
+
+/*
+Connects following the reconnect policy. Returns false when the
+session is stopped or the policy gives up
+*/
+func (s *Session) connect(stop chan struct{}) bool {
+	policy := s.Options.Reconnect
+	start := time.Now()
+	delay := policy.InitialDelay
+	for attempt := 1; ; attempt++ {
+		err := s.comm.Connect()
+		if err == nil {
+			s.mutex.Lock()
+			s.metrics.Connects++
+			s.mutex.Unlock()
+			return true
+		}
+		s.fail(err, func(metrics *SessionMetrics) { metrics.Attempts++ })
+		s.log(slog.LevelWarn, "connection failed", "attempt", attempt, "error", err)
+
+		exhausted := policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts
+		if policy.MaxDuration > 0 && time.Since(start)+delay > policy.MaxDuration {
+			exhausted = true
+		}
+		if exhausted {
+			s.giveUp(fmt.Errorf("gave up connecting after %d attempts in %v: %w", attempt, time.Since(start).Round(time.Millisecond), err))
+			return false
+		}
+		select {
+		case <-stop:
+			return false
+		case <-time.After(delay):
+		}
+		delay = min(time.Duration(float64(delay)*policy.Multiplier), policy.MaxDelay)
+	}
+}
+
+/*
+Stops reconnecting until the session is resumed, reporting the
+terminal failure
+*/
+func (s *Session) giveUp(err error) {
+	s.fail(err, func(*SessionMetrics) {})
+	s.log(slog.LevelError, "giving up connecting", "error", err)
+	s.setState(SessionFailed)
+	report(s.Options.OnFailure, err)
+}
+
+/*
+Runs the health checks until they fail. Returns false when the
+session is stopped
+*/
+func (s *Session) supervise(stop chan struct{}) bool {
+	ticker := time.NewTicker(s.Options.Health.Interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-stop:
+			return false
+		case <-ticker.C:
+		}
+
+		err := s.check()
+		if err == nil {
+			failures = 0
+			continue
+		}
+		failures++
+		s.fail(err, func(metrics *SessionMetrics) { metrics.HealthFailures++ })
+		s.log(slog.LevelWarn, "health check failed", "failures", failures, "error", err)
+		if failures >= s.Options.Health.Failures {
+			return true
+		}
+	}
+}
+
+/*
+Runs one health check
+*/
+func (s *Session) check() error {
+	if !s.comm.IsConnected() {
+		return fmt.Errorf("connection lost")
+	}
+	_, err := guard(func() (any, error) {
+		switch {
+		case s.Options.Health.Check != nil:
+			return nil, s.Options.Health.Check(s.client)
+		case len(s.Options.Health.Command) > 0:
+			response, err := s.client.QueryFresh(s.Options.Health.Command)
+			if err != nil {
+				return nil, err
+			}
+			if !strings.Contains(string(response), s.Options.Health.Expect) {
+				return nil, fmt.Errorf("unexpected health response %q", response)
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
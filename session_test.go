@@ -0,0 +1,134 @@
+package unicomm_test
+
+import (
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestSessionReconnectsOnHealthFailure(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte {
+			switch strings.TrimSpace(string(request)) {
+			case "*RST":
+				return []byte("OK\n")
+			case "*IDN?":
+				if healthy.Load() {
+					return []byte("ACME,PSU\n")
+				}
+				return []byte("BUSY\n")
+			}
+			return nil
+		},
+	})
+
+	var states []unicomm.SessionState
+	session := unicomm.NewSession(unicomm.SessionOptions{
+		Transport: unicomm.Options{
+			Protocol: unicomm.TCP,
+			TCP:      server.TCPOptions(),
+			WarmUp:   []unicomm.WarmUpStep{{Command: []byte("*RST\n"), Expect: "OK"}},
+		},
+		Health: unicomm.HealthOptions{
+			Interval: 20 * time.Millisecond,
+			Command:  []byte("*IDN?\n"),
+			Expect:   "ACME",
+		},
+		Reconnect: unicomm.ReconnectPolicy{InitialDelay: 10 * time.Millisecond},
+		OnState:   func(state unicomm.SessionState) { states = append(states, state) },
+	})
+	if err := session.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.WaitReady(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	healthy.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	if err := session.WaitReady(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	response, err := session.Client().Query([]byte("*IDN?\n"))
+	if err != nil || string(response) != "ACME,PSU\n" {
+		t.Fatalf("query returned %q, %v", response, err)
+	}
+	if err := session.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	metrics := session.Metrics()
+	if metrics.Connects < 2 || metrics.HealthFailures == 0 || metrics.Latency[unicomm.OpQuery].Count == 0 {
+		t.Fatalf("unexpected metrics %+v", metrics)
+	}
+	if states[0] != unicomm.SessionConnecting || states[len(states)-1] != unicomm.SessionStopped {
+		t.Fatalf("unexpected states %v", states)
+	}
+}
+
+func TestSessionGivesUpAndResumes(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().(*net.TCPAddr)
+	listener.Close()
+
+	failures := make(chan error, 2)
+	session := unicomm.NewSession(unicomm.SessionOptions{
+		Transport: unicomm.Options{
+			Protocol: unicomm.TCP,
+			TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: uint(address.Port)},
+		},
+		Reconnect: unicomm.ReconnectPolicy{InitialDelay: 10 * time.Millisecond, MaxDuration: 100 * time.Millisecond},
+		OnFailure: func(err error) { failures <- err },
+	})
+	if err := session.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer session.Stop()
+
+	select {
+	case err := <-failures:
+		if !strings.Contains(err.Error(), "gave up connecting") {
+			t.Fatalf("unexpected failure %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the session did not give up")
+	}
+	if state := session.State(); state != unicomm.SessionFailed {
+		t.Fatalf("got state %v", state)
+	}
+
+	// The device comes back and attempts are restarted by hand
+	listener, err = net.Listen("tcp", address.String())
+	if err != nil {
+		t.Skipf("port was taken meanwhile: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+	if err := session.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.WaitReady(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.Resume(); err == nil {
+		t.Fatal("expected resuming a ready session to fail")
+	}
+}
@@ -0,0 +1,145 @@
+package unicomm_test
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestSharedPollerManyLinks(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	poller, err := unicommtcp.NewSharedPoller()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer poller.Close()
+
+	const links = 64
+	var mutex sync.Mutex
+	received := make(map[int]string)
+	closed := make(chan int, links)
+	devices := make([]*unicommtcp.UnicommTCP, links)
+	for index := range devices {
+		devices[index] = unicommtcp.NewTCP(server.TCPOptions())
+		if err := devices[index].Connect(); err != nil {
+			t.Fatal(err)
+		}
+		err := poller.Watch(devices[index], func(data []byte, err error) {
+			if err != nil {
+				if err == io.EOF {
+					closed <- index
+				}
+				return
+			}
+			mutex.Lock()
+			received[index] += string(data)
+			mutex.Unlock()
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if poller.Len() != links {
+		t.Fatalf("watching %d links", poller.Len())
+	}
+
+	for index, device := range devices {
+		if err := device.Write([]byte(fmt.Sprintf("device %d\n", index))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mutex.Lock()
+		complete := len(received) == links
+		for index, data := range received {
+			complete = complete && data == fmt.Sprintf("device %d\n", index)
+		}
+		mutex.Unlock()
+		if complete {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("received %v", received)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Unwatched links are read directly again
+	if err := poller.Unwatch(devices[0]); err != nil {
+		t.Fatal(err)
+	}
+	devices[0].Write([]byte("direct\n"))
+	unicommtest.ExpectFrame(t, devices[0], "\n", "direct\n")
+	devices[0].Disconnect()
+
+	server.Close()
+	for range links - 1 {
+		select {
+		case <-closed:
+		case <-time.After(2 * time.Second):
+			t.Fatal("peer close was not reported")
+		}
+	}
+	if poller.Len() != 0 {
+		t.Fatalf("still watching %d links", poller.Len())
+	}
+}
+
+func TestSharedPollerUnwatchWaitsForHandler(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	poller, err := unicommtcp.NewSharedPoller()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer poller.Close()
+
+	device := unicommtcp.NewTCP(server.TCPOptions())
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer device.Disconnect()
+
+	// Released on failures too, since a blocked handler holds Close
+	entered, release := make(chan struct{}), make(chan struct{})
+	var releaseOnce sync.Once
+	defer releaseOnce.Do(func() { close(release) })
+	err = poller.Watch(device, func(data []byte, err error) {
+		if err == nil {
+			close(entered)
+			<-release
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	device.Write([]byte("event\n"))
+	select {
+	case <-entered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler was not called")
+	}
+
+	// The handler in progress keeps Unwatch from returning
+	unwatched := make(chan error, 1)
+	go func() { unwatched <- poller.Unwatch(device) }()
+	select {
+	case err := <-unwatched:
+		t.Fatalf("Unwatch returned during the handler call: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	releaseOnce.Do(func() { close(release) })
+	select {
+	case err := <-unwatched:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Unwatch did not return after the handler")
+	}
+}
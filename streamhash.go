@@ -0,0 +1,155 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"encoding/hex"
+	"hash"
+	"hash/crc32"
+	"sync"
+	"time"
+)
+
+type StreamHashOptions struct {
+	New func() hash.Hash // Hash of the received stream, defaults to CRC-32 (IEEE)
+}
+
+type StreamStats struct {
+	Started     time.Time
+	Received    uint64 // Bytes received since the start
+	Transmitted uint64 // Bytes written since the start
+	Sum         []byte // Hash of every byte received, in order
+}
+
+type StreamHash struct {
+	Unicomm
+	Options StreamHashOptions
+
+	hash  hash.Hash
+	stats StreamStats
+	mutex sync.Mutex // Protect the hash and the counters
+}
+
+/*
+Returns the hash as hexadecimal, as usually printed by devices
+and checksum tools
+*/
+func (ss StreamStats) Hex() string {
+	return hex.EncodeToString(ss.Sum)
+}
+
+/*
+Creates a wrapper that keeps a rolling hash and byte counters
+of the received stream, so long captures can be verified against
+what the device reports having sent
+*/
+func NewStreamHash(comm Unicomm, options StreamHashOptions) *StreamHash {
+	if options.New == nil {
+		options.New = func() hash.Hash { return crc32.NewIEEE() }
+	}
+	return &StreamHash{
+		Unicomm: comm,
+		Options: options,
+		hash:    options.New(),
+		stats:   StreamStats{Started: time.Now()},
+	}
+}
+
+/*
+Feeds received data to the hash
+*/
+func (sh *StreamHash) receive(data []byte) {
+	sh.mutex.Lock()
+	defer sh.mutex.Unlock()
+
+	sh.hash.Write(data)
+	sh.stats.Received += uint64(len(data))
+}
+
+/*
+Returns the counters and the hash of the stream received so far
+*/
+func (sh *StreamHash) Stats() StreamStats {
+	sh.mutex.Lock()
+	defer sh.mutex.Unlock()
+
+	stats := sh.stats
+	stats.Sum = sh.hash.Sum(nil)
+	return stats
+}
+
+/*
+Restarts the hash and the counters, e.g. at the start of a
+new capture
+*/
+func (sh *StreamHash) Reset() {
+	sh.mutex.Lock()
+	defer sh.mutex.Unlock()
+
+	sh.hash.Reset()
+	sh.stats = StreamStats{Started: time.Now()}
+}
+
+/*
+Reads a number of bytes
+*/
+func (sh *StreamHash) Read(n uint) ([]byte, error) {
+	data, err := sh.Unicomm.Read(n)
+	sh.receive(data)
+	return data, err
+}
+
+/*
+Reads data until a target delimiter is found
+*/
+func (sh *StreamHash) ReadUntil(delimiter string) ([]byte, error) {
+	data, err := ReadUntil(sh.Unicomm, delimiter)
+	sh.receive(data)
+	return data, err
+}
+
+/*
+Reads everything currently buffered by the instance
+*/
+func (sh *StreamHash) ReadAvailable() ([]byte, error) {
+	data, err := ReadAvailable(sh.Unicomm)
+	sh.receive(data)
+	return data, err
+}
+
+/*
+Writes an array of bytes
+*/
+func (sh *StreamHash) Write(message []byte) error {
+	err := sh.Unicomm.Write(message)
+	if err == nil {
+		sh.mutex.Lock()
+		sh.stats.Transmitted += uint64(len(message))
+		sh.mutex.Unlock()
+	}
+	return err
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (sh *StreamHash) WriteRaw(message []byte) error {
+	err := writeRaw(sh.Unicomm, message)
+	if err == nil {
+		sh.mutex.Lock()
+		sh.stats.Transmitted += uint64(len(message))
+		sh.mutex.Unlock()
+	}
+	return err
+}
+
+/*
+Returns the wrapped instance
+*/
+func (sh *StreamHash) Unwrap() Unicomm {
+	return sh.Unicomm
+}
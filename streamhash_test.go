@@ -0,0 +1,39 @@
+package unicomm_test
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestStreamHash(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	stream := unicomm.NewStreamHash(
+		unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions()}),
+		unicomm.StreamHashOptions{},
+	)
+	if err := stream.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Disconnect()
+
+	// Frames read separately hash as one continuous stream
+	for _, frame := range []string{"S1,23.5\n", "S2,19.0\n"} {
+		if err := stream.Write([]byte(frame)); err != nil {
+			t.Fatal(err)
+		}
+		unicommtest.ExpectFrame(t, stream, "\n", frame)
+	}
+
+	stats := stream.Stats()
+	if stats.Received != 16 || stats.Transmitted != 16 {
+		t.Fatalf("unexpected counters %+v", stats)
+	}
+	want := crc32.ChecksumIEEE([]byte("S1,23.5\nS2,19.0\n"))
+	if got := binary.BigEndian.Uint32(stats.Sum); got != want {
+		t.Fatalf("got crc %08x, want %08x", got, want)
+	}
+}
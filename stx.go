@@ -0,0 +1,252 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+const (
+	STX byte = 0x02 // Start of text
+	ETX byte = 0x03 // End of text
+	DLE byte = 0x10 // Data link escape
+)
+
+type STXOptions struct {
+	Escape bool // Prefix STX, ETX and DLE inside the payload with DLE
+	BCC    bool // Append the XOR of the payload and ETX after ETX
+}
+
+type STXFramed struct {
+	Unicomm
+	Options STXOptions
+
+	pending []byte     // Decoded payload bytes not consumed yet
+	raw     []byte     // Received bytes of a frame not complete yet
+	ended   bool       // The frame in raw ended, its BCC is still missing
+	mutex   sync.Mutex // Protect pending bytes
+}
+
+/*
+Creates a middleware that sends each written message as a
+STX/ETX frame, as used by payment terminals and laboratory
+analyzers. The wrapped instance must not append delimiters
+*/
+func NewSTXFramed(comm Unicomm, options STXOptions) *STXFramed {
+	return &STXFramed{
+		Unicomm: comm,
+		Options: options,
+	}
+}
+
+/*
+Computes the block check character of the payload
+*/
+func blockCheck(payload []byte) byte {
+	bcc := ETX
+	for _, b := range payload {
+		bcc ^= b
+	}
+	return bcc
+}
+
+/*
+Encodes the payload into a frame
+*/
+func (s *STXFramed) encode(payload []byte) []byte {
+	frame := make([]byte, 0, len(payload)+4)
+	frame = append(frame, STX)
+	for _, b := range payload {
+		if s.Options.Escape && (b == STX || b == ETX || b == DLE) {
+			frame = append(frame, DLE)
+		}
+		frame = append(frame, b)
+	}
+	frame = append(frame, ETX)
+	if s.Options.BCC {
+		frame = append(frame, blockCheck(payload))
+	}
+	return frame
+}
+
+/*
+Decodes the bytes between STX and ETX, removing the escapes
+*/
+func (s *STXFramed) decode(body []byte) []byte {
+	if !s.Options.Escape {
+		return bytes.Clone(body)
+	}
+	payload := make([]byte, 0, len(body))
+	for i := 0; i < len(body); i++ {
+		if body[i] == DLE && i+1 < len(body) {
+			i++
+		}
+		payload = append(payload, body[i])
+	}
+	return payload
+}
+
+/*
+Returns true if the last byte of the data is escaped by an
+odd number of DLE bytes
+*/
+func (s *STXFramed) escaped(data []byte) bool {
+	if !s.Options.Escape {
+		return false
+	}
+	count := 0
+	for i := len(data) - 2; i >= 0 && data[i] == DLE; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+/*
+Reads the next frame and decodes it, must be called with the
+mutex locked. A frame interrupted by a timeout is kept and
+resumed by the next call. Frames failing validation are returned
+along with the error
+*/
+func (s *STXFramed) readFrame() (Frame, error) {
+	for !s.ended {
+		data, err := ReadUntil(s.Unicomm, string(ETX))
+		s.raw = append(s.raw, data...)
+		if err != nil {
+			return Frame{}, err
+		}
+		s.ended = !s.escaped(s.raw)
+	}
+	raw := s.raw
+	frame := Frame{Raw: raw, Received: time.Now(), Delimiter: string(ETX)}
+
+	// Bytes before the last unescaped STX are line noise
+	start := -1
+	for i := len(raw) - 1; i >= 0; i-- {
+		if raw[i] == STX && !s.escaped(raw[:i+1]) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		s.raw, s.ended = nil, false
+		frame.Err = fmt.Errorf("invalid STX frame: missing STX")
+		return frame, frame.Err
+	}
+	frame.Raw = raw[start:]
+	frame.Payload = s.decode(raw[start+1 : len(raw)-1])
+
+	if s.Options.BCC {
+		bcc, err := s.Unicomm.Read(1)
+		if err == nil && len(bcc) == 0 {
+			err = unicommio.TimeoutError("read BCC")
+		}
+		if err != nil {
+			return Frame{}, err
+		}
+		frame.Raw = append(frame.Raw, bcc...)
+		frame.Received = time.Now()
+		if expected := blockCheck(frame.Payload); bcc[0] != expected {
+			frame.Err = fmt.Errorf("invalid STX frame: BCC 0x%02X, expected 0x%02X", bcc[0], expected)
+		}
+	}
+	s.raw, s.ended = nil, false
+	return frame, frame.Err
+}
+
+/*
+Reads the next frame. The remainder of a frame partially
+consumed by Read is returned first, without raw bytes
+*/
+func (s *STXFramed) ReadFrame() (Frame, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.pending) > 0 {
+		payload := s.pending
+		s.pending = nil
+		return Frame{Payload: payload, Received: time.Now()}, nil
+	}
+	return s.readFrame()
+}
+
+/*
+Removes and returns the first n decoded bytes
+*/
+func (s *STXFramed) take(n int) []byte {
+	data := bytes.Clone(s.pending[:n])
+	s.pending = s.pending[n:]
+	return data
+}
+
+/*
+Reads up to n payload bytes
+*/
+func (s *STXFramed) Read(n uint) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.pending) == 0 {
+		frame, err := s.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		s.pending = frame.Payload
+	}
+	return s.take(min(int(n), len(s.pending))), nil
+}
+
+/*
+Reads payload data until a target delimiter is found
+*/
+func (s *STXFramed) ReadUntil(delimiter string) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for {
+		if index := bytes.Index(s.pending, []byte(delimiter)); index >= 0 {
+			return s.take(index + len(delimiter)), nil
+		}
+		frame, err := s.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		s.pending = append(s.pending, frame.Payload...)
+	}
+}
+
+/*
+Writes an array of bytes as a single frame
+*/
+func (s *STXFramed) Write(message []byte) error {
+	return s.Unicomm.Write(s.encode(message))
+}
+
+/*
+Writes an array of bytes as a single frame, without the end
+delimiter of the backend
+*/
+func (s *STXFramed) WriteRaw(message []byte) error {
+	return writeRaw(s.Unicomm, s.encode(message))
+}
+
+/*
+Returns the wrapped instance
+*/
+func (s *STXFramed) Unwrap() Unicomm {
+	return s.Unicomm
+}
+
+/*
+Stops the lookup of data capabilities, which would bypass the
+decoding of the frames
+*/
+func (s *STXFramed) transformsData() {}
@@ -0,0 +1,73 @@
+package unicomm_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+func TestSTXFramedReadFrame(t *testing.T) {
+	// Line noise, then a frame whose payload contains an escaped ETX
+	payload := []byte{'a', unicomm.ETX, 'b'}
+	frame := []byte{'x', 'x', unicomm.STX, 'a', unicomm.DLE, unicomm.ETX, 'b', unicomm.ETX}
+	bcc := unicomm.ETX
+	for _, b := range payload {
+		bcc ^= b
+	}
+	frame = append(frame, bcc)
+
+	port := serveOnce(t, frame)
+	comm := unicomm.NewSTXFramed(unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+	}), unicomm.STXOptions{Escape: true, BCC: true})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	received, err := comm.ReadFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(received.Payload, payload) {
+		t.Fatalf("got %q, want %q", received.Payload, payload)
+	}
+	if !bytes.Equal(received.Raw, frame[2:]) || received.Delimiter != string(unicomm.ETX) || !received.Valid() {
+		t.Fatalf("unexpected frame metadata %+v", received)
+	}
+}
+
+func TestSTXFramedDelayedBCC(t *testing.T) {
+	frame := []byte{unicomm.STX, 'O', 'K', unicomm.ETX}
+	bcc := unicomm.ETX ^ 'O' ^ 'K'
+
+	// The frame is split, and its BCC arrives after the read timeout
+	port := serveChunks(t, 150*time.Millisecond, frame[:2], frame[2:], []byte{bcc})
+	comm := unicomm.NewSTXFramed(unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port, ReadTimeout: 50 * time.Millisecond},
+	}), unicomm.STXOptions{BCC: true})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		received, err := comm.ReadFrame()
+		if err == nil {
+			if string(received.Payload) != "OK" || !bytes.Equal(received.Raw, append(frame, bcc)) {
+				t.Fatalf("unexpected frame %+v", received)
+			}
+			return
+		}
+		if !unicommio.IsTimeout(err) || time.Now().After(deadline) {
+			t.Fatalf("delayed frame failed: %v", err)
+		}
+	}
+}
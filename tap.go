@@ -0,0 +1,160 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+type TapDirection uint8
+
+const (
+	Received    TapDirection = 0
+	Transmitted TapDirection = 1
+)
+
+type TapEvent struct {
+	Direction TapDirection
+	Data      []byte
+	Time      time.Time
+}
+
+type Tapped struct {
+	Unicomm
+	subscribers map[chan TapEvent]struct{}
+	mutex       sync.Mutex // Protect subscribers
+}
+
+/*
+Returns RX or TX
+*/
+func (d TapDirection) String() string {
+	if d == Transmitted {
+		return "TX"
+	}
+	return "RX"
+}
+
+/*
+Mirrors the traffic of a Unicomm instance to any number of
+read-only taps, without interfering with the operations
+*/
+func NewTapped(comm Unicomm) *Tapped {
+	return &Tapped{
+		Unicomm:     comm,
+		subscribers: make(map[chan TapEvent]struct{}),
+	}
+}
+
+/*
+Returns a stream with the received and transmitted data and a
+function to close it. Events are dropped when the stream is
+full, so a slow consumer never blocks the connection
+*/
+func (t *Tapped) Tap(size int) (<-chan TapEvent, func()) {
+	stream := make(chan TapEvent, size)
+
+	t.mutex.Lock()
+	t.subscribers[stream] = struct{}{}
+	t.mutex.Unlock()
+
+	var once sync.Once
+	return stream, func() {
+		once.Do(func() {
+			t.mutex.Lock()
+			delete(t.subscribers, stream)
+			t.mutex.Unlock()
+			close(stream)
+		})
+	}
+}
+
+/*
+Delivers the data to every tap
+*/
+func (t *Tapped) publish(direction TapDirection, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.subscribers) == 0 {
+		return
+	}
+	event := TapEvent{
+		Direction: direction,
+		Data:      bytes.Clone(data),
+		Time:      time.Now(),
+	}
+	for stream := range t.subscribers {
+		select {
+		case stream <- event:
+		default:
+		}
+	}
+}
+
+/*
+Reads a number of bytes
+*/
+func (t *Tapped) Read(n uint) ([]byte, error) {
+	data, err := t.Unicomm.Read(n)
+	t.publish(Received, data)
+	return data, err
+}
+
+/*
+Reads data until a target delimiter is found
+*/
+func (t *Tapped) ReadUntil(delimiter string) ([]byte, error) {
+	data, err := ReadUntil(t.Unicomm, delimiter)
+	t.publish(Received, data)
+	return data, err
+}
+
+/*
+Reads everything currently buffered by the instance
+*/
+func (t *Tapped) ReadAvailable() ([]byte, error) {
+	data, err := ReadAvailable(t.Unicomm)
+	t.publish(Received, data)
+	return data, err
+}
+
+/*
+Writes an array of bytes
+*/
+func (t *Tapped) Write(message []byte) error {
+	data := bytes.Clone(message)
+	err := t.Unicomm.Write(message)
+	if err == nil {
+		t.publish(Transmitted, data)
+	}
+	return err
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (t *Tapped) WriteRaw(message []byte) error {
+	data := bytes.Clone(message)
+	err := writeRaw(t.Unicomm, message)
+	if err == nil {
+		t.publish(Transmitted, data)
+	}
+	return err
+}
+
+/*
+Returns the wrapped instance
+*/
+func (t *Tapped) Unwrap() Unicomm {
+	return t.Unicomm
+}
@@ -0,0 +1,66 @@
+package unicomm_test
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestTappedMirrorsTraffic(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions()})
+	tapped := unicomm.NewTapped(comm)
+	if err := tapped.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer tapped.Disconnect()
+
+	stream, closeStream := tapped.Tap(8)
+	slow, closeSlow := tapped.Tap(1)
+	defer closeSlow()
+
+	if err := tapped.Write([]byte("PING\n")); err != nil {
+		t.Fatal(err)
+	}
+	unicommtest.ExpectFrame(t, tapped, "\n", "PING\n")
+
+	for _, want := range []struct {
+		direction unicomm.TapDirection
+		data      string
+	}{{unicomm.Transmitted, "PING\n"}, {unicomm.Received, "PING\n"}} {
+		event := <-stream
+		if event.Direction != want.direction || string(event.Data) != want.data || event.Time.IsZero() {
+			t.Fatalf("got %s %q, want %s %q", event.Direction, event.Data, want.direction, want.data)
+		}
+	}
+
+	// A full tap drops the events instead of blocking the operations
+	if event := <-slow; event.Direction != unicomm.Transmitted || len(slow) != 0 {
+		t.Fatalf("slow tap got %s and %d more events", event.Direction, len(slow))
+	}
+
+	// Closed taps stop receiving, and closing again is harmless
+	closeStream()
+	closeStream()
+	tapped.Write([]byte("PONG\n"))
+	if _, open := <-stream; open {
+		t.Fatal("closed tap received an event")
+	}
+}
+
+func TestTappedSkipsFailedWrites(t *testing.T) {
+	path := &failingPath{writeErr: syscall.EPIPE}
+	tapped := unicomm.NewTapped(path)
+	tapped.Connect()
+	stream, closeStream := tapped.Tap(4)
+	defer closeStream()
+
+	if err := tapped.Write([]byte("LOST\n")); err == nil {
+		t.Fatal("expected the write to fail")
+	}
+	if len(stream) != 0 {
+		t.Fatalf("failed write was mirrored: %q", (<-stream).Data)
+	}
+}
@@ -0,0 +1,94 @@
+package unicomm_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+/*
+Listens on an address and accepts connections until the test
+finishes, skipping the test when the address family is missing
+*/
+func listenFamily(t *testing.T, network, address string) uint {
+	t.Helper()
+
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		t.Skipf("%s unavailable: %v", network, err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	return uint(listener.Addr().(*net.TCPAddr).Port)
+}
+
+func TestTCPConnectIPv6(t *testing.T) {
+	port := listenFamily(t, "tcp6", "[::1]:0")
+
+	tests := []struct {
+		name    string
+		options unicommtcp.TCPOptions
+		fails   bool
+	}{
+		{"plain literal", unicommtcp.TCPOptions{Host: "::1", Port: port}, false},
+		{"bracketed literal", unicommtcp.TCPOptions{Host: "[::1]", Port: port}, false},
+		{"IPv6 only", unicommtcp.TCPOptions{Host: "[::1]", Port: port, Network: "tcp6"}, false},
+		{"IPv4 only", unicommtcp.TCPOptions{Host: "[::1]", Port: port, Network: "tcp4"}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			comm := unicommtcp.NewTCP(test.options)
+			err := comm.Connect()
+			if test.fails {
+				if err == nil {
+					comm.Disconnect()
+					t.Fatal("expected the connection to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer comm.Disconnect()
+			if remote := comm.Connection.RemoteAddr().(*net.TCPAddr); !remote.IP.Equal(net.IPv6loopback) {
+				t.Fatalf("connected to %v, want the IPv6 loopback", remote)
+			}
+		})
+	}
+}
+
+func TestTCPConnectFallback(t *testing.T) {
+	addresses, err := net.LookupIP("localhost")
+	if err != nil || len(addresses) < 2 || (addresses[0].To4() == nil) == (addresses[len(addresses)-1].To4() == nil) {
+		t.Skipf("localhost does not resolve to both address families: %v", addresses)
+	}
+
+	// Only the family dialed last listens, so the connection is made by
+	// the fallback racing the first family
+	network, address := "tcp6", "[::1]:0"
+	if addresses[0].To4() == nil {
+		network, address = "tcp4", "127.0.0.1:0"
+	}
+	port := listenFamily(t, network, address)
+
+	comm := unicommtcp.NewTCP(unicommtcp.TCPOptions{
+		Host:          "localhost",
+		Port:          port,
+		DialTimeout:   2 * time.Second,
+		FallbackDelay: 50 * time.Millisecond,
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	comm.Disconnect()
+}
@@ -0,0 +1,111 @@
+package unicomm_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestTCPOptionsDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		options unicommtcp.TCPOptions
+		want    unicommtcp.TCPOptions
+	}{
+		{
+			"zero values",
+			unicommtcp.TCPOptions{Host: "10.0.0.5", Port: 502},
+			unicommtcp.TCPOptions{
+				Host:            "10.0.0.5",
+				Port:            502,
+				Network:         "tcp",
+				ReadTimeout:     100 * time.Millisecond,
+				WriteTimeout:    100 * time.Millisecond,
+				MaxReadDuration: 5 * time.Second,
+				DialTimeout:     500 * time.Millisecond,
+				FallbackDelay:   250 * time.Millisecond,
+			},
+		},
+		{
+			"values kept",
+			unicommtcp.TCPOptions{Network: "tcp6", ReadTimeout: time.Second, WriteTimeout: time.Second, MaxReadDuration: time.Minute, DialTimeout: time.Second, FallbackDelay: -1},
+			unicommtcp.TCPOptions{Network: "tcp6", ReadTimeout: time.Second, WriteTimeout: time.Second, MaxReadDuration: time.Minute, DialTimeout: time.Second, FallbackDelay: -1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.options.Defaults(); got != test.want {
+				t.Fatalf("Defaults = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTCPOptionsValidate(t *testing.T) {
+	valid := unicommtcp.TCPOptions{Host: "10.0.0.5", Port: 502}.Defaults()
+	tests := []struct {
+		name   string
+		change func(options *unicommtcp.TCPOptions)
+		err    string // Part of the error, empty when valid
+	}{
+		{"defaults", func(options *unicommtcp.TCPOptions) {}, ""},
+		{"empty host", func(options *unicommtcp.TCPOptions) { options.Host = "" }, "host"},
+		{"zero port", func(options *unicommtcp.TCPOptions) { options.Port = 0 }, "port"},
+		{"port out of range", func(options *unicommtcp.TCPOptions) { options.Port = 70000 }, "port"},
+		{"IPv6 only", func(options *unicommtcp.TCPOptions) { options.Network = "tcp6" }, ""},
+		{"unknown network", func(options *unicommtcp.TCPOptions) { options.Network = "udp" }, "network"},
+		{"negative timeout", func(options *unicommtcp.TCPOptions) { options.DialTimeout = -time.Second }, "negative"},
+		{"burst without rate", func(options *unicommtcp.TCPOptions) { options.BurstBytes = 64 }, "burst"},
+		{"burst with rate", func(options *unicommtcp.TCPOptions) {
+			options.BurstBytes = 64
+			options.MaxBytesPerSecond = 1024
+		}, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := valid
+			test.change(&options)
+			err := options.Validate()
+			if test.err == "" && err != nil {
+				t.Fatalf("Validate = %v, want valid options", err)
+			}
+			if test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)) {
+				t.Fatalf("Validate = %v, want an error about %s", err, test.err)
+			}
+		})
+	}
+}
+
+func TestTCPConnectWhileReconfigured(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	comm := unicommtcp.NewTCP(server.TCPOptions())
+
+	// Run with the race detector, the options must not be read unlocked
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				comm.Reconfigure(unicommtcp.TCPOptions{ReadTimeout: 50 * time.Millisecond})
+			}
+		}
+	}()
+	defer wg.Wait()
+	defer close(stop)
+
+	for range 20 {
+		if err := comm.Connect(); err != nil {
+			t.Fatal(err)
+		}
+		comm.Disconnect()
+	}
+}
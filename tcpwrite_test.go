@@ -0,0 +1,96 @@
+package unicomm_test
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+/*
+Result of a single write of a scripted connection
+*/
+type writeResult struct {
+	n   int
+	err error
+}
+
+/*
+Connection returning the scripted results in order, and the last
+one from then on. Only the methods used by writes are provided
+*/
+type scriptedConn struct {
+	net.Conn
+	results []writeResult
+	written []byte
+}
+
+func (sc *scriptedConn) SetWriteDeadline(deadline time.Time) error { return nil }
+
+func (sc *scriptedConn) Write(data []byte) (int, error) {
+	result := sc.results[0]
+	if len(sc.results) > 1 {
+		sc.results = sc.results[1:]
+	}
+	n := min(result.n, len(data))
+	sc.written = append(sc.written, data[:n]...)
+	return n, result.err
+}
+
+func TestTCPWriteContinues(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []writeResult
+	}{
+		{"short writes", []writeResult{{3, nil}, {2, nil}, {100, nil}}},
+		{"temporary errors", []writeResult{{3, syscall.EAGAIN}, {0, syscall.EAGAIN}, {100, nil}}},
+		{"error with progress", []writeResult{{3, syscall.EINTR}, {100, nil}}},
+		{"fatal error with progress", []writeResult{{3, syscall.ECONNRESET}, {100, nil}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := &scriptedConn{results: test.results}
+			comm := unicommtcp.NewTCPFromConn(conn, unicommtcp.TCPOptions{WriteTimeout: time.Second})
+			if err := comm.WriteRaw([]byte("MEAS:VOLT?\n")); err != nil {
+				t.Fatal(err)
+			}
+			if string(conn.written) != "MEAS:VOLT?\n" {
+				t.Fatalf("wrote %q", conn.written)
+			}
+		})
+	}
+}
+
+func TestTCPWriteReportsCommittedBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []writeResult
+		written int
+		err     error
+	}{
+		{"fatal error", []writeResult{{4, nil}, {0, syscall.EPIPE}}, 4, syscall.EPIPE},
+		{"temporary until the deadline", []writeResult{{2, nil}, {0, syscall.EAGAIN}}, 2, syscall.EAGAIN},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn := &scriptedConn{results: test.results}
+			comm := unicommtcp.NewTCPFromConn(conn, unicommtcp.TCPOptions{WriteTimeout: 50 * time.Millisecond})
+			start := time.Now()
+			err := comm.WriteRaw([]byte("MEAS:VOLT?\n"))
+			var partial *unicommio.PartialWriteError
+			if !errors.As(err, &partial) || partial.Written != test.written || partial.Expected != 11 {
+				t.Fatalf("WriteRaw = %v, want %d bytes committed", err, test.written)
+			}
+			if !errors.Is(err, test.err) {
+				t.Fatalf("WriteRaw = %v, want %v", err, test.err)
+			}
+			if test.err == syscall.EPIPE && time.Since(start) > 40*time.Millisecond {
+				t.Fatal("fatal error was retried until the deadline")
+			}
+		})
+	}
+}
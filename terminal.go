@@ -0,0 +1,259 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"time"
+)
+
+type TerminalOptions struct {
+	Address       string        // Listen address, e.g. ":7000"
+	MaxClients    int           // Simultaneous clients, defaults to one
+	SharedControl bool          // Every client may write, not only the first one
+	ChunkSize     uint          // Bytes requested from the device on each read
+	WriteTimeout  time.Duration // Slow clients are dropped after this
+	RetryDelay    time.Duration // Wait after a failed device read
+	OnError       func(error)   // Called for errors other than timeouts
+}
+
+type TerminalServer struct {
+	Options TerminalOptions
+
+	comm     Unicomm
+	listener net.Listener
+	clients  []net.Conn // Oldest first, the first one controls the device
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	mutex    sync.Mutex // Protect clients and running state
+}
+
+/*
+Creates a server that shares a connected instance, typically a
+serial console, with remote TCP clients. Only the first client
+writes to the device unless control is shared, while the others
+observe the output
+*/
+func NewTerminalServer(comm Unicomm, options TerminalOptions) *TerminalServer {
+	if options.MaxClients == 0 {
+		options.MaxClients = 1
+	}
+	if options.ChunkSize == 0 {
+		options.ChunkSize = 1024
+	}
+	if options.WriteTimeout == 0 {
+		options.WriteTimeout = time.Second
+	}
+	if options.RetryDelay == 0 {
+		options.RetryDelay = 100 * time.Millisecond
+	}
+	return &TerminalServer{
+		Options: options,
+		comm:    comm,
+	}
+}
+
+/*
+Starts listening for clients and forwarding the device output
+*/
+func (ts *TerminalServer) Start() error {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	if ts.stop != nil {
+		return fmt.Errorf("terminal server is already running")
+	}
+	listener, err := net.Listen("tcp", ts.Options.Address)
+	if err != nil {
+		return err
+	}
+	ts.listener = listener
+	ts.stop = make(chan struct{})
+	ts.wg.Add(2)
+	go ts.accept(listener)
+	go ts.forward(ts.stop)
+	return nil
+}
+
+/*
+Stops the server and disconnects the clients. The device is
+left connected
+*/
+func (ts *TerminalServer) Stop() error {
+	ts.mutex.Lock()
+	if ts.stop == nil {
+		ts.mutex.Unlock()
+		return fmt.Errorf("terminal server is not running")
+	}
+	close(ts.stop)
+	ts.listener.Close()
+	for _, client := range ts.clients {
+		client.Close()
+	}
+	ts.clients = nil
+	ts.mutex.Unlock()
+
+	ts.wg.Wait()
+
+	ts.mutex.Lock()
+	ts.stop = nil
+	ts.mutex.Unlock()
+	return nil
+}
+
+/*
+Returns the address the server is listening on
+*/
+func (ts *TerminalServer) Addr() net.Addr {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	if ts.listener == nil {
+		return nil
+	}
+	return ts.listener.Addr()
+}
+
+/*
+Returns the number of connected clients
+*/
+func (ts *TerminalServer) Clients() int {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	return len(ts.clients)
+}
+
+/*
+Reports an error other than a timeout
+*/
+func (ts *TerminalServer) fail(err error) {
+	if !isTimeout(err) {
+		report(ts.Options.OnError, err)
+	}
+}
+
+/*
+Accepts clients until the listener is closed
+*/
+func (ts *TerminalServer) accept(listener net.Listener) {
+	defer ts.wg.Done()
+
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			ts.fail(err)
+			continue
+		}
+
+		ts.mutex.Lock()
+		if len(ts.clients) >= ts.Options.MaxClients {
+			ts.mutex.Unlock()
+			conn.Write([]byte("terminal server is full\r\n"))
+			conn.Close()
+			continue
+		}
+		ts.clients = append(ts.clients, conn)
+		ts.mutex.Unlock()
+
+		ts.wg.Add(1)
+		go ts.serve(conn)
+	}
+}
+
+/*
+Returns true if the client may write to the device
+*/
+func (ts *TerminalServer) canWrite(conn net.Conn) bool {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	return ts.Options.SharedControl || (len(ts.clients) > 0 && ts.clients[0] == conn)
+}
+
+/*
+Removes the client, handing control to the next oldest one
+*/
+func (ts *TerminalServer) remove(conn net.Conn) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	ts.clients = slices.DeleteFunc(ts.clients, func(client net.Conn) bool {
+		return client == conn
+	})
+	conn.Close()
+}
+
+/*
+Writes the input of the client to the device, discarding the
+input of observers
+*/
+func (ts *TerminalServer) serve(conn net.Conn) {
+	defer ts.wg.Done()
+	defer ts.remove(conn)
+
+	buffer := make([]byte, 1024)
+	for {
+		n, err := conn.Read(buffer)
+		if err != nil {
+			return
+		}
+		if !ts.canWrite(conn) {
+			continue
+		}
+		if err := ts.comm.Write(buffer[:n]); err != nil {
+			ts.fail(err)
+		}
+	}
+}
+
+/*
+Sends the device output to every client until stopped
+*/
+func (ts *TerminalServer) forward(stop chan struct{}) {
+	defer ts.wg.Done()
+
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		data, err := guard(func() ([]byte, error) {
+			return ts.comm.Read(ts.Options.ChunkSize)
+		})
+		if err != nil {
+			ts.fail(err)
+			if !isTimeout(err) {
+				time.Sleep(ts.Options.RetryDelay)
+			}
+			continue
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		ts.mutex.Lock()
+		clients := slices.Clone(ts.clients)
+		ts.mutex.Unlock()
+
+		for _, client := range clients {
+			client.SetWriteDeadline(time.Now().Add(ts.Options.WriteTimeout))
+			if _, err := client.Write(data); err != nil {
+				ts.remove(client)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package unicomm_test
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+func TestTerminalServerObserver(t *testing.T) {
+	device, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer device.Close()
+
+	// The device echoes every line it receives
+	go func() {
+		conn, err := device.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buffer := make([]byte, 1024)
+		for {
+			n, err := conn.Read(buffer)
+			if err != nil {
+				return
+			}
+			conn.Write(buffer[:n])
+		}
+	}()
+
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP: unicommtcp.TCPOptions{
+			Host: "127.0.0.1",
+			Port: uint(device.Addr().(*net.TCPAddr).Port),
+		},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	server := unicomm.NewTerminalServer(comm, unicomm.TerminalOptions{
+		Address:    "127.0.0.1:0",
+		MaxClients: 2,
+	})
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	controller, err := net.Dial("tcp", server.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer controller.Close()
+	observer, err := net.Dial("tcp", server.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer observer.Close()
+
+	for server.Clients() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Input of the observer is discarded, the controller reaches the device
+	observer.Write([]byte("ignored\n"))
+	time.Sleep(50 * time.Millisecond)
+	controller.Write([]byte("show version\n"))
+
+	observer.SetReadDeadline(time.Now().Add(2 * time.Second))
+	line, err := bufio.NewReader(observer).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line != "show version\n" {
+		t.Fatalf("observer got %q", line)
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+type Newline uint8
+
+const (
+	NewlineKeep Newline = 0 // Line endings are not changed
+	NewlineLF   Newline = 1
+	NewlineCR   Newline = 2
+	NewlineCRLF Newline = 3
+)
+
+type TextOptions struct {
+	Read  Newline // Line ending of the received text
+	Write Newline // Line ending of the written text
+
+	// Telnet NVT padding: a received CR NUL is read as CR, and a
+	// written CR not followed by LF is sent as CR NUL
+	CRNUL bool
+}
+
+/*
+Newline rules of Telnet console servers: received lines end
+with LF, written ones with CRLF, and bare CRs are NUL padded
+*/
+var TelnetText = TextOptions{Read: NewlineLF, Write: NewlineCRLF, CRNUL: true}
+
+type Text struct {
+	Unicomm
+	Options TextOptions
+
+	lastCR  bool       // Last received byte was a CR, a following LF is dropped
+	rawCR   bool       // Last received byte was a CR, a following NUL is dropped
+	pending []byte     // Normalized bytes not consumed yet
+	mutex   sync.Mutex // Protect pending bytes
+}
+
+/*
+Returns the bytes of the line ending
+*/
+func (n Newline) bytes() []byte {
+	switch n {
+	case NewlineLF:
+		return []byte("\n")
+	case NewlineCR:
+		return []byte("\r")
+	case NewlineCRLF:
+		return []byte("\r\n")
+	}
+	return nil
+}
+
+/*
+Returns true if no normalization is configured
+*/
+func (to TextOptions) IsZero() bool {
+	return to.Read == NewlineKeep && to.Write == NewlineKeep && !to.CRNUL
+}
+
+/*
+Creates a middleware that normalizes CR, LF and CRLF line
+endings of the received and written text, each direction
+with its own line ending, and handles the NUL padding of
+Telnet when enabled
+*/
+func NewText(comm Unicomm, options TextOptions) *Text {
+	return &Text{
+		Unicomm: comm,
+		Options: options,
+	}
+}
+
+/*
+Replaces every CR, LF and CRLF of the data by the line ending.
+When the previous data ended with CR, a leading LF is dropped
+as the rest of that CRLF. Returns whether the data ended with CR
+*/
+func normalizeNewlines(data []byte, newline []byte, lastCR bool) ([]byte, bool) {
+	normalized := make([]byte, 0, len(data))
+	for i, b := range data {
+		switch {
+		case b == '\n' && (i == 0 && lastCR || i > 0 && data[i-1] == '\r'):
+			continue
+		case b == '\r' || b == '\n':
+			normalized = append(normalized, newline...)
+		default:
+			normalized = append(normalized, b)
+		}
+	}
+	if len(data) == 0 {
+		return normalized, lastCR
+	}
+	return normalized, data[len(data)-1] == '\r'
+}
+
+/*
+Removes the NUL padding each bare CR. Returns whether the data
+ended with CR
+*/
+func stripCRNUL(data []byte, lastCR bool) ([]byte, bool) {
+	stripped := make([]byte, 0, len(data))
+	for i, b := range data {
+		if b == 0 && (i == 0 && lastCR || i > 0 && data[i-1] == '\r') {
+			continue
+		}
+		stripped = append(stripped, b)
+	}
+	if len(data) == 0 {
+		return stripped, lastCR
+	}
+	return stripped, data[len(data)-1] == '\r'
+}
+
+/*
+Pads with NUL every CR not followed by LF
+*/
+func padCRNUL(message []byte) []byte {
+	padded := make([]byte, 0, len(message))
+	for i, b := range message {
+		padded = append(padded, b)
+		if b == '\r' && (i+1 == len(message) || message[i+1] != '\n') {
+			padded = append(padded, 0)
+		}
+	}
+	return padded
+}
+
+/*
+Reads a chunk and normalizes it into the pending bytes, must be
+called with the mutex locked
+*/
+func (t *Text) fill() error {
+	data, err := t.Unicomm.Read(unicommio.ReadChunkSize)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return unicommio.TimeoutError("read")
+	}
+	if t.Options.CRNUL {
+		data, t.rawCR = stripCRNUL(data, t.rawCR)
+	}
+	if t.Options.Read == NewlineKeep {
+		t.pending = append(t.pending, data...)
+		return nil
+	}
+	normalized, lastCR := normalizeNewlines(data, t.Options.Read.bytes(), t.lastCR)
+	t.pending = append(t.pending, normalized...)
+	t.lastCR = lastCR
+	return nil
+}
+
+/*
+Removes and returns the first n normalized bytes
+*/
+func (t *Text) take(n int) []byte {
+	data := bytes.Clone(t.pending[:n])
+	t.pending = t.pending[n:]
+	return data
+}
+
+/*
+Reads up to n normalized bytes
+*/
+func (t *Text) Read(n uint) ([]byte, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for len(t.pending) == 0 {
+		if err := t.fill(); err != nil {
+			return nil, err
+		}
+	}
+	return t.take(min(int(n), len(t.pending))), nil
+}
+
+/*
+Reads normalized text until a target delimiter is found, which
+is matched after the normalization. On timeout the partial text
+is returned with the error, so it is not returned again by the
+next read
+*/
+func (t *Text) ReadUntil(delimiter string) ([]byte, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for {
+		if index := bytes.Index(t.pending, []byte(delimiter)); index >= 0 {
+			return t.take(index + len(delimiter)), nil
+		}
+		if err := t.fill(); err != nil {
+			return t.take(len(t.pending)), err
+		}
+	}
+}
+
+/*
+Writes text with its line endings normalized
+*/
+func (t *Text) Write(message []byte) error {
+	if t.Options.Write != NewlineKeep {
+		message, _ = normalizeNewlines(message, t.Options.Write.bytes(), false)
+	}
+	if t.Options.CRNUL {
+		message = padCRNUL(message)
+	}
+	return t.Unicomm.Write(message)
+}
+
+/*
+Discards the pending text
+*/
+func (t *Text) Disconnect() error {
+	t.mutex.Lock()
+	t.pending = nil
+	t.lastCR = false
+	t.rawCR = false
+	t.mutex.Unlock()
+
+	return t.Unicomm.Disconnect()
+}
+
+/*
+Writes an array of bytes without the end delimiter, converting
+the newlines as Write does
+*/
+func (t *Text) WriteRaw(message []byte) error {
+	if t.Options.Write != NewlineKeep {
+		message, _ = normalizeNewlines(message, t.Options.Write.bytes(), false)
+	}
+	if t.Options.CRNUL {
+		message = padCRNUL(message)
+	}
+	return writeRaw(t.Unicomm, message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (t *Text) Unwrap() Unicomm {
+	return t.Unicomm
+}
+
+/*
+Stops the lookup of data capabilities, which would bypass the
+newline conversion
+*/
+func (t *Text) transformsData() {}
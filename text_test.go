@@ -0,0 +1,83 @@
+package unicomm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestTextNormalizesLineEndings(t *testing.T) {
+	port := serveOnce(t, []byte("first\rsecond\r\nthird\n"))
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port},
+		Text:     unicomm.TextOptions{Read: unicomm.NewlineLF},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	for _, expected := range []string{"first\n", "second\n", "third\n"} {
+		line, err := unicomm.ReadUntil(comm, "\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(line) != expected {
+			t.Fatalf("got %q, want %q", line, expected)
+		}
+	}
+}
+
+func TestTelnetText(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte { return []byte("login:\r\x00\rprompt>\r\n") },
+	})
+	tcp := server.TCPOptions()
+	tcp.ReadTimeout = time.Second
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      tcp,
+		Text:     unicomm.TextOptions{Read: unicomm.NewlineLF, CRNUL: true},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	// A bare CR is sent padded with NUL
+	if err := comm.Write([]byte("user\r")); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"login:\n", "\n", "prompt>\n"} {
+		unicommtest.ExpectFrame(t, comm, "\n", expected)
+	}
+	if received := string(server.Received()); received != "user\r\x00" {
+		t.Fatalf("server received %q", received)
+	}
+}
+
+func TestTextTimeoutReturnsPartialOnce(t *testing.T) {
+	port := serveOnce(t, []byte("partial"))
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port, ReadTimeout: 50 * time.Millisecond},
+		Text:     unicomm.TextOptions{Read: unicomm.NewlineLF},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	line, err := unicomm.ReadUntil(comm, "\n")
+	if !unicommio.IsTimeout(err) || string(line) != "partial" {
+		t.Fatalf("expected partial text with a timeout, got %q, %v", line, err)
+	}
+	if line, err := unicomm.ReadUntil(comm, "\n"); len(line) != 0 {
+		t.Fatalf("partial text returned again: %q, %v", line, err)
+	}
+}
@@ -0,0 +1,97 @@
+package unicomm_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestThrottleDoesNotBlockReads(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte { return nil },
+	})
+	options := server.TCPOptions()
+	options.MaxBytesPerSecond = 100
+	options.BurstBytes = 10
+	options.WriteTimeout = time.Second
+	comm := unicommtcp.NewTCP(options)
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	// Fifty bytes beyond the burst take about half a second
+	message := bytes.Repeat([]byte("x"), 60)
+	done := make(chan error, 1)
+	written := time.Now()
+	go func() { done <- comm.WriteRaw(message) }()
+
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	if err := comm.ProbeLink(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := comm.Read(1); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Fatalf("reads waited %v for the throttled write", elapsed)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(written); elapsed < 450*time.Millisecond {
+		t.Fatalf("write took %v, faster than the rate allows", elapsed)
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(server.Received()) < len(message) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if received := server.Received(); !bytes.Equal(received, message) {
+		t.Fatalf("unexpected bytes received %q", received)
+	}
+}
+
+func TestThrottleRate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  uint
+		burst uint
+		size  int
+		want  time.Duration // Bytes beyond the burst divided by the rate
+	}{
+		{"within the burst", 100, 60, 60, 0},
+		{"beyond the burst", 100, 10, 60, 500 * time.Millisecond},
+		{"default burst of one second", 100, 0, 150, 500 * time.Millisecond},
+		{"higher rate", 400, 20, 220, 500 * time.Millisecond},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+				Handler: func(request []byte) []byte { return nil },
+			})
+			options := server.TCPOptions()
+			options.MaxBytesPerSecond = test.rate
+			options.BurstBytes = test.burst
+			options.WriteTimeout = 2 * time.Second
+			comm := unicommtcp.NewTCP(options)
+			if err := comm.Connect(); err != nil {
+				t.Fatal(err)
+			}
+			defer comm.Disconnect()
+
+			start := time.Now()
+			if err := comm.WriteRaw(bytes.Repeat([]byte("x"), test.size)); err != nil {
+				t.Fatal(err)
+			}
+			elapsed := time.Since(start)
+			if elapsed < test.want-50*time.Millisecond || elapsed > test.want+250*time.Millisecond {
+				t.Fatalf("writing %d bytes took %v, want about %v", test.size, elapsed, test.want)
+			}
+		})
+	}
+}
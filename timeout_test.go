@@ -0,0 +1,82 @@
+package unicomm_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestTimeoutPartialData(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	options := server.TCPOptions()
+	options.ReadTimeout = 50 * time.Millisecond
+	comm := unicommtcp.NewTCP(options)
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	// Silence is not an error for Read
+	data, err := comm.Read(16)
+	if err != nil || len(data) != 0 {
+		t.Fatalf("expected no data and no error, got %q, %v", data, err)
+	}
+
+	// ReadUntil returns the bytes received before the timeout
+	if err := comm.Write([]byte("PARTIAL")); err != nil {
+		t.Fatal(err)
+	}
+	data, err = comm.ReadUntil("\r")
+	if !errors.Is(err, unicommio.ErrTimeout) || !unicommio.IsTemporary(err) || string(data) != "PARTIAL" {
+		t.Fatalf("expected partial data with a timeout, got %q, %v", data, err)
+	}
+}
+
+func TestTimeoutTricklingPeer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// One byte at a time, never the delimiter
+		for {
+			if _, err := conn.Write([]byte("x")); err != nil {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	address := listener.Addr().(*net.TCPAddr)
+	options := unicommtcp.TCPOptions{Host: "127.0.0.1", Port: uint(address.Port), ReadTimeout: 50 * time.Millisecond}
+	if got := unicommtcp.NewTCP(options).Options.MaxReadDuration; got != unicommtcp.DefaultMaxReadDuration {
+		t.Fatalf("expected a default read limit, got %v", got)
+	}
+
+	options.MaxReadDuration = 200 * time.Millisecond
+	comm := unicommtcp.NewTCP(options)
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	start := time.Now()
+	data, err := comm.ReadUntil("\n")
+	if !errors.Is(err, unicommio.ErrTimeout) || len(data) == 0 {
+		t.Fatalf("expected partial data with a timeout, got %q, %v", data, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("read took %v despite the limit", elapsed)
+	}
+}
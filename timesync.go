@@ -0,0 +1,88 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+/*
+Parses the device time from the response of the time command
+*/
+type ClockParser func(response []byte) (time.Time, error)
+
+type TimeSyncOptions struct {
+	Command []byte        // Query answered with the device time
+	Parse   ClockParser   // Reads the device time from the response
+	Samples int           // Queries performed, defaults to 8
+	Delay   time.Duration // Wait between queries
+}
+
+type ClockEstimate struct {
+	Offset    time.Duration // Device clock minus host clock
+	RoundTrip time.Duration // Shortest round trip of the link
+	Jitter    time.Duration // Standard deviation of the measured offsets
+	Samples   int
+}
+
+/*
+Converts a timestamp of the device clock to the host clock
+*/
+func (ce ClockEstimate) ToHost(device time.Time) time.Time {
+	return device.Add(-ce.Offset)
+}
+
+/*
+Estimates the offset of the device clock by repeatedly querying
+its time. Each sample assumes the device read its clock halfway
+through the round trip, and the sample with the shortest round
+trip, the least affected by queueing, gives the offset
+*/
+func (c *Client) SyncClock(options TimeSyncOptions) (ClockEstimate, error) {
+	if options.Parse == nil || len(options.Command) == 0 {
+		return ClockEstimate{}, fmt.Errorf("invalid options: command and parser are required")
+	}
+	if options.Samples <= 0 {
+		options.Samples = 8
+	}
+
+	offsets := make([]float64, 0, options.Samples)
+	estimate := ClockEstimate{RoundTrip: time.Duration(math.MaxInt64)}
+	for index := range options.Samples {
+		if index > 0 {
+			time.Sleep(options.Delay)
+		}
+		response, start, roundTrip, err := c.queryTimed(options.Command)
+		if err != nil {
+			return ClockEstimate{}, fmt.Errorf("time sample %d failed: %w", index, err)
+		}
+		device, err := options.Parse(response)
+		if err != nil {
+			return ClockEstimate{}, fmt.Errorf("time sample %d failed: %w", index, err)
+		}
+
+		offset := device.Sub(start.Add(roundTrip / 2))
+		offsets = append(offsets, float64(offset))
+		if roundTrip < estimate.RoundTrip {
+			estimate.RoundTrip = roundTrip
+			estimate.Offset = offset
+		}
+	}
+
+	var mean, variance float64
+	for _, offset := range offsets {
+		mean += offset / float64(len(offsets))
+	}
+	for _, offset := range offsets {
+		variance += (offset - mean) * (offset - mean) / float64(len(offsets))
+	}
+	estimate.Jitter = time.Duration(math.Sqrt(variance))
+	estimate.Samples = len(offsets)
+	return estimate, nil
+}
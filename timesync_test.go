@@ -0,0 +1,91 @@
+package unicomm_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestSyncClock(t *testing.T) {
+	// The device clock runs 3 seconds ahead of the host
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte {
+			now := time.Now().Add(3 * time.Second).UnixMicro()
+			return []byte(strconv.FormatInt(now, 10) + "\n")
+		},
+	})
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions()})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	client := unicomm.NewClient(comm, "\n")
+	estimate, err := client.SyncClock(unicomm.TimeSyncOptions{
+		Command: []byte("TIME?\n"),
+		Parse: func(response []byte) (time.Time, error) {
+			micros, err := strconv.ParseInt(strings.TrimSpace(string(response)), 10, 64)
+			return time.UnixMicro(micros), err
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deviation := estimate.Offset - 3*time.Second; deviation.Abs() > 50*time.Millisecond {
+		t.Fatalf("offset %v too far from 3s", estimate.Offset)
+	}
+	if estimate.Samples != 8 || estimate.RoundTrip <= 0 {
+		t.Fatalf("unexpected estimate %+v", estimate)
+	}
+}
+
+func TestSyncClockWaitingForClient(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte {
+			if string(request) == "SLOW?\n" {
+				time.Sleep(200 * time.Millisecond)
+				return []byte("0\n")
+			}
+			return []byte(strconv.FormatInt(time.Now().UnixMicro(), 10) + "\n")
+		},
+	})
+	options := server.TCPOptions()
+	options.ReadTimeout = time.Second
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: options})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	// Another query holds the client when the sample starts, which
+	// must not be counted in the round trip
+	client := unicomm.NewClient(comm, "\n")
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Query([]byte("SLOW?\n"))
+		done <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	estimate, err := client.SyncClock(unicomm.TimeSyncOptions{
+		Command: []byte("TIME?\n"),
+		Samples: 1,
+		Parse: func(response []byte) (time.Time, error) {
+			micros, err := strconv.ParseInt(strings.TrimSpace(string(response)), 10, 64)
+			return time.UnixMicro(micros), err
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if estimate.Offset.Abs() > 50*time.Millisecond {
+		t.Fatalf("offset %v of a synchronized clock includes the wait for the client", estimate.Offset)
+	}
+}
@@ -0,0 +1,258 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"sync"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommframe"
+)
+
+/*
+Reversible transformation applied to each message. Encode runs on
+write and Decode reverts it on read
+*/
+type PayloadTransform interface {
+	Encode(payload []byte) []byte
+	Decode(data []byte) ([]byte, error)
+}
+
+/*
+Armors the payload with base64, as required by radios that only
+carry printable text. Defaults to the standard encoding
+*/
+type Base64Transform struct {
+	Encoding *base64.Encoding
+}
+
+/*
+Scrambles the payload with a repeating XOR key, restarted on
+every message
+*/
+type XORScrambler struct {
+	Key []byte
+}
+
+/*
+Removes the zero bytes of the payload with COBS, without the
+zero delimiter of the frame codec
+*/
+type COBSTransform struct{}
+
+type Transformed struct {
+	Unicomm
+	Delimiter string             // Ends each message on the link
+	Pipeline  []PayloadTransform // Applied in order on write, in reverse on read
+
+	pending []byte     // Decoded bytes not consumed yet
+	raw     []byte     // Received bytes of a message not complete yet
+	mutex   sync.Mutex // Protect pending bytes
+}
+
+/*
+Returns the encoding in use
+*/
+func (bt Base64Transform) encoding() *base64.Encoding {
+	if bt.Encoding == nil {
+		return base64.StdEncoding
+	}
+	return bt.Encoding
+}
+
+/*
+Encodes the payload as base64
+*/
+func (bt Base64Transform) Encode(payload []byte) []byte {
+	return bt.encoding().AppendEncode(nil, payload)
+}
+
+/*
+Decodes the base64 payload
+*/
+func (bt Base64Transform) Decode(data []byte) ([]byte, error) {
+	return bt.encoding().AppendDecode(nil, data)
+}
+
+/*
+XORs the payload with the key
+*/
+func (xs XORScrambler) Encode(payload []byte) []byte {
+	data := bytes.Clone(payload)
+	if len(xs.Key) == 0 {
+		return data
+	}
+	for index := range data {
+		data[index] ^= xs.Key[index%len(xs.Key)]
+	}
+	return data
+}
+
+/*
+XORs the data with the key, which reverts the scrambling
+*/
+func (xs XORScrambler) Decode(data []byte) ([]byte, error) {
+	return xs.Encode(data), nil
+}
+
+/*
+Encodes the payload without zero bytes
+*/
+func (COBSTransform) Encode(payload []byte) []byte {
+	frame := unicommframe.COBS{}.Encode(payload)
+	return frame[:len(frame)-1]
+}
+
+/*
+Restores the zero bytes of the payload
+*/
+func (COBSTransform) Decode(data []byte) ([]byte, error) {
+	payload, _, err := unicommframe.COBS{}.Decode(append(bytes.Clone(data), 0))
+	return payload, err
+}
+
+/*
+Creates a middleware that applies the transformations to every
+message. The delimiter is kept outside of the transformations, so
+delimiter framing keeps working on the link
+*/
+func NewTransformed(comm Unicomm, delimiter string, pipeline ...PayloadTransform) *Transformed {
+	return &Transformed{
+		Unicomm:   comm,
+		Delimiter: delimiter,
+		Pipeline:  pipeline,
+	}
+}
+
+/*
+Applies the transformations in order
+*/
+func (t *Transformed) encode(payload []byte) []byte {
+	for _, transform := range t.Pipeline {
+		payload = transform.Encode(payload)
+	}
+	return payload
+}
+
+/*
+Reverts the transformations in reverse order
+*/
+func (t *Transformed) decode(data []byte) ([]byte, error) {
+	for index := len(t.Pipeline) - 1; index >= 0; index-- {
+		var err error
+		if data, err = t.Pipeline[index].Decode(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+/*
+Reads the next message and decodes it, keeping its delimiter.
+Bytes received before a timeout are kept until the delimiter
+arrives, since a partial message cannot be decoded. Must be
+called with the mutex locked
+*/
+func (t *Transformed) readMessage(delimiter string) ([]byte, error) {
+	if !bytes.Contains(t.raw, []byte(delimiter)) {
+		frame, err := ReadUntil(t.Unicomm, delimiter)
+		t.raw = append(t.raw, frame...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// A delimiter split between two reads ends the message earlier
+	index := bytes.Index(t.raw, []byte(delimiter))
+	frame := t.raw[:index]
+	t.raw = bytes.Clone(t.raw[index+len(delimiter):])
+
+	payload, err := t.decode(frame)
+	if err != nil {
+		return nil, err
+	}
+	return append(payload, delimiter...), nil
+}
+
+/*
+Reads up to n decoded bytes. Without a delimiter the received
+chunk is decoded as is, which suits byte-wise transformations
+*/
+func (t *Transformed) Read(n uint) ([]byte, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.pending) == 0 {
+		if t.Delimiter == "" {
+			data, err := t.Unicomm.Read(n)
+			if err != nil || len(data) == 0 {
+				return data, err
+			}
+			return t.decode(data)
+		}
+		message, err := t.readMessage(t.Delimiter)
+		if err != nil {
+			return nil, err
+		}
+		t.pending = message
+	}
+	size := min(int(n), len(t.pending))
+	data := bytes.Clone(t.pending[:size])
+	t.pending = t.pending[size:]
+	return data, nil
+}
+
+/*
+Reads the next message until the delimiter and decodes it. The
+delimiter is kept at the end, as in untransformed reads
+*/
+func (t *Transformed) ReadUntil(delimiter string) ([]byte, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if index := bytes.Index(t.pending, []byte(delimiter)); index >= 0 {
+		data := bytes.Clone(t.pending[:index+len(delimiter)])
+		t.pending = t.pending[index+len(delimiter):]
+		return data, nil
+	}
+	message, err := t.readMessage(delimiter)
+	if err != nil {
+		return nil, err
+	}
+	data := append(t.pending, message...)
+	t.pending = nil
+	return data, nil
+}
+
+/*
+Encodes the message and writes it followed by the delimiter
+*/
+func (t *Transformed) Write(message []byte) error {
+	payload := bytes.TrimSuffix(message, []byte(t.Delimiter))
+	return t.Unicomm.Write(append(t.encode(payload), t.Delimiter...))
+}
+
+/*
+Writes an array of bytes encoded by the transformation, without
+the delimiter
+*/
+func (t *Transformed) WriteRaw(message []byte) error {
+	return writeRaw(t.Unicomm, t.encode(message))
+}
+
+/*
+Returns the wrapped instance
+*/
+func (t *Transformed) Unwrap() Unicomm {
+	return t.Unicomm
+}
+
+/*
+Stops the lookup of data capabilities, which would bypass the
+transformation
+*/
+func (t *Transformed) transformsData() {}
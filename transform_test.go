@@ -0,0 +1,86 @@
+package unicomm_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestTransformPipeline(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{})
+	comm := unicomm.New(unicomm.Options{
+		Protocol:  unicomm.TCP,
+		TCP:       server.TCPOptions(),
+		Delimiter: "\n",
+		Transforms: []unicomm.PayloadTransform{
+			unicomm.XORScrambler{Key: []byte{0x5A, 0xA5}},
+			unicomm.Base64Transform{},
+		},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	// The scrambled payload holds a newline, which the armor hides
+	message := []byte{'P', 0x50, '\n' ^ 0xA5, 'Q'}
+	if err := comm.Write(append(message, '\n')); err != nil {
+		t.Fatal(err)
+	}
+	unicommtest.ExpectFrame(t, comm, "\n", string(message)+"\n")
+
+	scrambled := unicomm.XORScrambler{Key: []byte{0x5A, 0xA5}}.Encode(message)
+	if want := base64.StdEncoding.EncodeToString(scrambled) + "\n"; string(server.Received()) != want {
+		t.Fatalf("link carried %q, want %q", server.Received(), want)
+	}
+}
+
+/*
+Reads until a complete frame arrives, failing on errors other
+than timeouts
+*/
+func readSplitFrame(t *testing.T, comm unicomm.Unicomm, delimiter string) []byte {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		frame, err := unicomm.ReadUntil(comm, delimiter)
+		if err == nil {
+			return frame
+		}
+		if !unicommio.IsTimeout(err) {
+			t.Fatalf("split frame failed: %v", err)
+		}
+	}
+	t.Fatal("split frame never completed")
+	return nil
+}
+
+func TestTransformSplitFrame(t *testing.T) {
+	first := base64.StdEncoding.EncodeToString([]byte("hello world")) + "\r\n"
+	second := base64.StdEncoding.EncodeToString([]byte("again")) + "\r\n"
+
+	// The delimiter itself is split by the delay too
+	link := first + second
+	split := len(first) - 1
+	port := serveChunks(t, 150*time.Millisecond, []byte(link[:5]), []byte(link[5:split]), []byte(link[split:]))
+	comm := unicomm.NewTransformed(unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port, ReadTimeout: 50 * time.Millisecond},
+	}), "\r\n", unicomm.Base64Transform{})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	for _, want := range []string{"hello world\r\n", "again\r\n"} {
+		if frame := readSplitFrame(t, comm, "\r\n"); string(frame) != want {
+			t.Fatalf("got %q, want %q", frame, want)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package unicomm_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/credentials"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestReverseTunnel(t *testing.T) {
+	rendezvous, err := unicommtcp.ListenRendezvous("127.0.0.1:0", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rendezvous.Close()
+
+	options := unicommtcp.TunnelOptions{
+		TCPOptions:    unicommtcp.TCPOptions{Host: "127.0.0.1", Port: uint(rendezvous.Addr().(*net.TCPAddr).Port)},
+		DeviceID:      "psu-17",
+		Token:         "secret",
+		RetryInterval: 20 * time.Millisecond,
+	}
+	device := unicomm.New(unicomm.Options{Protocol: unicomm.Tunnel, Tunnel: options})
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer device.Disconnect()
+
+	for range 2 {
+		central, err := rendezvous.Dial("psu-17", unicommtcp.TCPOptions{}, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for !device.IsConnected() {
+			time.Sleep(time.Millisecond)
+		}
+		if err := central.Write([]byte("MEAS?\n")); err != nil {
+			t.Fatal(err)
+		}
+		unicommtest.ExpectFrame(t, device, "\n", "MEAS?\n")
+		if err := device.Write([]byte("12.5\n")); err != nil {
+			t.Fatal(err)
+		}
+		unicommtest.ExpectFrame(t, central, "\n", "12.5\n")
+
+		// The device dials the tunnel again after it drops
+		central.Disconnect()
+		unicomm.ReadUntil(device, "\n")
+	}
+
+	options.Token = "wrong"
+	if err := unicommtcp.NewTunnel(options).Connect(); err == nil {
+		t.Fatal("tunnel with a wrong token was accepted")
+	}
+}
+
+func TestTunnelCredentials(t *testing.T) {
+	rendezvous, err := unicommtcp.ListenRendezvous("127.0.0.1:0", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rendezvous.Close()
+
+	var resolved atomic.Int32
+	options := unicommtcp.TunnelOptions{
+		TCPOptions:    unicommtcp.TCPOptions{Host: "127.0.0.1", Port: uint(rendezvous.Addr().(*net.TCPAddr).Port)},
+		DeviceID:      "psu-17",
+		Token:         "ignored",
+		RetryInterval: 20 * time.Millisecond,
+		Credentials: credentials.ProviderFunc(func(ctx context.Context) (credentials.Credentials, error) {
+			resolved.Add(1)
+			return credentials.Credentials{Token: "secret"}, nil
+		}),
+	}
+	device := unicommtcp.NewTunnel(options)
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer device.Disconnect()
+
+	// The token is resolved again when the tunnel is dialed again
+	central, err := rendezvous.Dial("psu-17", unicommtcp.TCPOptions{}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	central.Disconnect()
+	unicomm.ReadUntil(device, "\n")
+	if _, err := rendezvous.Dial("psu-17", unicommtcp.TCPOptions{}, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Load() < 2 {
+		t.Fatalf("token resolved %d times, want one per dial", resolved.Load())
+	}
+
+	options.Credentials = credentials.ProviderFunc(func(ctx context.Context) (credentials.Credentials, error) {
+		return credentials.Credentials{}, errors.New("vault sealed")
+	})
+	if err := unicommtcp.NewTunnel(options).Connect(); err == nil || !strings.Contains(err.Error(), "vault sealed") {
+		t.Fatalf("Connect = %v, want the provider error", err)
+	}
+	options.Credentials = credentials.Static(credentials.Credentials{Token: "wrong"})
+	if err := unicommtcp.NewTunnel(options).Connect(); err == nil {
+		t.Fatal("tunnel with a wrong resolved token was accepted")
+	}
+}
+
+func TestRendezvousSkipsClosedTunnel(t *testing.T) {
+	rendezvous, err := unicommtcp.ListenRendezvous("127.0.0.1:0", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rendezvous.Close()
+
+	// Opens a tunnel by hand, waiting until it is registered
+	open := func() net.Conn {
+		conn, err := net.Dial("tcp", rendezvous.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Write([]byte("UNICOMM-TUNNEL psu-17 secret\n"))
+		reply := make([]byte, 3)
+		if _, err := io.ReadFull(conn, reply); err != nil || string(reply) != "OK\n" {
+			t.Fatalf("hello reply %q, %v", reply, err)
+		}
+		for len(rendezvous.Devices()) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		return conn
+	}
+
+	// The device gave up on the tunnel before any driver took it
+	open().Close()
+	time.Sleep(20 * time.Millisecond)
+	if central, err := rendezvous.Dial("psu-17", unicommtcp.TCPOptions{}, 100*time.Millisecond); err == nil {
+		central.Disconnect()
+		t.Fatal("Dial returned a tunnel closed by the device")
+	}
+
+	device := open()
+	defer device.Close()
+	central, err := rendezvous.Dial("psu-17", unicommtcp.TCPOptions{}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer central.Disconnect()
+	if err := central.Write([]byte("MEAS?\n")); err != nil {
+		t.Fatal(err)
+	}
+	received := make([]byte, 6)
+	if _, err := io.ReadFull(device, received); err != nil || string(received) != "MEAS?\n" {
+		t.Fatalf("device received %q, %v", received, err)
+	}
+}
+
+func TestReverseTunnelTLS(t *testing.T) {
+	certificate, roots := selfSigned(t)
+	rendezvous, err := unicommtcp.ListenRendezvousTLS("127.0.0.1:0", "secret", &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rendezvous.Close()
+
+	options := unicommtcp.TunnelOptions{
+		TCPOptions: unicommtcp.TCPOptions{Host: "127.0.0.1", Port: uint(rendezvous.Addr().(*net.TCPAddr).Port)},
+		DeviceID:   "psu-17",
+		Token:      "secret",
+		TLS:        &tls.Config{RootCAs: roots, ServerName: "rendezvous"},
+	}
+	device := unicommtcp.NewTunnel(options)
+	if err := device.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer device.Disconnect()
+
+	central, err := rendezvous.Dial("psu-17", unicommtcp.TCPOptions{}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer central.Disconnect()
+	if err := central.Write([]byte("MEAS?\n")); err != nil {
+		t.Fatal(err)
+	}
+	unicommtest.ExpectFrame(t, device, "\n", "MEAS?\n")
+
+	// Plain tunnels and unknown servers are refused
+	options.TLS = nil
+	options.DialTimeout = 200 * time.Millisecond
+	if err := unicommtcp.NewTunnel(options).Connect(); err == nil {
+		t.Fatal("plain tunnel was accepted by a TLS rendezvous")
+	}
+	options.TLS = &tls.Config{ServerName: "rendezvous"}
+	if err := unicommtcp.NewTunnel(options).Connect(); err == nil {
+		t.Fatal("tunnel trusted an unknown certificate")
+	}
+}
+
+/*
+Creates a certificate for the name "rendezvous" and the pool
+trusting it
+*/
+func selfSigned(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "rendezvous"},
+		DNSNames:     []string{"rendezvous"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(parsed)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, roots
+}
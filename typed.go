@@ -0,0 +1,130 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+/*
+Converts values of type T to frame payloads and back
+*/
+type TypeCodec[T any] interface {
+	Marshal(value T) ([]byte, error)
+	Unmarshal(payload []byte) (T, error)
+}
+
+/*
+Encodes values as JSON documents
+*/
+type JSONCodec[T any] struct{}
+
+/*
+Encodes fixed-size values, such as structs of numbers, with
+encoding/binary in the given byte order
+*/
+type BinaryCodec[T any] struct {
+	Order binary.ByteOrder // Defaults to little endian
+}
+
+type Typed[T any] struct {
+	codec  TypeCodec[T]
+	framed *Framed
+}
+
+/*
+Encodes the value as JSON
+*/
+func (JSONCodec[T]) Marshal(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+/*
+Decodes a JSON document into a value
+*/
+func (JSONCodec[T]) Unmarshal(payload []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(payload, &value)
+	return value, err
+}
+
+/*
+Returns the byte order of the codec
+*/
+func (bc BinaryCodec[T]) order() binary.ByteOrder {
+	if bc.Order == nil {
+		return binary.LittleEndian
+	}
+	return bc.Order
+}
+
+/*
+Encodes the fields of the value in order, without padding
+*/
+func (bc BinaryCodec[T]) Marshal(value T) ([]byte, error) {
+	return binary.Append(nil, bc.order(), value)
+}
+
+/*
+Decodes a payload of exactly the size of the value
+*/
+func (bc BinaryCodec[T]) Unmarshal(payload []byte) (T, error) {
+	var value T
+	if size := binary.Size(value); size != len(payload) {
+		return value, fmt.Errorf("invalid payload: %d bytes, expected %d", len(payload), size)
+	}
+	err := binary.Read(bytes.NewReader(payload), bc.order(), &value)
+	return value, err
+}
+
+/*
+Creates a transport of typed values, where each value is
+marshaled by the codec and sent as one frame of the frame codec
+*/
+func NewTyped[T any](comm Unicomm, codec TypeCodec[T], frames FrameCodec) *Typed[T] {
+	return &Typed[T]{
+		codec:  codec,
+		framed: NewFramed(comm, frames),
+	}
+}
+
+/*
+Marshals the value and writes it as one frame
+*/
+func (t *Typed[T]) Send(value T) error {
+	payload, err := t.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal failed: %w", err)
+	}
+	return t.framed.Write(payload)
+}
+
+/*
+Reads the next frame and unmarshals it
+*/
+func (t *Typed[T]) Receive() (T, error) {
+	frame, err := t.framed.ReadFrame()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	value, err := t.codec.Unmarshal(frame.Payload)
+	if err != nil {
+		return value, fmt.Errorf("unmarshal failed: %w", err)
+	}
+	return value, nil
+}
+
+/*
+Returns the framed instance used by the transport
+*/
+func (t *Typed[T]) Unwrap() Unicomm {
+	return t.framed
+}
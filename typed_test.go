@@ -0,0 +1,37 @@
+package unicomm_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+)
+
+type telemetry struct {
+	Sequence    uint16
+	Temperature float32
+	Flags       uint8
+}
+
+func TestBinaryCodecRoundTrip(t *testing.T) {
+	codec := unicomm.BinaryCodec[telemetry]{Order: binary.BigEndian}
+	record := telemetry{Sequence: 7, Temperature: 21.5, Flags: 0x81}
+
+	payload, err := codec.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payload) != 7 {
+		t.Fatalf("got %d bytes, want 7", len(payload))
+	}
+	decoded, err := codec.Unmarshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != record {
+		t.Fatalf("got %+v, want %+v", decoded, record)
+	}
+	if _, err := codec.Unmarshal(payload[:6]); err == nil {
+		t.Fatal("expected an error for a short payload")
+	}
+}
@@ -1,12 +1,17 @@
 /*
 Author: Leonardo Rossi Leao
 Createdt at: September 22nd, 2025
-Last update: September 22nd, 2025
+Last update: October 15th, 2026
 */
 
 package unicomm
 
 import (
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommbroker"
+	"github.com/devicehub-go/unicomm/protocol/unicommgrpc"
+	"github.com/devicehub-go/unicomm/protocol/unicommi2c"
 	"github.com/devicehub-go/unicomm/protocol/unicommserial"
 	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
 )
@@ -17,33 +22,214 @@ type Options struct {
 	Protocol  Protocol
 	Serial    unicommserial.SerialOptions
 	TCP       unicommtcp.TCPOptions
+	I2C       unicommi2c.I2COptions
+	SPI       unicommi2c.SPIOptions
+	GRPC      unicommgrpc.GRPCOptions
+	Tunnel    unicommtcp.TunnelOptions
+	Broker    unicommbroker.BrokerOptions
 	Delimiter string
+
+	// Encodes written messages and decodes received frames, nil
+	// leaves the stream untouched
+	Codec FrameCodec
+
+	// Stop-and-wait retransmission for lossy links, nil disables it.
+	// Both ends must use it
+	ARQ *ARQOptions
+
+	// Transformations applied in order to each written message and
+	// in reverse to each received one, delimited by Delimiter
+	Transforms []PayloadTransform
+
+	// Normalization of the line endings in each direction
+	Text TextOptions
+
+	// Payload semantics of ReadUntil, nil keeps the delimiter and
+	// returns partial data along with timeout errors
+	ReadUntil *ReadUntilOptions
+
+	// Named framings selectable per operation through Profiled, for
+	// devices whose command classes end differently
+	Profiles map[string]Profile
+
+	// Waits for an idle line before each write, for lines shared by
+	// several masters, nil disables it
+	ListenBeforeTalk *ListenBeforeTalkOptions
+
+	// Latency histograms of the operations and alerts for the slow
+	// ones, nil disables them
+	Latency *LatencyOptions
+
+	// Histogram of the received byte values and rate of framing
+	// errors, for diagnosing noise, nil disables it
+	ByteStats *ByteStatsOptions
+
+	// Candidate protocols tried after every successful connect,
+	// the first one matching is reported by DetectedProtocol
+	Probes []ProtocolProbe
+
+	// Commands executed after every successful connect
+	WarmUp []WarmUpStep
+
+	// Replays the configuration commands not confirmed by the device
+	// after every successful connect, nil disables the journal
+	Journal *JournalOptions
+
+	// Command sent whenever the connection is idle, zero interval
+	// disables it
+	KeepWarm KeepWarmOptions
+
+	// Queues the writes issued while disconnected and flushes them on
+	// reconnect, nil disables it
+	Outbox *OutboxOptions
+
+	// Closes the connection after this period without operations
+	// and reconnects on the next one, zero keeps it always open
+	IdleTimeout time.Duration
+
+	// Defers the connection until the first operation, which returns
+	// the connection errors
+	LazyConnect bool
+
+	// Fails fast after consecutive failures instead of waiting for
+	// the timeouts of a dead device, nil disables it
+	Breaker *BreakerOptions
+
+	// Returns the panics of codecs, transformations and hooks as
+	// PanicError instead of crashing the caller
+	RecoverPanics bool
+
+	// Publishes the connection events, operation errors and breaker
+	// states to every subscriber of the bus, nil disables it
+	Events *EventBus
+
+	// Identity of the device, surfaced in logs and error messages
+	Metadata Metadata
 }
 
 type Unicomm interface {
-	Connect() error
-	Disconnect() error
-	IsConnected() bool
-	Read(size uint) ([]byte, error)
-	ReadUntil(delimiter string) ([]byte, error)
-	Write(message []byte) error
+	Connector
+	Reader
+	Writer
 }
 
 const (
 	Serial Protocol = 0
 	TCP    Protocol = 1
+	I2C    Protocol = 2
+	GRPC   Protocol = 3
+	Tunnel Protocol = 4 // Reverse tunnel to a rendezvous server
+	Broker Protocol = 5 // Port shared by a broker of the same host
+	SPI    Protocol = 6 // Through a FT232H bridge
 )
 
+/*
+Returns a copy of the options with the defaults of each
+protocol applied to its zero values
+*/
+func (o Options) Defaults() Options {
+	o.Serial = o.Serial.Defaults()
+	o.TCP = o.TCP.Defaults()
+	return o
+}
+
 /*
 Creates a new instance of unified communication based on
 the target protocol
 */
 func New(options Options) Unicomm {
+	var comm Unicomm
+
 	switch options.Protocol {
 	case Serial:
-		return unicommserial.NewSerial(options.Serial)
+		comm = unicommserial.NewSerial(options.Serial)
 	case TCP:
-		return unicommtcp.NewTCP(options.TCP)
+		comm = unicommtcp.NewTCP(options.TCP)
+	case I2C:
+		comm = unicommi2c.NewI2C(options.I2C)
+	case GRPC:
+		comm = unicommgrpc.NewGRPC(options.GRPC)
+	case Tunnel:
+		comm = unicommtcp.NewTunnel(options.Tunnel)
+	case Broker:
+		comm = unicommbroker.NewBroker(options.Broker)
+	case SPI:
+		comm = unicommi2c.NewSPI(options.SPI)
+	default:
+		return nil
+	}
+
+	// Bytes are counted as received, before any wrapper consumes them
+	if options.ByteStats != nil {
+		comm = NewByteStats(comm, *options.ByteStats)
+	}
+
+	// Probes talk to the device before any transformation
+	if len(options.Probes) > 0 {
+		comm = NewDetector(comm, options.Probes)
+	}
+	if options.ListenBeforeTalk != nil {
+		comm = NewListenBeforeTalk(comm, *options.ListenBeforeTalk)
+	}
+	if options.Latency != nil {
+		comm = NewLatencyMonitor(comm, *options.Latency)
+	}
+	if options.Codec != nil {
+		comm = NewFramed(comm, options.Codec)
+	}
+	if options.ARQ != nil {
+		comm = NewARQ(comm, *options.ARQ)
+	}
+	if len(options.Transforms) > 0 {
+		comm = NewTransformed(comm, options.Delimiter, options.Transforms...)
+	}
+	if !options.Text.IsZero() {
+		comm = NewText(comm, options.Text)
+	}
+	if options.ReadUntil != nil {
+		comm = NewDelimited(comm, *options.ReadUntil)
+	}
+	if len(options.Profiles) > 0 {
+		comm = NewProfiled(comm, options.Profiles)
+	}
+	if len(options.WarmUp) > 0 {
+		comm = NewWarmUp(comm, options.WarmUp)
+	}
+	if options.Journal != nil {
+		comm = NewJournal(comm, *options.Journal)
+	}
+	if options.KeepWarm.Interval > 0 {
+		comm = NewKeepWarm(comm, options.KeepWarm)
+	}
+	if options.Outbox != nil {
+		comm = NewOutbox(comm, *options.Outbox)
+	}
+	if options.IdleTimeout > 0 {
+		comm = NewIdleCloser(comm, options.IdleTimeout)
+	}
+	if options.LazyConnect {
+		comm = NewLazyConnector(comm)
+	}
+	if options.Breaker != nil {
+		breaker := *options.Breaker
+		if bus, onState := options.Events, breaker.OnState; bus != nil {
+			breaker.OnState = func(state BreakerState) {
+				bus.Publish(Event{Kind: EventState, Source: options.Metadata.String(), State: state.String()})
+				if onState != nil {
+					onState(state)
+				}
+			}
+		}
+		comm = NewBreaker(comm, breaker)
+	}
+	if options.RecoverPanics {
+		comm = NewSafe(comm)
+	}
+	if options.Events != nil {
+		comm = NewNotifier(comm, options.Events, options.Metadata.String())
+	}
+	if !options.Metadata.IsZero() {
+		comm = NewIdentified(comm, options.Metadata)
 	}
-	return nil
+	return comm
 }
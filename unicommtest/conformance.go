@@ -0,0 +1,381 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommio"
+)
+
+/*
+Methods of the instances checked by the conformance suite, the
+Unicomm interface along with ReadUntil, since the suite checks
+the delimiter handling of stream links
+*/
+type Conn interface {
+	Connect() error
+	Disconnect() error
+	IsConnected() bool
+	Read(size uint) ([]byte, error)
+	ReadUntil(delimiter string) ([]byte, error)
+	Write(message []byte) error
+}
+
+/*
+Creates a connected instance along with the device end of its
+link, such as the accepted side of a TCP connection or the
+controller of a pseudo-terminal. Both are released with cleanups
+of the test
+*/
+type MakePair func(t testing.TB) (comm Conn, device io.ReadWriteCloser)
+
+type ConformanceOptions struct {
+	EndDelimiter string        // Appended by Write, empty when messages are written as is
+	Timeout      time.Duration // Longest a read may take once the device goes silent, defaults to 1s
+	Skip         []string      // Subtests not run, for behaviors the implementation documents otherwise
+}
+
+/*
+Runs the conformance suite against a Unicomm implementation,
+built-in or third-party, checking the connection state, writes,
+delimiter handling, partial reads, timeouts, concurrent use and
+the errors after the device closes the link. Each subtest works on
+a new pair
+*/
+func TestConformance(t *testing.T, makePair MakePair, options ConformanceOptions) {
+	if options.Timeout == 0 {
+		options.Timeout = time.Second
+	}
+	suite := conformance{makePair: makePair, options: options}
+
+	for _, test := range []struct {
+		name string
+		run  func(*testing.T)
+	}{
+		{"Connection", suite.testConnection},
+		{"Write", suite.testWrite},
+		{"ReadUntil", suite.testReadUntil},
+		{"ReadUntilTimeout", suite.testReadUntilTimeout},
+		{"PartialRead", suite.testPartialRead},
+		{"ConcurrentWrites", suite.testConcurrentWrites},
+		{"ConcurrentReads", suite.testConcurrentReads},
+		{"PeerClose", suite.testPeerClose},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if slices.Contains(options.Skip, test.name) {
+				t.Skip("skipped by the options")
+			}
+			test.run(t)
+		})
+	}
+}
+
+type conformance struct {
+	makePair MakePair
+	options  ConformanceOptions
+}
+
+/*
+Reads exactly size bytes from the device end, failing the test
+after the timeout
+*/
+func (c conformance) readDevice(t testing.TB, device io.Reader, size int) []byte {
+	t.Helper()
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		data := make([]byte, size)
+		_, err := io.ReadFull(device, data)
+		done <- result{data, err}
+	}()
+	select {
+	case result := <-done:
+		if result.err != nil {
+			t.Fatalf("device read failed: %v", result.err)
+		}
+		return result.data
+	case <-time.After(c.options.Timeout):
+		t.Fatalf("device did not receive %d bytes in %v", size, c.options.Timeout)
+		return nil
+	}
+}
+
+/*
+Writes to the device end, failing the test on errors
+*/
+func writeDevice(t testing.TB, device io.Writer, data string) {
+	t.Helper()
+
+	if _, err := device.Write([]byte(data)); err != nil {
+		t.Fatalf("device write failed: %v", err)
+	}
+}
+
+/*
+Connect fails while connected, and operations fail without
+panicking once disconnected
+*/
+func (c conformance) testConnection(t *testing.T) {
+	comm, _ := c.makePair(t)
+
+	if !comm.IsConnected() {
+		t.Fatal("new pair is not connected")
+	}
+	if err := comm.Connect(); err == nil {
+		t.Fatal("connecting twice did not fail")
+	}
+	if err := comm.Disconnect(); err != nil {
+		t.Fatalf("disconnect failed: %v", err)
+	}
+	if comm.IsConnected() {
+		t.Fatal("still connected after disconnect")
+	}
+	if err := comm.Disconnect(); err == nil {
+		t.Fatal("disconnecting twice did not fail")
+	}
+	if _, err := comm.Read(1); err == nil {
+		t.Fatal("read while disconnected did not fail")
+	}
+	if _, err := comm.ReadUntil("\n"); err == nil {
+		t.Fatal("read until while disconnected did not fail")
+	}
+	if err := comm.Write([]byte("x")); err == nil {
+		t.Fatal("write while disconnected did not fail")
+	}
+}
+
+/*
+Messages reach the device whole, followed by the end delimiter
+*/
+func (c conformance) testWrite(t *testing.T) {
+	comm, device := c.makePair(t)
+
+	for _, message := range []string{"hello", "\x00\x01\xfe\xff", string(bytes.Repeat([]byte("0123456789"), 500))} {
+		if err := comm.Write([]byte(message)); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		expected := message + c.options.EndDelimiter
+		if got := c.readDevice(t, device, len(expected)); string(got) != expected {
+			t.Fatalf("device received %q, want %q", got, expected)
+		}
+	}
+}
+
+/*
+Frames keep their delimiter, bytes after it are kept for the
+next read, and delimiters split between writes are found
+*/
+func (c conformance) testReadUntil(t *testing.T) {
+	comm, device := c.makePair(t)
+
+	writeDevice(t, device, "one\ntwo\nthr")
+	ExpectFrame(t, comm, "\n", "one\n")
+	ExpectFrame(t, comm, "\n", "two\n")
+	writeDevice(t, device, "ee\n")
+	ExpectFrame(t, comm, "\n", "three\n")
+
+	writeDevice(t, device, "A\r")
+	time.Sleep(10 * time.Millisecond)
+	writeDevice(t, device, "\nB\r\n")
+	ExpectFrame(t, comm, "\r\n", "A\r\n")
+	ExpectFrame(t, comm, "\r\n", "B\r\n")
+}
+
+/*
+Without the delimiter, ReadUntil returns the bytes received along
+with a timeout error, once the device goes silent
+*/
+func (c conformance) testReadUntilTimeout(t *testing.T) {
+	comm, device := c.makePair(t)
+
+	writeDevice(t, device, "partial")
+	start := time.Now()
+	data, err := comm.ReadUntil("\n")
+	if !unicommio.IsTimeout(err) {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	if string(data) != "partial" {
+		t.Fatalf("got %q along with the timeout, want %q", data, "partial")
+	}
+	if elapsed := time.Since(start); elapsed > c.options.Timeout {
+		t.Fatalf("timeout took %v, more than %v", elapsed, c.options.Timeout)
+	}
+	if !comm.IsConnected() {
+		t.Fatal("timeout closed the connection")
+	}
+}
+
+/*
+Reads return what was received, up to the size, and silence is
+not an error other than a timeout
+*/
+func (c conformance) testPartialRead(t *testing.T) {
+	comm, device := c.makePair(t)
+
+	writeDevice(t, device, "abc")
+	var received []byte
+	deadline := time.Now().Add(c.options.Timeout)
+	for len(received) < 3 && time.Now().Before(deadline) {
+		data, err := comm.Read(64)
+		if err != nil && !unicommio.IsTimeout(err) {
+			t.Fatalf("read failed: %v", err)
+		}
+		received = append(received, data...)
+	}
+	if string(received) != "abc" {
+		t.Fatalf("got %q, want %q", received, "abc")
+	}
+
+	writeDevice(t, device, "defgh")
+	received = nil
+	for len(received) < 5 && time.Now().Before(deadline.Add(c.options.Timeout)) {
+		data, err := comm.Read(2)
+		if len(data) > 2 {
+			t.Fatalf("read returned %d bytes, more than the 2 asked", len(data))
+		}
+		if err != nil && !unicommio.IsTimeout(err) {
+			t.Fatalf("read failed: %v", err)
+		}
+		received = append(received, data...)
+	}
+	if string(received) != "defgh" {
+		t.Fatalf("got %q, want %q", received, "defgh")
+	}
+
+	data, err := comm.Read(64)
+	if len(data) > 0 || (err != nil && !unicommio.IsTimeout(err)) {
+		t.Fatalf("read on a silent link returned %q, %v", data, err)
+	}
+}
+
+/*
+Messages written from several goroutines are never interleaved
+*/
+func (c conformance) testConcurrentWrites(t *testing.T) {
+	comm, device := c.makePair(t)
+
+	const writers, messages = 8, 16
+	var wg sync.WaitGroup
+	for writer := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for message := range messages {
+				payload := fmt.Sprintf("[%02d:%02d:%s]", writer, message, bytes.Repeat([]byte{'x'}, 64))
+				if err := comm.Write([]byte(payload)); err != nil {
+					t.Errorf("write failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	size := len(fmt.Sprintf("[00:00:%s]", bytes.Repeat([]byte{'x'}, 64))) + len(c.options.EndDelimiter)
+	received := c.readDevice(t, device, writers*messages*size)
+	wg.Wait()
+
+	var got []string
+	for chunk := range slices.Chunk(received, size) {
+		if !bytes.HasSuffix(chunk, []byte("]"+c.options.EndDelimiter)) || chunk[0] != '[' {
+			t.Fatalf("interleaved message %q", chunk)
+		}
+		got = append(got, string(chunk[:7]))
+	}
+	slices.Sort(got)
+	if len(slices.Compact(got)) != writers*messages {
+		t.Fatalf("received %d distinct messages, want %d", len(got), writers*messages)
+	}
+}
+
+/*
+Frames read from several goroutines are each received whole and
+exactly once
+*/
+func (c conformance) testConcurrentReads(t *testing.T) {
+	comm, device := c.makePair(t)
+
+	const readers, frames = 4, 64
+	var stream bytes.Buffer
+	for frame := range frames {
+		fmt.Fprintf(&stream, "frame %03d\n", frame)
+	}
+	writeDevice(t, device, stream.String())
+
+	var mutex sync.Mutex
+	var got []string
+	var wg sync.WaitGroup
+	deadline := time.Now().Add(c.options.Timeout)
+	for range readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				mutex.Lock()
+				complete := len(got) == frames
+				mutex.Unlock()
+				if complete {
+					return
+				}
+				frame, err := comm.ReadUntil("\n")
+				if unicommio.IsTimeout(err) && len(frame) == 0 {
+					continue
+				}
+				if err != nil {
+					t.Errorf("read until failed with %q: %v", frame, err)
+					return
+				}
+				mutex.Lock()
+				got = append(got, string(frame))
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(got)
+	expected := make([]string, frames)
+	for frame := range frames {
+		expected[frame] = fmt.Sprintf("frame %03d\n", frame)
+	}
+	if !slices.Equal(got, expected) {
+		t.Fatalf("received frames %q", got)
+	}
+}
+
+/*
+Once the device closes its end, reads fail with an error other
+than a timeout, or the instance reports it is disconnected
+*/
+func (c conformance) testPeerClose(t *testing.T) {
+	comm, device := c.makePair(t)
+
+	writeDevice(t, device, "last\n")
+	ExpectFrame(t, comm, "\n", "last\n")
+	device.Close()
+
+	deadline := time.Now().Add(c.options.Timeout)
+	for time.Now().Before(deadline) {
+		if _, err := comm.ReadUntil("\n"); err != nil && !unicommio.IsTimeout(err) {
+			return
+		}
+		if !comm.IsConnected() {
+			return
+		}
+	}
+	t.Fatal("closing the device end was not reported")
+}
@@ -0,0 +1,312 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+/*
+Duration written as a Go duration string, e.g. "250ms"
+*/
+type Duration time.Duration
+
+/*
+Reaction of the device to the commands matching a pattern.
+Responses and assigned values are templates executed with the
+variables as .Vars, the submatches as .Match and the current
+state as .State
+*/
+type Rule struct {
+	Match   string            `json:"match" yaml:"match"`     // Regular expression of the command, without the delimiter
+	Respond string            `json:"respond" yaml:"respond"` // Response template, empty sends nothing
+	Set     map[string]string `json:"set" yaml:"set"`         // Variables assigned from templates
+	Goto    string            `json:"goto" yaml:"goto"`       // Next state, empty keeps the current one
+	Delay   Duration          `json:"delay" yaml:"delay"`     // Wait before responding
+
+	pattern  *regexp.Regexp
+	response *template.Template
+	values   map[string]*template.Template
+}
+
+type State struct {
+	Rules []Rule `json:"rules" yaml:"rules"` // Tried in order, the first match wins
+}
+
+/*
+Declarative description of a device, loaded from JSON or YAML
+so its behavior can be modeled without writing Go code
+*/
+type Model struct {
+	Name      string            `json:"name" yaml:"name"`
+	Delimiter string            `json:"delimiter" yaml:"delimiter"` // Ends commands and responses, defaults to "\n"
+	Initial   string            `json:"initial" yaml:"initial"`     // State after power-up
+	Variables map[string]string `json:"variables" yaml:"variables"` // Initial values of the variables
+	States    map[string]State  `json:"states" yaml:"states"`
+	Global    []Rule            `json:"global" yaml:"global"`   // Tried in every state, after its rules
+	Unknown   string            `json:"unknown" yaml:"unknown"` // Response template of unmatched commands
+
+	unknown *template.Template
+}
+
+type templateData struct {
+	Vars  map[string]string
+	Match []string
+	State string
+}
+
+/*
+Emulated device executing a model. Handle can be used as the
+handler of a Server
+*/
+type Device struct {
+	Model Model
+
+	state     string
+	variables map[string]string
+	pending   []byte     // Received bytes without delimiter yet
+	mutex     sync.Mutex // Protect the state and the variables
+}
+
+/*
+Parses a duration string
+*/
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+/*
+Compiles the pattern and the templates of the rule
+*/
+func (r *Rule) compile(model *Model) error {
+	var err error
+	if r.pattern, err = regexp.Compile(r.Match); err != nil {
+		return fmt.Errorf("rule %q: %w", r.Match, err)
+	}
+	if r.response, err = template.New(r.Match).Parse(r.Respond); err != nil {
+		return fmt.Errorf("rule %q: %w", r.Match, err)
+	}
+	r.values = make(map[string]*template.Template, len(r.Set))
+	for name, value := range r.Set {
+		if r.values[name], err = template.New(name).Parse(value); err != nil {
+			return fmt.Errorf("rule %q: %w", r.Match, err)
+		}
+	}
+	if _, ok := model.States[r.Goto]; r.Goto != "" && !ok {
+		return fmt.Errorf("rule %q: unknown state %q", r.Match, r.Goto)
+	}
+	return nil
+}
+
+/*
+Parses a JSON model and checks its states, patterns and
+templates
+*/
+func ParseModel(data []byte) (*Model, error) {
+	var model Model
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, err
+	}
+	return compileModel(model)
+}
+
+/*
+Parses a YAML model, with the same fields as the JSON one
+*/
+func ParseModelYAML(data []byte) (*Model, error) {
+	var model Model
+	if err := yaml.Unmarshal(data, &model); err != nil {
+		return nil, err
+	}
+	return compileModel(model)
+}
+
+/*
+Checks the states of a decoded model and compiles its patterns
+and templates
+*/
+func compileModel(model Model) (*Model, error) {
+	if model.Delimiter == "" {
+		model.Delimiter = "\n"
+	}
+	if _, ok := model.States[model.Initial]; !ok {
+		return nil, fmt.Errorf("unknown initial state %q", model.Initial)
+	}
+
+	for name, state := range model.States {
+		for index := range state.Rules {
+			if err := state.Rules[index].compile(&model); err != nil {
+				return nil, fmt.Errorf("state %q: %w", name, err)
+			}
+		}
+	}
+	for index := range model.Global {
+		if err := model.Global[index].compile(&model); err != nil {
+			return nil, fmt.Errorf("global: %w", err)
+		}
+	}
+
+	var err error
+	if model.unknown, err = template.New("unknown").Parse(model.Unknown); err != nil {
+		return nil, fmt.Errorf("unknown: %w", err)
+	}
+	return &model, nil
+}
+
+/*
+Reads and parses a model file, as YAML when its extension is
+.yaml or .yml and as JSON otherwise
+*/
+func LoadModel(path string) (*Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseModelYAML(data)
+	}
+	return ParseModel(data)
+}
+
+/*
+Creates a device in the initial state of the model, which must
+come from ParseModel or LoadModel
+*/
+func NewDevice(model *Model) *Device {
+	device := &Device{Model: *model}
+	device.Reset()
+	return device
+}
+
+/*
+Starts a TCP server emulating the model, closed when the test
+finishes
+*/
+func NewSimulator(t testing.TB, model *Model) (*Server, *Device) {
+	t.Helper()
+
+	device := NewDevice(model)
+	return NewServer(t, ServerOptions{Handler: device.Handle}), device
+}
+
+/*
+Returns the device to its initial state and variables
+*/
+func (d *Device) Reset() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.state = d.Model.Initial
+	d.variables = make(map[string]string, len(d.Model.Variables))
+	for name, value := range d.Model.Variables {
+		d.variables[name] = value
+	}
+	d.pending = nil
+}
+
+/*
+Returns the current state
+*/
+func (d *Device) State() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.state
+}
+
+/*
+Returns the current value of a variable
+*/
+func (d *Device) Variable(name string) string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.variables[name]
+}
+
+/*
+Receives data from the link and returns the responses of the
+complete commands in it
+*/
+func (d *Device) Handle(request []byte) []byte {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.pending = append(d.pending, request...)
+	var reply []byte
+	for {
+		index := bytes.Index(d.pending, []byte(d.Model.Delimiter))
+		if index < 0 {
+			return reply
+		}
+		command := strings.TrimSpace(string(d.pending[:index]))
+		d.pending = d.pending[index+len(d.Model.Delimiter):]
+		reply = append(reply, d.execute(command)...)
+	}
+}
+
+/*
+Runs the first rule matching the command, must be called with
+the mutex locked
+*/
+func (d *Device) execute(command string) []byte {
+	for _, rule := range slices.Concat(d.Model.States[d.state].Rules, d.Model.Global) {
+		match := rule.pattern.FindStringSubmatch(command)
+		if match == nil {
+			continue
+		}
+
+		data := templateData{Vars: d.variables, Match: match, State: d.state}
+		values := make(map[string]string, len(rule.values))
+		for name, value := range rule.values {
+			values[name] = render(value, data)
+		}
+		response := render(rule.response, data)
+		for name, value := range values {
+			d.variables[name] = value
+		}
+		if rule.Goto != "" {
+			d.state = rule.Goto
+		}
+		time.Sleep(time.Duration(rule.Delay))
+		return []byte(response)
+	}
+
+	data := templateData{Vars: d.variables, Match: []string{command}, State: d.state}
+	return []byte(render(d.Model.unknown, data))
+}
+
+/*
+Executes a template, rendering failures as the error message
+so they show up in the test output
+*/
+func render(tmpl *template.Template, data templateData) string {
+	var output strings.Builder
+	if err := tmpl.Execute(&output, data); err != nil {
+		return err.Error()
+	}
+	return output.String()
+}
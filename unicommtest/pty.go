@@ -0,0 +1,37 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtest
+
+import (
+	"os"
+	"testing"
+)
+
+/*
+Pseudo-terminal standing in for a serial device. The serial
+backend opens PortName, while the test plays the device by
+reading and writing Device
+*/
+type PTY struct {
+	Device   *os.File
+	PortName string
+}
+
+/*
+Opens a pseudo-terminal closed when the test finishes. The test
+is skipped on platforms without pseudo-terminal support
+*/
+func NewPTY(t testing.TB) *PTY {
+	t.Helper()
+
+	device, portName, err := openPTY()
+	if err != nil {
+		t.Skipf("pseudo-terminal unavailable: %v", err)
+	}
+	t.Cleanup(func() { device.Close() })
+	return &PTY{Device: device, PortName: portName}
+}
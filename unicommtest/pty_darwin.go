@@ -0,0 +1,46 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+/*
+Opens a pseudo-terminal, returning the controller side and the
+path of the terminal side. The ioctls are the ones behind
+grantpt, unlockpt and ptsname of the C library
+*/
+func openPTY() (*os.File, string, error) {
+	controller, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := ioctl(controller, syscall.TIOCPTYGRANT, nil); err != nil {
+		controller.Close()
+		return nil, "", fmt.Errorf("granting pseudo-terminal: %w", err)
+	}
+	if err := ioctl(controller, syscall.TIOCPTYUNLK, nil); err != nil {
+		controller.Close()
+		return nil, "", fmt.Errorf("unlocking pseudo-terminal: %w", err)
+	}
+	// The name fills a buffer of 128 bytes, ended by a null byte
+	var name [128]byte
+	if err := ioctl(controller, syscall.TIOCPTYGNAME, unsafe.Pointer(&name[0])); err != nil {
+		controller.Close()
+		return nil, "", fmt.Errorf("reading pseudo-terminal name: %w", err)
+	}
+	if end := bytes.IndexByte(name[:], 0); end >= 0 {
+		return controller, string(name[:end]), nil
+	}
+	return controller, string(name[:]), nil
+}
@@ -0,0 +1,37 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtest
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+/*
+Opens a pseudo-terminal, returning the controller side and the
+path of the terminal side
+*/
+func openPTY() (*os.File, string, error) {
+	controller, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var unlock int32
+	if err := ioctl(controller, syscall.TIOCSPTLCK, unsafe.Pointer(&unlock)); err != nil {
+		controller.Close()
+		return nil, "", fmt.Errorf("unlocking pseudo-terminal: %w", err)
+	}
+	var number uint32
+	if err := ioctl(controller, syscall.TIOCGPTN, unsafe.Pointer(&number)); err != nil {
+		controller.Close()
+		return nil, "", fmt.Errorf("reading pseudo-terminal number: %w", err)
+	}
+	return controller, fmt.Sprintf("/dev/pts/%d", number), nil
+}
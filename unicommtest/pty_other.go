@@ -0,0 +1,21 @@
+//go:build !linux && !darwin
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtest
+
+import (
+	"fmt"
+	"os"
+)
+
+/*
+Pseudo-terminals are only supported on Linux and macOS
+*/
+func openPTY() (*os.File, string, error) {
+	return nil, "", fmt.Errorf("pseudo-terminals are not supported on this platform")
+}
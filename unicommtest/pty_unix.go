@@ -0,0 +1,33 @@
+//go:build linux || darwin
+
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicommtest
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+/*
+Runs an ioctl request on the file
+*/
+func ioctl(file *os.File, request uintptr, argument unsafe.Pointer) error {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errno syscall.Errno
+	conn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, request, uintptr(argument))
+	})
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+/*
+Fixtures for tests that exercise the transports without
+hardware: a local TCP server with configurable latency, devices
+emulated from declarative models, on Linux, pseudo-terminals
+that the serial backend can open, and a conformance suite for
+Unicomm implementations
+*/
+package unicommtest
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+/*
+Computes the reply to the data received by the server, nil
+sends nothing back
+*/
+type Handler func(request []byte) []byte
+
+type ServerOptions struct {
+	Latency time.Duration // Delay before each reply
+	Handler Handler       // Defaults to echoing the data back
+}
+
+type Server struct {
+	Options ServerOptions
+	Host    string
+	Port    uint
+
+	listener net.Listener
+	received []byte
+	conns    []net.Conn
+	wg       sync.WaitGroup
+	mutex    sync.Mutex // Protect received data and connections
+}
+
+/*
+Starts a TCP server on the loopback interface, closed when the
+test finishes. Every connection is served by the handler
+*/
+func NewServer(t testing.TB, options ServerOptions) *Server {
+	t.Helper()
+
+	if options.Handler == nil {
+		options.Handler = func(request []byte) []byte { return request }
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &Server{
+		Options:  options,
+		Host:     "127.0.0.1",
+		Port:     uint(listener.Addr().(*net.TCPAddr).Port),
+		listener: listener,
+	}
+	server.wg.Add(1)
+	go server.accept()
+	t.Cleanup(server.Close)
+	return server
+}
+
+/*
+Returns TCP options pointing to the server
+*/
+func (s *Server) TCPOptions() unicommtcp.TCPOptions {
+	return unicommtcp.TCPOptions{Host: s.Host, Port: s.Port}
+}
+
+/*
+Returns a copy of every byte received by the server
+*/
+func (s *Server) Received() []byte {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return bytes.Clone(s.received)
+}
+
+/*
+Stops the server and closes its connections
+*/
+func (s *Server) Close() {
+	s.listener.Close()
+	s.mutex.Lock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.mutex.Unlock()
+	s.wg.Wait()
+}
+
+/*
+Accepts connections until the listener is closed
+*/
+func (s *Server) accept() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			continue
+		}
+		s.mutex.Lock()
+		s.conns = append(s.conns, conn)
+		s.mutex.Unlock()
+
+		s.wg.Add(1)
+		go s.serve(conn)
+	}
+}
+
+/*
+Replies to the data received on the connection
+*/
+func (s *Server) serve(conn net.Conn) {
+	defer s.wg.Done()
+	defer conn.Close()
+
+	buffer := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buffer)
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		s.received = append(s.received, buffer[:n]...)
+		s.mutex.Unlock()
+
+		reply := s.Options.Handler(bytes.Clone(buffer[:n]))
+		if len(reply) == 0 {
+			continue
+		}
+		time.Sleep(s.Options.Latency)
+		if _, err := conn.Write(reply); err != nil {
+			return
+		}
+	}
+}
+
+/*
+Reads the next frame and fails the test if it differs from
+the expected one
+*/
+func ExpectFrame(t testing.TB, comm unicomm.Unicomm, delimiter string, expected string) {
+	t.Helper()
+
+	frame, err := unicomm.ReadUntil(comm, delimiter)
+	if err != nil {
+		t.Fatalf("reading frame %q: %v", expected, err)
+	}
+	if string(frame) != expected {
+		t.Fatalf("got frame %q, want %q", frame, expected)
+	}
+}
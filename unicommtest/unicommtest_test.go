@@ -0,0 +1,232 @@
+package unicommtest_test
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestServerLatency(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Latency: 20 * time.Millisecond,
+		Handler: func(request []byte) []byte {
+			return append([]byte("ACK "), request...)
+		},
+	})
+	comm := unicommtcp.NewTCP(server.TCPOptions())
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	start := time.Now()
+	if err := comm.Write([]byte("PING\n")); err != nil {
+		t.Fatal(err)
+	}
+	unicommtest.ExpectFrame(t, comm, "\n", "ACK PING\n")
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Fatalf("reply arrived after %v, before the latency", elapsed)
+	}
+	if !bytes.Equal(server.Received(), []byte("PING\n")) {
+		t.Fatalf("server received %q", server.Received())
+	}
+}
+
+func TestPTYSerial(t *testing.T) {
+	pty := unicommtest.NewPTY(t)
+	comm := unicommserial.NewSerial(unicommserial.SerialOptions{
+		PortName:    pty.PortName,
+		BaudRate:    115200,
+		ReadTimeout: time.Second,
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	if _, err := pty.Device.Write([]byte("READY\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	unicommtest.ExpectFrame(t, comm, "\r\n", "READY\r\n")
+
+	if err := comm.Write([]byte("RUN\n")); err != nil {
+		t.Fatal(err)
+	}
+	buffer := make([]byte, 16)
+	n, err := pty.Device.Read(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buffer[:n]) != "RUN\n" {
+		t.Fatalf("device received %q", buffer[:n])
+	}
+}
+
+func TestSimulatorModel(t *testing.T) {
+	model, err := unicommtest.ParseModel([]byte(`{
+		"name": "Power supply",
+		"initial": "off",
+		"variables": {"voltage": "0.00"},
+		"states": {
+			"off": {"rules": [
+				{"match": "^OUTP ON$", "respond": "OK\n", "goto": "on"},
+				{"match": "^MEAS\\?$", "respond": "0.00\n"}
+			]},
+			"on": {"rules": [
+				{"match": "^OUTP OFF$", "respond": "OK\n", "goto": "off"},
+				{"match": "^MEAS\\?$", "respond": "{{.Vars.voltage}}\n", "delay": "20ms"}
+			]}
+		},
+		"global": [
+			{"match": "^VOLT (\\d+\\.\\d+)$", "set": {"voltage": "{{index .Match 1}}"}, "respond": "OK\n"}
+		],
+		"unknown": "ERR {{index .Match 0}}\n"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, device := unicommtest.NewSimulator(t, model)
+	comm := unicommtcp.NewTCP(server.TCPOptions())
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	for _, exchange := range [][2]string{
+		{"VOLT 12.50\n", "OK\n"},
+		{"MEAS?\n", "0.00\n"},
+		{"OUTP ON\n", "OK\n"},
+		{"MEAS?\n", "12.50\n"},
+		{"RESET\n", "ERR RESET\n"},
+	} {
+		if err := comm.Write([]byte(exchange[0])); err != nil {
+			t.Fatal(err)
+		}
+		unicommtest.ExpectFrame(t, comm, "\n", exchange[1])
+	}
+	if device.State() != "on" || device.Variable("voltage") != "12.50" {
+		t.Fatalf("device in state %q with voltage %q", device.State(), device.Variable("voltage"))
+	}
+
+	if _, err := unicommtest.ParseModel([]byte(`{"initial": "idle", "states": {"idle": {"rules": [{"match": "X", "goto": "missing"}]}}}`)); err == nil {
+		t.Fatal("model with an unknown state was accepted")
+	}
+}
+
+func TestSimulatorModelYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "psu.yaml")
+	err := os.WriteFile(path, []byte(`
+name: Power supply
+initial: "off"
+variables: {voltage: "0.00"}
+states:
+  "off":
+    rules:
+      - {match: '^OUTP ON$', respond: "OK\n", goto: "on"}
+  "on":
+    rules:
+      - {match: '^MEAS\?$', respond: "{{.Vars.voltage}}\n", delay: 20ms}
+global:
+  - match: '^VOLT (\d+\.\d+)$'
+    set: {voltage: "{{index .Match 1}}"}
+    respond: "OK\n"
+unknown: "ERR\n"
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, err := unicommtest.LoadModel(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(model.States["on"].Rules[0].Delay) != 20*time.Millisecond {
+		t.Fatalf("delay = %v", model.States["on"].Rules[0].Delay)
+	}
+	server, device := unicommtest.NewSimulator(t, model)
+	comm := unicommtcp.NewTCP(server.TCPOptions())
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	for _, exchange := range [][2]string{
+		{"VOLT 5.00\n", "OK\n"},
+		{"OUTP ON\n", "OK\n"},
+		{"MEAS?\n", "5.00\n"},
+		{"RESET\n", "ERR\n"},
+	} {
+		if err := comm.Write([]byte(exchange[0])); err != nil {
+			t.Fatal(err)
+		}
+		unicommtest.ExpectFrame(t, comm, "\n", exchange[1])
+	}
+	if device.State() != "on" {
+		t.Fatalf("device in state %q", device.State())
+	}
+
+	if _, err := unicommtest.ParseModelYAML([]byte("initial: idle\nstates: {idle: {rules: [{match: X, goto: missing}]}}\n")); err == nil {
+		t.Fatal("model with an unknown state was accepted")
+	}
+}
+
+func TestConformanceTCP(t *testing.T) {
+	unicommtest.TestConformance(t, func(t testing.TB) (unicommtest.Conn, io.ReadWriteCloser) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close()
+
+		comm := unicommtcp.NewTCP(unicommtcp.TCPOptions{
+			Host:        "127.0.0.1",
+			Port:        uint(listener.Addr().(*net.TCPAddr).Port),
+			ReadTimeout: 100 * time.Millisecond,
+		})
+		if err := comm.Connect(); err != nil {
+			t.Fatal(err)
+		}
+		device, err := listener.Accept()
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			device.Close()
+			if comm.IsConnected() {
+				comm.Disconnect()
+			}
+		})
+		return comm, device
+	}, unicommtest.ConformanceOptions{})
+}
+
+func TestConformanceSerial(t *testing.T) {
+	unicommtest.TestConformance(t, func(t testing.TB) (unicommtest.Conn, io.ReadWriteCloser) {
+		pty := unicommtest.NewPTY(t)
+		comm := unicommserial.NewSerial(unicommserial.SerialOptions{
+			PortName:    pty.PortName,
+			BaudRate:    115200,
+			ReadTimeout: 100 * time.Millisecond,
+		})
+		if err := comm.Connect(); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if comm.IsConnected() {
+				comm.Disconnect()
+			}
+		})
+		return comm, pty.Device
+	}, unicommtest.ConformanceOptions{
+		// Each write resets the buffers of the port, discarding the
+		// bytes of the previous writes not yet transmitted
+		Skip: []string{"ConcurrentWrites"},
+	})
+}
@@ -0,0 +1,82 @@
+package unicomm_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+func TestReadAtLeastAndVectored(t *testing.T) {
+	port := serveOnce(t, []byte("HDR1payload-one\x00HDR2payload-two\x00TAIL"))
+	comm := unicomm.New(unicomm.Options{
+		Protocol: unicomm.TCP,
+		TCP:      unicommtcp.TCPOptions{Host: "127.0.0.1", Port: port, ReadTimeout: 200 * time.Millisecond},
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	header, payload := make([]byte, 4), make([]byte, 12)
+	for _, expected := range []string{"HDR1payload-one\x00", "HDR2payload-two\x00"} {
+		n, err := unicomm.ReadVectored(comm, [][]byte{header, payload})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(header) + string(payload[:n-len(header)]); got != expected {
+			t.Fatalf("got %q, want %q", got, expected)
+		}
+	}
+
+	// Fewer bytes than the minimum are returned with a timeout
+	data, err := unicomm.ReadAtLeast(comm, 8, 64)
+	if !unicomm.IsTimeout(err) || string(data) != "TAIL" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+	if _, err := unicomm.ReadAtLeast(comm, 8, 4); err == nil {
+		t.Fatal("expected an error for maximum below minimum")
+	}
+}
+
+// Hides the socket of the connection, like tunnels do
+type plainConn struct {
+	net.Conn
+}
+
+func TestReadVectoredWithoutSocket(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("AB"))
+		time.Sleep(50 * time.Millisecond)
+		conn.Write([]byte("CDEF"))
+		time.Sleep(time.Second)
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	comm := unicommtcp.NewTCPFromConn(plainConn{conn}, unicommtcp.TCPOptions{ReadTimeout: time.Second})
+	defer comm.Disconnect()
+
+	first, second := make([]byte, 3), make([]byte, 3)
+	n, err := comm.ReadVectored([][]byte{first, second})
+	if err != nil || n != 6 {
+		t.Fatalf("read %d bytes: %v", n, err)
+	}
+	if string(first)+string(second) != "ABCDEF" {
+		t.Fatalf("got %q %q", first, second)
+	}
+}
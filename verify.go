@@ -0,0 +1,105 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type VerifyOptions struct {
+	Attempts int           // Writes before giving up, defaults to 3
+	Delay    time.Duration // Wait between the write and the read-back
+}
+
+/*
+Returned when the read-back never matched the expected value
+*/
+type VerifyError struct {
+	Command  string
+	Expected string
+	Got      string // Last read-back response
+	Attempts int
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf(
+		"write %q not confirmed after %d attempts: got %q, expected %q",
+		e.Command, e.Attempts, e.Got, e.Expected,
+	)
+}
+
+/*
+Writes a command and queries the device to confirm it took
+effect, writing it again while the response differs from the
+expected value. Each write and its read-back form one
+transaction, so no other query interleaves. Responses are
+compared without the delimiter and surrounding whitespaces, and
+read-backs bypass the cache
+*/
+func (c *Client) VerifiedWrite(command, verifyQuery, expected string, options VerifyOptions) error {
+	if options.Attempts == 0 {
+		options.Attempts = 3
+	}
+
+	var got string
+	for range options.Attempts {
+		response, err := c.writeAndVerify([]byte(command), []byte(verifyQuery), options.Delay)
+		if err != nil {
+			return err
+		}
+		got = strings.TrimSpace(string(bytes.TrimSuffix(response, []byte(c.Delimiter))))
+		if got == expected {
+			return nil
+		}
+	}
+	return &VerifyError{
+		Command:  command,
+		Expected: expected,
+		Got:      got,
+		Attempts: options.Attempts,
+	}
+}
+
+/*
+Writes a command and reads back the response of the verify
+query under the client lock. The cached response of the query
+is dropped once the command is written and refreshed by the
+read-back
+*/
+func (c *Client) writeAndVerify(command, verifyQuery []byte, delay time.Duration) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.Unicomm.Write(command); err != nil {
+		return nil, err
+	}
+	if c.Cache != nil {
+		c.Cache.Invalidate(string(verifyQuery))
+	}
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+
+	start := time.Now()
+	if err := c.Unicomm.Write(verifyQuery); err != nil {
+		c.observe(start, err)
+		return nil, err
+	}
+	response, err := ReadUntil(c.Unicomm, c.Delimiter)
+	c.observe(start, err)
+	if err != nil {
+		return response, err
+	}
+	c.latency = time.Since(start)
+	if c.Cache != nil {
+		c.Cache.store(string(verifyQuery), response)
+	}
+	return response, nil
+}
@@ -0,0 +1,87 @@
+package unicomm_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+/*
+Serves a device whose setpoint is read back by "VOLT?" and
+changed by "VOLT <value>", ignoring the first ignored changes
+*/
+func serveSetpoint(t *testing.T, ignored int) *unicomm.Client {
+	t.Helper()
+
+	var mutex sync.Mutex
+	setpoint := "0"
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			var reply []byte
+			for _, line := range strings.Split(strings.TrimSpace(string(request)), "\n") {
+				switch {
+				case line == "VOLT?":
+					reply = append(reply, setpoint+"\r\n"...)
+				case ignored > 0:
+					ignored--
+				default:
+					setpoint = strings.TrimPrefix(line, "VOLT ")
+				}
+			}
+			return reply
+		},
+	})
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions()})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { comm.Disconnect() })
+	return unicomm.NewClient(comm, "\r\n")
+}
+
+func TestVerifiedWrite(t *testing.T) {
+	client := serveSetpoint(t, 0)
+	client.Cache = unicomm.NewResponseCache()
+	client.Cache.Register("VOLT?\n", time.Hour)
+	if response, err := client.Query([]byte("VOLT?\n")); err != nil || string(response) != "0\r\n" {
+		t.Fatalf("Query = %q, %v", response, err)
+	}
+
+	if err := client.VerifiedWrite("VOLT 5\n", "VOLT?\n", "5", unicomm.VerifyOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cached read-back holds the value written
+	if response, err := client.Query([]byte("VOLT?\n")); err != nil || string(response) != "5\r\n" {
+		t.Fatalf("Query = %q, %v, want the verified value", response, err)
+	}
+}
+
+func TestVerifiedWriteRetry(t *testing.T) {
+	client := serveSetpoint(t, 1)
+	err := client.VerifiedWrite("VOLT 5\n", "VOLT?\n", "5", unicomm.VerifyOptions{Delay: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("expected the second write to be confirmed, got %v", err)
+	}
+}
+
+func TestVerifiedWriteFailure(t *testing.T) {
+	client := serveSetpoint(t, 3)
+	err := client.VerifiedWrite("VOLT 5\n", "VOLT?\n", "5", unicomm.VerifyOptions{})
+
+	var verifyErr *unicomm.VerifyError
+	if !errors.As(err, &verifyErr) {
+		t.Fatalf("expected a VerifyError, got %v", err)
+	}
+	if verifyErr.Attempts != 3 || verifyErr.Got != "0" || verifyErr.Expected != "5" {
+		t.Fatalf("unexpected error %+v", verifyErr)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+/*
+A command executed after the connection is established. When
+Expect is not empty, the response is read until the delimiter
+and must contain it
+*/
+type WarmUpStep struct {
+	Command   []byte
+	Expect    string
+	Delimiter string // Defaults to "\n"
+	Delay     time.Duration
+}
+
+type WarmUp struct {
+	Unicomm
+	Steps []WarmUpStep
+}
+
+/*
+Creates a wrapper that runs the initialization commands after
+every successful connect, so the device always starts from a
+known state
+*/
+func NewWarmUp(comm Unicomm, steps []WarmUpStep) *WarmUp {
+	return &WarmUp{
+		Unicomm: comm,
+		Steps:   steps,
+	}
+}
+
+/*
+Runs the warm-up steps in order
+*/
+func (w *WarmUp) run() error {
+	for index, step := range w.Steps {
+		if err := w.Unicomm.Write(step.Command); err != nil {
+			return fmt.Errorf("warm-up step %d failed: %w", index, err)
+		}
+		if step.Expect != "" {
+			delimiter := step.Delimiter
+			if delimiter == "" {
+				delimiter = "\n"
+			}
+			response, err := ReadUntil(w.Unicomm, delimiter)
+			if err != nil {
+				return fmt.Errorf("warm-up step %d failed: %w", index, err)
+			}
+			if !strings.Contains(string(response), step.Expect) {
+				return fmt.Errorf("warm-up step %d failed: expected %q, got %q", index, step.Expect, response)
+			}
+		}
+		time.Sleep(step.Delay)
+	}
+	return nil
+}
+
+/*
+Establishes the connection and runs the warm-up steps. The
+connection is closed if any step fails
+*/
+func (w *WarmUp) Connect() error {
+	if err := w.Unicomm.Connect(); err != nil {
+		return err
+	}
+	if err := w.run(); err != nil {
+		w.Unicomm.Disconnect()
+		return err
+	}
+	return nil
+}
+
+/*
+Writes an array of bytes without the end delimiter
+*/
+func (w *WarmUp) WriteRaw(message []byte) error {
+	return writeRaw(w.Unicomm, message)
+}
+
+/*
+Returns the wrapped instance
+*/
+func (w *WarmUp) Unwrap() Unicomm {
+	return w.Unicomm
+}
@@ -0,0 +1,49 @@
+package unicomm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestWarmUp(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func(request []byte) []byte {
+			if strings.Contains(string(request), "SYST:REM\n") {
+				return []byte("REMOTE OK\n")
+			}
+			return nil
+		},
+	})
+	steps := []unicomm.WarmUpStep{
+		{Command: []byte("*RST\n")},
+		{Command: []byte("SYST:REM\n"), Expect: "OK"},
+	}
+	comm := unicomm.New(unicomm.Options{Protocol: unicomm.TCP, TCP: server.TCPOptions(), WarmUp: steps})
+
+	// The steps run on every connect, reconnections included
+	for range 2 {
+		if err := comm.Connect(); err != nil {
+			t.Fatal(err)
+		}
+		if err := comm.Disconnect(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := string(server.Received()); got != "*RST\nSYST:REM\n*RST\nSYST:REM\n" {
+		t.Fatalf("device received %q", got)
+	}
+
+	// An unexpected response fails the connect and closes it
+	warmUp, _ := unicomm.As[*unicomm.WarmUp](comm)
+	warmUp.Steps = []unicomm.WarmUpStep{{Command: []byte("SYST:REM\n"), Expect: "LOCKED"}}
+	err := comm.Connect()
+	if err == nil || !strings.Contains(err.Error(), "warm-up step 0 failed") {
+		t.Fatalf("Connect = %v, want the failed step", err)
+	}
+	if comm.IsConnected() {
+		t.Fatal("connection kept open after the warm-up failed")
+	}
+}
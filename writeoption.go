@@ -0,0 +1,58 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 15th, 2026
+Last update: October 15th, 2026
+*/
+
+package unicomm
+
+import "fmt"
+
+type WriteOption uint8
+
+const (
+	// Sends the message without the end delimiter of the backend,
+	// e.g. a raw binary block in the middle of a text protocol
+	NoAutoDelimiter WriteOption = 1
+)
+
+/*
+Implemented by instances able to write a message without
+appending the end delimiter
+*/
+type RawWriter interface {
+	WriteRaw(message []byte) error
+}
+
+/*
+Implemented by backends that append an end delimiter on writes,
+so wrappers merging messages can keep their boundaries
+*/
+type DelimitedWriter interface {
+	EndDelimiter() string
+}
+
+/*
+Writes a message with options that apply to this write only.
+Without options it is the same as Write
+*/
+func WriteWith(comm Unicomm, message []byte, options ...WriteOption) error {
+	for _, option := range options {
+		if option == NoAutoDelimiter {
+			return writeRaw(comm, message)
+		}
+	}
+	return comm.Write(message)
+}
+
+/*
+Writes the message without the end delimiter. Every wrapper
+implements WriteRaw applying its own transformation, so a raw
+write never skips one
+*/
+func writeRaw(comm Unicomm, message []byte) error {
+	if writer, ok := comm.(RawWriter); ok {
+		return writer.WriteRaw(message)
+	}
+	return fmt.Errorf("writes without the end delimiter are not supported")
+}
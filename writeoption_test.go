@@ -0,0 +1,76 @@
+package unicomm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/unicommtest"
+)
+
+func TestWriteNoAutoDelimiter(t *testing.T) {
+	server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+		Handler: func([]byte) []byte { return nil },
+	})
+	options := server.TCPOptions()
+	options.EndDelimiter = "\n"
+	comm := unicomm.New(unicomm.Options{
+		Protocol:    unicomm.TCP,
+		TCP:         options,
+		IdleTimeout: time.Minute,
+	})
+	if err := comm.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer comm.Disconnect()
+
+	if err := comm.Write([]byte("DATA 4")); err != nil {
+		t.Fatal(err)
+	}
+	if err := unicomm.WriteWith(comm, []byte{0x00, 0x01, 0x02, 0x03}, unicomm.NoAutoDelimiter); err != nil {
+		t.Fatal(err)
+	}
+	if err := unicomm.WriteWith(comm, []byte("END")); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if received := string(server.Received()); received != "DATA 4\n\x00\x01\x02\x03END\n" {
+		t.Fatalf("server received %q", received)
+	}
+}
+
+func TestWriteNoAutoDelimiterThroughWrappers(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		options  unicomm.Options
+		message  string
+		expected string
+	}{
+		{"ReadUntil", unicomm.Options{ReadUntil: &unicomm.ReadUntilOptions{StripDelimiter: true}}, "\x00\x01", "\x00\x01"},
+		{"Outbox", unicomm.Options{Outbox: &unicomm.OutboxOptions{}}, "\x00\x01", "\x00\x01"},
+		{"Text", unicomm.Options{Text: unicomm.TextOptions{Write: unicomm.NewlineCRLF}}, "A\nB", "A\r\nB"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			server := unicommtest.NewServer(t, unicommtest.ServerOptions{
+				Handler: func([]byte) []byte { return nil },
+			})
+			test.options.Protocol = unicomm.TCP
+			test.options.TCP = server.TCPOptions()
+			test.options.TCP.EndDelimiter = "\n"
+			comm := unicomm.New(test.options)
+			if err := comm.Connect(); err != nil {
+				t.Fatal(err)
+			}
+			defer comm.Disconnect()
+
+			if err := unicomm.WriteWith(comm, []byte(test.message), unicomm.NoAutoDelimiter); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(50 * time.Millisecond)
+			if received := string(server.Received()); received != test.expected {
+				t.Fatalf("server received %q, want %q", received, test.expected)
+			}
+		})
+	}
+}